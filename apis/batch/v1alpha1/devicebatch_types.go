@@ -0,0 +1,217 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Batch states
+const (
+	// StateFailed indicates the batch could not provision its devices
+	StateFailed = "failed"
+
+	// StateCompleted indicates the batch has provisioned its devices
+	StateCompleted = "completed"
+)
+
+// DeviceBatchSpec defines the desired state of DeviceBatch
+type DeviceBatchSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       DeviceBatchParameters `json:"forProvider"`
+}
+
+// DeviceBatchStatus defines the observed state of DeviceBatch
+type DeviceBatchStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          DeviceBatchObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A DeviceBatch is a managed resource that represents an Equinix Metal Batch
+// of identical devices. Batches can not be modified once created, so all of
+// their parameters are immutable.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.atProvider.id"
+// +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.atProvider.state"
+// +kubebuilder:printcolumn:name="QUANTITY",type="integer",JSONPath=".spec.forProvider.quantity"
+// +kubebuilder:printcolumn:name="PROVISIONED",type="integer",JSONPath=".status.atProvider.provisionedCount"
+// +kubebuilder:printcolumn:name="RECLAIM-POLICY",type="string",JSONPath=".spec.reclaimPolicy"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix}
+type DeviceBatch struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DeviceBatchSpec   `json:"spec"`
+	Status DeviceBatchStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DeviceBatchList contains a list of DeviceBatches
+type DeviceBatchList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DeviceBatch `json:"items"`
+}
+
+// DeviceBatchParameters define the desired state of an Equinix Metal Batch.
+// https://metal.equinix.com/developers/api/batches/
+type DeviceBatchParameters struct {
+	// +immutable
+	ProjectID string `json:"projectId,omitempty"`
+
+	// +optional
+	// +immutable
+	ProjectIDRef *xpv1.Reference `json:"projectIdRef,omitempty"`
+
+	// +optional
+	ProjectIDSelector *xpv1.Selector `json:"projectIdSelector,omitempty"`
+
+	// Quantity is the number of devices provisioned by the batch
+	// +immutable
+	// +required
+	// +kubebuilder:validation:Minimum=1
+	Quantity int `json:"quantity"`
+
+	// FacilityDiversityLevel is the maximum number of devices provisioned in
+	// any one facility
+	// +immutable
+	// +optional
+	FacilityDiversityLevel *int `json:"facilityDiversityLevel,omitempty"`
+
+	// DeleteDevices deletes the devices provisioned by the batch when the
+	// batch is deleted
+	// +optional
+	DeleteDevices *bool `json:"deleteDevices,omitempty"`
+
+	// +immutable
+	// +required
+	InstanceParameters DeviceBatchInstanceParameters `json:"instanceParameters"`
+}
+
+// DeviceBatchInstanceParameters define the devices provisioned by a Batch.
+type DeviceBatchInstanceParameters struct {
+	// +immutable
+	// +required
+	Plan string `json:"plan"`
+
+	// +immutable
+	// +required
+	OS string `json:"operatingSystem"`
+
+	// Hostname is the hostname template of the devices, for example
+	// "worker-{{index}}"
+	// +immutable
+	// +required
+	Hostname string `json:"hostname"`
+
+	// +immutable
+	// +optional
+	Facilities []string `json:"facilities,omitempty"`
+
+	// +immutable
+	// +optional
+	Metro *string `json:"metro,omitempty"`
+
+	// +immutable
+	// +optional
+	Description *string `json:"description,omitempty"`
+
+	// +immutable
+	// +optional
+	BillingCycle *string `json:"billingCycle,omitempty"`
+
+	// +immutable
+	// +optional
+	UserData *string `json:"userdata,omitempty"`
+
+	// +immutable
+	// +optional
+	CustomData *string `json:"customData,omitempty"`
+
+	// +immutable
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// +immutable
+	// +optional
+	Features map[string]string `json:"features,omitempty"`
+
+	// +immutable
+	// +optional
+	ProjectSSHKeys []string `json:"projectSSHKeys,omitempty"`
+
+	// +immutable
+	// +optional
+	UserSSHKeys []string `json:"userSSHKeys,omitempty"`
+
+	// +immutable
+	// +optional
+	PublicIPv4SubnetSize *int `json:"publicIPv4SubnetSize,omitempty"`
+
+	// +immutable
+	// +optional
+	AlwaysPXE *bool `json:"alwaysPXE,omitempty"`
+
+	// +immutable
+	// +optional
+	IPXEScriptURL *string `json:"ipxeScriptUrl,omitempty"`
+
+	// +immutable
+	// +optional
+	SpotInstance *bool `json:"spotInstance,omitempty"`
+
+	// SpotPriceMax is the maximum price per hour, in US dollars, bid for each
+	// spot instance
+	// +immutable
+	// +optional
+	SpotPriceMax *resource.Quantity `json:"spotPriceMax,omitempty"`
+
+	// TerminationTime is the time at which the devices are terminated
+	// +immutable
+	// +optional
+	TerminationTime *metav1.Time `json:"terminationTime,omitempty"`
+}
+
+// DeviceBatchObservation is used to reflect in the Kubernetes API, the
+// observed state of the DeviceBatch resource from the Equinix Metal API.
+type DeviceBatchObservation struct {
+	ID    string `json:"id"`
+	Href  string `json:"href,omitempty"`
+	State string `json:"state,omitempty"`
+
+	// ProvisionedCount is the number of active devices of the batch
+	ProvisionedCount int `json:"provisionedCount"`
+
+	// DeviceIDs are the IDs of the devices provisioned by the batch
+	// +optional
+	DeviceIDs []string `json:"deviceIds,omitempty"`
+
+	// ErrorMessages explain why the batch failed
+	// +optional
+	ErrorMessages []string `json:"errorMessages,omitempty"`
+
+	// +optional
+	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BGP address families
+const (
+	AddressFamilyIPv4 = "ipv4"
+	AddressFamilyIPv6 = "ipv6"
+)
+
+// BGPSessionSpec defines the desired state of BGPSession
+type BGPSessionSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       BGPSessionParameters `json:"forProvider"`
+}
+
+// BGPSessionStatus defines the observed state of BGPSession
+type BGPSessionStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          BGPSessionObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BGPSession is a managed resource that represents an Equinix Metal BGP
+// Session of a Device
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.atProvider.id"
+// +kubebuilder:printcolumn:name="FAMILY",type="string",JSONPath=".spec.forProvider.addressFamily"
+// +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.atProvider.status"
+// +kubebuilder:printcolumn:name="RECLAIM-POLICY",type="string",JSONPath=".spec.reclaimPolicy"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix}
+type BGPSession struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BGPSessionSpec   `json:"spec"`
+	Status BGPSessionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BGPSessionList contains a list of BGPSessions
+type BGPSessionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BGPSession `json:"items"`
+}
+
+// BGPSessionParameters define the desired state of an Equinix Metal BGP
+// Session.
+// https://metal.equinix.com/developers/api/bgp/#create-a-bgp-session
+//
+// Reference values are used for optional parameters to determine if
+// LateInitialization should update the parameter after creation.
+type BGPSessionParameters struct {
+	// +immutable
+	DeviceID string `json:"deviceId,omitempty"`
+
+	// +optional
+	// +immutable
+	DeviceIDRef *xpv1.Reference `json:"deviceIdRef,omitempty"`
+
+	// +optional
+	DeviceIDSelector *xpv1.Selector `json:"deviceIdSelector,omitempty"`
+
+	// AddressFamily of the session. Changing the address family recreates
+	// the session.
+	// +required
+	// +kubebuilder:validation:Enum=ipv4;ipv6
+	AddressFamily string `json:"addressFamily"`
+
+	// DefaultRoute advertises a default route to the device
+	// +optional
+	DefaultRoute *bool `json:"defaultRoute,omitempty"`
+}
+
+// BGPSessionObservation is used to reflect in the Kubernetes API, the
+// observed state of the BGPSession resource from the Equinix Metal API.
+type BGPSessionObservation struct {
+	ID            string   `json:"id"`
+	Href          string   `json:"href,omitempty"`
+	Status        string   `json:"status,omitempty"`
+	LearnedRoutes []string `json:"learnedRoutes,omitempty"`
+
+	// +optional
+	Peer *BGPPeerObservation `json:"peer,omitempty"`
+}
+
+// BGPPeerObservation describes the Equinix Metal BGP neighbor of the session
+type BGPPeerObservation struct {
+	PeerAS     int      `json:"peerAS,omitempty"`
+	PeerIPs    []string `json:"peerIPs,omitempty"`
+	CustomerAS int      `json:"customerAS,omitempty"`
+	CustomerIP string   `json:"customerIP,omitempty"`
+	MD5Enabled bool     `json:"md5Enabled,omitempty"`
+	Multihop   bool     `json:"multihop,omitempty"`
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BGPConfig statuses
+const (
+	StatusEnabled   = "enabled"
+	StatusRequested = "requested"
+	StatusDisabled  = "disabled"
+)
+
+// BGPConfigSpec defines the desired state of BGPConfig
+type BGPConfigSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       BGPConfigParameters `json:"forProvider"`
+}
+
+// BGPConfigStatus defines the observed state of BGPConfig
+type BGPConfigStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          BGPConfigObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BGPConfig is a managed resource that represents the BGP configuration of an
+// Equinix Metal Project. Equinix Metal does not support disabling BGP, so
+// deleting a BGPConfig only removes the custom resource.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.atProvider.id"
+// +kubebuilder:printcolumn:name="TYPE",type="string",JSONPath=".spec.forProvider.deploymentType"
+// +kubebuilder:printcolumn:name="ASN",type="integer",JSONPath=".spec.forProvider.asn"
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.atProvider.status"
+// +kubebuilder:printcolumn:name="RECLAIM-POLICY",type="string",JSONPath=".spec.reclaimPolicy"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix}
+type BGPConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BGPConfigSpec   `json:"spec"`
+	Status BGPConfigStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BGPConfigList contains a list of BGPConfigs
+type BGPConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BGPConfig `json:"items"`
+}
+
+// BGPConfigParameters define the desired state of the BGP configuration of an
+// Equinix Metal Project.
+// https://metal.equinix.com/developers/api/bgp/#requesting-bgp-config
+//
+// Reference values are used for optional parameters to determine if
+// LateInitialization should update the parameter after creation.
+type BGPConfigParameters struct {
+	// +immutable
+	ProjectID string `json:"projectId,omitempty"`
+
+	// +optional
+	// +immutable
+	ProjectIDRef *xpv1.Reference `json:"projectIdRef,omitempty"`
+
+	// +optional
+	ProjectIDSelector *xpv1.Selector `json:"projectIdSelector,omitempty"`
+
+	// DeploymentType is "local" for private ASNs or "global" for public ASNs,
+	// which require approval
+	// +immutable
+	// +required
+	// +kubebuilder:validation:Enum=local;global
+	DeploymentType string `json:"deploymentType"`
+
+	// +immutable
+	// +required
+	ASN int `json:"asn"`
+
+	// MD5 is the password of the BGP sessions of the project
+	// +immutable
+	// +optional
+	MD5 *string `json:"md5,omitempty"`
+}
+
+// BGPConfigObservation is used to reflect in the Kubernetes API, the observed
+// state of the BGPConfig resource from the Equinix Metal API.
+type BGPConfigObservation struct {
+	ID          string `json:"id"`
+	Href        string `json:"href,omitempty"`
+	Status      string `json:"status,omitempty"`
+	RouteObject string `json:"routeObject,omitempty"`
+	MaxPrefix   int    `json:"maxPrefix,omitempty"`
+
+	// +optional
+	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
+}
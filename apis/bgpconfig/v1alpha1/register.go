@@ -0,0 +1,50 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/scheme"
+)
+
+// Equinix Metal type metadata.
+const (
+	Group   = "bgpconfig.metal.equinix.com"
+	Version = "v1alpha1"
+)
+
+var (
+	// SchemeGroupVersion is group version used to register these objects
+	SchemeGroupVersion = schema.GroupVersion{Group: Group, Version: Version}
+
+	// SchemeBuilder is used to add go types to the GroupVersionKind scheme
+	SchemeBuilder = &scheme.Builder{GroupVersion: SchemeGroupVersion}
+)
+
+// BGPConfig type metadata.
+var (
+	BGPConfigKind             = reflect.TypeOf(BGPConfig{}).Name()
+	BGPConfigGroupKind        = schema.GroupKind{Group: Group, Kind: BGPConfigKind}.String()
+	BGPConfigKindAPIVersion   = BGPConfigKind + "." + SchemeGroupVersion.String()
+	BGPConfigGroupVersionKind = SchemeGroupVersion.WithKind(BGPConfigKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&BGPConfig{}, &BGPConfigList{})
+}
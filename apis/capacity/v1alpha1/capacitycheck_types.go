@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CapacityCheckSpec defines the desired state of CapacityCheck
+type CapacityCheckSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       CapacityCheckParameters `json:"forProvider"`
+}
+
+// CapacityCheckStatus defines the observed state of CapacityCheck
+type CapacityCheckStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          CapacityCheckObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A CapacityCheck is an observe-only managed resource that reports whether
+// Equinix Metal has the capacity to provision devices of a plan in a facility
+// or metro. Capacity is checked each time the resource is reconciled.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="AVAILABLE",type="boolean",JSONPath=".status.atProvider.available"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix}
+type CapacityCheck struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CapacityCheckSpec   `json:"spec"`
+	Status CapacityCheckStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CapacityCheckList contains a list of CapacityChecks
+type CapacityCheckList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CapacityCheck `json:"items"`
+}
+
+// CapacityCheckParameters identify the Equinix Metal capacity to check.
+// https://metal.equinix.com/developers/api/capacity/
+type CapacityCheckParameters struct {
+	// Servers are the plans and locations to check the capacity of
+	// +required
+	Servers []ServerCapacity `json:"servers"`
+}
+
+// ServerCapacity is a number of devices of a plan in a facility or metro.
+// Exactly one of Facility or Metro must be specified.
+type ServerCapacity struct {
+	// Plan is the slug of the device plan, such as c3.medium.x86
+	// +required
+	Plan string `json:"plan"`
+
+	// +optional
+	Facility *string `json:"facility,omitempty"`
+
+	// +optional
+	Metro *string `json:"metro,omitempty"`
+
+	// Quantity is the number of devices to check the capacity for, one
+	// device is checked when Quantity is not set
+	// +optional
+	Quantity int `json:"quantity,omitempty"`
+}
+
+// CapacityCheckObservation is used to reflect in the Kubernetes API, the
+// observed state of the CapacityCheck resource from the Equinix Metal API.
+type CapacityCheckObservation struct {
+	// Available is true when every server can be provisioned
+	Available bool `json:"available"`
+
+	// Servers reports the availability of each server, in the order of
+	// spec.forProvider.servers
+	// +optional
+	Servers []ServerAvailability `json:"servers,omitempty"`
+}
+
+// ServerAvailability is the observed availability of a ServerCapacity
+type ServerAvailability struct {
+	Plan      string `json:"plan"`
+	Facility  string `json:"facility,omitempty"`
+	Metro     string `json:"metro,omitempty"`
+	Quantity  int    `json:"quantity,omitempty"`
+	Available bool   `json:"available"`
+}
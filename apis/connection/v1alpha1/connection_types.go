@@ -0,0 +1,175 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Connection types.
+const (
+	ConnectionTypeDedicated = "dedicated"
+	ConnectionTypeShared    = "shared"
+)
+
+// Connection redundancies.
+const (
+	ConnectionRedundancyPrimary   = "primary"
+	ConnectionRedundancyRedundant = "redundant"
+)
+
+// Connection statuses reported by the Equinix Metal API.
+const (
+	ConnectionStatusActive         = "active"
+	ConnectionStatusDeprovisioning = "deprovisioning"
+)
+
+// ConnectionSpec defines the desired state of Connection
+type ConnectionSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       ConnectionParameters `json:"forProvider"`
+}
+
+// ConnectionStatus defines the observed state of Connection
+type ConnectionStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          ConnectionObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// Connection is a managed resource that represents an Equinix Metal
+// interconnection to Equinix Fabric
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.atProvider.id"
+// +kubebuilder:printcolumn:name="TYPE",type="string",JSONPath=".spec.forProvider.type"
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.atProvider.status"
+// +kubebuilder:printcolumn:name="METRO",type="string",JSONPath=".status.atProvider.metro"
+// +kubebuilder:printcolumn:name="RECLAIM-POLICY",type="string",JSONPath=".spec.reclaimPolicy"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix}
+type Connection struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ConnectionSpec   `json:"spec"`
+	Status ConnectionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ConnectionList contains a list of Connections
+type ConnectionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Connection `json:"items"`
+}
+
+// ConnectionParameters define the desired state of an Equinix Metal
+// interconnection. The Equinix Metal API does not allow connections to be
+// changed once they are created.
+// https://metal.equinix.com/developers/api/interconnections/
+type ConnectionParameters struct {
+	// ProjectID is the project the Connection is created in. The project of
+	// the ProviderConfig credentials is used when none is specified.
+	// +immutable
+	// +optional
+	ProjectID string `json:"projectId,omitempty"`
+
+	// +optional
+	// +immutable
+	ProjectIDRef *xpv1.Reference `json:"projectIdRef,omitempty"`
+
+	// +optional
+	ProjectIDSelector *xpv1.Selector `json:"projectIdSelector,omitempty"`
+
+	// Name of the Connection
+	// +immutable
+	Name string `json:"name"`
+
+	// +immutable
+	// +optional
+	Description *string `json:"description,omitempty"`
+
+	// Type of the Connection. Dedicated connections use ports reserved for
+	// the project, shared connections use ports shared with other Equinix
+	// Metal customers and are set up with a service token.
+	// +immutable
+	// +kubebuilder:validation:Enum=dedicated;shared
+	Type string `json:"type"`
+
+	// Redundancy of the Connection, either a single primary port or a
+	// redundant pair of ports
+	// +immutable
+	// +kubebuilder:validation:Enum=primary;redundant
+	Redundancy string `json:"redundancy"`
+
+	// Speed of the Connection in bits per second. The API default is used,
+	// and late-initialized, when none is specified.
+	// +immutable
+	// +optional
+	Speed *int `json:"speed,omitempty"`
+
+	// Metro in which to create the Connection. Metro may not be set along
+	// with Facility.
+	// +immutable
+	// +optional
+	Metro string `json:"metro,omitempty"`
+
+	// Facility in which to create the Connection. Facility may not be set
+	// along with Metro.
+	// +immutable
+	// +optional
+	Facility string `json:"facility,omitempty"`
+
+	// +immutable
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+}
+
+// ConnectionObservation is used to reflect in the Kubernetes API, the
+// observed state of the Connection resource from the Equinix Metal API.
+type ConnectionObservation struct {
+	ID     string `json:"id"`
+	Status string `json:"status,omitempty"`
+
+	Metro          string `json:"metro,omitempty"`
+	Facility       string `json:"facility,omitempty"`
+	OrganizationID string `json:"organizationId,omitempty"`
+	Speed          int    `json:"speed,omitempty"`
+
+	// ServiceToken is used to set up a shared Connection in Equinix Fabric
+	// +optional
+	ServiceToken string `json:"serviceToken,omitempty"`
+
+	// Ports of the Connection
+	// +optional
+	Ports []ConnectionPortObservation `json:"ports,omitempty"`
+}
+
+// ConnectionPortObservation is the observed state of a port of a Connection
+type ConnectionPortObservation struct {
+	ID         string `json:"id"`
+	Name       string `json:"name,omitempty"`
+	Role       string `json:"role,omitempty"`
+	Status     string `json:"status,omitempty"`
+	LinkStatus string `json:"linkStatus,omitempty"`
+	Speed      int    `json:"speed,omitempty"`
+}
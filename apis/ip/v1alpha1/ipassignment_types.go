@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IPAssignmentSpec defines the desired state of IPAssignment
+type IPAssignmentSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       IPAssignmentParameters `json:"forProvider"`
+}
+
+// IPAssignmentStatus defines the observed state of IPAssignment
+type IPAssignmentStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          IPAssignmentObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// An IPAssignment is a managed resource that represents the assignment of
+// addresses of an Equinix Metal IP Reservation to a Device
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="ADDRESS",type="string",JSONPath=".spec.forProvider.address"
+// +kubebuilder:printcolumn:name="DEVICE",type="string",JSONPath=".spec.forProvider.deviceId"
+// +kubebuilder:printcolumn:name="RECLAIM-POLICY",type="string",JSONPath=".spec.reclaimPolicy"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix}
+type IPAssignment struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IPAssignmentSpec   `json:"spec"`
+	Status IPAssignmentStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// IPAssignmentList contains a list of IPAssignments
+type IPAssignmentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IPAssignment `json:"items"`
+}
+
+// IPAssignmentParameters define the desired state of an Equinix Metal IP
+// assignment.
+// https://metal.equinix.com/developers/api/ipaddresses/#assign-an-ip-address
+type IPAssignmentParameters struct {
+	// +immutable
+	DeviceID string `json:"deviceId,omitempty"`
+
+	// +optional
+	// +immutable
+	DeviceIDRef *xpv1.Reference `json:"deviceIdRef,omitempty"`
+
+	// +optional
+	DeviceIDSelector *xpv1.Selector `json:"deviceIdSelector,omitempty"`
+
+	// IPReservationID is the reservation the assigned addresses are drawn
+	// from when Address is not set
+	// +optional
+	// +immutable
+	IPReservationID string `json:"ipReservationId,omitempty"`
+
+	// +optional
+	// +immutable
+	IPReservationIDRef *xpv1.Reference `json:"ipReservationIdRef,omitempty"`
+
+	// +optional
+	IPReservationIDSelector *xpv1.Selector `json:"ipReservationIdSelector,omitempty"`
+
+	// Address is the block of addresses assigned to the device in CIDR
+	// notation, such as 147.75.1.2/32. The first available block of the
+	// reservation of size CIDR is assigned when it is not set.
+	// +optional
+	// +immutable
+	Address string `json:"address,omitempty"`
+
+	// CIDR is the size of the block of addresses assigned from the
+	// reservation when Address is not set. Defaults to a single address.
+	// +optional
+	// +immutable
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=128
+	CIDR *int `json:"cidr,omitempty"`
+}
+
+// IPAssignmentObservation is used to reflect in the Kubernetes API, the
+// observed state of the IPAssignment resource from the Equinix Metal API.
+type IPAssignmentObservation struct {
+	ID      string `json:"id"`
+	Href    string `json:"href,omitempty"`
+	Address string `json:"address,omitempty"`
+	Network string `json:"network,omitempty"`
+	Gateway string `json:"gateway,omitempty"`
+	Netmask string `json:"netmask,omitempty"`
+	CIDR    int    `json:"cidr,omitempty"`
+	Public  bool   `json:"public"`
+
+	// AddressFamily is 4 for IPv4 and 6 for IPv6 addresses
+	// +optional
+	AddressFamily int `json:"addressFamily,omitempty"`
+
+	// +optional
+	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Reservation states reported by the Equinix Metal API while a request for
+// addresses awaits approval.
+const (
+	ReservationStateRequested = "requested"
+	ReservationStatePending   = "pending"
+)
+
+// Reservation types. Global reservations are not tied to a facility or metro
+// and their addresses may be assigned to devices in any metro.
+const (
+	ReservationTypePublicIPv4 = "public_ipv4"
+	ReservationTypeGlobalIPv4 = "global_ipv4"
+)
+
+// ReservationSpec defines the desired state of Reservation
+type ReservationSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       ReservationParameters `json:"forProvider"`
+}
+
+// ReservationStatus defines the observed state of Reservation
+type ReservationStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          ReservationObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// Reservation is a managed resource that represents an Equinix Metal IP
+// Reservation
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.atProvider.id"
+// +kubebuilder:printcolumn:name="TYPE",type="string",JSONPath=".spec.forProvider.type"
+// +kubebuilder:printcolumn:name="NETWORK",type="string",JSONPath=".status.atProvider.network"
+// +kubebuilder:printcolumn:name="CIDR",type="integer",JSONPath=".status.atProvider.cidr"
+// +kubebuilder:printcolumn:name="METRO",type="string",JSONPath=".status.atProvider.metro",priority=1
+// +kubebuilder:printcolumn:name="FACILITY",type="string",JSONPath=".status.atProvider.facility",priority=1
+// +kubebuilder:printcolumn:name="RECLAIM-POLICY",type="string",JSONPath=".spec.reclaimPolicy"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix}
+type Reservation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ReservationSpec   `json:"spec"`
+	Status ReservationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ReservationList contains a list of Reservations
+type ReservationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Reservation `json:"items"`
+}
+
+// ReservationParameters define the desired state of an Equinix Metal IP
+// Reservation.
+// https://metal.equinix.com/developers/api/ipaddresses/#requesting-ip-reservations
+//
+// Reference values are used for optional parameters to determine if
+// LateInitialization should update the parameter after creation.
+type ReservationParameters struct {
+	// +immutable
+	// +required
+	// +kubebuilder:validation:Enum=public_ipv4;global_ipv4
+	Type string `json:"type"`
+
+	// Quantity is the number of addresses to reserve. It must be a power of 2.
+	// +immutable
+	// +required
+	// +kubebuilder:validation:Minimum=1
+	Quantity int `json:"quantity"`
+
+	// Facility is required for public_ipv4 reservations unless Metro is
+	// specified. It must not be specified for global_ipv4 reservations.
+	// +immutable
+	// +optional
+	Facility *string `json:"facility,omitempty"`
+
+	// Metro is required for public_ipv4 reservations unless Facility is
+	// specified. It must not be specified for global_ipv4 reservations.
+	// +immutable
+	// +optional
+	Metro *string `json:"metro,omitempty"`
+
+	// ProjectID is the project that the addresses are reserved for. The
+	// ProjectID of the ProviderConfig is used when none is specified.
+	// +immutable
+	// +optional
+	ProjectID *string `json:"projectID,omitempty"`
+
+	// +immutable
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// Details describe the reason for the request and are required when the
+	// reservation needs approval
+	// +immutable
+	// +optional
+	Details *string `json:"details,omitempty"`
+}
+
+// ReservationObservation is used to reflect in the Kubernetes API, the
+// observed state of the Reservation resource from the Equinix Metal API.
+type ReservationObservation struct {
+	ID       string `json:"id"`
+	Href     string `json:"href,omitempty"`
+	State    string `json:"state,omitempty"`
+	Facility string `json:"facility,omitempty"`
+	Metro    string `json:"metro,omitempty"`
+	Network  string `json:"network,omitempty"`
+	Address  string `json:"address,omitempty"`
+	Gateway  string `json:"gateway,omitempty"`
+	Netmask  string `json:"netmask,omitempty"`
+	CIDR     int    `json:"cidr,omitempty"`
+	Public   bool   `json:"public"`
+	Global   bool   `json:"global"`
+
+	// Addresses are the addresses of the reservation that have been assigned
+	// to devices, in CIDR notation
+	// +optional
+	Addresses []string `json:"addresses,omitempty"`
+
+	// AssignedMetros are the metros of the devices that addresses of the
+	// reservation have been assigned to. Addresses of global reservations
+	// may be assigned in several metros.
+	// +optional
+	AssignedMetros []string `json:"assignedMetros,omitempty"`
+
+	// +optional
+	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
+}
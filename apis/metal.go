@@ -20,19 +20,45 @@ package apis
 import (
 	"k8s.io/apimachinery/pkg/runtime"
 
+	batchv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/batch/v1alpha1"
+	bgpv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/bgp/v1alpha1"
+	bgpconfigv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/bgpconfig/v1alpha1"
+	capacityv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/capacity/v1alpha1"
+	connectionv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/connection/v1alpha1"
+	ipv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/ip/v1alpha1"
+	metalgatewayv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/metalgateway/v1alpha1"
+	organizationv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/organization/v1alpha1"
 	portsv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/ports/v1alpha1"
+	projectv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/project/v1alpha1"
 	serverv1alpha2 "github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	spotmarketv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/spotmarket/v1alpha1"
+	sshkeyv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/sshkey/v1alpha1"
 	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
 	vlanv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/vlan/v1alpha1"
+	volumev1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/volume/v1alpha1"
+	vrfv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/vrf/v1alpha1"
 )
 
 func init() {
 	// Register the types with the Scheme so the components can map objects to GroupVersionKinds and back
 	AddToSchemes = append(AddToSchemes,
 		packetv1beta1.SchemeBuilder.AddToScheme,
+		batchv1alpha1.SchemeBuilder.AddToScheme,
+		bgpv1alpha1.SchemeBuilder.AddToScheme,
+		bgpconfigv1alpha1.SchemeBuilder.AddToScheme,
+		capacityv1alpha1.SchemeBuilder.AddToScheme,
+		connectionv1alpha1.SchemeBuilder.AddToScheme,
+		ipv1alpha1.SchemeBuilder.AddToScheme,
+		metalgatewayv1alpha1.SchemeBuilder.AddToScheme,
+		organizationv1alpha1.SchemeBuilder.AddToScheme,
 		portsv1alpha1.SchemeBuilder.AddToScheme,
+		projectv1alpha1.SchemeBuilder.AddToScheme,
 		serverv1alpha2.SchemeBuilder.AddToScheme,
+		spotmarketv1alpha1.SchemeBuilder.AddToScheme,
+		sshkeyv1alpha1.SchemeBuilder.AddToScheme,
 		vlanv1alpha1.SchemeBuilder.AddToScheme,
+		volumev1alpha1.SchemeBuilder.AddToScheme,
+		vrfv1alpha1.SchemeBuilder.AddToScheme,
 	)
 }
 
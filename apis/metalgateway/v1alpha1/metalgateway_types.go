@@ -0,0 +1,147 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MetalGateway states reported by the Equinix Metal API.
+const (
+	StateReady    = "ready"
+	StateActive   = "active"
+	StateDeleting = "deleting"
+)
+
+// MetalGatewaySpec defines the desired state of MetalGateway
+type MetalGatewaySpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       MetalGatewayParameters `json:"forProvider"`
+}
+
+// MetalGatewayStatus defines the observed state of MetalGateway
+type MetalGatewayStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          MetalGatewayObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MetalGateway is a managed resource that represents an Equinix Metal
+// gateway routing between a VirtualNetwork and an IP reservation
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.atProvider.id"
+// +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.atProvider.state"
+// +kubebuilder:printcolumn:name="ADDRESS",type="string",JSONPath=".status.atProvider.address"
+// +kubebuilder:printcolumn:name="RECLAIM-POLICY",type="string",JSONPath=".spec.reclaimPolicy"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix}
+type MetalGateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MetalGatewaySpec   `json:"spec"`
+	Status MetalGatewayStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MetalGatewayList contains a list of MetalGateways
+type MetalGatewayList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MetalGateway `json:"items"`
+}
+
+// MetalGatewayParameters define the desired state of an Equinix Metal
+// gateway. The gateway routes a VirtualNetwork through either an existing IP
+// reservation or a private IPv4 subnet that is reserved for it. The Equinix
+// Metal API does not allow gateways to be changed once they are created.
+// https://metal.equinix.com/developers/api/metalgateways/
+type MetalGatewayParameters struct {
+	// ProjectID is the project the MetalGateway is created in. The project of
+	// the ProviderConfig credentials is used when none is specified.
+	// +immutable
+	// +optional
+	ProjectID string `json:"projectId,omitempty"`
+
+	// +optional
+	// +immutable
+	ProjectIDRef *xpv1.Reference `json:"projectIdRef,omitempty"`
+
+	// +optional
+	ProjectIDSelector *xpv1.Selector `json:"projectIdSelector,omitempty"`
+
+	// VirtualNetworkID is the VirtualNetwork routed by the MetalGateway
+	// +immutable
+	// +optional
+	VirtualNetworkID string `json:"virtualNetworkId,omitempty"`
+
+	// +optional
+	// +immutable
+	VirtualNetworkIDRef *xpv1.Reference `json:"virtualNetworkIdRef,omitempty"`
+
+	// +optional
+	VirtualNetworkIDSelector *xpv1.Selector `json:"virtualNetworkIdSelector,omitempty"`
+
+	// IPReservationID is the IP reservation the MetalGateway routes to. It
+	// may not be set along with PrivateIPv4SubnetSize.
+	// +immutable
+	// +optional
+	IPReservationID string `json:"ipReservationId,omitempty"`
+
+	// +optional
+	// +immutable
+	IPReservationIDRef *xpv1.Reference `json:"ipReservationIdRef,omitempty"`
+
+	// +optional
+	IPReservationIDSelector *xpv1.Selector `json:"ipReservationIdSelector,omitempty"`
+
+	// PrivateIPv4SubnetSize is the number of addresses in the private IPv4
+	// subnet reserved for the MetalGateway when no IPReservationID is set
+	// +immutable
+	// +optional
+	// +kubebuilder:validation:Enum=8;16;32;64;128
+	PrivateIPv4SubnetSize *int `json:"privateIpv4SubnetSize,omitempty"`
+}
+
+// MetalGatewayObservation is used to reflect in the Kubernetes API, the
+// observed state of the MetalGateway resource from the Equinix Metal API.
+type MetalGatewayObservation struct {
+	ID    string `json:"id"`
+	Href  string `json:"href,omitempty"`
+	State string `json:"state,omitempty"`
+
+	// IPReservationID is the IP reservation routed to, including the private
+	// subnet reserved for the MetalGateway
+	// +optional
+	IPReservationID string `json:"ipReservationId,omitempty"`
+
+	// Address is the IP address of the MetalGateway
+	// +optional
+	Address string `json:"address,omitempty"`
+
+	// Subnet is the subnet routed to, in CIDR notation
+	// +optional
+	Subnet string `json:"subnet,omitempty"`
+
+	// +optional
+	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
+}
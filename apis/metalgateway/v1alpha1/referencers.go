@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reference"
+
+	ipv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/ip/v1alpha1"
+	projectv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/project/v1alpha1"
+	vlanv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/vlan/v1alpha1"
+)
+
+// ResolveReferences of this MetalGateway
+func (mg *MetalGateway) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	// Resolve spec.forProvider.projectId
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ProjectID,
+		Reference:    mg.Spec.ForProvider.ProjectIDRef,
+		Selector:     mg.Spec.ForProvider.ProjectIDSelector,
+		To:           reference.To{Managed: &projectv1alpha1.Project{}, List: &projectv1alpha1.ProjectList{}},
+		Extract:      reference.ExternalName(),
+	})
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.ProjectID = rsp.ResolvedValue
+	mg.Spec.ForProvider.ProjectIDRef = rsp.ResolvedReference
+
+	// Resolve spec.forProvider.virtualNetworkId
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.VirtualNetworkID,
+		Reference:    mg.Spec.ForProvider.VirtualNetworkIDRef,
+		Selector:     mg.Spec.ForProvider.VirtualNetworkIDSelector,
+		To:           reference.To{Managed: &vlanv1alpha1.VirtualNetwork{}, List: &vlanv1alpha1.VirtualNetworkList{}},
+		Extract:      vlanv1alpha1.VirtualNetworkID(),
+	})
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.VirtualNetworkID = rsp.ResolvedValue
+	mg.Spec.ForProvider.VirtualNetworkIDRef = rsp.ResolvedReference
+
+	// Resolve spec.forProvider.ipReservationId
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.IPReservationID,
+		Reference:    mg.Spec.ForProvider.IPReservationIDRef,
+		Selector:     mg.Spec.ForProvider.IPReservationIDSelector,
+		To:           reference.To{Managed: &ipv1alpha1.Reservation{}, List: &ipv1alpha1.ReservationList{}},
+		Extract:      ipv1alpha1.ReservationID(),
+	})
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.IPReservationID = rsp.ResolvedValue
+	mg.Spec.ForProvider.IPReservationIDRef = rsp.ResolvedReference
+
+	return nil
+}
@@ -87,4 +87,9 @@ type AssignmentParameters struct {
 
 	// +optional
 	VirtualNetworkIDSelector *xpv1.Selector `json:"virtualNetworkIdSelector,omitempty"`
+
+	// Native assigns the VirtualNetwork as the native VLAN of the port, for
+	// untagged traffic. A port has at most one native VLAN.
+	// +optional
+	Native *bool `json:"native,omitempty"`
 }
@@ -107,6 +107,11 @@ func (in *AssignmentParameters) DeepCopyInto(out *AssignmentParameters) {
 		*out = new(v1.Selector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Native != nil {
+		in, out := &in.Native, &out.Native
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AssignmentParameters.
@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProjectSpec defines the desired state of Project
+type ProjectSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       ProjectParameters `json:"forProvider"`
+}
+
+// ProjectStatus defines the observed state of Project
+type ProjectStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          ProjectObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// Project is a managed resource that represents an Equinix Metal Project
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.atProvider.id"
+// +kubebuilder:printcolumn:name="NAME",type="string",JSONPath=".spec.forProvider.name"
+// +kubebuilder:printcolumn:name="RECLAIM-POLICY",type="string",JSONPath=".spec.reclaimPolicy"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix}
+type Project struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProjectSpec   `json:"spec"`
+	Status ProjectStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ProjectList contains a list of Projects
+type ProjectList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Project `json:"items"`
+}
+
+// ProjectParameters define the desired state of an Equinix Metal Project.
+// https://metal.equinix.com/developers/api/projects/#create-a-project
+//
+// Reference values are used for optional parameters to determine if
+// LateInitialization should update the parameter after creation.
+type ProjectParameters struct {
+	// +required
+	Name string `json:"name"`
+
+	// OrganizationID is the organization that owns the project. The default
+	// organization of the user will be used when none is specified.
+	// +immutable
+	// +optional
+	OrganizationID *string `json:"organizationID,omitempty"`
+
+	// +optional
+	// +immutable
+	OrganizationIDRef *xpv1.Reference `json:"organizationIDRef,omitempty"`
+
+	// +optional
+	OrganizationIDSelector *xpv1.Selector `json:"organizationIDSelector,omitempty"`
+
+	// +optional
+	PaymentMethodID *string `json:"paymentMethodID,omitempty"`
+
+	// +optional
+	BackendTransferEnabled *bool `json:"backendTransferEnabled,omitempty"`
+}
+
+// ProjectObservation is used to reflect in the Kubernetes API, the observed
+// state of the Project resource from the Equinix Metal API.
+type ProjectObservation struct {
+	ID string `json:"id"`
+
+	// +optional
+	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
+
+	// +optional
+	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
+}
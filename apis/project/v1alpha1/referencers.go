@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reference"
+	resource "github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	organizationv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/organization/v1alpha1"
+)
+
+// ProjectID extracts the ID of a Project.
+func ProjectID() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		c, ok := mg.(*Project)
+		if !ok {
+			return ""
+		}
+		return c.Status.AtProvider.ID
+	}
+}
+
+// ResolveReferences of this Project
+func (mg *Project) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	// Resolve spec.forProvider.organizationID
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: reference.FromPtrValue(mg.Spec.ForProvider.OrganizationID),
+		Reference:    mg.Spec.ForProvider.OrganizationIDRef,
+		Selector:     mg.Spec.ForProvider.OrganizationIDSelector,
+		To:           reference.To{Managed: &organizationv1alpha1.Organization{}, List: &organizationv1alpha1.OrganizationList{}},
+		Extract:      organizationv1alpha1.OrganizationID(),
+	})
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.OrganizationID = reference.ToPtrValue(rsp.ResolvedValue)
+	mg.Spec.ForProvider.OrganizationIDRef = rsp.ResolvedReference
+
+	return nil
+}
@@ -51,12 +51,96 @@ const (
 	StateQueued = "queued"
 )
 
+const (
+	// PowerStateOn requests that the device is powered on
+	PowerStateOn = "on"
+
+	// PowerStateOff requests that the device is powered off
+	PowerStateOff = "off"
+)
+
+// Actions the provider performs on a device
+const (
+	// DeviceActionReboot reboots the device
+	DeviceActionReboot = "reboot"
+
+	// DeviceActionReinstall reinstalls the operating system of the device
+	DeviceActionReinstall = "reinstall"
+
+	// DeviceActionPowerOn powers the device on
+	DeviceActionPowerOn = "power-on"
+
+	// DeviceActionPowerOff powers the device off
+	DeviceActionPowerOff = "power-off"
+
+	// DeviceActionLock locks the device
+	DeviceActionLock = "lock"
+
+	// DeviceActionUnlock unlocks the device
+	DeviceActionUnlock = "unlock"
+)
+
+// OSCustomIPXE is the operating system that boots a Device from the script at
+// IPXEScriptURL
+const OSCustomIPXE = "custom_ipxe"
+
+// Formats of userdata combined from several references
+const (
+	// UserDataFormatConcat joins the referenced userdata with a separator
+	UserDataFormatConcat = "concat"
+
+	// UserDataFormatMIME combines the referenced userdata into a cloud-init
+	// multipart MIME archive
+	UserDataFormatMIME = "mime"
+)
+
+// Encodings of userdata and customdata
+const (
+	// DataEncodingPlain is data used as it is
+	DataEncodingPlain = "plain"
+
+	// DataEncodingBase64 is base64 encoded data, decoded before it is sent
+	// to the API
+	DataEncodingBase64 = "base64"
+)
+
+// FacilityAny requests that Equinix Metal selects the facility of a Device
+const FacilityAny = "any"
+
+// Hardware features that can be requested for a Device
+const (
+	// FeatureTPM is a Trusted Platform Module
+	FeatureTPM = "tpm"
+
+	// FeatureRAID is a hardware RAID controller
+	FeatureRAID = "raid"
+
+	// FeatureTXT is Intel Trusted Execution Technology
+	FeatureTXT = "txt"
+)
+
+// Requirements of requested hardware features
+const (
+	// FeatureRequired fails the creation of a Device without the feature
+	FeatureRequired = "required"
+
+	// FeaturePreferred prefers, but does not require, a Device with the
+	// feature
+	FeaturePreferred = "preferred"
+)
+
 // TODO: make optional parameters pointers and add +optional
 
 // DeviceSpec defines the desired state of Device
 type DeviceSpec struct {
 	xpv1.ResourceSpec `json:",inline"`
 	ForProvider       DeviceParameters `json:"forProvider"`
+
+	// APIKeySecretRef references a Secret key holding an Equinix Metal API
+	// key, such as a project API key, that is used for this Device instead of
+	// the API key of the ProviderConfig
+	// +optional
+	APIKeySecretRef *xpv1.SecretKeySelector `json:"apiKeySecretRef,omitempty"`
 }
 
 // DeviceStatus defines the observed state of Device
@@ -72,7 +156,7 @@ type DeviceStatus struct {
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.atProvider.state"
 // +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.atProvider.id"
-// +kubebuilder:printcolumn:name="HOSTNAME",type="string",JSONPath=".spec.forProvider.hostname"
+// +kubebuilder:printcolumn:name="HOSTNAME",type="string",JSONPath=".status.atProvider.hostname"
 // +kubebuilder:printcolumn:name="METRO",type="string",JSONPath=".status.atProvider.metro"
 // +kubebuilder:printcolumn:name="FACILITY",type="string",JSONPath=".status.atProvider.facility",priority=1
 // +kubebuilder:printcolumn:name="IPV4",type="string",JSONPath=".status.atProvider.ipv4"
@@ -88,6 +172,12 @@ type Device struct {
 	Status DeviceStatus `json:"status,omitempty"`
 }
 
+// GetAPIKeySecretRef returns the Secret key holding the API key used for the
+// Device, if any
+func (d *Device) GetAPIKeySecretRef() *xpv1.SecretKeySelector {
+	return d.Spec.APIKeySecretRef
+}
+
 // +kubebuilder:object:root=true
 
 // DeviceList contains a list of Devices
@@ -100,10 +190,39 @@ type DeviceList struct {
 // IPAddress is a packngo.IPAddressCreateRequest used for managing IP addresses
 // at Device, at creation and observer time.
 type IPAddress struct {
-	AddressFamily int      `json:"address_family"`
-	Public        bool     `json:"public"`
-	CIDR          int      `json:"cidr,omitempty"`
-	Reservations  []string `json:"ip_reservations,omitempty"`
+	// AddressFamily is 4 for an IPv4 address or 6 for an IPv6 address
+	// +kubebuilder:validation:Enum=4;6
+	AddressFamily int `json:"address_family"`
+
+	// Public addresses are reachable from the internet. IPv6 addresses must
+	// be public.
+	Public bool `json:"public"`
+
+	// CIDR is the size of the subnet assigned to the device, such as 29 for
+	// a /29 of 8 addresses. Public IPv4 subnets may be /28 to /31, private
+	// IPv4 subnets /25 to /30 and IPv6 subnets /64 to /127. Defaults to the
+	// size the API assigns.
+	// +optional
+	CIDR int `json:"cidr,omitempty"`
+
+	// Reservations are the IDs of the IP reservations the addresses are
+	// drawn from
+	// +optional
+	Reservations []string `json:"ip_reservations,omitempty"`
+}
+
+// DeviceIPAddress is an IP address assigned to a Device
+type DeviceIPAddress struct {
+	Address       string `json:"address"`
+	AddressFamily int    `json:"addressFamily"`
+	Public        bool   `json:"public"`
+
+	// CIDR is the size of the subnet the address was assigned from
+	CIDR int `json:"cidr"`
+
+	// Management addresses are the primary addresses of the device
+	// +optional
+	Management bool `json:"management,omitempty"`
 }
 
 // NamespacedName represents a namespaced object name
@@ -119,6 +238,56 @@ type DataKeySelector struct {
 	Kind     string `json:"kind"`
 	Key      string `json:"key,omitempty"`
 	Optional bool   `json:"optional,omitempty"`
+
+	// Encoding of the referenced data, which is decoded before it is used.
+	// Defaults to plain.
+	// +optional
+	// +kubebuilder:validation:Enum=plain;base64
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// ReinstallOptions control how a Device is reinstalled when its userdata has
+// changed.
+type ReinstallOptions struct {
+	// PreserveData preserves the contents of non-OS disks during the reinstall
+	// +optional
+	PreserveData bool `json:"preserveData,omitempty"`
+
+	// DeprovisionFast skips the disk wipe that is otherwise performed during
+	// the reinstall
+	// +optional
+	DeprovisionFast bool `json:"deprovisionFast,omitempty"`
+}
+
+// Actions of a FailurePolicy
+const (
+	// FailureActionReport reports a persistently failed device with an
+	// Unavailable condition and an event
+	FailureActionReport = "Report"
+
+	// FailureActionReinstall reinstalls a persistently failed device
+	FailureActionReinstall = "Reinstall"
+)
+
+// DefaultFailureThreshold is the number of consecutive failed observations
+// after which a FailurePolicy acts when no threshold is set
+const DefaultFailureThreshold = 3
+
+// FailurePolicy controls how a device that remains in the failed state is
+// handled.
+type FailurePolicy struct {
+	// Threshold is the number of consecutive observations of the device in
+	// the failed state after which the Action is taken. Defaults to 3.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Threshold *int `json:"threshold,omitempty"`
+
+	// Action is taken once the device has failed Threshold observations in a
+	// row. Report, the default, reports the device as persistently failed.
+	// Reinstall reinstalls the device, using the ReinstallOptions.
+	// +optional
+	// +kubebuilder:validation:Enum=Report;Reinstall
+	Action string `json:"action,omitempty"`
 }
 
 // DeviceParameters define the desired state of an Equinix Metal device.
@@ -127,20 +296,47 @@ type DataKeySelector struct {
 // Reference values are used for optional parameters to determine if
 // LateInitialization should update the parameter after creation.
 type DeviceParameters struct {
+	// ProjectID is the project the device is deployed in. The project of the
+	// ProviderConfig credentials is used when none is specified.
+	// +immutable
+	// +optional
+	ProjectID string `json:"projectId,omitempty"`
+
+	// +optional
+	// +immutable
+	ProjectIDRef *xpv1.Reference `json:"projectIdRef,omitempty"`
+
+	// +optional
+	ProjectIDSelector *xpv1.Selector `json:"projectIdSelector,omitempty"`
+
 	// +immutable
 	// +required
 	Plan string `json:"plan"`
 
+	// Facility is where the device is deployed. Facility and Metro can not
+	// both be set, the defaults of the ProviderConfig credentials are used
+	// when neither is set.
 	// +immutable
 	Facility string `json:"facility,omitempty"`
 
+	// Metro is where the device is deployed, as an alternative to Facility.
 	// +immutable
 	Metro string `json:"metro,omitempty"`
 
+	// Facilities are the facilities the device may be deployed in, as an
+	// alternative to Facility and Metro. The device is deployed in the first
+	// of the listed facilities with capacity. Listing the facilities in a
+	// different order for each device spreads devices across facilities, a
+	// DeviceBatch can limit the devices deployed in each facility.
+	// +immutable
+	// +optional
+	Facilities []string `json:"facilities,omitempty"`
+
 	// +immutable
 	// +required
 	OS string `json:"operatingSystem"`
 
+	// Hostname of the device. Defaults to the name of the managed resource.
 	// +optional
 	Hostname *string `json:"hostname,omitempty"`
 
@@ -153,15 +349,77 @@ type DeviceParameters struct {
 	// +optional
 	UserData *string `json:"userdata,omitempty"`
 
+	// UserDataEncoding is the encoding of UserData, which is decoded before
+	// it is sent to the API. Defaults to plain.
+	// +optional
+	// +kubebuilder:validation:Enum=plain;base64
+	UserDataEncoding *string `json:"userdataEncoding,omitempty"`
+
 	// +optional
 	UserDataRef *DataKeySelector `json:"userdataRef,omitempty"`
 
+	// UserDataRefs reference ConfigMap or Secret keys holding parts of the
+	// userdata. The parts are combined in order, following any UserDataRef.
+	// Optional references that can not be resolved are skipped.
+	// +optional
+	UserDataRefs []DataKeySelector `json:"userdataRefs,omitempty"`
+
+	// UserDataFormat is how userdata from several references is combined.
+	// Parts are concatenated with the UserDataSeparator by default.
+	// +optional
+	// +kubebuilder:validation:Enum=concat;mime
+	UserDataFormat *string `json:"userdataFormat,omitempty"`
+
+	// UserDataSeparator is placed between concatenated userdata parts.
+	// Defaults to a newline.
+	// +optional
+	UserDataSeparator *string `json:"userdataSeparator,omitempty"`
+
+	// ReinstallOptions are used when a change to the userdata requires the
+	// device to be reinstalled.
+	// +optional
+	ReinstallOptions *ReinstallOptions `json:"reinstallOptions,omitempty"`
+
+	// RebootToken reboots the device once whenever it is changed to a new,
+	// non-empty value, such as a timestamp or rollout ID. The token of the
+	// last reboot is recorded in status.atProvider.lastRebootToken. Devices
+	// are not rebooted for the token they were created with.
+	// +optional
+	RebootToken *string `json:"rebootToken,omitempty"`
+
+	// FailurePolicy is the action taken once the device has been observed in
+	// the failed state several times in a row. Failed devices are only
+	// reported when no policy is set.
+	// +optional
+	FailurePolicy *FailurePolicy `json:"failurePolicy,omitempty"`
+
+	// Tags of the Device. Devices are also created with the tag
+	// crossplane-uid=<uid of the Device resource>, which finds a Device that
+	// was created but not recorded so that it is not created twice. That tag
+	// is not compared with Tags.
 	// +optional
 	Tags []string `json:"tags,omitempty"`
 
+	// Locked devices can not be deleted through the Equinix Metal API. A
+	// locked Device is unlocked before it is deleted by its managed resource.
 	// +optional
 	Locked *bool `json:"locked,omitempty"`
 
+	// ForceDelete deprovisions the Device when it is deleted even if it is
+	// stuck, skipping the deprovisioning steps that could not complete.
+	// Devices are not force deleted by default.
+	// +optional
+	ForceDelete *bool `json:"forceDelete,omitempty"`
+
+	// HardwareReservationReleaseProjectID is the ID of the project that the
+	// Hardware Reservation of the Device is moved to once the Device has been
+	// deleted. Hardware Reservations are kept by the project of the Device
+	// by default.
+	// +optional
+	HardwareReservationReleaseProjectID *string `json:"hardwareReservationReleaseProjectID,omitempty"`
+
+	// IPXEScriptURL is the URL of the iPXE script used to boot the Device. It
+	// may only be set when the operating system is custom_ipxe.
 	// +optional
 	IPXEScriptURL *string `json:"ipxeScriptUrl,omitempty"`
 
@@ -169,33 +427,117 @@ type DeviceParameters struct {
 	// +optional
 	PublicIPv4SubnetSize *int `json:"publicIPv4SubnetSize,omitempty"`
 
+	// AlwaysPXE boots the Device from the iPXE script on every boot rather
+	// than only when it is provisioned
 	// +optional
 	AlwaysPXE *bool `json:"alwaysPXE,omitempty"`
 
+	// PowerState is the desired power state of the device. The power state
+	// is not managed when none is specified.
+	// +optional
+	// +kubebuilder:validation:Enum=on;off
+	PowerState *string `json:"powerState,omitempty"`
+
+	// HardwareReservationID provisions the device into a reserved piece of
+	// hardware. Use "next-available" to select any available reservation for
+	// the plan. The plan of a specific reservation must match Plan.
 	// +immutable
 	// +optional
 	HardwareReservationID *string `json:"hardwareReservationID,omitempty"`
 
+	// SpotInstance provisions the device from the spot market
+	// +immutable
+	// +optional
+	SpotInstance *bool `json:"spotInstance,omitempty"`
+
+	// SpotPriceMax is the maximum price per hour, in US dollars, bid for a
+	// spot instance. The Equinix Metal API does not support changing the bid
+	// of a spot instance, so a device bidding another price is reported as
+	// invalid.
+	// +immutable
+	// +optional
+	SpotPriceMax *resource.Quantity `json:"spotPriceMax,omitempty"`
+
+	// SpotTerminationProtection would protect a spot instance from being
+	// terminated by the spot market. The Equinix Metal API does not support
+	// it, so a device requesting protection is reported as invalid rather
+	// than left unprotected. Spot instances are terminated when the spot
+	// price exceeds SpotPriceMax.
+	// +optional
+	SpotTerminationProtection *bool `json:"spotTerminationProtection,omitempty"`
+
+	// TerminationTime is when the device is automatically terminated. It
+	// must be in the future when the device is created.
+	// +immutable
+	// +optional
+	TerminationTime *metav1.Time `json:"terminationTime,omitempty"`
+
+	// Storage is a JSON document describing the disks, RAID arrays and
+	// filesystems of the device, applied when the device is provisioned.
+	// https://metal.equinix.com/developers/docs/storage/custom-partitioning-raid/
+	// +immutable
+	// +optional
+	Storage *string `json:"storage,omitempty"`
+
+	// CustomData is a JSON document made available to the device through the
+	// metadata service. It can only be provided when the device is created.
+	// +immutable
 	// +optional
 	CustomData *string `json:"customData,omitempty"`
 
+	// CustomDataRef references a ConfigMap or Secret key holding CustomData.
+	// The "customdata" key is used when no key is specified.
+	// +immutable
+	// +optional
+	CustomDataRef *DataKeySelector `json:"customDataRef,omitempty"`
+
+	// UserSSHKeys is a list of user UUIDs. The SSH keys of these users are
+	// added to the device along with any ProjectSSHKeys.
 	// +immutable
 	// +optional
 	UserSSHKeys []string `json:"userSSHKeys,omitempty"`
 
+	// ProjectSSHKeys is a list of SSH key UUIDs. When set, only the listed
+	// keys and the keys of UserSSHKeys are added to the device.
 	// +immutable
 	// +optional
 	ProjectSSHKeys []string `json:"projectSSHKeys,omitempty"`
 
+	// ProjectSSHKeyRefs references SSHKeys to add to ProjectSSHKeys
+	// +immutable
+	// +optional
+	ProjectSSHKeyRefs []xpv1.Reference `json:"projectSSHKeyRefs,omitempty"`
+
+	// ProjectSSHKeySelector selects SSHKeys to add to ProjectSSHKeys
+	// +immutable
+	// +optional
+	ProjectSSHKeySelector *xpv1.Selector `json:"projectSSHKeySelector,omitempty"`
+
+	// NetworkType is the port bonding and layer 2 or layer 3 mode of the
+	// device. The device is provisioned in the default mode of its plan and
+	// converted to NetworkType once it is no longer being provisioned. Devices
+	// of some legacy plans can not be converted.
 	// +optional
 	// +kubebuilder:validation:Enum="hybrid";"layer2-individual";"layer2-bonded";"layer3"
 	NetworkType *string `json:"networkType,omitempty"`
 
-	// Features can be used to require or prefer devices with optional features:
+	// VLANs are the IDs of the VLANs attached to the device once it has been
+	// converted to NetworkType, which must be a layer 2 or hybrid network
+	// type. The Equinix Metal API does not accept network types or VLANs when
+	// a device is created, so they are configured after provisioning. VLANs
+	// are attached to port bond0 of layer2-bonded devices, and to port eth1
+	// of hybrid and layer2-individual devices. VLANs attached to the port by
+	// other means are not detached.
+	// +optional
+	VLANs []string `json:"vlans,omitempty"`
+
+	// Features can be used to require or prefer devices with optional
+	// hardware features. The features tpm, raid and txt may each be required
+	// or preferred:
 	//
 	// features:
-	// - tpm: required
-	// - tpm: preferred
+	//   tpm: required
+	//   raid: preferred
 	// +immutable
 	// +optional
 	Features map[string]string `json:"features,omitempty"`
@@ -206,6 +548,58 @@ type DeviceParameters struct {
 	// +immutable
 	// +optional
 	IPAddresses []IPAddress `json:"ipAddresses,omitempty"`
+
+	// IPReservationRef references an IP Reservation that the public IPv4
+	// address of the Device is drawn from when it is created. Creation is
+	// retried while the reservation is not ready or has no unassigned
+	// addresses.
+	// +immutable
+	// +optional
+	IPReservationRef *xpv1.Reference `json:"ipReservationRef,omitempty"`
+
+	// IPReservationSelector selects the IP Reservation of IPReservationRef
+	// +immutable
+	// +optional
+	IPReservationSelector *xpv1.Selector `json:"ipReservationSelector,omitempty"`
+
+	// NoPublicIP provisions the Device without public IPv4 or IPv6 addresses,
+	// with only a private IPv4 address. It can not be set with
+	// publicIPv4SubnetSize or with public ipAddresses. Defaults to false.
+	// +immutable
+	// +optional
+	NoPublicIP *bool `json:"noPublicIP,omitempty"`
+}
+
+// DeviceNetworkAttachment is a VLAN attached to a port of a device, and the
+// VRFs and interconnections it connects the device to
+type DeviceNetworkAttachment struct {
+	// Port is the name of the port the VLAN is attached to, such as bond0
+	Port string `json:"port"`
+
+	// VLANID is the ID of the VLAN
+	VLANID string `json:"vlanID"`
+
+	// +optional
+	VXLAN int `json:"vxlan,omitempty"`
+
+	// VRFs are the IDs of the VRFs that Metal Gateways of the VLAN route to
+	// +optional
+	VRFs []string `json:"vrfs,omitempty"`
+
+	// Interconnection is true when the VLAN is assigned to a virtual circuit
+	// of an interconnection
+	// +optional
+	Interconnection bool `json:"interconnection,omitempty"`
+}
+
+// DeviceAction is an action the provider performed on a device
+type DeviceAction struct {
+	// Type is the action performed, one of reboot, reinstall, power-on,
+	// power-off, lock or unlock
+	Type string `json:"type"`
+
+	// Time is when the provider requested the action
+	Time metav1.Time `json:"time"`
 }
 
 // DeviceObservation is used to reflect in the Kubernetes API, the observed
@@ -214,35 +608,133 @@ type DeviceObservation struct {
 	ID   string `json:"id"`
 	Href string `json:"href,omitempty"`
 
-	// Facility is where the device is deployed. This field may differ from
-	// spec.forProvider.facility when the "any" value was used.
+	// Facility is where the device is currently deployed. This field may
+	// differ from spec.forProvider.facility when the "any" value was used or
+	// when the device was migrated to another facility.
 	Facility            string            `json:"facility"`
 	Metro               string            `json:"metro,omitempty"`
 	State               string            `json:"state,omitempty"`
+	Hostname            string            `json:"hostname,omitempty"`
 	ProvisionPercentage resource.Quantity `json:"provisionPercentage,omitempty"`
 	IPv4                string            `json:"ipv4,omitempty"`
 	Locked              bool              `json:"locked"`
 
+	// PrivateIPv4 is the private IPv4 address of the device
+	// +optional
+	PrivateIPv4 string `json:"privateIPv4,omitempty"`
+
+	// PublicIP is true when the device has a public IPv4 or IPv6 address,
+	// false for devices provisioned with noPublicIP
+	PublicIP bool `json:"publicIP"`
+
+	// IPAddresses are the addresses assigned to the device, with the subnet
+	// sizes that were assigned
+	// +optional
+	IPAddresses []DeviceIPAddress `json:"ipAddresses,omitempty"`
+
+	// NetworkAttachments are the VLANs attached to the ports of the device,
+	// with the VRFs and interconnections they connect it to. Devices without
+	// attached VLANs have none.
+	// +optional
+	NetworkAttachments []DeviceNetworkAttachment `json:"networkAttachments,omitempty"`
+
+	// PrivateNetworks are the private IPv4 network blocks of the device, in
+	// CIDR notation
+	// +optional
+	PrivateNetworks []string `json:"privateNetworks,omitempty"`
+
+	// IQN is the iSCSI qualified name of the device, which identifies it as
+	// the initiator of iSCSI storage
+	// +optional
+	IQN string `json:"iqn,omitempty"`
+
+	// Volumes are the IDs of the storage volumes attached to the device
+	// +optional
+	Volumes []string `json:"volumes,omitempty"`
+
+	// HardwareReservationID is the reservation the device was provisioned
+	// into, including the reservation selected for "next-available".
+	// +optional
+	HardwareReservationID string `json:"hardwareReservationID,omitempty"`
+
+	// CustomDataSet is true when the device was provisioned with customdata
+	// +optional
+	CustomDataSet bool `json:"customDataSet,omitempty"`
+
+	// StorageSet is true when the device was provisioned with a custom
+	// storage layout
+	// +optional
+	StorageSet bool `json:"storageSet,omitempty"`
+
+	// SSHKeys are the IDs of the SSH keys added to the device
+	// +optional
+	SSHKeys []string `json:"sshKeys,omitempty"`
+
+	// ProjectSSHKeys are the IDs of the SSH keys added to the device that
+	// are owned by a project
+	// +optional
+	ProjectSSHKeys []string `json:"projectSSHKeys,omitempty"`
+
+	// UserSSHKeys are the IDs of the SSH keys added to the device that are
+	// owned by a user
+	// +optional
+	UserSSHKeys []string `json:"userSSHKeys,omitempty"`
+
+	// SpotInstance is true when the device was provisioned from the spot
+	// market
+	// +optional
+	SpotInstance bool `json:"spotInstance,omitempty"`
+
+	// SpotPriceMax is the maximum price per hour, in US dollars, bid for the
+	// spot instance
+	// +optional
+	SpotPriceMax *resource.Quantity `json:"spotPriceMax,omitempty"`
+
+	// TerminationTime is when the spot market terminates the spot instance
+	// +optional
+	TerminationTime *metav1.Time `json:"terminationTime,omitempty"`
+
+	// Features are the optional hardware features of the plan of the device,
+	// such as raid and txt
+	// +optional
+	Features []string `json:"features,omitempty"`
+
+	// LastRebootToken is the RebootToken the device was last rebooted, or
+	// created, with
+	// +optional
+	LastRebootToken string `json:"lastRebootToken,omitempty"`
+
+	// LastAction is the action the provider last performed on the device,
+	// such as a reboot or a reinstall. The Equinix Metal API does not report
+	// actions, so only those performed by the provider are recorded.
+	// +optional
+	LastAction *DeviceAction `json:"lastAction,omitempty"`
+
+	// DeletionStartedAt is when the provider requested the deletion of the
+	// device. Devices that remain deprovisioning for long after it are
+	// reported.
+	// +optional
+	DeletionStartedAt *metav1.Time `json:"deletionStartedAt,omitempty"`
+
+	// FailedObservations is the number of consecutive observations of the
+	// device in the failed state
+	// +optional
+	FailedObservations int `json:"failedObservations,omitempty"`
+
 	// +optional
 	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
 
 	// +optional
 	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
 
-	// IQN string is omitted
 	// ImageURL *string is omitted
-	// Hostname string is omitted (represented in ForProvider)
 	// Tags []string is omitted (represented in ForProvider)
 	// BillingCycle string is omitted (represented in ForProvider)
-	// HardwareReservation map is omitted (represented in ForProvider by HardwareReservationID)
-	// IPAddresses []map is omitted
 	// NetworkPorts []map is omitted
 	// OperatingSystem map is omitted
 	// Plan map is omitted (represented in ForProvider by Plan)
-	// Project map is omitted (represented through ProviderReference)
+	// Project map is omitted (represented in ForProvider by ProjectID)
 	// ShortID string is omitted
-	// SSHKeys []map is omitted
-	// Volumes []map is omitted
 
 	// User string is omitted (written to Credentials)
 	// RootPassword string is omitted (written to Credentials)
@@ -17,8 +17,15 @@ limitations under the License.
 package v1alpha2
 
 import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
 	"github.com/crossplane/crossplane-runtime/pkg/reference"
 	resource "github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	projectv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/project/v1alpha1"
+	sshkeyv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/sshkey/v1alpha1"
 )
 
 // DeviceID extracts the ID of a Device.
@@ -31,3 +38,38 @@ func DeviceID() reference.ExtractValueFn {
 		return c.Status.AtProvider.ID
 	}
 }
+
+// ResolveReferences of this Device
+func (mg *Device) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	// Resolve spec.forProvider.projectId
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ProjectID,
+		Reference:    mg.Spec.ForProvider.ProjectIDRef,
+		Selector:     mg.Spec.ForProvider.ProjectIDSelector,
+		To:           reference.To{Managed: &projectv1alpha1.Project{}, List: &projectv1alpha1.ProjectList{}},
+		Extract:      reference.ExternalName(),
+	})
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.ProjectID = rsp.ResolvedValue
+	mg.Spec.ForProvider.ProjectIDRef = rsp.ResolvedReference
+
+	// Resolve spec.forProvider.projectSSHKeys
+	mrsp, err := r.ResolveMultiple(ctx, reference.MultiResolutionRequest{
+		CurrentValues: mg.Spec.ForProvider.ProjectSSHKeys,
+		References:    mg.Spec.ForProvider.ProjectSSHKeyRefs,
+		Selector:      mg.Spec.ForProvider.ProjectSSHKeySelector,
+		To:            reference.To{Managed: &sshkeyv1alpha1.SSHKey{}, List: &sshkeyv1alpha1.SSHKeyList{}},
+		Extract:       sshkeyv1alpha1.SSHKeyID(),
+	})
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.ProjectSSHKeys = mrsp.ResolvedValues
+	mg.Spec.ForProvider.ProjectSSHKeyRefs = mrsp.ResolvedReferences
+
+	return nil
+}
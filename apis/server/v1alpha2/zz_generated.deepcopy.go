@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha2
 
 import (
+	"github.com/crossplane/crossplane-runtime/apis/common/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -67,6 +68,37 @@ func (in *Device) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceAction) DeepCopyInto(out *DeviceAction) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceAction.
+func (in *DeviceAction) DeepCopy() *DeviceAction {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceIPAddress) DeepCopyInto(out *DeviceIPAddress) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceIPAddress.
+func (in *DeviceIPAddress) DeepCopy() *DeviceIPAddress {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceIPAddress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeviceList) DeepCopyInto(out *DeviceList) {
 	*out = *in
@@ -99,10 +131,90 @@ func (in *DeviceList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceNetworkAttachment) DeepCopyInto(out *DeviceNetworkAttachment) {
+	*out = *in
+	if in.VRFs != nil {
+		in, out := &in.VRFs, &out.VRFs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceNetworkAttachment.
+func (in *DeviceNetworkAttachment) DeepCopy() *DeviceNetworkAttachment {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceNetworkAttachment)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeviceObservation) DeepCopyInto(out *DeviceObservation) {
 	*out = *in
 	out.ProvisionPercentage = in.ProvisionPercentage.DeepCopy()
+	if in.PrivateNetworks != nil {
+		in, out := &in.PrivateNetworks, &out.PrivateNetworks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPAddresses != nil {
+		in, out := &in.IPAddresses, &out.IPAddresses
+		*out = make([]DeviceIPAddress, len(*in))
+		copy(*out, *in)
+	}
+	if in.NetworkAttachments != nil {
+		in, out := &in.NetworkAttachments, &out.NetworkAttachments
+		*out = make([]DeviceNetworkAttachment, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SSHKeys != nil {
+		in, out := &in.SSHKeys, &out.SSHKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProjectSSHKeys != nil {
+		in, out := &in.ProjectSSHKeys, &out.ProjectSSHKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UserSSHKeys != nil {
+		in, out := &in.UserSSHKeys, &out.UserSSHKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SpotPriceMax != nil {
+		in, out := &in.SpotPriceMax, &out.SpotPriceMax
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.TerminationTime != nil {
+		in, out := &in.TerminationTime, &out.TerminationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastAction != nil {
+		in, out := &in.LastAction, &out.LastAction
+		*out = new(DeviceAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeletionStartedAt != nil {
+		in, out := &in.DeletionStartedAt, &out.DeletionStartedAt
+		*out = (*in).DeepCopy()
+	}
 	if in.CreatedAt != nil {
 		in, out := &in.CreatedAt, &out.CreatedAt
 		*out = (*in).DeepCopy()
@@ -126,6 +238,21 @@ func (in *DeviceObservation) DeepCopy() *DeviceObservation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeviceParameters) DeepCopyInto(out *DeviceParameters) {
 	*out = *in
+	if in.ProjectIDRef != nil {
+		in, out := &in.ProjectIDRef, &out.ProjectIDRef
+		*out = new(v1.Reference)
+		**out = **in
+	}
+	if in.ProjectIDSelector != nil {
+		in, out := &in.ProjectIDSelector, &out.ProjectIDSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Facilities != nil {
+		in, out := &in.Facilities, &out.Facilities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Hostname != nil {
 		in, out := &in.Hostname, &out.Hostname
 		*out = new(string)
@@ -146,11 +273,46 @@ func (in *DeviceParameters) DeepCopyInto(out *DeviceParameters) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.UserDataEncoding != nil {
+		in, out := &in.UserDataEncoding, &out.UserDataEncoding
+		*out = new(string)
+		**out = **in
+	}
 	if in.UserDataRef != nil {
 		in, out := &in.UserDataRef, &out.UserDataRef
 		*out = new(DataKeySelector)
 		**out = **in
 	}
+	if in.UserDataRefs != nil {
+		in, out := &in.UserDataRefs, &out.UserDataRefs
+		*out = make([]DataKeySelector, len(*in))
+		copy(*out, *in)
+	}
+	if in.UserDataFormat != nil {
+		in, out := &in.UserDataFormat, &out.UserDataFormat
+		*out = new(string)
+		**out = **in
+	}
+	if in.UserDataSeparator != nil {
+		in, out := &in.UserDataSeparator, &out.UserDataSeparator
+		*out = new(string)
+		**out = **in
+	}
+	if in.ReinstallOptions != nil {
+		in, out := &in.ReinstallOptions, &out.ReinstallOptions
+		*out = new(ReinstallOptions)
+		**out = **in
+	}
+	if in.RebootToken != nil {
+		in, out := &in.RebootToken, &out.RebootToken
+		*out = new(string)
+		**out = **in
+	}
+	if in.FailurePolicy != nil {
+		in, out := &in.FailurePolicy, &out.FailurePolicy
+		*out = new(FailurePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Tags != nil {
 		in, out := &in.Tags, &out.Tags
 		*out = make([]string, len(*in))
@@ -161,6 +323,16 @@ func (in *DeviceParameters) DeepCopyInto(out *DeviceParameters) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.ForceDelete != nil {
+		in, out := &in.ForceDelete, &out.ForceDelete
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HardwareReservationReleaseProjectID != nil {
+		in, out := &in.HardwareReservationReleaseProjectID, &out.HardwareReservationReleaseProjectID
+		*out = new(string)
+		**out = **in
+	}
 	if in.IPXEScriptURL != nil {
 		in, out := &in.IPXEScriptURL, &out.IPXEScriptURL
 		*out = new(string)
@@ -176,16 +348,50 @@ func (in *DeviceParameters) DeepCopyInto(out *DeviceParameters) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.PowerState != nil {
+		in, out := &in.PowerState, &out.PowerState
+		*out = new(string)
+		**out = **in
+	}
 	if in.HardwareReservationID != nil {
 		in, out := &in.HardwareReservationID, &out.HardwareReservationID
 		*out = new(string)
 		**out = **in
 	}
+	if in.SpotInstance != nil {
+		in, out := &in.SpotInstance, &out.SpotInstance
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SpotPriceMax != nil {
+		in, out := &in.SpotPriceMax, &out.SpotPriceMax
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.SpotTerminationProtection != nil {
+		in, out := &in.SpotTerminationProtection, &out.SpotTerminationProtection
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TerminationTime != nil {
+		in, out := &in.TerminationTime, &out.TerminationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(string)
+		**out = **in
+	}
 	if in.CustomData != nil {
 		in, out := &in.CustomData, &out.CustomData
 		*out = new(string)
 		**out = **in
 	}
+	if in.CustomDataRef != nil {
+		in, out := &in.CustomDataRef, &out.CustomDataRef
+		*out = new(DataKeySelector)
+		**out = **in
+	}
 	if in.UserSSHKeys != nil {
 		in, out := &in.UserSSHKeys, &out.UserSSHKeys
 		*out = make([]string, len(*in))
@@ -196,11 +402,26 @@ func (in *DeviceParameters) DeepCopyInto(out *DeviceParameters) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ProjectSSHKeyRefs != nil {
+		in, out := &in.ProjectSSHKeyRefs, &out.ProjectSSHKeyRefs
+		*out = make([]v1.Reference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProjectSSHKeySelector != nil {
+		in, out := &in.ProjectSSHKeySelector, &out.ProjectSSHKeySelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.NetworkType != nil {
 		in, out := &in.NetworkType, &out.NetworkType
 		*out = new(string)
 		**out = **in
 	}
+	if in.VLANs != nil {
+		in, out := &in.VLANs, &out.VLANs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Features != nil {
 		in, out := &in.Features, &out.Features
 		*out = make(map[string]string, len(*in))
@@ -215,6 +436,21 @@ func (in *DeviceParameters) DeepCopyInto(out *DeviceParameters) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.IPReservationRef != nil {
+		in, out := &in.IPReservationRef, &out.IPReservationRef
+		*out = new(v1.Reference)
+		**out = **in
+	}
+	if in.IPReservationSelector != nil {
+		in, out := &in.IPReservationSelector, &out.IPReservationSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NoPublicIP != nil {
+		in, out := &in.NoPublicIP, &out.NoPublicIP
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceParameters.
@@ -232,6 +468,11 @@ func (in *DeviceSpec) DeepCopyInto(out *DeviceSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.APIKeySecretRef != nil {
+		in, out := &in.APIKeySecretRef, &out.APIKeySecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceSpec.
@@ -261,6 +502,26 @@ func (in *DeviceStatus) DeepCopy() *DeviceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailurePolicy) DeepCopyInto(out *FailurePolicy) {
+	*out = *in
+	if in.Threshold != nil {
+		in, out := &in.Threshold, &out.Threshold
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailurePolicy.
+func (in *FailurePolicy) DeepCopy() *FailurePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(FailurePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IPAddress) DeepCopyInto(out *IPAddress) {
 	*out = *in
@@ -295,3 +556,18 @@ func (in *NamespacedName) DeepCopy() *NamespacedName {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReinstallOptions) DeepCopyInto(out *ReinstallOptions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReinstallOptions.
+func (in *ReinstallOptions) DeepCopy() *ReinstallOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ReinstallOptions)
+	in.DeepCopyInto(out)
+	return out
+}
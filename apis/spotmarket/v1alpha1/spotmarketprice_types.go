@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SpotMarketPriceSpec defines the desired state of SpotMarketPrice
+type SpotMarketPriceSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       SpotMarketPriceParameters `json:"forProvider"`
+}
+
+// SpotMarketPriceStatus defines the observed state of SpotMarketPrice
+type SpotMarketPriceStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          SpotMarketPriceObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A SpotMarketPrice is an observe-only managed resource that reports the
+// current Equinix Metal Spot Market price of a plan in a facility or metro.
+// The price is refreshed each time the resource is reconciled.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="PLAN",type="string",JSONPath=".spec.forProvider.plan"
+// +kubebuilder:printcolumn:name="PRICE",type="string",JSONPath=".status.atProvider.price"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix}
+type SpotMarketPrice struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SpotMarketPriceSpec   `json:"spec"`
+	Status SpotMarketPriceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SpotMarketPriceList contains a list of SpotMarketPrices
+type SpotMarketPriceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SpotMarketPrice `json:"items"`
+}
+
+// SpotMarketPriceParameters identify the Equinix Metal Spot Market price to
+// observe. Exactly one of Facility or Metro must be specified.
+// https://metal.equinix.com/developers/api/spotmarket/
+type SpotMarketPriceParameters struct {
+	// Plan is the slug of the device plan, such as c3.medium.x86
+	// +required
+	Plan string `json:"plan"`
+
+	// +optional
+	Facility *string `json:"facility,omitempty"`
+
+	// +optional
+	Metro *string `json:"metro,omitempty"`
+}
+
+// SpotMarketPriceObservation is used to reflect in the Kubernetes API, the
+// observed state of the SpotMarketPrice resource from the Equinix Metal API.
+type SpotMarketPriceObservation struct {
+	// Price is the current price per hour, in US dollars, of a device
+	// +optional
+	Price *resource.Quantity `json:"price,omitempty"`
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SpotMarketRequestSpec defines the desired state of SpotMarketRequest
+type SpotMarketRequestSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       SpotMarketRequestParameters `json:"forProvider"`
+}
+
+// SpotMarketRequestStatus defines the observed state of SpotMarketRequest
+type SpotMarketRequestStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          SpotMarketRequestObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A SpotMarketRequest is a managed resource that represents an Equinix Metal
+// Spot Market Request. Spot Market Requests can not be modified once created,
+// so all of their parameters are immutable. Deleting a SpotMarketRequest
+// terminates the devices it has provisioned.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.atProvider.id"
+// +kubebuilder:printcolumn:name="PLAN",type="string",JSONPath=".spec.forProvider.instanceParameters.plan"
+// +kubebuilder:printcolumn:name="DEVICES",type="integer",JSONPath=".status.atProvider.deviceCount"
+// +kubebuilder:printcolumn:name="RECLAIM-POLICY",type="string",JSONPath=".spec.reclaimPolicy"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix}
+type SpotMarketRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SpotMarketRequestSpec   `json:"spec"`
+	Status SpotMarketRequestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SpotMarketRequestList contains a list of SpotMarketRequests
+type SpotMarketRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SpotMarketRequest `json:"items"`
+}
+
+// SpotMarketRequestParameters define the desired state of an Equinix Metal
+// Spot Market Request.
+// https://metal.equinix.com/developers/api/spotmarket/
+//
+// The Equinix Metal API does not support updating Spot Market Requests, all
+// parameters are immutable.
+type SpotMarketRequestParameters struct {
+	// +immutable
+	ProjectID string `json:"projectId,omitempty"`
+
+	// +optional
+	// +immutable
+	ProjectIDRef *xpv1.Reference `json:"projectIdRef,omitempty"`
+
+	// +optional
+	ProjectIDSelector *xpv1.Selector `json:"projectIdSelector,omitempty"`
+
+	// DevicesMin is the minimum number of devices to provision
+	// +immutable
+	// +required
+	// +kubebuilder:validation:Minimum=1
+	DevicesMin int `json:"devicesMin"`
+
+	// DevicesMax is the maximum number of devices to provision
+	// +immutable
+	// +required
+	// +kubebuilder:validation:Minimum=1
+	DevicesMax int `json:"devicesMax"`
+
+	// MaxBidPrice is the maximum price per hour, in US dollars, bid for each
+	// device
+	// +immutable
+	// +required
+	MaxBidPrice resource.Quantity `json:"maxBidPrice"`
+
+	// Facilities are the facility codes devices may be provisioned in. They
+	// may not be set with Metro. The metro, or facility, of the ProviderConfig
+	// is used when neither is set.
+	// +immutable
+	// +optional
+	Facilities []string `json:"facilities,omitempty"`
+
+	// Metro is the metro code devices are provisioned in. It may not be set
+	// with Facilities.
+	// +immutable
+	// +optional
+	Metro *string `json:"metro,omitempty"`
+
+	// EndAt is the time at which the request expires
+	// +immutable
+	// +optional
+	EndAt *metav1.Time `json:"endAt,omitempty"`
+
+	// +immutable
+	// +required
+	InstanceParameters SpotMarketRequestInstanceParameters `json:"instanceParameters"`
+}
+
+// SpotMarketRequestInstanceParameters define the devices provisioned by a
+// Spot Market Request.
+type SpotMarketRequestInstanceParameters struct {
+	// +immutable
+	// +required
+	Plan string `json:"plan"`
+
+	// +immutable
+	// +required
+	OS string `json:"operatingSystem"`
+
+	// Hostname is the hostname template of the devices, for example
+	// "worker-{{index}}"
+	// +immutable
+	// +optional
+	Hostname *string `json:"hostname,omitempty"`
+
+	// +immutable
+	// +optional
+	Hostnames []string `json:"hostnames,omitempty"`
+
+	// +immutable
+	// +optional
+	Description *string `json:"description,omitempty"`
+
+	// +immutable
+	// +optional
+	BillingCycle *string `json:"billingCycle,omitempty"`
+
+	// +immutable
+	// +optional
+	UserData *string `json:"userdata,omitempty"`
+
+	// +immutable
+	// +optional
+	CustomData *string `json:"customData,omitempty"`
+
+	// +immutable
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// +immutable
+	// +optional
+	Features []string `json:"features,omitempty"`
+
+	// +immutable
+	// +optional
+	ProjectSSHKeys []string `json:"projectSSHKeys,omitempty"`
+
+	// +immutable
+	// +optional
+	UserSSHKeys []string `json:"userSSHKeys,omitempty"`
+
+	// +immutable
+	// +optional
+	Locked *bool `json:"locked,omitempty"`
+
+	// +immutable
+	// +optional
+	AlwaysPXE *bool `json:"alwaysPXE,omitempty"`
+
+	// +immutable
+	// +optional
+	IPXEScriptURL *string `json:"ipxeScriptUrl,omitempty"`
+
+	// TerminationTime is the time at which the devices are terminated
+	// +immutable
+	// +optional
+	TerminationTime *metav1.Time `json:"terminationTime,omitempty"`
+}
+
+// SpotMarketRequestDevice is a device provisioned by a SpotMarketRequest
+type SpotMarketRequestDevice struct {
+	// ID is the ID of the device
+	ID string `json:"id"`
+
+	// State is the state of the device, such as provisioning or active. It
+	// is empty when the state of the device was not observed.
+	// +optional
+	State string `json:"state,omitempty"`
+}
+
+// SpotMarketRequestObservation is used to reflect in the Kubernetes API, the
+// observed state of the SpotMarketRequest resource from the Equinix Metal API.
+type SpotMarketRequestObservation struct {
+	ID   string `json:"id"`
+	Href string `json:"href,omitempty"`
+
+	// DeviceCount is the number of devices provisioned by the request
+	DeviceCount int `json:"deviceCount"`
+
+	// DeviceIDs are the IDs of the devices provisioned by the request
+	// +optional
+	DeviceIDs []string `json:"deviceIds,omitempty"`
+
+	// Devices are the devices provisioned by the request and their states.
+	// It is empty until the first device is provisioned.
+	// +optional
+	Devices []SpotMarketRequestDevice `json:"devices,omitempty"`
+
+	// +optional
+	Facilities []string `json:"facilities,omitempty"`
+
+	// +optional
+	Metro string `json:"metro,omitempty"`
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SSHKeySpec defines the desired state of SSHKey
+type SSHKeySpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       SSHKeyParameters `json:"forProvider"`
+}
+
+// SSHKeyStatus defines the observed state of SSHKey
+type SSHKeyStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          SSHKeyObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SSHKey is a managed resource that represents an Equinix Metal SSH Key
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.atProvider.id"
+// +kubebuilder:printcolumn:name="LABEL",type="string",JSONPath=".spec.forProvider.label"
+// +kubebuilder:printcolumn:name="FINGERPRINT",type="string",JSONPath=".status.atProvider.fingerprint",priority=1
+// +kubebuilder:printcolumn:name="RECLAIM-POLICY",type="string",JSONPath=".spec.reclaimPolicy"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix}
+type SSHKey struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SSHKeySpec   `json:"spec"`
+	Status SSHKeyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SSHKeyList contains a list of SSHKeys
+type SSHKeyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SSHKey `json:"items"`
+}
+
+// SSHKeyParameters define the desired state of an Equinix Metal SSH Key.
+// https://metal.equinix.com/developers/api/sshkeys/#create-a-ssh-key-for-the-current-user
+type SSHKeyParameters struct {
+	// +required
+	Label string `json:"label"`
+
+	// Key is the public key, in OpenSSH authorized_keys format
+	// +required
+	Key string `json:"key"`
+}
+
+// SSHKeyObservation is used to reflect in the Kubernetes API, the observed
+// state of the SSHKey resource from the Equinix Metal API.
+type SSHKeyObservation struct {
+	ID          string `json:"id"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// +optional
+	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
+
+	// +optional
+	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
+}
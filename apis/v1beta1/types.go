@@ -31,6 +31,63 @@ type ProviderConfigSpec struct {
 	// providerID).
 	// +kubebuilder:validation:Optional
 	ProjectID string `json:"projectID"`
+
+	// MaxRetries is the number of times an Equinix Metal API request that was
+	// rate limited or failed with a server error is retried. Defaults to 5.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	MaxRetries *int `json:"maxRetries,omitempty"`
+
+	// RetryBaseDelay is the delay before the first retry of an Equinix Metal
+	// API request. The delay doubles with each retry unless the API responds
+	// with a Retry-After header. Defaults to 1s.
+	// +kubebuilder:validation:Optional
+	RetryBaseDelay *metav1.Duration `json:"retryBaseDelay,omitempty"`
+
+	// RequestTimeout is the time limit for each attempt of an Equinix Metal
+	// API request, such as 1m. Requests that time out are not retried.
+	// Defaults to 30s.
+	// +kubebuilder:validation:Optional
+	RequestTimeout *metav1.Duration `json:"requestTimeout,omitempty"`
+
+	// PollInterval is how often managed resources using this ProviderConfig
+	// are observed once they are up to date, such as 5m. Intervals that are
+	// not positive are rejected. Defaults to the poll interval of the
+	// provider.
+	// +kubebuilder:validation:Optional
+	PollInterval *metav1.Duration `json:"pollInterval,omitempty"`
+
+	// DefaultTags are added to the tags of the resources created using this
+	// ProviderConfig, such as Devices and IP reservations, as key=value tags.
+	// A tag of the resource with the same key is kept rather than the
+	// default tag.
+	// +kubebuilder:validation:Optional
+	DefaultTags map[string]string `json:"defaultTags,omitempty"`
+
+	// BaseURL is the URL of the Equinix Metal API, such as the URL of a mock
+	// server or of a private endpoint. Defaults to the public API,
+	// https://api.equinix.com/metal/v1/.
+	// +kubebuilder:validation:Optional
+	BaseURL string `json:"baseURL,omitempty"`
+
+	// UserAgent replaces the provider name and version at the start of the
+	// User-Agent header of Equinix Metal API requests, such as
+	// my-platform/1.2. Defaults to crossplane-provider-equinix-metal and the
+	// version of the provider.
+	// +kubebuilder:validation:Optional
+	UserAgent string `json:"userAgent,omitempty"`
+
+	// UniqueHostnames prevents Devices from being created with the hostname
+	// of another Device of their project. Creating such a Device fails
+	// rather than creating a duplicate hostname. Defaults to false.
+	// +kubebuilder:validation:Optional
+	UniqueHostnames bool `json:"uniqueHostnames,omitempty"`
+
+	// DeprovisionWarningThreshold is how long a deleted Device may remain
+	// deprovisioning before a warning event reports that its deletion may
+	// be stuck, such as 1h. Defaults to 30m.
+	// +kubebuilder:validation:Optional
+	DeprovisionWarningThreshold *metav1.Duration `json:"deprovisionWarningThreshold,omitempty"`
 }
 
 // ProviderCredentials required to authenticate.
@@ -69,14 +69,36 @@ type VirtualNetworkList struct {
 // Reference values are used for optional parameters to determine if
 // LateInitialization should update the parameter after creation.
 type VirtualNetworkParameters struct {
+	// ProjectID is the project the VirtualNetwork is created in. The project
+	// of the ProviderConfig credentials is used when none is specified.
+	// +immutable
+	// +optional
+	ProjectID string `json:"projectId,omitempty"`
+
+	// +optional
+	// +immutable
+	ProjectIDRef *xpv1.Reference `json:"projectIdRef,omitempty"`
+
+	// +optional
+	ProjectIDSelector *xpv1.Selector `json:"projectIdSelector,omitempty"`
+
+	// Facility in which to create the VirtualNetwork. Facility may not be
+	// set along with Metro. When the VirtualNetwork is created in a Metro,
+	// Facility is late-initialized from the facility reported by the API.
 	// +immutable
 	// +optional
 	Facility string `json:"facility,omitempty"`
 
+	// Metro in which to create the VirtualNetwork. Metro may not be set along
+	// with Facility. When neither is set, the metro, or else the facility, of
+	// the ProviderConfig is used.
 	// +immutable
 	// +optional
 	Metro string `json:"metro,omitempty"`
 
+	// VXLAN requests a specific VXLAN network identifier (VNID). A VXLAN may
+	// only be requested for VirtualNetworks created in a Metro and must not
+	// already be in use by another VirtualNetwork of the project in that Metro.
 	// +immutable
 	// +optional
 	VXLAN int `json:"vxlan,omitempty"`
@@ -92,5 +114,7 @@ type VirtualNetworkObservation struct {
 	Href         string       `json:"href,omitempty"`
 	VXLAN        int          `json:"vxlan,omitempty"`
 	FacilityCode string       `json:"facilityCode,omitempty"`
+	MetroCode    string       `json:"metroCode,omitempty"`
+	AssignedTo   string       `json:"assignedTo,omitempty"`
 	CreatedAt    *metav1.Time `json:"createdAt,omitempty"`
 }
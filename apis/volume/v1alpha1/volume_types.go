@@ -0,0 +1,146 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Volume states
+const (
+	// StateQueued indicates the volume is waiting to be provisioned
+	StateQueued = "queued"
+
+	// StateProvisioning indicates the volume is being provisioned
+	StateProvisioning = "provisioning"
+
+	// StateActive indicates the volume is ready for use
+	StateActive = "active"
+
+	// StateFailed indicates the volume could not be provisioned
+	StateFailed = "failed"
+)
+
+// VolumeSpec defines the desired state of Volume
+type VolumeSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       VolumeParameters `json:"forProvider"`
+}
+
+// VolumeStatus defines the observed state of Volume
+type VolumeStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          VolumeObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Volume is a managed resource that represents an Equinix Metal elastic
+// block storage Volume
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.atProvider.id"
+// +kubebuilder:printcolumn:name="SIZE",type="integer",JSONPath=".spec.forProvider.size"
+// +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.atProvider.state"
+// +kubebuilder:printcolumn:name="RECLAIM-POLICY",type="string",JSONPath=".spec.reclaimPolicy"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix}
+type Volume struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VolumeSpec   `json:"spec"`
+	Status VolumeStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VolumeList contains a list of Volumes
+type VolumeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Volume `json:"items"`
+}
+
+// VolumeParameters define the desired state of an Equinix Metal Volume.
+// https://metal.equinix.com/developers/api/volumes/
+//
+// Reference values are used for optional parameters to determine if
+// LateInitialization should update the parameter after creation.
+type VolumeParameters struct {
+	// +immutable
+	ProjectID string `json:"projectId,omitempty"`
+
+	// +optional
+	// +immutable
+	ProjectIDRef *xpv1.Reference `json:"projectIdRef,omitempty"`
+
+	// +optional
+	ProjectIDSelector *xpv1.Selector `json:"projectIdSelector,omitempty"`
+
+	// Size of the volume in GB. Volumes can be grown but not shrunk.
+	// +required
+	// +kubebuilder:validation:Minimum=1
+	Size int `json:"size"`
+
+	// PlanID is the storage plan of the volume, such as storage_1 or
+	// storage_2
+	// +optional
+	PlanID *string `json:"planId,omitempty"`
+
+	// Facility is where the volume is provisioned. The facility of the
+	// ProviderConfig credentials is used when none is specified.
+	// +immutable
+	// +optional
+	Facility *string `json:"facility,omitempty"`
+
+	// +optional
+	BillingCycle *string `json:"billingCycle,omitempty"`
+
+	// +optional
+	Description *string `json:"description,omitempty"`
+
+	// Locked volumes can not be deleted
+	// +optional
+	Locked *bool `json:"locked,omitempty"`
+}
+
+// VolumeObservation is used to reflect in the Kubernetes API, the observed
+// state of the Volume resource from the Equinix Metal API.
+type VolumeObservation struct {
+	ID    string `json:"id"`
+	Href  string `json:"href,omitempty"`
+	Name  string `json:"name,omitempty"`
+	State string `json:"state,omitempty"`
+
+	// +optional
+	Attachments []Attachment `json:"attachments,omitempty"`
+
+	// +optional
+	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
+
+	// +optional
+	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
+}
+
+// Attachment reflects an attachment of a Volume to a Device
+type Attachment struct {
+	ID       string `json:"id"`
+	DeviceID string `json:"deviceId"`
+}
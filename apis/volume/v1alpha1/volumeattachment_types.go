@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VolumeAttachmentSpec defines the desired state of VolumeAttachment
+type VolumeAttachmentSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       VolumeAttachmentParameters `json:"forProvider"`
+}
+
+// VolumeAttachmentStatus defines the observed state of VolumeAttachment
+type VolumeAttachmentStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          VolumeAttachmentObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A VolumeAttachment is a managed resource that represents the attachment of
+// an Equinix Metal Volume to a Device
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="VOLUME",type="string",JSONPath=".spec.forProvider.volumeId"
+// +kubebuilder:printcolumn:name="DEVICE",type="string",JSONPath=".spec.forProvider.deviceId"
+// +kubebuilder:printcolumn:name="RECLAIM-POLICY",type="string",JSONPath=".spec.reclaimPolicy"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix}
+type VolumeAttachment struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VolumeAttachmentSpec   `json:"spec"`
+	Status VolumeAttachmentStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VolumeAttachmentList contains a list of VolumeAttachments
+type VolumeAttachmentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VolumeAttachment `json:"items"`
+}
+
+// VolumeAttachmentParameters define the desired state of an Equinix Metal
+// Volume attachment.
+// https://metal.equinix.com/developers/api/volumes/#attach-your-volume-to-a-device
+type VolumeAttachmentParameters struct {
+	// +immutable
+	VolumeID string `json:"volumeId,omitempty"`
+
+	// +optional
+	// +immutable
+	VolumeIDRef *xpv1.Reference `json:"volumeIdRef,omitempty"`
+
+	// +optional
+	VolumeIDSelector *xpv1.Selector `json:"volumeIdSelector,omitempty"`
+
+	// +immutable
+	DeviceID string `json:"deviceId,omitempty"`
+
+	// +optional
+	// +immutable
+	DeviceIDRef *xpv1.Reference `json:"deviceIdRef,omitempty"`
+
+	// +optional
+	DeviceIDSelector *xpv1.Selector `json:"deviceIdSelector,omitempty"`
+}
+
+// VolumeAttachmentObservation is used to reflect in the Kubernetes API, the
+// observed state of the VolumeAttachment resource from the Equinix Metal API.
+type VolumeAttachmentObservation struct {
+	ID   string `json:"id"`
+	Href string `json:"href,omitempty"`
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VRFSpec defines the desired state of VRF
+type VRFSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       VRFParameters `json:"forProvider"`
+}
+
+// VRFStatus defines the observed state of VRF
+type VRFStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          VRFObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VRF is a managed resource that represents an Equinix Metal Virtual Routing
+// and Forwarding instance
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.atProvider.id"
+// +kubebuilder:printcolumn:name="METRO",type="string",JSONPath=".status.atProvider.metro"
+// +kubebuilder:printcolumn:name="ASN",type="integer",JSONPath=".spec.forProvider.localAsn"
+// +kubebuilder:printcolumn:name="RECLAIM-POLICY",type="string",JSONPath=".spec.reclaimPolicy"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix}
+type VRF struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VRFSpec   `json:"spec"`
+	Status VRFStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VRFList contains a list of VRFs
+type VRFList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VRF `json:"items"`
+}
+
+// VRFParameters define the desired state of an Equinix Metal VRF.
+// https://metal.equinix.com/developers/api/vrfs/
+//
+// Reference values are used for optional parameters to determine if
+// LateInitialization should update the parameter after creation.
+type VRFParameters struct {
+	// ProjectID is the project the VRF is created in. The project of the
+	// ProviderConfig credentials is used when none is specified.
+	// +immutable
+	// +optional
+	ProjectID string `json:"projectId,omitempty"`
+
+	// +optional
+	// +immutable
+	ProjectIDRef *xpv1.Reference `json:"projectIdRef,omitempty"`
+
+	// +optional
+	ProjectIDSelector *xpv1.Selector `json:"projectIdSelector,omitempty"`
+
+	// Name of the VRF
+	// +immutable
+	Name string `json:"name"`
+
+	// +optional
+	Description *string `json:"description,omitempty"`
+
+	// Metro in which to create the VRF
+	// +immutable
+	Metro string `json:"metro"`
+
+	// LocalASN is the autonomous system number of the VRF. The API default is
+	// used, and late-initialized, when none is specified.
+	// +immutable
+	// +optional
+	LocalASN *int `json:"localAsn,omitempty"`
+
+	// IPRanges are the IPv4 and IPv6 ranges, in CIDR notation, that may be
+	// used within the VRF. Ranges that are in use by IP reservations can not
+	// be removed.
+	// +optional
+	IPRanges []string `json:"ipRanges,omitempty"`
+}
+
+// VRFObservation is used to reflect in the Kubernetes API, the observed state
+// of the VRF resource from the Equinix Metal API.
+type VRFObservation struct {
+	ID    string `json:"id"`
+	Href  string `json:"href,omitempty"`
+	Metro string `json:"metro,omitempty"`
+
+	// +optional
+	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
+
+	// +optional
+	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
+}
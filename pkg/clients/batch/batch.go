@@ -0,0 +1,169 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batch
+
+import (
+	"context"
+	"path"
+
+	"github.com/packethost/packngo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/batch/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+// Client implements the Equinix Metal API methods needed to interact with
+// Batches for the Equinix Metal Crossplane Provider
+type Client interface {
+	Get(batchID string, getOpt *packngo.GetOptions) (*packngo.Batch, *packngo.Response, error)
+	Create(projectID string, batches *packngo.BatchCreateRequest) ([]packngo.Batch, *packngo.Response, error)
+	Delete(batchID string, removeDevices bool) (*packngo.Response, error)
+}
+
+// build-time test that the interface is implemented
+var _ Client = (&packngo.Client{}).Batches
+
+// ClientWithDefaults is an interface that provides Batch services and
+// provides default values for common properties
+type ClientWithDefaults interface {
+	Client
+	clients.DefaultGetter
+}
+
+// CredentialedClient is a credentialed client to Equinix Metal Batch services
+type CredentialedClient struct {
+	Client
+	*clients.Credentials
+}
+
+var _ ClientWithDefaults = &CredentialedClient{}
+
+// NewClient returns a Client implementing the Equinix Metal API methods needed
+// to interact with Batches for the Equinix Metal Crossplane Provider
+func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefaults, error) {
+	client, err := clients.NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	batchClient := CredentialedClient{
+		Client:      client.Client.Batches,
+		Credentials: client.Credentials,
+	}
+	batchClient.SetProjectID(config.ProjectID)
+	return batchClient, nil
+}
+
+// CreateFromDeviceBatch return packngo.BatchCreateRequest created from
+// Kubernetes. The request describes a single batch of devices.
+func CreateFromDeviceBatch(b *v1alpha1.DeviceBatch, projectID string) *packngo.BatchCreateRequest {
+	p := b.Spec.ForProvider
+	i := p.InstanceParameters
+
+	device := packngo.BatchCreateDevice{
+		DeviceCreateRequest: packngo.DeviceCreateRequest{
+			Hostname:             i.Hostname,
+			Plan:                 i.Plan,
+			Facility:             i.Facilities,
+			Metro:                emptyIfNil(i.Metro),
+			OS:                   i.OS,
+			BillingCycle:         emptyIfNil(i.BillingCycle),
+			ProjectID:            projectID,
+			UserData:             emptyIfNil(i.UserData),
+			CustomData:           emptyIfNil(i.CustomData),
+			Tags:                 i.Tags,
+			Description:          emptyIfNil(i.Description),
+			IPXEScriptURL:        emptyIfNil(i.IPXEScriptURL),
+			PublicIPv4SubnetSize: zeroIfNil(i.PublicIPv4SubnetSize),
+			AlwaysPXE:            falseIfNil(i.AlwaysPXE),
+			UserSSHKeys:          i.UserSSHKeys,
+			ProjectSSHKeys:       i.ProjectSSHKeys,
+			Features:             i.Features,
+		},
+		Quantity:               int32(p.Quantity),
+		FacilityDiversityLevel: int32(zeroIfNil(p.FacilityDiversityLevel)),
+		SpotInstance:           falseIfNil(i.SpotInstance),
+	}
+
+	if i.SpotPriceMax != nil {
+		// The Equinix Metal API accepts bids in dollars with a precision of
+		// cents
+		device.SpotPriceMax = float64(i.SpotPriceMax.MilliValue()) / 1000
+	}
+	if i.TerminationTime != nil {
+		device.TerminationTime = &packngo.Timestamp{Time: i.TerminationTime.Time}
+	}
+
+	return &packngo.BatchCreateRequest{Batches: []packngo.BatchCreateDevice{device}}
+}
+
+func emptyIfNil(in *string) string {
+	if in == nil {
+		return ""
+	}
+	return *in
+}
+
+func falseIfNil(in *bool) bool {
+	if in == nil {
+		return false
+	}
+	return *in
+}
+
+func zeroIfNil(in *int) int {
+	if in == nil {
+		return 0
+	}
+	return *in
+}
+
+// GenerateObservation produces v1alpha1.DeviceBatchObservation from
+// packngo.Batch
+func GenerateObservation(b *packngo.Batch) v1alpha1.DeviceBatchObservation {
+	observation := v1alpha1.DeviceBatchObservation{
+		ID:            b.ID,
+		Href:          b.Href,
+		State:         b.State,
+		ErrorMessages: b.ErrorMessages,
+	}
+
+	for _, d := range b.Devices {
+		// Devices are only listed by href unless they are included
+		id := d.ID
+		if id == "" && d.Href != "" {
+			id = path.Base(d.Href)
+		}
+		observation.DeviceIDs = append(observation.DeviceIDs, id)
+
+		if d.State == v1alpha2.StateActive {
+			observation.ProvisionedCount++
+		}
+	}
+
+	if b.CreatedAt != nil {
+		observation.CreatedAt = &metav1.Time{Time: b.CreatedAt.Time}
+	}
+
+	return observation
+}
+
+// IsProvisioned returns true if all of the devices of the batch are active
+func IsProvisioned(b *v1alpha1.DeviceBatch) bool {
+	return b.Status.AtProvider.ProvisionedCount >= b.Spec.ForProvider.Quantity
+}
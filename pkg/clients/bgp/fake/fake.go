@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/packethost/packngo"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/bgp"
+)
+
+var _ bgp.ClientWithDefaults = &MockClient{}
+
+// MockClient is a fake implementation of packngo.Client.
+type MockClient struct {
+	MockGet    func(sessionID string, getOpt *packngo.GetOptions) (*packngo.BGPSession, *packngo.Response, error)
+	MockCreate func(deviceID string, request packngo.CreateBGPSessionRequest) (*packngo.BGPSession, *packngo.Response, error)
+	MockDelete func(sessionID string) (*packngo.Response, error)
+
+	MockListBGPSessions  func(deviceID string, opts *packngo.ListOptions) ([]packngo.BGPSession, *packngo.Response, error)
+	MockListBGPNeighbors func(deviceID string, opts *packngo.ListOptions) ([]packngo.BGPNeighbor, *packngo.Response, error)
+
+	MockGetProjectID  func(string) string
+	MockGetFacilityID func(string) string
+	MockGetMetro      func(string) string
+}
+
+// Get calls the MockClient's MockGet function.
+func (c *MockClient) Get(sessionID string, getOpt *packngo.GetOptions) (*packngo.BGPSession, *packngo.Response, error) {
+	return c.MockGet(sessionID, getOpt)
+}
+
+// Create calls the MockClient's MockCreate function.
+func (c *MockClient) Create(deviceID string, request packngo.CreateBGPSessionRequest) (*packngo.BGPSession, *packngo.Response, error) {
+	return c.MockCreate(deviceID, request)
+}
+
+// Delete calls the MockClient's MockDelete function.
+func (c *MockClient) Delete(sessionID string) (*packngo.Response, error) {
+	return c.MockDelete(sessionID)
+}
+
+// ListBGPSessions calls the MockClient's MockListBGPSessions function.
+func (c *MockClient) ListBGPSessions(deviceID string, opts *packngo.ListOptions) ([]packngo.BGPSession, *packngo.Response, error) {
+	return c.MockListBGPSessions(deviceID, opts)
+}
+
+// ListBGPNeighbors calls the MockClient's MockListBGPNeighbors function.
+func (c *MockClient) ListBGPNeighbors(deviceID string, opts *packngo.ListOptions) ([]packngo.BGPNeighbor, *packngo.Response, error) {
+	return c.MockListBGPNeighbors(deviceID, opts)
+}
+
+// GetFacilityID calls the MockClient's MockGetFacilityID function.
+func (c *MockClient) GetFacilityID(id string) string {
+	return c.MockGetFacilityID(id)
+}
+
+// GetMetro calls the MockClient's MockGetMetro function.
+func (c *MockClient) GetMetro(metro string) string {
+	return c.MockGetMetro(metro)
+}
+
+// GetProjectID calls the MockClient's MockGetProjectID function.
+func (c *MockClient) GetProjectID(id string) string {
+	return c.MockGetProjectID(id)
+}
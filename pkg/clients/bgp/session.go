@@ -0,0 +1,179 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bgp
+
+import (
+	"context"
+
+	"github.com/packethost/packngo"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/bgp/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+// Client implements the Equinix Metal API methods needed to interact with BGP
+// Sessions for the Equinix Metal Crossplane Provider
+type Client interface {
+	Get(sessionID string, getOpt *packngo.GetOptions) (*packngo.BGPSession, *packngo.Response, error)
+	Create(deviceID string, request packngo.CreateBGPSessionRequest) (*packngo.BGPSession, *packngo.Response, error)
+	Delete(sessionID string) (*packngo.Response, error)
+}
+
+// DeviceClient implements the Equinix Metal API methods needed to find the
+// BGP Sessions and neighbors of a Device
+type DeviceClient interface {
+	ListBGPSessions(deviceID string, opts *packngo.ListOptions) ([]packngo.BGPSession, *packngo.Response, error)
+	ListBGPNeighbors(deviceID string, opts *packngo.ListOptions) ([]packngo.BGPNeighbor, *packngo.Response, error)
+}
+
+// build-time test that the interface is implemented
+var _ Client = (&packngo.Client{}).BGPSessions
+var _ DeviceClient = (&packngo.Client{}).Devices
+
+// ClientWithDefaults is an interface that provides BGP Session services and
+// provides default values for common properties
+type ClientWithDefaults interface {
+	Client
+	DeviceClient
+	clients.DefaultGetter
+}
+
+// CredentialedClient is a credentialed client to Equinix Metal BGP Session
+// services
+type CredentialedClient struct {
+	Client
+	DeviceClient
+	*clients.Credentials
+}
+
+var _ ClientWithDefaults = &CredentialedClient{}
+
+// NewClient returns a Client implementing the Equinix Metal API methods needed
+// to interact with BGP Sessions for the Equinix Metal Crossplane Provider
+func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefaults, error) {
+	client, err := clients.NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	bgpClient := CredentialedClient{
+		Client:       client.Client.BGPSessions,
+		DeviceClient: client.Client.Devices,
+		Credentials:  client.Credentials,
+	}
+	bgpClient.SetProjectID(config.ProjectID)
+	return bgpClient, nil
+}
+
+// CreateFromBGPSession return packngo.CreateBGPSessionRequest created from
+// Kubernetes
+func CreateFromBGPSession(s *v1alpha1.BGPSession) packngo.CreateBGPSessionRequest {
+	return packngo.CreateBGPSessionRequest{
+		AddressFamily: s.Spec.ForProvider.AddressFamily,
+		DefaultRoute:  s.Spec.ForProvider.DefaultRoute,
+	}
+}
+
+// ListSessions returns every page of the BGP Sessions of a Device
+func ListSessions(c DeviceClient, deviceID string) ([]packngo.BGPSession, error) {
+	var sessions []packngo.BGPSession
+	err := clients.ListAll(func(opts *packngo.ListOptions) error {
+		page, _, err := c.ListBGPSessions(deviceID, opts)
+		sessions = append(sessions, page...)
+		return err
+	})
+	return sessions, err
+}
+
+// FindSession returns the session with the supplied ID or, when there is no
+// such session, the session of the supplied address family
+func FindSession(sessions []packngo.BGPSession, sessionID, addressFamily string) *packngo.BGPSession {
+	for i := range sessions {
+		if sessions[i].ID == sessionID {
+			return &sessions[i]
+		}
+	}
+	for i := range sessions {
+		if sessions[i].AddressFamily == addressFamily {
+			return &sessions[i]
+		}
+	}
+	return nil
+}
+
+// FindNeighbor returns the BGP neighbor of the supplied address family
+func FindNeighbor(neighbors []packngo.BGPNeighbor, addressFamily string) *packngo.BGPNeighbor {
+	family := 4
+	if addressFamily == v1alpha1.AddressFamilyIPv6 {
+		family = 6
+	}
+	for i := range neighbors {
+		if neighbors[i].AddressFamily == family {
+			return &neighbors[i]
+		}
+	}
+	return nil
+}
+
+// GenerateObservation produces v1alpha1.BGPSessionObservation from
+// packngo.BGPSession and its packngo.BGPNeighbor, if any
+func GenerateObservation(session *packngo.BGPSession, neighbor *packngo.BGPNeighbor) v1alpha1.BGPSessionObservation {
+	observation := v1alpha1.BGPSessionObservation{
+		ID:            session.ID,
+		Href:          session.Href,
+		Status:        session.Status,
+		LearnedRoutes: session.LearnedRoutes,
+	}
+
+	if neighbor != nil {
+		observation.Peer = &v1alpha1.BGPPeerObservation{
+			PeerAS:     neighbor.PeerAs,
+			PeerIPs:    neighbor.PeerIps,
+			CustomerAS: neighbor.CustomerAs,
+			CustomerIP: neighbor.CustomerIP,
+			MD5Enabled: neighbor.Md5Enabled,
+			Multihop:   neighbor.Multihop,
+		}
+	}
+
+	return observation
+}
+
+// LateInitialize fills the empty fields in *v1alpha1.BGPSessionParameters
+// with the values seen in packngo.BGPSession
+func LateInitialize(in *v1alpha1.BGPSessionParameters, session *packngo.BGPSession) {
+	if session == nil {
+		return
+	}
+
+	in.DefaultRoute = clients.LateInitializeBoolPtr(in.DefaultRoute, session.DefaultRoute)
+}
+
+// IsUpToDate returns true if the supplied Kubernetes resource does not differ
+// from the supplied Equinix Metal resource. BGP Sessions can not be modified,
+// so a session that is not up to date must be recreated.
+func IsUpToDate(s *v1alpha1.BGPSession, session *packngo.BGPSession) bool {
+	if s.Spec.ForProvider.AddressFamily != session.AddressFamily {
+		return false
+	}
+
+	if s.Spec.ForProvider.DefaultRoute != nil && session.DefaultRoute != nil &&
+		*s.Spec.ForProvider.DefaultRoute != *session.DefaultRoute {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bgpconfig
+
+import (
+	"context"
+
+	"github.com/packethost/packngo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/bgpconfig/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+// Client implements the Equinix Metal API methods needed to interact with
+// the BGP configuration of Projects for the Equinix Metal Crossplane Provider
+type Client interface {
+	Get(projectID string, getOpt *packngo.GetOptions) (*packngo.BGPConfig, *packngo.Response, error)
+	Create(projectID string, request packngo.CreateBGPConfigRequest) (*packngo.Response, error)
+}
+
+// build-time test that the interface is implemented
+var _ Client = (&packngo.Client{}).BGPConfig
+
+// ClientWithDefaults is an interface that provides BGP configuration
+// services and provides default values for common properties
+type ClientWithDefaults interface {
+	Client
+	clients.DefaultGetter
+}
+
+// CredentialedClient is a credentialed client to Equinix Metal BGP
+// configuration services
+type CredentialedClient struct {
+	Client
+	*clients.Credentials
+}
+
+var _ ClientWithDefaults = &CredentialedClient{}
+
+// NewClient returns a Client implementing the Equinix Metal API methods needed
+// to interact with BGP configuration for the Equinix Metal Crossplane Provider
+func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefaults, error) {
+	client, err := clients.NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	bgpConfigClient := CredentialedClient{
+		Client:      client.Client.BGPConfig,
+		Credentials: client.Credentials,
+	}
+	bgpConfigClient.SetProjectID(config.ProjectID)
+	return bgpConfigClient, nil
+}
+
+// CreateFromBGPConfig return packngo.CreateBGPConfigRequest created from
+// Kubernetes
+func CreateFromBGPConfig(c *v1alpha1.BGPConfig) packngo.CreateBGPConfigRequest {
+	return packngo.CreateBGPConfigRequest{
+		DeploymentType: c.Spec.ForProvider.DeploymentType,
+		Asn:            c.Spec.ForProvider.ASN,
+		Md5:            emptyIfNil(c.Spec.ForProvider.MD5),
+	}
+}
+
+func emptyIfNil(in *string) string {
+	if in == nil {
+		return ""
+	}
+	return *in
+}
+
+// IsEnabled returns true if BGP has been enabled or requested for the
+// project. Projects without BGP respond with an empty or disabled config.
+func IsEnabled(config *packngo.BGPConfig) bool {
+	return config != nil && config.Status != "" && config.Status != v1alpha1.StatusDisabled
+}
+
+// IsRequested returns true if the BGP configuration awaits approval
+func IsRequested(config *packngo.BGPConfig) bool {
+	return config.Status == v1alpha1.StatusRequested
+}
+
+// GenerateObservation produces v1alpha1.BGPConfigObservation from
+// packngo.BGPConfig
+func GenerateObservation(config *packngo.BGPConfig) v1alpha1.BGPConfigObservation {
+	observation := v1alpha1.BGPConfigObservation{
+		ID:          config.ID,
+		Href:        config.Href,
+		Status:      config.Status,
+		RouteObject: config.RouteObject,
+		MaxPrefix:   config.MaxPrefix,
+	}
+
+	if !config.CreatedAt.IsZero() {
+		createdAt := metav1.NewTime(config.CreatedAt.Time)
+		observation.CreatedAt = &createdAt
+	}
+
+	return observation
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacity
+
+import (
+	"context"
+
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/capacity/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+const (
+	errNoServers             = "at least one server must be set"
+	errFacilityMetroConflict = "facility %q and metro %q can not both be set"
+	errFacilityMetroMissing  = "one of facility or metro must be set for plan %q"
+	errUnexpectedServers     = "expected %d servers in the capacity check response, got %d"
+)
+
+// Client implements the Equinix Metal API methods needed to check capacity
+// for the Equinix Metal Crossplane Provider
+type Client interface {
+	Check(*packngo.CapacityInput) (*packngo.CapacityInput, *packngo.Response, error)
+	CheckMetros(*packngo.CapacityInput) (*packngo.CapacityInput, *packngo.Response, error)
+}
+
+// build-time test that the interface is implemented
+var _ Client = (&packngo.Client{}).CapacityService
+
+// ClientWithDefaults is an interface that provides capacity services and
+// provides default values for common properties
+type ClientWithDefaults interface {
+	Client
+	clients.DefaultGetter
+}
+
+// CredentialedClient is a credentialed client to Equinix Metal capacity
+// services
+type CredentialedClient struct {
+	Client
+	*clients.Credentials
+}
+
+var _ ClientWithDefaults = &CredentialedClient{}
+
+// NewClient returns a Client implementing the Equinix Metal API methods needed
+// to check capacity for the Equinix Metal Crossplane Provider
+func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefaults, error) {
+	client, err := clients.NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	capacityClient := CredentialedClient{
+		Client:      client.Client.CapacityService,
+		Credentials: client.Credentials,
+	}
+	capacityClient.SetProjectID(config.ProjectID)
+	return capacityClient, nil
+}
+
+// CheckCapacity returns the availability of each server of the supplied
+// CapacityCheck, in the order the servers were specified. Servers in
+// facilities and servers in metros are checked separately.
+func CheckCapacity(c Client, p *v1alpha1.CapacityCheck) ([]v1alpha1.ServerAvailability, error) {
+	servers := p.Spec.ForProvider.Servers
+	if len(servers) == 0 {
+		return nil, errors.New(errNoServers)
+	}
+
+	facilities, metros := &packngo.CapacityInput{}, &packngo.CapacityInput{}
+	var facilityIdx, metroIdx []int
+	for i, s := range servers {
+		info := packngo.ServerInfo{Plan: s.Plan, Quantity: s.Quantity}
+		if info.Quantity == 0 {
+			info.Quantity = 1
+		}
+		switch {
+		case s.Facility != nil && s.Metro != nil:
+			return nil, errors.Errorf(errFacilityMetroConflict, *s.Facility, *s.Metro)
+		case s.Facility != nil:
+			info.Facility = *s.Facility
+			facilities.Servers = append(facilities.Servers, info)
+			facilityIdx = append(facilityIdx, i)
+		case s.Metro != nil:
+			info.Metro = *s.Metro
+			metros.Servers = append(metros.Servers, info)
+			metroIdx = append(metroIdx, i)
+		default:
+			return nil, errors.Errorf(errFacilityMetroMissing, s.Plan)
+		}
+	}
+
+	availability := make([]v1alpha1.ServerAvailability, len(servers))
+	for _, check := range []struct {
+		fn    func(*packngo.CapacityInput) (*packngo.CapacityInput, *packngo.Response, error)
+		input *packngo.CapacityInput
+		idx   []int
+	}{
+		{fn: c.Check, input: facilities, idx: facilityIdx},
+		{fn: c.CheckMetros, input: metros, idx: metroIdx},
+	} {
+		if len(check.input.Servers) == 0 {
+			continue
+		}
+		checked, _, err := check.fn(check.input)
+		if err != nil {
+			return nil, err
+		}
+		if len(checked.Servers) != len(check.input.Servers) {
+			return nil, errors.Errorf(errUnexpectedServers, len(check.input.Servers), len(checked.Servers))
+		}
+		// The API does not echo every requested field, the request is used
+		// to describe each server and the response only for availability
+		for j, i := range check.idx {
+			requested := check.input.Servers[j]
+			availability[i] = v1alpha1.ServerAvailability{
+				Plan:      requested.Plan,
+				Facility:  requested.Facility,
+				Metro:     requested.Metro,
+				Quantity:  requested.Quantity,
+				Available: checked.Servers[j].Available,
+			}
+		}
+	}
+	return availability, nil
+}
+
+// GenerateObservation produces v1alpha1.CapacityCheckObservation from the
+// availability of each server. Capacity is only available when every server
+// is available.
+func GenerateObservation(servers []v1alpha1.ServerAvailability) v1alpha1.CapacityCheckObservation {
+	observation := v1alpha1.CapacityCheckObservation{
+		Available: len(servers) > 0,
+		Servers:   servers,
+	}
+	for _, s := range servers {
+		if !s.Available {
+			observation.Available = false
+		}
+	}
+	return observation
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/packethost/packngo"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/capacity"
+)
+
+var _ capacity.ClientWithDefaults = &MockClient{}
+
+// MockClient is a fake implementation of packngo.Client.
+type MockClient struct {
+	MockCheck       func(input *packngo.CapacityInput) (*packngo.CapacityInput, *packngo.Response, error)
+	MockCheckMetros func(input *packngo.CapacityInput) (*packngo.CapacityInput, *packngo.Response, error)
+
+	MockGetProjectID  func(string) string
+	MockGetFacilityID func(string) string
+	MockGetMetro      func(string) string
+}
+
+// Check calls the MockClient's MockCheck function.
+func (c *MockClient) Check(input *packngo.CapacityInput) (*packngo.CapacityInput, *packngo.Response, error) {
+	return c.MockCheck(input)
+}
+
+// CheckMetros calls the MockClient's MockCheckMetros function.
+func (c *MockClient) CheckMetros(input *packngo.CapacityInput) (*packngo.CapacityInput, *packngo.Response, error) {
+	return c.MockCheckMetros(input)
+}
+
+// GetFacilityID calls the MockClient's MockGetFacilityID function.
+func (c *MockClient) GetFacilityID(id string) string {
+	return c.MockGetFacilityID(id)
+}
+
+// GetMetro calls the MockClient's MockGetMetro function.
+func (c *MockClient) GetMetro(metro string) string {
+	return c.MockGetMetro(metro)
+}
+
+// GetProjectID calls the MockClient's MockGetProjectID function.
+func (c *MockClient) GetProjectID(id string) string {
+	return c.MockGetProjectID(id)
+}
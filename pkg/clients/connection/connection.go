@@ -0,0 +1,152 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"context"
+
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/connection/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+const (
+	errFacilityMetroConflict = "facility %q and metro %q can not both be set"
+)
+
+// Client implements the Equinix Metal API methods needed to interact with
+// Connections for the Equinix Metal Crossplane Provider
+type Client interface {
+	ProjectCreate(projectID string, createRequest *packngo.ConnectionCreateRequest) (*packngo.Connection, *packngo.Response, error)
+	Get(connectionID string, getOpt *packngo.GetOptions) (*packngo.Connection, *packngo.Response, error)
+	Delete(connectionID string) (*packngo.Response, error)
+}
+
+// build-time test that the interface is implemented
+var _ Client = (&packngo.Client{}).Connections
+
+// ClientWithDefaults is an interface that provides Connection services and
+// provides default values for common properties
+type ClientWithDefaults interface {
+	Client
+	clients.DefaultGetter
+}
+
+// CredentialedClient is a credentialed client to Equinix Metal Connection
+// services
+type CredentialedClient struct {
+	Client
+	*clients.Credentials
+}
+
+var _ ClientWithDefaults = &CredentialedClient{}
+
+// NewClient returns a Client implementing the Equinix Metal API methods needed
+// to interact with Connections for the Equinix Metal Crossplane Provider
+func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefaults, error) {
+	client, err := clients.NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	connectionClient := CredentialedClient{
+		Client:      client.Client.Connections,
+		Credentials: client.Credentials,
+	}
+	connectionClient.SetProjectID(config.ProjectID)
+	return connectionClient, nil
+}
+
+// CreateFromConnection returns packngo.ConnectionCreateRequest created from
+// Kubernetes
+func CreateFromConnection(c *v1alpha1.Connection) *packngo.ConnectionCreateRequest {
+	p := c.Spec.ForProvider
+	r := &packngo.ConnectionCreateRequest{
+		Name:        p.Name,
+		Description: p.Description,
+		Type:        packngo.ConnectionType(p.Type),
+		Redundancy:  packngo.ConnectionRedundancy(p.Redundancy),
+		Metro:       p.Metro,
+		Facility:    p.Facility,
+		Tags:        p.Tags,
+	}
+	if p.Speed != nil {
+		r.Speed = *p.Speed
+	}
+	return r
+}
+
+// ValidateLocation returns an error if the supplied Connection requests both a
+// metro and a facility
+func ValidateLocation(c *v1alpha1.Connection) error {
+	p := c.Spec.ForProvider
+	if p.Metro != "" && p.Facility != "" {
+		return errors.Errorf(errFacilityMetroConflict, p.Facility, p.Metro)
+	}
+	return nil
+}
+
+// GenerateObservation produces v1alpha1.ConnectionObservation from
+// packngo.Connection
+func GenerateObservation(conn *packngo.Connection) v1alpha1.ConnectionObservation {
+	o := v1alpha1.ConnectionObservation{
+		ID:           conn.ID,
+		Status:       conn.Status,
+		Speed:        conn.Speed,
+		ServiceToken: conn.Token,
+	}
+
+	if conn.Metro != nil {
+		o.Metro = conn.Metro.Code
+	}
+	if conn.Facility != nil {
+		o.Facility = conn.Facility.Code
+	}
+	if conn.Organization != nil {
+		o.OrganizationID = conn.Organization.ID
+	}
+
+	for _, p := range conn.Ports {
+		o.Ports = append(o.Ports, v1alpha1.ConnectionPortObservation{
+			ID:         p.ID,
+			Name:       p.Name,
+			Role:       string(p.Role),
+			Status:     p.Status,
+			LinkStatus: p.LinkStatus,
+			Speed:      p.Speed,
+		})
+	}
+
+	return o
+}
+
+// LateInitialize fills the empty fields in *v1alpha1.ConnectionParameters with
+// the values seen in packngo.Connection
+func LateInitialize(in *v1alpha1.ConnectionParameters, conn *packngo.Connection) {
+	if conn == nil {
+		return
+	}
+	if conn.Speed != 0 {
+		in.Speed = clients.LateInitializeIntPtr(in.Speed, &conn.Speed)
+	}
+}
+
+// IsDeprovisioning returns true if the Connection is being deprovisioned
+func IsDeprovisioning(conn *packngo.Connection) bool {
+	return conn.Status == v1alpha1.ConnectionStatusDeprovisioning
+}
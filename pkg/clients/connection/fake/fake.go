@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/packethost/packngo"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/connection"
+)
+
+var _ connection.ClientWithDefaults = &MockClient{}
+
+// MockClient is a fake implementation of packngo.Client.
+type MockClient struct {
+	MockProjectCreate func(projectID string, createRequest *packngo.ConnectionCreateRequest) (*packngo.Connection, *packngo.Response, error)
+	MockGet           func(connectionID string, getOpt *packngo.GetOptions) (*packngo.Connection, *packngo.Response, error)
+	MockDelete        func(connectionID string) (*packngo.Response, error)
+
+	MockGetProjectID  func(string) string
+	MockGetFacilityID func(string) string
+	MockGetMetro      func(string) string
+}
+
+// ProjectCreate calls the MockClient's MockProjectCreate function.
+func (c *MockClient) ProjectCreate(projectID string, createRequest *packngo.ConnectionCreateRequest) (*packngo.Connection, *packngo.Response, error) {
+	return c.MockProjectCreate(projectID, createRequest)
+}
+
+// Get calls the MockClient's MockGet function.
+func (c *MockClient) Get(connectionID string, getOpt *packngo.GetOptions) (*packngo.Connection, *packngo.Response, error) {
+	return c.MockGet(connectionID, getOpt)
+}
+
+// Delete calls the MockClient's MockDelete function.
+func (c *MockClient) Delete(connectionID string) (*packngo.Response, error) {
+	return c.MockDelete(connectionID)
+}
+
+// GetFacilityID calls the MockClient's MockGetFacilityID function.
+func (c *MockClient) GetFacilityID(id string) string {
+	return c.MockGetFacilityID(id)
+}
+
+// GetMetro calls the MockClient's MockGetMetro function.
+func (c *MockClient) GetMetro(metro string) string {
+	return c.MockGetMetro(metro)
+}
+
+// GetProjectID calls the MockClient's MockGetProjectID function.
+func (c *MockClient) GetProjectID(id string) string {
+	return c.MockGetProjectID(id)
+}
@@ -16,12 +16,49 @@ limitations under the License.
 
 package clients
 
+import "time"
+
 // Credentials is a common credential format used by various Equinix Metal Kubernetes
 // providers
 type Credentials struct {
 	APIKey     string `json:"apiKey"`
 	ProjectID  string `json:"projectID"`
 	FacilityID string `json:"facilityID"`
+	Metro      string `json:"metro"`
+
+	// RetryPolicy is configured by the ProviderConfig rather than the
+	// credentials. The DefaultRetryPolicy is used when none is set.
+	RetryPolicy *RetryPolicy `json:"-"`
+
+	// RequestTimeout is configured by the ProviderConfig rather than the
+	// credentials. The DefaultRequestTimeout is used when none is set.
+	RequestTimeout time.Duration `json:"-"`
+
+	// DefaultTags are configured by the ProviderConfig rather than the
+	// credentials. They are merged into the tags of created resources.
+	DefaultTags map[string]string `json:"-"`
+
+	// BaseURL is configured by the ProviderConfig rather than the
+	// credentials. The public Equinix Metal API is used when none is set.
+	BaseURL string `json:"-"`
+
+	// UserAgent is configured by the ProviderConfig rather than the
+	// credentials. It replaces the provider name and version in the
+	// User-Agent header when set.
+	UserAgent string `json:"-"`
+
+	// UniqueHostnames is configured by the ProviderConfig rather than the
+	// credentials. Devices are not created with the hostname of another
+	// Device of their project when it is set.
+	UniqueHostnames bool `json:"-"`
+
+	// DeprovisionWarningThreshold is configured by the ProviderConfig rather
+	// than the credentials. Devices deprovisioning for longer are reported.
+	DeprovisionWarningThreshold time.Duration `json:"-"`
+
+	// Kind is the kind of managed resource the credentials are used for. It
+	// labels the Equinix Metal API metrics.
+	Kind string `json:"-"`
 }
 
 // Using these constants causes Credential methods to return the credential
@@ -30,18 +67,21 @@ const (
 	CredentialAPIKey     = ""
 	CredentialProjectID  = ""
 	CredentialFacilityID = ""
+	CredentialMetro      = ""
 )
 
 // DefaultGetter provides setters for common Equinix Metal client properties
 type DefaultGetter interface {
 	GetProjectID(string) string
 	GetFacilityID(string) string
+	GetMetro(string) string
 }
 
 // DefaultSetter provides setters for common Equinix Metal client properties
 type DefaultSetter interface {
 	SetProjectID(string)
 	SetFacilityID(string)
+	SetMetro(string)
 }
 
 // Defaulter provides getter and setters for common Equinix Metal client properties
@@ -68,6 +108,15 @@ func (c *Credentials) GetFacilityID(facilityID string) string {
 	return c.FacilityID
 }
 
+// GetMetro returns the supplied Metro or the Metro included with the Client
+// credentials (if any)
+func (c *Credentials) GetMetro(metro string) string {
+	if metro != "" {
+		return metro
+	}
+	return c.Metro
+}
+
 // GetAPIKey returns the supplied APIKey or the APIKey included with the
 // Client credentials (if any)
 func (c *Credentials) GetAPIKey(apiKey string) string {
@@ -87,6 +136,11 @@ func (c *Credentials) SetFacilityID(facilityID string) {
 	c.FacilityID = facilityID
 }
 
+// SetMetro sets the default Metro for the client
+func (c *Credentials) SetMetro(metro string) {
+	c.Metro = metro
+}
+
 // SetAPIKey sets the default APIKey for the client
 func (c *Credentials) SetAPIKey(apiKey string) {
 	c.APIKey = apiKey
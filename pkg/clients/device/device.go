@@ -17,9 +17,20 @@ limitations under the License.
 package device
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
-	"reflect"
+	"math"
+	"mime/multipart"
+	"net/textproto"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/packethost/packngo"
 	"github.com/pkg/errors"
@@ -27,41 +38,200 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 
+	ipv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/ip/v1alpha1"
 	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
 	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	ipclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/ip"
+	portsclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/ports"
 )
 
 const (
-	errUnmarshalDate = "cannot unmarshal date"
+	errUnmarshalDate           = "cannot unmarshal date"
+	errReservationPlanConflict = "hardware reservation %s is for plan %q, not %q"
+	errFacilityMetroConflict   = "facility %q and metro %q can not both be set"
+	errFacilitiesConflict      = "facilities %q can not be set with a facility or metro"
+	errIPXEScriptURLOS         = "ipxeScriptUrl may only be set when operatingSystem is %q, not %q"
+	errNetworkTypeUnsupported  = "network type %q is not supported, use one of %q, %q, %q or %q"
+	errNetworkTypeFixed        = "plan %q only supports network type %q, not %q"
+	errNetworkTypesPlan        = "plan %q only supports network types %s, not %q"
+	errTerminationTimePast     = "termination time %s must be in the future"
+	errDecodeBase64            = "data is not valid base64"
+	errDataEncodingFmt         = "encoding %q is not supported, use %q or %q"
+	errVLANsNetworkType        = `vlans may only be set when networkType is "layer2-bonded", "layer2-individual" or "hybrid", not %q`
+	errSpotProtection          = "spotTerminationProtection is not supported by the Equinix Metal API, spot instances are terminated when the spot price exceeds spotPriceMax"
+	errSpotPriceMaxUpdate      = "spotPriceMax can not be updated from %s to %s, the Equinix Metal API does not support changing the bid of a spot instance"
+	errFeatureUnknown          = "feature %q is not supported, use one of %q, %q or %q"
+	errFeatureRequirement      = "feature %q must be %q or %q, not %q"
+	errFieldRequired           = "%s is required"
+	errStorageInvalid          = "storage is not a valid storage layout"
+	errSlugInvalid             = "%s %q must be a slug of lowercase letters, digits, dots, underscores and hyphens"
+	errHostnameInvalid         = "hostname %q must be at most %d characters of dot separated labels of letters, digits and hyphens that do not begin or end with a hyphen"
+	errNoPublicIPSubnetSize    = "publicIPv4SubnetSize can not be set when noPublicIP is true"
+	errNoPublicIPAddress       = "ipAddresses can not include public addresses when noPublicIP is true"
+	errIPAddressFamily         = "ipAddresses[%d] address family %d is not supported, use 4 or 6"
+	errIPAddressPrivateIPv6    = "ipAddresses[%d] IPv6 addresses must be public"
+	errIPAddressCIDR           = "ipAddresses[%d] cidr /%d of a %s address must be between /%d and /%d"
+	errIPReservationNotReady   = "IP reservation %q has not been created yet"
+	errIPReservationExhausted  = "IP reservation %q (%s) has no unassigned addresses"
+
+	// maxHostnameLength is the longest hostname accepted by the API
+	maxHostnameLength = 253
+
+	// MaxUserDataSize is the largest userdata, in bytes, accepted by the
+	// API. The same limit is applied to customdata.
+	MaxUserDataSize = 64 * 1024
+
+	devicePathFmt        = "devices/%s"
+	deviceActionsPathFmt = "devices/%s/actions"
+	actionReinstall      = "reinstall"
+
+	// networkPortsIncludes expand the virtual networks of the ports of a
+	// Device, and the VRFs that their Metal Gateways route to
+	networkPortsIncludes = "network_ports.virtual_networks.metal_gateways.vrf"
+
+	// ConnectionPublicIPv4Key is the connection secret key of the public
+	// management IPv4 address of the Device
+	ConnectionPublicIPv4Key = "publicIPv4"
+	// ConnectionPublicIPv4IndexedKeyFmt is the format of the connection secret
+	// keys of each public IPv4 address of the Device, management addresses
+	// first, so that publicIPv4-0 is the address of publicIPv4
+	ConnectionPublicIPv4IndexedKeyFmt = "publicIPv4-%d"
+	// ConnectionPrivateIPv4Key is the connection secret key of the private
+	// management IPv4 address of the Device
+	ConnectionPrivateIPv4Key = "privateIPv4"
+	// ConnectionPublicIPv6Key is the connection secret key of the public
+	// management IPv6 address of the Device
+	ConnectionPublicIPv6Key = "publicIPv6"
+	// ConnectionGatewayIPv4Key is the connection secret key of the gateway of
+	// the public management IPv4 address of the Device
+	ConnectionGatewayIPv4Key = "gatewayIPv4"
+
+	// HardwareReservationNextAvailable is the HardwareReservationID that
+	// requests any available reservation matching the Device plan
+	HardwareReservationNextAvailable = "next-available"
 )
 
 // Client implements the Equinix Metal API methods needed to interact with
 // Devices for the Equinix Metal Crossplane Provider
 type Client interface {
 	Get(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error)
+	List(projectID string, listOpt *packngo.ListOptions) ([]packngo.Device, *packngo.Response, error)
 	Create(*packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error)
 	Delete(deviceID string, force bool) (*packngo.Response, error)
 	Update(string, *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error)
+	PowerOn(deviceID string) (*packngo.Response, error)
+	PowerOff(deviceID string) (*packngo.Response, error)
+	Lock(deviceID string) (*packngo.Response, error)
+	Unlock(deviceID string) (*packngo.Response, error)
+	Reboot(deviceID string) (*packngo.Response, error)
 }
 
 // PortsClient implements the Equinix Metal API methods needed to interact with
 // Ports for the Equinix Metal Crossplane Provider
 type PortsClient interface {
 	DeviceToNetworkType(string, string) (*packngo.Device, error)
+	Assign(*packngo.PortAssignRequest) (*packngo.Port, *packngo.Response, error)
 	DeviceNetworkType(string) (string, error)
 	ConvertDevice(*packngo.Device, string) error
 }
 
+// ActionsClient implements the Equinix Metal API Device actions that are not
+// offered by packngo.DeviceService
+type ActionsClient interface {
+	Reinstall(deviceID string, reinstallRequest *ReinstallRequest) (*packngo.Response, error)
+}
+
+// StorageClient implements the Equinix Metal API methods needed to get the
+// storage details of Devices that are not offered by packngo
+type StorageClient interface {
+	GetIQN(deviceID string) (string, *packngo.Response, error)
+}
+
+// NetworkClient implements the Equinix Metal API methods needed to get the
+// virtual networks attached to Devices with the details not offered by packngo
+type NetworkClient interface {
+	GetNetworkPorts(deviceID string) ([]NetworkPort, *packngo.Response, error)
+}
+
+// NetworkPort is a port of a Device and the virtual networks attached to it
+type NetworkPort struct {
+	Name            string                   `json:"name"`
+	VirtualNetworks []AttachedVirtualNetwork `json:"virtual_networks"`
+}
+
+// AttachedVirtualNetwork is a virtual network attached to a port of a Device,
+// including the interconnection and VRF details not offered by packngo
+type AttachedVirtualNetwork struct {
+	ID    string `json:"id"`
+	VXLAN int    `json:"vxlan"`
+
+	// AssignedToVirtualCircuit is true when the virtual network is assigned
+	// to a virtual circuit of an interconnection
+	AssignedToVirtualCircuit bool `json:"assigned_to_virtual_circuit"`
+
+	MetalGateways []AttachedMetalGateway `json:"metal_gateways"`
+}
+
+// AttachedMetalGateway is a Metal Gateway of an attached virtual network,
+// which routes the virtual network to a VRF when it is a VRF Metal Gateway
+type AttachedMetalGateway struct {
+	VRF *AttachedVRF `json:"vrf,omitempty"`
+}
+
+// AttachedVRF is the VRF a Metal Gateway routes a virtual network to
+type AttachedVRF struct {
+	ID string `json:"id"`
+}
+
+// HardwareReservationsClient implements the Equinix Metal API methods needed
+// to interact with the Hardware Reservations of Devices
+type HardwareReservationsClient interface {
+	GetHardwareReservation(hardwareReservationID string) (*packngo.HardwareReservation, *packngo.Response, error)
+	MoveHardwareReservation(hardwareReservationID, projectID string) (*packngo.HardwareReservation, *packngo.Response, error)
+}
+
+// FacilitiesClient implements the Equinix Metal API methods needed to list the
+// facilities Devices may be provisioned in
+type FacilitiesClient interface {
+	ListFacilities(listOpt *packngo.ListOptions) ([]packngo.Facility, *packngo.Response, error)
+}
+
+// OperatingSystemsClient implements the Equinix Metal API methods needed to
+// list the operating systems Devices may be provisioned with
+type OperatingSystemsClient interface {
+	ListOperatingSystems() ([]packngo.OS, *packngo.Response, error)
+}
+
+// ReinstallRequest is the body of a Device reinstall action
+type ReinstallRequest struct {
+	Type            string `json:"type"`
+	OperatingSystem string `json:"operating_system,omitempty"`
+	PreserveData    bool   `json:"preserve_data,omitempty"`
+	DeprovisionFast bool   `json:"deprovision_fast,omitempty"`
+}
+
 // build-time test that the interface is implemented
 var _ Client = (&packngo.Client{}).Devices
 var _ PortsClient = (&packngo.Client{}).DevicePorts //nolint:staticcheck
+var _ ActionsClient = &actionsClient{}
+var _ HardwareReservationsClient = &hardwareReservationsClient{}
+var _ StorageClient = &storageClient{}
+var _ NetworkClient = &networkClient{}
 
 // ClientWithDefaults is an interface that provides Device services and
 // provides default values for common properties
 type ClientWithDefaults interface {
 	Client
 	PortsClient
+	ActionsClient
+	StorageClient
+	NetworkClient
+	HardwareReservationsClient
+	FacilitiesClient
+	OperatingSystemsClient
 	clients.DefaultGetter
 }
 
@@ -69,11 +239,104 @@ type ClientWithDefaults interface {
 type CredentialedClient struct {
 	Client
 	PortsClient
+	ActionsClient
+	StorageClient
+	NetworkClient
+	HardwareReservationsClient
+	FacilitiesClient
+	OperatingSystemsClient
 	*clients.Credentials
 }
 
+// actionsClient performs Device actions through the packngo request helpers
+type actionsClient struct {
+	client *packngo.Client
+}
+
+// Reinstall reinstalls the operating system of a Device
+func (c *actionsClient) Reinstall(deviceID string, reinstallRequest *ReinstallRequest) (*packngo.Response, error) {
+	reinstallRequest.Type = actionReinstall
+	return c.client.DoRequest("POST", fmt.Sprintf(deviceActionsPathFmt, deviceID), reinstallRequest, nil)
+}
+
+// storageClient gets the storage details of Devices through the packngo
+// request helpers
+type storageClient struct {
+	client *packngo.Client
+}
+
+// GetIQN gets the iSCSI qualified name of a Device
+func (c *storageClient) GetIQN(deviceID string) (string, *packngo.Response, error) {
+	d := &struct {
+		IQN string `json:"iqn"`
+	}{}
+	resp, err := c.client.DoRequest("GET", fmt.Sprintf(devicePathFmt, deviceID), nil, d)
+	if err != nil {
+		return "", resp, err
+	}
+	return d.IQN, resp, nil
+}
+
+// networkClient gets the virtual networks attached to Devices through the
+// packngo request helpers
+type networkClient struct {
+	client *packngo.Client
+}
+
+// GetNetworkPorts gets the ports of a Device and their attached virtual
+// networks
+func (c *networkClient) GetNetworkPorts(deviceID string) ([]NetworkPort, *packngo.Response, error) {
+	d := &struct {
+		NetworkPorts []NetworkPort `json:"network_ports"`
+	}{}
+	path := fmt.Sprintf(devicePathFmt, deviceID) + "?include=" + networkPortsIncludes
+	resp, err := c.client.DoRequest("GET", path, nil, d)
+	if err != nil {
+		return nil, resp, err
+	}
+	return d.NetworkPorts, resp, nil
+}
+
+// hardwareReservationsClient gets Hardware Reservations without colliding with
+// the Get method of packngo.DeviceService
+type hardwareReservationsClient struct {
+	reservations packngo.HardwareReservationService
+}
+
+// GetHardwareReservation gets a Hardware Reservation by ID
+func (c *hardwareReservationsClient) GetHardwareReservation(hardwareReservationID string) (*packngo.HardwareReservation, *packngo.Response, error) {
+	return c.reservations.Get(hardwareReservationID, nil)
+}
+
+// MoveHardwareReservation moves a Hardware Reservation to another project
+func (c *hardwareReservationsClient) MoveHardwareReservation(hardwareReservationID, projectID string) (*packngo.HardwareReservation, *packngo.Response, error) {
+	return c.reservations.Move(hardwareReservationID, projectID)
+}
+
 var _ ClientWithDefaults = &CredentialedClient{}
 
+// facilitiesClient lists facilities without colliding with the methods of
+// packngo.DeviceService
+type facilitiesClient struct {
+	facilities packngo.FacilityService
+}
+
+// ListFacilities lists all facilities
+func (c *facilitiesClient) ListFacilities(listOpt *packngo.ListOptions) ([]packngo.Facility, *packngo.Response, error) {
+	return c.facilities.List(listOpt)
+}
+
+// operatingSystemsClient lists operating systems without colliding with the
+// methods of packngo.DeviceService
+type operatingSystemsClient struct {
+	operatingSystems packngo.OSService
+}
+
+// ListOperatingSystems lists all operating systems
+func (c *operatingSystemsClient) ListOperatingSystems() ([]packngo.OS, *packngo.Response, error) {
+	return c.operatingSystems.List()
+}
+
 // NewClient returns a Client implementing the Equinix Metal API methods needed
 // to interact with Devices for the Equinix Metal Crossplane Provider
 func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefaults, error) {
@@ -82,16 +345,33 @@ func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefa
 		return nil, err
 	}
 	deviceClient := CredentialedClient{
-		Client:      client.Client.Devices,
-		PortsClient: client.Client.DevicePorts, //nolint:staticcheck
+		Client:        client.Client.Devices,
+		PortsClient:   client.Client.DevicePorts, //nolint:staticcheck
+		ActionsClient: &actionsClient{client: client.Client},
+		StorageClient: &storageClient{client: client.Client},
+		NetworkClient: &networkClient{client: client.Client},
+		HardwareReservationsClient: &hardwareReservationsClient{
+			reservations: client.Client.HardwareReservations,
+		},
+		FacilitiesClient: &facilitiesClient{
+			facilities: client.Client.Facilities,
+		},
+		OperatingSystemsClient: &operatingSystemsClient{
+			operatingSystems: client.Client.OperatingSystems,
+		},
 		Credentials: client.Credentials,
 	}
 	deviceClient.SetProjectID(config.ProjectID)
 	return deviceClient, nil
 }
 
-// CreateFromDevice return packngo.DeviceCreateRequest created from Kubernetes
-func CreateFromDevice(d *v1alpha2.Device, projectID string) *packngo.DeviceCreateRequest {
+// CreateFromDevice return packngo.DeviceCreateRequest created from Kubernetes.
+// An error is returned if the Device fails ValidateDeviceParameters.
+func CreateFromDevice(d *v1alpha2.Device, projectID string) (*packngo.DeviceCreateRequest, error) {
+	if err := ValidateDeviceParameters(d); err != nil {
+		return nil, err
+	}
+
 	ips := []packngo.IPAddressCreateRequest{}
 	for _, ip := range d.Spec.ForProvider.IPAddresses {
 		ips = append(ips, packngo.IPAddressCreateRequest{
@@ -101,11 +381,16 @@ func CreateFromDevice(d *v1alpha2.Device, projectID string) *packngo.DeviceCreat
 			Reservations:  ip.Reservations,
 		})
 	}
+	// Without addresses the API assigns public addresses, so a private
+	// address is requested explicitly
+	if falseIfNil(d.Spec.ForProvider.NoPublicIP) && len(ips) == 0 {
+		ips = append(ips, packngo.IPAddressCreateRequest{AddressFamily: 4, Public: false})
+	}
 
 	r := &packngo.DeviceCreateRequest{
-		Hostname:              emptyIfNil(d.Spec.ForProvider.Hostname),
+		Hostname:              Hostname(d),
+		Description:           emptyIfNil(d.Spec.ForProvider.Description),
 		Plan:                  d.Spec.ForProvider.Plan,
-		Facility:              []string{d.Spec.ForProvider.Facility},
 		Metro:                 d.Spec.ForProvider.Metro,
 		OS:                    d.Spec.ForProvider.OS,
 		BillingCycle:          emptyIfNil(d.Spec.ForProvider.BillingCycle),
@@ -121,15 +406,33 @@ func CreateFromDevice(d *v1alpha2.Device, projectID string) *packngo.DeviceCreat
 		Features:              d.Spec.ForProvider.Features,
 		UserSSHKeys:           d.Spec.ForProvider.UserSSHKeys,
 		ProjectSSHKeys:        d.Spec.ForProvider.ProjectSSHKeys,
+		SpotInstance:          falseIfNil(d.Spec.ForProvider.SpotInstance),
+	}
 
-		// TODO:
-		// Storage
-		// SpotInstance
-		// SpotPriceMax
-		// TerminationTime
+	// Storage was validated, and is known to parse
+	if d.Spec.ForProvider.Storage != nil {
+		r.Storage, _ = ParseStorage(*d.Spec.ForProvider.Storage)
 	}
 
-	return r
+	if d.Spec.ForProvider.TerminationTime != nil {
+		r.TerminationTime = &packngo.Timestamp{Time: d.Spec.ForProvider.TerminationTime.Time}
+	}
+
+	if d.Spec.ForProvider.SpotPriceMax != nil {
+		// The Equinix Metal API accepts bids in dollars with a precision of
+		// cents
+		r.SpotPriceMax = float64(d.Spec.ForProvider.SpotPriceMax.MilliValue()) / 1000
+	}
+
+	// Metro is an alternative to Facility, the API rejects empty facilities
+	if d.Spec.ForProvider.Facility != "" {
+		r.Facility = []string{d.Spec.ForProvider.Facility}
+	}
+	if len(d.Spec.ForProvider.Facilities) > 0 {
+		r.Facility = d.Spec.ForProvider.Facilities
+	}
+
+	return r, nil
 }
 
 func emptyIfNil(in *string) string {
@@ -156,41 +459,236 @@ func falseIfNil(in *bool) bool {
 // GetConnectionDetails extracts managed.ConnectionDetails out of
 // packngo.Device.
 func GetConnectionDetails(device *packngo.Device) managed.ConnectionDetails {
-	// RootPassword is only in the device responses for 24h
-	// TODO(displague) Handle devices without public IPv4
-	if device.RootPassword == "" || device.GetNetworkInfo().PublicIPv4 == "" {
-		return managed.ConnectionDetails{}
+	details := managed.ConnectionDetails{}
+
+	// Devices that are provisioning may not have been assigned addresses yet,
+	// only the addresses that are present are included
+	var publicIPv4, elasticIPv4 []string
+	for _, ip := range device.Network {
+		if ip == nil || ip.Address == "" || ip.AddressFamily != 4 || !ip.Public {
+			continue
+		}
+		if ip.Management {
+			publicIPv4 = append(publicIPv4, ip.Address)
+		} else {
+			elasticIPv4 = append(elasticIPv4, ip.Address)
+		}
+	}
+	for i, address := range append(publicIPv4, elasticIPv4...) {
+		details[fmt.Sprintf(ConnectionPublicIPv4IndexedKeyFmt, i)] = []byte(address)
+	}
+
+	for _, ip := range device.Network {
+		if ip == nil || !ip.Management {
+			continue
+		}
+		switch {
+		case ip.AddressFamily == 4 && ip.Public:
+			setConnectionDetail(details, ConnectionPublicIPv4Key, ip.Address)
+			setConnectionDetail(details, ConnectionGatewayIPv4Key, ip.Gateway)
+		case ip.AddressFamily == 4:
+			setConnectionDetail(details, ConnectionPrivateIPv4Key, ip.Address)
+		case ip.AddressFamily == 6 && ip.Public:
+			setConnectionDetail(details, ConnectionPublicIPv6Key, ip.Address)
+		}
+	}
+
+	// The endpoint follows the current public IPv4 address so that the
+	// connection secret stays current when a device is reinstalled or its
+	// addresses change. Devices without public IPv4, such as devices
+	// provisioned with noPublicIP, only publish their private addresses.
+	endpoint, ok := details[ConnectionPublicIPv4Key]
+	if !ok {
+		return details
 	}
 
 	// TODO(displague) device.User is in the API but not included in packngo
 	user := "root"
 	port := "22" // ssh
 
-	return managed.ConnectionDetails{
-		xpv1.ResourceCredentialsSecretEndpointKey: []byte(device.GetNetworkInfo().PublicIPv4),
-		xpv1.ResourceCredentialsSecretUserKey:     []byte(user),
-		xpv1.ResourceCredentialsSecretPasswordKey: []byte(device.RootPassword),
-		xpv1.ResourceCredentialsSecretPortKey:     []byte(port),
+	details[xpv1.ResourceCredentialsSecretEndpointKey] = endpoint
+	details[xpv1.ResourceCredentialsSecretUserKey] = []byte(user)
+	details[xpv1.ResourceCredentialsSecretPortKey] = []byte(port)
+
+	// RootPassword is only in the device responses for 24h, the previously
+	// published password is retained in the secret after it expires
+	if device.RootPassword != "" {
+		details[xpv1.ResourceCredentialsSecretPasswordKey] = []byte(device.RootPassword)
+	}
+
+	return details
+}
+
+// setConnectionDetail sets the key to the first non-empty value seen
+func setConnectionDetail(details managed.ConnectionDetails, key, value string) {
+	if _, ok := details[key]; ok || value == "" {
+		return
+	}
+	details[key] = []byte(value)
+}
+
+// ObserveOptions expand the SSH keys of a Device so that the owners of the
+// keys can be observed
+var ObserveOptions = &packngo.GetOptions{Includes: []string{"ssh_keys"}}
+
+// Kinds of the owners of SSH keys, as named in the owner href
+const (
+	ownerKindProject = "projects"
+	ownerKindUser    = "users"
+)
+
+// sshKeyOwnerKind returns the kind of the owner of an SSH key from an owner
+// href such as /projects/{id}
+func sshKeyOwnerKind(href string) string {
+	href = strings.TrimSuffix(href, "/")
+	if href == "" {
+		return ""
+	}
+	return path.Base(path.Dir(href))
+}
+
+// HasAttachedVirtualNetworks returns true if virtual networks are attached to
+// any port of the supplied Device
+func HasAttachedVirtualNetworks(p *packngo.Device) bool {
+	for _, port := range p.NetworkPorts {
+		if len(port.AttachedVirtualNetworks) > 0 {
+			return true
+		}
 	}
+	return false
+}
+
+// GenerateNetworkAttachments produces the v1alpha2.DeviceNetworkAttachments of
+// the virtual networks attached to the supplied ports
+func GenerateNetworkAttachments(ports []NetworkPort) []v1alpha2.DeviceNetworkAttachment {
+	var attachments []v1alpha2.DeviceNetworkAttachment
+	for _, port := range ports {
+		for _, vn := range port.VirtualNetworks {
+			a := v1alpha2.DeviceNetworkAttachment{
+				Port:            port.Name,
+				VLANID:          vn.ID,
+				VXLAN:           vn.VXLAN,
+				Interconnection: vn.AssignedToVirtualCircuit,
+			}
+			for _, gw := range vn.MetalGateways {
+				if gw.VRF != nil && gw.VRF.ID != "" {
+					a.VRFs = append(a.VRFs, gw.VRF.ID)
+				}
+			}
+			attachments = append(attachments, a)
+		}
+	}
+	return attachments
 }
 
 // GenerateObservation produces v1alpha2.DeviceObservation from packngo.Device
 func GenerateObservation(device *packngo.Device) (v1alpha2.DeviceObservation, error) {
 	// Update device status
 	observation := v1alpha2.DeviceObservation{
-		ID:     device.ID,
-		Href:   device.Href,
-		State:  device.State,
-		Locked: device.Locked,
-		IPv4:   device.GetNetworkInfo().PublicIPv4,
+		ID:       device.ID,
+		Href:     device.Href,
+		State:    device.State,
+		Hostname: device.Hostname,
+		Locked:   device.Locked,
+		IPv4:     device.GetNetworkInfo().PublicIPv4,
+
+		PrivateIPv4: device.GetNetworkInfo().PrivateIPv4,
+	}
+
+	for _, ip := range device.Network {
+		if ip == nil {
+			continue
+		}
+		if ip.Public {
+			observation.PublicIP = true
+		}
+		observation.IPAddresses = append(observation.IPAddresses, v1alpha2.DeviceIPAddress{
+			Address:       ip.Address,
+			AddressFamily: ip.AddressFamily,
+			Public:        ip.Public,
+			CIDR:          ip.CIDR,
+			Management:    ip.Management,
+		})
 	}
 
 	if device.Facility != nil {
 		observation.Facility = device.Facility.Code
 	}
 
-	// TODO: investigate better way to do this
-	observation.ProvisionPercentage = apiresource.MustParse(fmt.Sprintf("%.6f", device.ProvisionPer))
+	if device.Metro != nil {
+		observation.Metro = device.Metro.Code
+	}
+
+	if device.HardwareReservation != nil {
+		observation.HardwareReservationID = device.HardwareReservation.ID
+	}
+
+	// Devices without private networks or volumes leave them unset
+	for _, n := range device.Network {
+		if n == nil {
+			continue
+		}
+		if !n.Public && n.AddressFamily == 4 && n.Network != "" {
+			observation.PrivateNetworks = append(observation.PrivateNetworks, fmt.Sprintf("%s/%d", n.Network, n.CIDR))
+		}
+	}
+	for _, v := range device.Volumes {
+		id := v.ID
+		if id == "" && v.Href != "" {
+			id = path.Base(v.Href)
+		}
+		if id != "" {
+			observation.Volumes = append(observation.Volumes, id)
+		}
+	}
+
+	observation.CustomDataSet = len(device.CustomData) > 0
+	observation.StorageSet = device.Storage != nil
+
+	for _, key := range device.SSHKeys {
+		id := key.ID
+		if id == "" && key.URL != "" {
+			id = path.Base(key.URL)
+		}
+		if id == "" {
+			continue
+		}
+		observation.SSHKeys = append(observation.SSHKeys, id)
+		switch sshKeyOwnerKind(key.Owner.Href) {
+		case ownerKindProject:
+			observation.ProjectSSHKeys = append(observation.ProjectSSHKeys, id)
+		case ownerKindUser:
+			observation.UserSSHKeys = append(observation.UserSSHKeys, id)
+		}
+	}
+
+	if device.SpotInstance {
+		observation.SpotInstance = true
+		if device.SpotPriceMax > 0 {
+			if q, err := apiresource.ParseQuantity(strconv.FormatFloat(device.SpotPriceMax, 'f', -1, 64)); err == nil {
+				observation.SpotPriceMax = &q
+			}
+		}
+	}
+
+	if device.TerminationTime != nil {
+		observation.TerminationTime = &metav1.Time{Time: device.TerminationTime.Time}
+	}
+
+	if device.Plan != nil && device.Plan.Specs != nil && device.Plan.Specs.Features != nil {
+		if device.Plan.Specs.Features.Raid {
+			observation.Features = append(observation.Features, v1alpha2.FeatureRAID)
+		}
+		if device.Plan.Specs.Features.Txt {
+			observation.Features = append(observation.Features, v1alpha2.FeatureTXT)
+		}
+	}
+
+	// The provisioning percentage may be omitted, in which case it is
+	// observed as zero. Values that can not be represented are ignored.
+	if pct, err := apiresource.ParseQuantity(strconv.FormatFloat(float64(device.ProvisionPer), 'f', -1, 32)); err == nil {
+		observation.ProvisionPercentage = pct
+	}
 
 	if !observation.CreatedAt.IsZero() {
 		if err := observation.CreatedAt.UnmarshalText([]byte(device.Created)); err != nil {
@@ -206,16 +704,32 @@ func GenerateObservation(device *packngo.Device) (v1alpha2.DeviceObservation, er
 	return observation, nil
 }
 
+// ProvisioningMessage describes the progress of a device that is being
+// provisioned or reinstalled, such as "provisioning 45%". An empty string is
+// returned when the API did not report a percentage.
+func ProvisioningMessage(device *packngo.Device) string {
+	pct := float64(device.ProvisionPer)
+	if pct <= 0 || math.IsNaN(pct) || math.IsInf(pct, 0) {
+		return ""
+	}
+	return fmt.Sprintf("%s %d%%", device.State, int(math.Min(pct, 100)))
+}
+
 // LateInitialize fills the empty fields in *v1alpha2.DeviceParameters with the
-// values seen in packngo.Device
-func LateInitialize(in *v1alpha2.DeviceParameters, device *packngo.Device) {
+// values seen in packngo.Device. It returns the fields that were filled, named
+// as they are serialized in the Device parameters.
+func LateInitialize(in *v1alpha2.DeviceParameters, device *packngo.Device) []string {
 	if device == nil {
-		return
+		return nil
 	}
+	before := in.DeepCopy()
 
 	if device.OS != nil {
 		in.OS = clients.LateInitializeString(in.OS, &device.OS.Slug)
 	}
+	if device.Project != nil {
+		in.ProjectID = clients.LateInitializeString(in.ProjectID, &device.Project.ID)
+	}
 
 	if device.Plan != nil {
 		in.Plan = clients.LateInitializeString(in.Plan, &device.Plan.Slug)
@@ -227,10 +741,25 @@ func LateInitialize(in *v1alpha2.DeviceParameters, device *packngo.Device) {
 	in.Hostname = clients.LateInitializeStringPtr(in.Hostname, &device.Hostname)
 	in.BillingCycle = clients.LateInitializeStringPtr(in.BillingCycle, &device.BillingCycle)
 	in.IPXEScriptURL = clients.LateInitializeStringPtr(in.IPXEScriptURL, &device.IPXEScriptURL)
-	in.UserData = clients.LateInitializeStringPtr(in.UserData, &device.UserData)
+	// UserData is resolved from UserDataRef or UserDataRefs, when set, and must not be copied
+	// into the spec where it would mask later changes to the reference. The
+	// API returns plain userdata, which is not copied into encoded UserData.
+	if in.UserDataRef == nil && len(in.UserDataRefs) == 0 && emptyIfNil(in.UserDataEncoding) != v1alpha2.DataEncodingBase64 {
+		in.UserData = clients.LateInitializeStringPtr(in.UserData, &device.UserData)
+	}
 	in.AlwaysPXE = clients.LateInitializeBoolPtr(in.AlwaysPXE, &device.AlwaysPXE)
 	in.Locked = clients.LateInitializeBoolPtr(in.Locked, &device.Locked)
 
+	// Spot market settings are only recorded for spot instances, leaving
+	// them unset for on-demand devices
+	if device.SpotInstance {
+		in.SpotInstance = clients.LateInitializeBoolPtr(in.SpotInstance, &device.SpotInstance)
+		if in.SpotPriceMax == nil && device.SpotPriceMax > 0 {
+			q := apiresource.MustParse(strconv.FormatFloat(device.SpotPriceMax, 'f', -1, 64))
+			in.SpotPriceMax = &q
+		}
+	}
+
 	for _, n := range device.Network {
 		if n.Public && n.AddressFamily == 4 {
 			in.PublicIPv4SubnetSize = clients.LateInitializeIntPtr(in.PublicIPv4SubnetSize, &n.CIDR)
@@ -247,52 +776,763 @@ func LateInitialize(in *v1alpha2.DeviceParameters, device *packngo.Device) {
 	// What's the format? Should it always be a map in k8s?
 	// in.CustomData = device.CustomData
 
-	// TODO(displague) Description is not yet supported
-	// in.Description = device.Description
+	if in.Description == nil {
+		in.Description = device.Description
+	}
 
 	if in.Tags == nil {
-		in.Tags = device.Tags
+		in.Tags = withoutCreateTag(device.Tags)
 	}
+
+	return lateInitializedFields(before, in)
 }
 
-// IsUpToDate returns true if the supplied Kubernetes resource does not differ
-// from the supplied Equinix Metal resource. It considers only fields that can be
-// modified in place without deleting and recreating the instance, which are
-// immutable.
-func IsUpToDate(d *v1alpha2.Device, p *packngo.Device) (upToDate bool, networkTypeUpToDate bool) {
-	networkType := p.GetNetworkType()
-	networkIsUpToDate := nilOrEqualStr(d.Spec.ForProvider.NetworkType, networkType)
+// lateInitializedFields returns the fields that are empty in before but set in
+// after, which LateInitialize filled
+func lateInitializedFields(before, after *v1alpha2.DeviceParameters) []string {
+	filled := []struct {
+		field  string
+		filled bool
+	}{
+		{"operatingSystem", before.OS == "" && after.OS != ""},
+		{"projectId", before.ProjectID == "" && after.ProjectID != ""},
+		{"plan", before.Plan == "" && after.Plan != ""},
+		{FieldNetworkType, before.NetworkType == nil && after.NetworkType != nil},
+		{FieldHostname, before.Hostname == nil && after.Hostname != nil},
+		{"billingCycle", before.BillingCycle == nil && after.BillingCycle != nil},
+		{FieldIPXEScriptURL, before.IPXEScriptURL == nil && after.IPXEScriptURL != nil},
+		{FieldUserData, before.UserData == nil && after.UserData != nil},
+		{FieldAlwaysPXE, before.AlwaysPXE == nil && after.AlwaysPXE != nil},
+		{FieldLocked, before.Locked == nil && after.Locked != nil},
+		{"spotInstance", before.SpotInstance == nil && after.SpotInstance != nil},
+		{FieldSpotPriceMax, before.SpotPriceMax == nil && after.SpotPriceMax != nil},
+		{"publicIPv4SubnetSize", before.PublicIPv4SubnetSize == nil && after.PublicIPv4SubnetSize != nil},
+		{FieldDescription, before.Description == nil && after.Description != nil},
+		{FieldTags, before.Tags == nil && after.Tags != nil},
+	}
+	var fields []string
+	for _, f := range filled {
+		if f.filled {
+			fields = append(fields, f.field)
+		}
+	}
+	return fields
+}
+
+// Fields of a Device that are compared with the Equinix Metal resource, named
+// as they are serialized in the Device parameters.
+const (
+	FieldHostname      = "hostname"
+	FieldDescription   = "description"
+	FieldUserData      = "userdata"
+	FieldPowerState    = "powerState"
+	FieldIPXEScriptURL = "ipxeScriptUrl"
+	FieldLocked        = "locked"
+	FieldAlwaysPXE     = "alwaysPxe"
+	FieldTags          = "tags"
+	FieldNetworkType   = "networkType"
+	FieldRebootToken   = "rebootToken"
+
+	FieldSpotTerminationProtection = "spotTerminationProtection"
+	FieldSpotPriceMax              = "spotPriceMax"
+	FieldVLANs                     = "vlans"
+)
+
+// DeviceDiff describes the fields of a Kubernetes resource that differ from
+// the Equinix Metal resource.
+type DeviceDiff struct {
+	Fields []string
+}
+
+// Has returns true if the supplied field differs.
+func (d DeviceDiff) Has(field string) bool {
+	for _, f := range d.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the differing fields as a comma separated list.
+func (d DeviceDiff) String() string {
+	return strings.Join(d.Fields, ", ")
+}
+
+// Diff returns the fields of the supplied Kubernetes resource that differ
+// from the supplied Equinix Metal resource. It considers only fields that can
+// be modified in place without deleting and recreating the instance, which
+// are immutable.
+func Diff(d *v1alpha2.Device, p *packngo.Device) DeviceDiff {
+	diff := DeviceDiff{}
 
 	if !nilOrEqualStr(d.Spec.ForProvider.Hostname, p.Hostname) {
-		return false, networkIsUpToDate
+		diff.Fields = append(diff.Fields, FieldHostname)
+	}
+	if !IsUserDataUpToDate(d, p) {
+		diff.Fields = append(diff.Fields, FieldUserData)
 	}
-	if !nilOrEqualStr(d.Spec.ForProvider.UserData, p.UserData) {
-		return false, networkIsUpToDate
+	if !IsPowerStateUpToDate(d, p) {
+		diff.Fields = append(diff.Fields, FieldPowerState)
 	}
 	if !nilOrEqualStr(d.Spec.ForProvider.IPXEScriptURL, p.IPXEScriptURL) {
-		return false, networkIsUpToDate
+		diff.Fields = append(diff.Fields, FieldIPXEScriptURL)
+	}
+	if !IsLockUpToDate(d, p) {
+		diff.Fields = append(diff.Fields, FieldLocked)
+	}
+	if !nilOrEqualBool(d.Spec.ForProvider.AlwaysPXE, p.AlwaysPXE) {
+		diff.Fields = append(diff.Fields, FieldAlwaysPXE)
 	}
 
-	if !nilOrEqualBool(d.Spec.ForProvider.Locked, p.Locked) {
-		return false, networkIsUpToDate
+	if !nilOrEqualStrPtr(d.Spec.ForProvider.Description, p.Description) {
+		diff.Fields = append(diff.Fields, FieldDescription)
 	}
 
-	if !nilOrEqualBool(d.Spec.ForProvider.AlwaysPXE, p.AlwaysPXE) {
-		return false, networkIsUpToDate
+	// CustomData can only be provided when the device is created, drift is
+	// not reconciled
+
+	if !equalTags(d.Spec.ForProvider.Tags, withoutCreateTag(p.Tags)) {
+		diff.Fields = append(diff.Fields, FieldTags)
+	}
+	if !nilOrEqualStr(d.Spec.ForProvider.NetworkType, p.GetNetworkType()) {
+		diff.Fields = append(diff.Fields, FieldNetworkType)
+	}
+	if IsRebootRequested(d) {
+		diff.Fields = append(diff.Fields, FieldRebootToken)
+	}
+	if len(MissingVLANs(d, p)) > 0 {
+		diff.Fields = append(diff.Fields, FieldVLANs)
 	}
+	// Devices are never protected, those requesting protection are updated
+	// so that Update reports it is unsupported
+	if !nilOrEqualBool(d.Spec.ForProvider.SpotTerminationProtection, false) {
+		diff.Fields = append(diff.Fields, FieldSpotTerminationProtection)
+	}
+	// The bid of a spot instance can not be updated, those bidding another
+	// price are updated so that Update reports it is unsupported
+	if !IsSpotPriceMaxUpToDate(d, p) {
+		diff.Fields = append(diff.Fields, FieldSpotPriceMax)
+	}
+
+	return diff
+}
 
-	// TODO(displague) CustomData is string vs map[string]interface{}
-	/* TODO(displague) missing: https://github.com/packethost/packngo/pull/182
-	if d.Spec.ForProvider.Description != p.Description {
+// IsUpToDate returns true if the supplied Kubernetes resource does not differ
+// from the supplied Equinix Metal resource. The network type, which is updated
+// separately from the other fields, is reported on its own. See Diff for the
+// fields that are compared.
+func IsUpToDate(d *v1alpha2.Device, p *packngo.Device) (upToDate bool, networkTypeUpToDate bool) {
+	diff := Diff(d, p)
+	networkTypeUpToDate = !diff.Has(FieldNetworkType)
+	upToDate = len(diff.Fields) == 0 || (len(diff.Fields) == 1 && !networkTypeUpToDate)
+	return upToDate, networkTypeUpToDate
+}
+
+// IsUpdateRequired returns true if the supplied Kubernetes resource differs
+// from the supplied Equinix Metal resource in a field that is set by an
+// update request.
+func IsUpdateRequired(d *v1alpha2.Device, p *packngo.Device) bool {
+	diff := Diff(d, p)
+	for _, f := range []string{FieldHostname, FieldDescription, FieldIPXEScriptURL, FieldAlwaysPXE, FieldTags} {
+		if diff.Has(f) {
+			return true
+		}
+	}
+	return false
+}
+
+// FailureThreshold returns the number of consecutive failed observations
+// after which the FailurePolicy of the supplied Kubernetes resource acts, or
+// zero when it has no policy
+func FailureThreshold(d *v1alpha2.Device) int {
+	p := d.Spec.ForProvider.FailurePolicy
+	if p == nil {
+		return 0
+	}
+	if p.Threshold != nil {
+		return *p.Threshold
+	}
+	return v1alpha2.DefaultFailureThreshold
+}
+
+// IsPersistentlyFailed returns true if the supplied Kubernetes resource has
+// been observed as failed at least as many times in a row as the threshold
+// of its FailurePolicy
+func IsPersistentlyFailed(d *v1alpha2.Device) bool {
+	t := FailureThreshold(d)
+	return t > 0 && d.Status.AtProvider.FailedObservations >= t
+}
+
+// IsFailureReinstallDue returns true if the supplied Kubernetes resource is
+// persistently failed and its FailurePolicy reinstalls failed devices
+func IsFailureReinstallDue(d *v1alpha2.Device) bool {
+	return IsPersistentlyFailed(d) && d.Spec.ForProvider.FailurePolicy.Action == v1alpha2.FailureActionReinstall
+}
+
+// IsRebootRequested returns true if the supplied Kubernetes resource requests
+// a reboot with a RebootToken that has not yet been processed
+func IsRebootRequested(d *v1alpha2.Device) bool {
+	t := emptyIfNil(d.Spec.ForProvider.RebootToken)
+	return t != "" && t != d.Status.AtProvider.LastRebootToken
+}
+
+// IsUserDataUpToDate returns true if the userdata of the supplied Kubernetes
+// resource does not differ from the userdata of the supplied Equinix Metal
+// resource. Userdata referenced by UserDataRef must be resolved into UserData
+// by the caller.
+func IsUserDataUpToDate(d *v1alpha2.Device, p *packngo.Device) bool {
+	return nilOrEqualStr(d.Spec.ForProvider.UserData, p.UserData)
+}
+
+// IsLockUpToDate returns true if the supplied Equinix Metal resource is locked,
+// or unlocked, as desired by the supplied Kubernetes resource.
+func IsLockUpToDate(d *v1alpha2.Device, p *packngo.Device) bool {
+	return nilOrEqualBool(d.Spec.ForProvider.Locked, p.Locked)
+}
+
+// IsPowerStateUpToDate returns true if the supplied Equinix Metal resource is
+// in, or is transitioning to, the power state desired by the supplied
+// Kubernetes resource. Devices in other states, such as provisioning, can not
+// be powered on or off and are considered up to date.
+func IsPowerStateUpToDate(d *v1alpha2.Device, p *packngo.Device) bool {
+	switch emptyIfNil(d.Spec.ForProvider.PowerState) {
+	case v1alpha2.PowerStateOn:
+		return p.State != v1alpha2.StateInactive
+	case v1alpha2.PowerStateOff:
+		return p.State != v1alpha2.StateActive
+	}
+	return true
+}
+
+// IsPoweredOffAsDesired returns true if the supplied Kubernetes resource
+// desires to be powered off and the observed device is off or powering off.
+func IsPoweredOffAsDesired(d *v1alpha2.Device) bool {
+	if emptyIfNil(d.Spec.ForProvider.PowerState) != v1alpha2.PowerStateOff {
+		return false
+	}
+	return d.Status.AtProvider.State == v1alpha2.StateInactive ||
+		d.Status.AtProvider.State == v1alpha2.StatePoweringOff
+}
+
+// IsSpecificHardwareReservation returns true if the supplied Kubernetes
+// resource requests a particular Hardware Reservation rather than none or the
+// next available reservation.
+func IsSpecificHardwareReservation(d *v1alpha2.Device) bool {
+	id := emptyIfNil(d.Spec.ForProvider.HardwareReservationID)
+	return id != "" && id != HardwareReservationNextAvailable
+}
+
+// slug matches the plan and operating system slugs of the API
+var slug = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// ValidateDeviceParameters returns an error if the supplied Kubernetes
+// resource could not be provisioned. Every rule is checked and all violations
+// are returned together, so the Device can be validated without creating it.
+// Additional validations, such as those that consult the API, are checked
+// along with the rules.
+func ValidateDeviceParameters(d *v1alpha2.Device, additional ...func(*v1alpha2.Device) error) error {
+	errs := []error{
+		validateSlug("plan", d.Spec.ForProvider.Plan),
+		validateSlug("operatingSystem", d.Spec.ForProvider.OS),
+	}
+	for _, validate := range []func(*v1alpha2.Device) error{
+		ValidateLocation,
+		ValidateHostname,
+		ValidateIPXEScriptURL,
+		ValidateTerminationTime,
+		ValidateSpotTerminationProtection,
+		ValidateNoPublicIP,
+		ValidateIPAddresses,
+		ValidateVLANs,
+		ValidateFeatures,
+		ValidateStorage,
+	} {
+		errs = append(errs, validate(d))
+	}
+	for _, validate := range additional {
+		errs = append(errs, validate(d))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// ParseStorage parses a storage layout. Unknown fields are rejected rather
+// than silently ignored.
+func ParseStorage(storage string) (*packngo.CPR, error) {
+	cpr := &packngo.CPR{}
+	dec := json.NewDecoder(strings.NewReader(storage))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(cpr); err != nil {
+		return nil, errors.Wrap(err, errStorageInvalid)
+	}
+	return cpr, nil
+}
+
+// ValidateStorage returns an error if the storage layout of the supplied
+// Kubernetes resource can not be parsed
+func ValidateStorage(d *v1alpha2.Device) error {
+	if d.Spec.ForProvider.Storage == nil {
+		return nil
+	}
+	_, err := ParseStorage(*d.Spec.ForProvider.Storage)
+	return err
+}
+
+func validateSlug(field, value string) error {
+	if value == "" {
+		return errors.Errorf(errFieldRequired, field)
+	}
+	if !slug.MatchString(value) {
+		return errors.Errorf(errSlugInvalid, field, value)
+	}
+	return nil
+}
+
+// ValidateHardwareReservation returns an error if the supplied Kubernetes
+// resource requests a plan that conflicts with the plan of the supplied
+// Hardware Reservation. A Device provisioned into a reservation always takes
+// the plan of the reserved hardware.
+func ValidateHardwareReservation(d *v1alpha2.Device, r *packngo.HardwareReservation) error {
+	if r.Plan.Slug != "" && r.Plan.Slug != d.Spec.ForProvider.Plan {
+		return errors.Errorf(errReservationPlanConflict, r.ID, r.Plan.Slug, d.Spec.ForProvider.Plan)
+	}
+	return nil
+}
+
+// ValidateLocation returns an error if the supplied Kubernetes resource sets
+// more than one of a facility, a metro or a list of facilities. Metros and
+// lists of facilities are alternatives to a facility.
+func ValidateLocation(d *v1alpha2.Device) error {
+	p := d.Spec.ForProvider
+	if p.Facility != "" && p.Metro != "" {
+		return errors.Errorf(errFacilityMetroConflict, p.Facility, p.Metro)
+	}
+	if len(p.Facilities) > 0 && (p.Facility != "" || p.Metro != "") {
+		return errors.Errorf(errFacilitiesConflict, strings.Join(p.Facilities, ", "))
+	}
+	return nil
+}
+
+// hostnameLabel matches a single label of a hostname
+var hostnameLabel = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+// Hostname returns the hostname of the Device, which defaults to the name of
+// the managed resource
+func Hostname(d *v1alpha2.Device) string {
+	if h := emptyIfNil(d.Spec.ForProvider.Hostname); h != "" {
+		return h
+	}
+	return d.GetName()
+}
+
+// planNetworkTypes are the network types supported by plans whose devices can
+// not be converted to every network type. Devices of other plans support all
+// network types.
+var planNetworkTypes = map[string][]string{
+	"baremetal_0":  {packngo.NetworkTypeL3},
+	"baremetal_1":  {packngo.NetworkTypeL3},
+	"baremetal_1e": {packngo.NetworkTypeHybrid},
+	"t1.small.x86": {packngo.NetworkTypeL3},
+	"c1.small.x86": {packngo.NetworkTypeL3},
+	"x1.small.x86": {packngo.NetworkTypeHybrid},
+}
+
+// UnsupportedNetworkTypeError is returned when the plan of a Device does not
+// support the requested network type. The Device can not be converted until
+// the network type is changed, so the conversion should not be retried.
+type UnsupportedNetworkTypeError struct {
+	Plan        string
+	NetworkType string
+	Supported   []string
+}
+
+func (e *UnsupportedNetworkTypeError) Error() string {
+	if len(e.Supported) == 1 {
+		return fmt.Sprintf(errNetworkTypeFixed, e.Plan, e.Supported[0], e.NetworkType)
+	}
+	quoted := make([]string, len(e.Supported))
+	for i, nt := range e.Supported {
+		quoted[i] = strconv.Quote(nt)
+	}
+	return fmt.Sprintf(errNetworkTypesPlan, e.Plan, strings.Join(quoted, ", "), e.NetworkType)
+}
+
+// ValidateNetworkType returns an error if the Device can not be converted to
+// the requested network type. An UnsupportedNetworkTypeError is returned when
+// the plan of the Device does not support the network type.
+func ValidateNetworkType(d *v1alpha2.Device, p *packngo.Device) error {
+	nt := d.Spec.ForProvider.NetworkType
+	if nt == nil {
+		return nil
+	}
+	switch *nt {
+	case packngo.NetworkTypeL3, packngo.NetworkTypeHybrid, packngo.NetworkTypeL2Individual, packngo.NetworkTypeL2Bonded:
+	default:
+		return errors.Errorf(errNetworkTypeUnsupported, *nt,
+			packngo.NetworkTypeL3, packngo.NetworkTypeHybrid, packngo.NetworkTypeL2Individual, packngo.NetworkTypeL2Bonded)
+	}
+	if p.Plan == nil {
+		return nil
+	}
+	supported, ok := planNetworkTypes[p.Plan.Slug]
+	if !ok {
+		return nil
+	}
+	for _, s := range supported {
+		if s == *nt {
+			return nil
+		}
+	}
+	return &UnsupportedNetworkTypeError{Plan: p.Plan.Slug, NetworkType: *nt, Supported: supported}
+}
+
+// VLANPortName returns the name of the port VLANs are attached to for the
+// network type, or an empty string if VLANs can not be attached
+func VLANPortName(networkType string) string {
+	switch networkType {
+	case packngo.NetworkTypeL2Bonded:
+		return "bond0"
+	case packngo.NetworkTypeHybrid, packngo.NetworkTypeL2Individual:
+		return "eth1"
+	}
+	return ""
+}
+
+// ValidateVLANs returns an error if the Device requests VLANs without a
+// network type they can be attached with
+func ValidateVLANs(d *v1alpha2.Device) error {
+	if len(d.Spec.ForProvider.VLANs) == 0 {
+		return nil
+	}
+	nt := emptyIfNil(d.Spec.ForProvider.NetworkType)
+	if VLANPortName(nt) == "" {
+		return errors.Errorf(errVLANsNetworkType, nt)
+	}
+	return nil
+}
+
+// VLANPort returns the port of the Device that VLANs are attached to, or nil
+// if the Device has not been converted to a network type that VLANs can be
+// attached with
+func VLANPort(d *v1alpha2.Device, p *packngo.Device) *packngo.Port {
+	nt := emptyIfNil(d.Spec.ForProvider.NetworkType)
+	name := VLANPortName(nt)
+	if name == "" || p.GetNetworkType() != nt {
+		return nil
+	}
+	for i := range p.NetworkPorts {
+		if p.NetworkPorts[i].Name == name {
+			return &p.NetworkPorts[i]
+		}
+	}
+	return nil
+}
+
+// MissingVLANs returns the VLANs of the Device that are not yet attached to
+// its VLAN port
+func MissingVLANs(d *v1alpha2.Device, p *packngo.Device) []string {
+	port := VLANPort(d, p)
+	var missing []string
+	for _, vlan := range d.Spec.ForProvider.VLANs {
+		if port == nil || !portsclient.IsAttached(port, vlan) {
+			missing = append(missing, vlan)
+		}
+	}
+	return missing
+}
+
+// IsNetworkTypeConvertible returns false while the ports of the Device can not
+// be reconfigured because it is being provisioned, reinstalled or removed
+func IsNetworkTypeConvertible(p *packngo.Device) bool {
+	switch p.State {
+	case v1alpha2.StateQueued, v1alpha2.StateProvisioning, v1alpha2.StateReinstalling, v1alpha2.StateDeprovisioning:
 		return false
 	}
-	*/
+	return true
+}
 
-	if !reflect.DeepEqual(d.Spec.ForProvider.Tags, p.Tags) {
-		return false, networkIsUpToDate
+// IsSpotTerminated returns true if the spot market has terminated the device,
+// either by deprovisioning it or because its termination time has passed
+func IsSpotTerminated(p *packngo.Device) bool {
+	if !p.SpotInstance {
+		return false
 	}
+	if p.State == v1alpha2.StateDeprovisioning {
+		return true
+	}
+	return p.TerminationTime != nil && !p.TerminationTime.After(time.Now())
+}
+
+// CreateTagKey is the key of the tag that identifies the Device created for a
+// managed resource by the UID of the resource. A Device that was created but
+// whose external name was not recorded is found by the tag rather than being
+// created again.
+const CreateTagKey = "crossplane-uid"
 
-	return true, networkIsUpToDate
+// CreateTag returns the tag that identifies the Device created for the
+// supplied Kubernetes Device, or an empty string if it has no UID
+func CreateTag(d *v1alpha2.Device) string {
+	if d.GetUID() == "" {
+		return ""
+	}
+	return CreateTagKey + "=" + string(d.GetUID())
+}
+
+// withoutCreateTag returns the tags other than the tag identifying the Device
+// created for a managed resource, which is not part of its desired tags
+func withoutCreateTag(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	kept := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if !strings.HasPrefix(t, CreateTagKey+"=") {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// ListedDevice identifies a Device listed by ListByTag or ListByHostname
+type ListedDevice struct {
+	ID       string
+	Hostname string
+}
+
+// ListByTag returns the ID and hostname of every Device of the project that
+// has the tag, listing every page of Devices. The API search narrows the
+// Devices listed, but may match other fields, so the tags of each Device are
+// compared exactly. Devices that are being deprovisioned, or were terminated
+// by the spot market, are gone or soon will be, and are not listed.
+func ListByTag(c Client, projectID, tag string) ([]ListedDevice, error) {
+	return listMatching(c, projectID, tag, func(d packngo.Device) bool {
+		if d.State == v1alpha2.StateDeprovisioning || IsSpotTerminated(&d) {
+			return false
+		}
+		for _, t := range d.Tags {
+			if t == tag {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// ListByHostname returns the ID and hostname of every Device of the project
+// with the hostname, listing every page of Devices. The API search narrows the
+// Devices listed, but may match other fields, so the hostname of each Device
+// is compared exactly.
+func ListByHostname(c Client, projectID, hostname string) ([]ListedDevice, error) {
+	return listMatching(c, projectID, hostname, func(d packngo.Device) bool {
+		return d.Hostname == hostname
+	})
+}
+
+// listMatching returns the ID and hostname of every Device of the project
+// found by the search that matches
+func listMatching(c Client, projectID, search string, matches func(packngo.Device) bool) ([]ListedDevice, error) {
+	var listed []ListedDevice
+	err := clients.ListAll(func(opts *packngo.ListOptions) error {
+		opts.Search = search
+		page, _, err := c.List(projectID, opts)
+		for _, d := range page {
+			if matches(d) {
+				listed = append(listed, ListedDevice{ID: d.ID, Hostname: d.Hostname})
+			}
+		}
+		return err
+	})
+	return listed, err
+}
+
+// RequestedFacilities returns the facilities the Device was requested in,
+// which may be empty when a metro was requested
+func RequestedFacilities(d *v1alpha2.Device) []string {
+	if d.Spec.ForProvider.Facility != "" {
+		return []string{d.Spec.ForProvider.Facility}
+	}
+	return d.Spec.ForProvider.Facilities
+}
+
+// IsFacilityMigrated returns true if the Device was requested in specific
+// facilities but is now deployed in a different facility
+func IsFacilityMigrated(d *v1alpha2.Device, p *packngo.Device) bool {
+	requested := RequestedFacilities(d)
+	if len(requested) == 0 || p.Facility == nil || p.Facility.Code == "" {
+		return false
+	}
+	for _, f := range requested {
+		if f == v1alpha2.FacilityAny || strings.EqualFold(f, p.Facility.Code) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateHostname returns an error if the hostname of the Device would be
+// rejected by the API
+func ValidateHostname(d *v1alpha2.Device) error {
+	h := Hostname(d)
+	if len(h) > maxHostnameLength {
+		return errors.Errorf(errHostnameInvalid, h, maxHostnameLength)
+	}
+	for _, label := range strings.Split(h, ".") {
+		if !hostnameLabel.MatchString(label) {
+			return errors.Errorf(errHostnameInvalid, h, maxHostnameLength)
+		}
+	}
+	return nil
+}
+
+// ValidateTerminationTime returns an error if the Device would be created with
+// a termination time that has already passed
+func ValidateTerminationTime(d *v1alpha2.Device) error {
+	t := d.Spec.ForProvider.TerminationTime
+	if t != nil && !t.After(time.Now()) {
+		return errors.Errorf(errTerminationTimePast, t.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// ValidateSpotTerminationProtection returns an error if the Device requests
+// spot termination protection, which the API does not offer
+func ValidateSpotTerminationProtection(d *v1alpha2.Device) error {
+	if p := d.Spec.ForProvider.SpotTerminationProtection; p != nil && *p {
+		return errors.New(errSpotProtection)
+	}
+	return nil
+}
+
+// ValidateNoPublicIP returns an error if a Device without public addresses
+// requests a public IPv4 subnet size or public IP addresses
+func ValidateNoPublicIP(d *v1alpha2.Device) error {
+	p := d.Spec.ForProvider
+	if !falseIfNil(p.NoPublicIP) {
+		return nil
+	}
+	if p.PublicIPv4SubnetSize != nil {
+		return errors.New(errNoPublicIPSubnetSize)
+	}
+	for _, ip := range p.IPAddresses {
+		if ip.Public {
+			return errors.New(errNoPublicIPAddress)
+		}
+	}
+	return nil
+}
+
+// ValidateIPAddresses returns an error if an address requested for a Device
+// has an unsupported address family or a subnet size the API does not assign
+func ValidateIPAddresses(d *v1alpha2.Device) error {
+	for i, ip := range d.Spec.ForProvider.IPAddresses {
+		var kind string
+		var min, max int
+		switch {
+		case ip.AddressFamily == 4 && ip.Public:
+			kind, min, max = "public IPv4", 28, 31
+		case ip.AddressFamily == 4:
+			kind, min, max = "private IPv4", 25, 30
+		case ip.AddressFamily == 6 && ip.Public:
+			kind, min, max = "IPv6", 64, 127
+		case ip.AddressFamily == 6:
+			return errors.Errorf(errIPAddressPrivateIPv6, i)
+		default:
+			return errors.Errorf(errIPAddressFamily, i, ip.AddressFamily)
+		}
+		// An unset size is assigned by the API
+		if ip.CIDR != 0 && (ip.CIDR < min || ip.CIDR > max) {
+			return errors.Errorf(errIPAddressCIDR, i, ip.CIDR, kind, min, max)
+		}
+	}
+	return nil
+}
+
+// AddIPReservation requests the public IPv4 address of the Device from the
+// supplied IP Reservation. A Device that requests no addresses also requests
+// the private IPv4 and public IPv6 addresses the API assigns by default. An
+// error is returned if the reservation is not ready or has no unassigned
+// addresses.
+func AddIPReservation(d *v1alpha2.Device, r *ipv1alpha1.Reservation) error {
+	o := r.Status.AtProvider
+	if o.ID == "" || o.State == ipv1alpha1.ReservationStateRequested || o.State == ipv1alpha1.ReservationStatePending {
+		return errors.Errorf(errIPReservationNotReady, r.GetName())
+	}
+	if ipclient.UnassignedAddresses(o) == 0 {
+		return errors.Errorf(errIPReservationExhausted, r.GetName(), o.ID)
+	}
+
+	p := &d.Spec.ForProvider
+	for i, ip := range p.IPAddresses {
+		if ip.AddressFamily == 4 && ip.Public {
+			p.IPAddresses[i].Reservations = append(ip.Reservations, o.ID)
+			return nil
+		}
+	}
+	if len(p.IPAddresses) == 0 {
+		p.IPAddresses = []v1alpha2.IPAddress{
+			{AddressFamily: 4, Public: false},
+			{AddressFamily: 6, Public: true},
+		}
+	}
+	p.IPAddresses = append([]v1alpha2.IPAddress{{AddressFamily: 4, Public: true, Reservations: []string{o.ID}}}, p.IPAddresses...)
+	return nil
+}
+
+// IsSpotPriceMaxUpToDate returns true if the supplied Kubernetes resource bids
+// the spot price max of the supplied Equinix Metal spot instance, or does not
+// set a bid
+func IsSpotPriceMaxUpToDate(d *v1alpha2.Device, p *packngo.Device) bool {
+	q := d.Spec.ForProvider.SpotPriceMax
+	if q == nil || !p.SpotInstance {
+		return true
+	}
+	return float64(q.MilliValue())/1000 == p.SpotPriceMax
+}
+
+// ValidateSpotPriceMax returns an error if the supplied Kubernetes resource
+// bids a spot price max other than that of the supplied Equinix Metal spot
+// instance, which the API can not update
+func ValidateSpotPriceMax(d *v1alpha2.Device, p *packngo.Device) error {
+	if IsSpotPriceMaxUpToDate(d, p) {
+		return nil
+	}
+	bid := float64(d.Spec.ForProvider.SpotPriceMax.MilliValue()) / 1000
+	return errors.Errorf(errSpotPriceMaxUpdate, strconv.FormatFloat(p.SpotPriceMax, 'f', -1, 64), strconv.FormatFloat(bid, 'f', -1, 64))
+}
+
+// ValidateFeatures returns an error if the supplied Kubernetes resource
+// requests an unknown hardware feature, or a feature that is neither required
+// nor preferred
+func ValidateFeatures(d *v1alpha2.Device) error {
+	features := make([]string, 0, len(d.Spec.ForProvider.Features))
+	for f := range d.Spec.ForProvider.Features {
+		features = append(features, f)
+	}
+	// report the first invalid feature consistently
+	sort.Strings(features)
+
+	for _, f := range features {
+		switch f {
+		case v1alpha2.FeatureTPM, v1alpha2.FeatureRAID, v1alpha2.FeatureTXT:
+		default:
+			return errors.Errorf(errFeatureUnknown, f, v1alpha2.FeatureTPM, v1alpha2.FeatureRAID, v1alpha2.FeatureTXT)
+		}
+		switch r := d.Spec.ForProvider.Features[f]; r {
+		case v1alpha2.FeatureRequired, v1alpha2.FeaturePreferred:
+		default:
+			return errors.Errorf(errFeatureRequirement, f, v1alpha2.FeatureRequired, v1alpha2.FeaturePreferred, r)
+		}
+	}
+	return nil
+}
+
+// ValidateIPXEScriptURL returns an error if the supplied Kubernetes resource
+// sets an iPXE script URL for an operating system other than custom_ipxe.
+func ValidateIPXEScriptURL(d *v1alpha2.Device) error {
+	if emptyIfNil(d.Spec.ForProvider.IPXEScriptURL) != "" && d.Spec.ForProvider.OS != v1alpha2.OSCustomIPXE {
+		return errors.Errorf(errIPXEScriptURLOS, v1alpha2.OSCustomIPXE, d.Spec.ForProvider.OS)
+	}
+	return nil
 }
 
 // nilOrEqualStr is true if a (aPtr) is non-nil and equal to b
@@ -300,22 +1540,163 @@ func nilOrEqualStr(aPtr *string, b string) bool {
 	return (aPtr == nil || *aPtr == b)
 }
 
+// nilOrEqualStrPtr is true if a (aPtr) is nil or equal to b (bPtr). A nil b
+// is equal to the empty string, the API omits empty optional strings.
+func nilOrEqualStrPtr(aPtr, bPtr *string) bool {
+	return nilOrEqualStr(aPtr, emptyIfNil(bPtr))
+}
+
 // nilOrEqualBool is true if a (aPtr) is non-nil and equal to b
 func nilOrEqualBool(aPtr *bool, b bool) bool {
 	return (aPtr == nil || *aPtr == b)
 }
 
+// equalTags is true if a and b contain the same tags, in any order
+func equalTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	as := append([]string(nil), a...)
+	bs := append([]string(nil), b...)
+	sort.Strings(as)
+	sort.Strings(bs)
+	for i := range as {
+		if as[i] != bs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// userDataBoundary separates the parts of multipart userdata. It is fixed so
+// that userdata combined from unchanged parts is unchanged.
+const userDataBoundary = "==CROSSPLANE-USERDATA=="
+
+// userDataContentTypes are the cloud-init content types of userdata parts
+// that start with each prefix. Longer prefixes are listed first.
+var userDataContentTypes = []struct {
+	prefix      string
+	contentType string
+}{
+	{"#cloud-config-archive", "text/cloud-config-archive"},
+	{"#cloud-config", "text/cloud-config"},
+	{"#cloud-boothook", "text/cloud-boothook"},
+	{"#include", "text/x-include-url"},
+	{"#part-handler", "text/part-handler"},
+	{"#upstart-job", "text/upstart-job"},
+	{"## template: jinja", "text/jinja2"},
+	{"#!", "text/x-shellscript"},
+}
+
+// DecodeData returns the data decoded from the encoding, one of
+// v1alpha2.DataEncodingPlain or v1alpha2.DataEncodingBase64. Whitespace in
+// base64 data, such as the line breaks of wrapped output, is ignored.
+func DecodeData(data, encoding string) (string, error) {
+	switch encoding {
+	case "", v1alpha2.DataEncodingPlain:
+		return data, nil
+	case v1alpha2.DataEncodingBase64:
+		decoded, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(data), ""))
+		if err != nil {
+			return "", errors.Wrap(err, errDecodeBase64)
+		}
+		return string(decoded), nil
+	}
+	return "", errors.Errorf(errDataEncodingFmt, encoding, v1alpha2.DataEncodingPlain, v1alpha2.DataEncodingBase64)
+}
+
+// CombineUserData combines parts of userdata in order. The parts are joined
+// with the separator, or, when the format is v1alpha2.UserDataFormatMIME,
+// combined into a cloud-init multipart MIME archive.
+func CombineUserData(parts []string, format, separator string) (string, error) {
+	if format != v1alpha2.UserDataFormatMIME {
+		return strings.Join(parts, separator), nil
+	}
+
+	buf := &bytes.Buffer{}
+	w := multipart.NewWriter(buf)
+	if err := w.SetBoundary(userDataBoundary); err != nil {
+		return "", err
+	}
+	fmt.Fprintf(buf, "Content-Type: multipart/mixed; boundary=%q\r\nMIME-Version: 1.0\r\n\r\n", w.Boundary())
+
+	for _, p := range parts {
+		pw, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type": {userDataContentType(p) + `; charset="utf-8"`},
+			"MIME-Version": {"1.0"},
+		})
+		if err != nil {
+			return "", err
+		}
+		if _, err := pw.Write([]byte(p)); err != nil {
+			return "", err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func userDataContentType(part string) string {
+	for _, t := range userDataContentTypes {
+		if strings.HasPrefix(part, t.prefix) {
+			return t.contentType
+		}
+	}
+	return "text/plain"
+}
+
 // NewUpdateDeviceRequest creates a request to update an instance suitable for
-// use with the Equinix Metal API.
-func NewUpdateDeviceRequest(d *v1alpha2.Device) *packngo.DeviceUpdateRequest {
+// use with the Equinix Metal API. Devices are locked and unlocked through
+// their lock actions rather than the update request, and userdata is updated
+// once the reinstall that applies it was requested, see
+// NewUserDataUpdateRequest. The tags of the request replace those of the
+// device, so the tag identifying the Device created for a managed resource is
+// kept.
+func NewUpdateDeviceRequest(d *v1alpha2.Device, p *packngo.Device) *packngo.DeviceUpdateRequest {
 	return &packngo.DeviceUpdateRequest{
 		Hostname:      d.Spec.ForProvider.Hostname,
-		Locked:        d.Spec.ForProvider.Locked,
-		UserData:      d.Spec.ForProvider.UserData,
 		IPXEScriptURL: d.Spec.ForProvider.IPXEScriptURL,
 		AlwaysPXE:     d.Spec.ForProvider.AlwaysPXE,
-		Tags:          &d.Spec.ForProvider.Tags,
+		Tags:          updateTags(d, p),
 		Description:   d.Spec.ForProvider.Description,
-		CustomData:    d.Spec.ForProvider.CustomData,
 	}
 }
+
+// updateTags returns the desired tags of the Device along with any tag found
+// on the device identifying it as created for a managed resource
+func updateTags(d *v1alpha2.Device, p *packngo.Device) *[]string {
+	if p == nil || len(p.Tags) == len(withoutCreateTag(p.Tags)) {
+		return &d.Spec.ForProvider.Tags
+	}
+	tags := make([]string, 0, len(d.Spec.ForProvider.Tags)+1)
+	tags = append(tags, d.Spec.ForProvider.Tags...)
+	for _, t := range p.Tags {
+		if strings.HasPrefix(t, CreateTagKey+"=") {
+			tags = append(tags, t)
+		}
+	}
+	return &tags
+}
+
+// NewUserDataUpdateRequest creates a request to update the userdata of an
+// instance suitable for use with the Equinix Metal API. Userdata is compared
+// with that of the instance to decide whether it must be reinstalled, so it is
+// only updated once the reinstall was requested.
+func NewUserDataUpdateRequest(d *v1alpha2.Device) *packngo.DeviceUpdateRequest {
+	return &packngo.DeviceUpdateRequest{UserData: d.Spec.ForProvider.UserData}
+}
+
+// NewReinstallRequest creates a request to reinstall a Device suitable for use
+// with the Equinix Metal API.
+func NewReinstallRequest(d *v1alpha2.Device) *ReinstallRequest {
+	r := &ReinstallRequest{
+		OperatingSystem: d.Spec.ForProvider.OS,
+	}
+	if o := d.Spec.ForProvider.ReinstallOptions; o != nil {
+		r.PreserveData = o.PreserveData
+		r.DeprovisionFast = o.DeprovisionFast
+	}
+	return r
+}
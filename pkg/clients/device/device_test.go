@@ -0,0 +1,861 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+)
+
+const (
+	errHostnameFmt = `hostname %q must be at most 253 characters of dot separated labels of letters, digits and hyphens that do not begin or end with a hyphen`
+)
+
+type parametersModifier func(*v1alpha2.DeviceParameters)
+
+func validDevice(name string, pm ...parametersModifier) *v1alpha2.Device {
+	d := &v1alpha2.Device{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha2.DeviceSpec{
+			ForProvider: v1alpha2.DeviceParameters{
+				Plan:  "c3.small.x86",
+				OS:    "ubuntu_20_04",
+				Metro: "sv",
+			},
+		},
+	}
+	for _, m := range pm {
+		m(&d.Spec.ForProvider)
+	}
+	return d
+}
+
+func aggregate(errs ...error) error {
+	return utilerrors.NewAggregate(errs)
+}
+
+func TestValidateDeviceParameters(t *testing.T) {
+	ipxeScriptURL := "https://example.com/boot.ipxe"
+	hostname := "-not-valid"
+	past := metav1.NewTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	future := metav1.NewTime(time.Now().Add(time.Hour))
+	noPublicIP := true
+
+	cases := map[string]struct {
+		device *v1alpha2.Device
+		want   error
+	}{
+		"Valid": {
+			device: validDevice("my-device"),
+		},
+		"ValidFacility": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.Metro = ""
+				p.Facility = "sv15"
+			}),
+		},
+		"ValidFacilities": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.Metro = ""
+				p.Facilities = []string{"sv15", "sv16"}
+			}),
+		},
+		"ValidWithoutLocation": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.Metro = ""
+			}),
+		},
+		"ValidCustomIPXE": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.OS = v1alpha2.OSCustomIPXE
+				p.IPXEScriptURL = &ipxeScriptURL
+			}),
+		},
+		"ValidTerminationTime": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.TerminationTime = &future
+			}),
+		},
+		"ValidFeatures": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.Features = map[string]string{
+					v1alpha2.FeatureTPM:  v1alpha2.FeatureRequired,
+					v1alpha2.FeatureRAID: v1alpha2.FeaturePreferred,
+				}
+			}),
+		},
+		"UnprotectedSpotInstance": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				protect := false
+				p.SpotTerminationProtection = &protect
+			}),
+		},
+		"SpotTerminationProtection": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				protect := true
+				p.SpotTerminationProtection = &protect
+			}),
+			want: aggregate(errors.New(errSpotProtection)),
+		},
+		"ValidVLANs": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				networkType := packngo.NetworkTypeHybrid
+				p.NetworkType = &networkType
+				p.VLANs = []string{"vlan-1"}
+			}),
+		},
+		"VLANsWithoutNetworkType": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.VLANs = []string{"vlan-1"}
+			}),
+			want: aggregate(errors.New(`vlans may only be set when networkType is "layer2-bonded", "layer2-individual" or "hybrid", not ""`)),
+		},
+		"VLANsWithLayer3": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				networkType := packngo.NetworkTypeL3
+				p.NetworkType = &networkType
+				p.VLANs = []string{"vlan-1"}
+			}),
+			want: aggregate(errors.New(`vlans may only be set when networkType is "layer2-bonded", "layer2-individual" or "hybrid", not "layer3"`)),
+		},
+		"ValidNoPublicIP": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.NoPublicIP = &noPublicIP
+				p.IPAddresses = []v1alpha2.IPAddress{{AddressFamily: 4, Public: false}}
+			}),
+		},
+		"NoPublicIPWithSubnetSize": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				size := 31
+				p.NoPublicIP = &noPublicIP
+				p.PublicIPv4SubnetSize = &size
+			}),
+			want: aggregate(errors.New(errNoPublicIPSubnetSize)),
+		},
+		"NoPublicIPWithPublicAddress": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.NoPublicIP = &noPublicIP
+				p.IPAddresses = []v1alpha2.IPAddress{{AddressFamily: 6, Public: true}}
+			}),
+			want: aggregate(errors.New(errNoPublicIPAddress)),
+		},
+		"ValidIPAddresses": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.IPAddresses = []v1alpha2.IPAddress{
+					{AddressFamily: 4, Public: true, CIDR: 29},
+					{AddressFamily: 4, Public: false},
+					{AddressFamily: 6, Public: true, CIDR: 64},
+				}
+			}),
+		},
+		"IPAddressFamily": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.IPAddresses = []v1alpha2.IPAddress{{AddressFamily: 5, Public: true}}
+			}),
+			want: aggregate(errors.New("ipAddresses[0] address family 5 is not supported, use 4 or 6")),
+		},
+		"PrivateIPv6Address": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.IPAddresses = []v1alpha2.IPAddress{{AddressFamily: 6, Public: false}}
+			}),
+			want: aggregate(errors.New("ipAddresses[0] IPv6 addresses must be public")),
+		},
+		"PublicIPv4SubnetTooLarge": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.IPAddresses = []v1alpha2.IPAddress{
+					{AddressFamily: 4, Public: false, CIDR: 30},
+					{AddressFamily: 4, Public: true, CIDR: 24},
+				}
+			}),
+			want: aggregate(errors.New("ipAddresses[1] cidr /24 of a public IPv4 address must be between /28 and /31")),
+		},
+		"PrivateIPv4SubnetTooSmall": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.IPAddresses = []v1alpha2.IPAddress{{AddressFamily: 4, Public: false, CIDR: 31}}
+			}),
+			want: aggregate(errors.New("ipAddresses[0] cidr /31 of a private IPv4 address must be between /25 and /30")),
+		},
+		"MissingPlan": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.Plan = ""
+			}),
+			want: aggregate(errors.New("plan is required")),
+		},
+		"InvalidPlan": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.Plan = "C3 Small"
+			}),
+			want: aggregate(errors.New(`plan "C3 Small" must be a slug of lowercase letters, digits, dots, underscores and hyphens`)),
+		},
+		"MissingOperatingSystem": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.OS = ""
+			}),
+			want: aggregate(errors.New("operatingSystem is required")),
+		},
+		"InvalidOperatingSystem": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.OS = "ubuntu 20.04"
+			}),
+			want: aggregate(errors.New(`operatingSystem "ubuntu 20.04" must be a slug of lowercase letters, digits, dots, underscores and hyphens`)),
+		},
+		"FacilityAndMetro": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.Facility = "sv15"
+			}),
+			want: aggregate(errors.New(`facility "sv15" and metro "sv" can not both be set`)),
+		},
+		"FacilitiesAndMetro": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.Facilities = []string{"sv15", "sv16"}
+			}),
+			want: aggregate(errors.New(`facilities "sv15, sv16" can not be set with a facility or metro`)),
+		},
+		"InvalidHostname": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.Hostname = &hostname
+			}),
+			want: aggregate(errors.Errorf(errHostnameFmt, hostname)),
+		},
+		"InvalidDefaultHostname": {
+			device: validDevice("my_device"),
+			want:   aggregate(errors.Errorf(errHostnameFmt, "my_device")),
+		},
+		"IPXEScriptURLWithoutCustomIPXE": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.IPXEScriptURL = &ipxeScriptURL
+			}),
+			want: aggregate(errors.New(`ipxeScriptUrl may only be set when operatingSystem is "custom_ipxe", not "ubuntu_20_04"`)),
+		},
+		"PastTerminationTime": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.TerminationTime = &past
+			}),
+			want: aggregate(errors.New("termination time 2020-01-01T00:00:00Z must be in the future")),
+		},
+		"UnknownFeature": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.Features = map[string]string{"gpu": v1alpha2.FeatureRequired}
+			}),
+			want: aggregate(errors.New(`feature "gpu" is not supported, use one of "tpm", "raid" or "txt"`)),
+		},
+		"InvalidFeatureRequirement": {
+			device: validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+				p.Features = map[string]string{v1alpha2.FeatureTPM: "optional"}
+			}),
+			want: aggregate(errors.New(`feature "tpm" must be "required" or "preferred", not "optional"`)),
+		},
+		"MultipleViolations": {
+			device: validDevice("my_device", func(p *v1alpha2.DeviceParameters) {
+				p.Plan = ""
+				p.Facility = "sv15"
+				p.TerminationTime = &past
+			}),
+			want: aggregate(
+				errors.New("plan is required"),
+				errors.New(`facility "sv15" and metro "sv" can not both be set`),
+				errors.Errorf(errHostnameFmt, "my_device"),
+				errors.New("termination time 2020-01-01T00:00:00Z must be in the future"),
+			),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateDeviceParameters(tc.device)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateDeviceParameters(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreateFromDevice(t *testing.T) {
+	t.Run("Invalid", func(t *testing.T) {
+		d := validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+			p.Plan = ""
+		})
+		r, err := CreateFromDevice(d, "project")
+		if diff := cmp.Diff(aggregate(errors.New("plan is required")), err, test.EquateErrors()); diff != "" {
+			t.Errorf("CreateFromDevice(...): -want error, +got error:\n%s", diff)
+		}
+		if r != nil {
+			t.Errorf("CreateFromDevice(...): want no request, got %+v", r)
+		}
+	})
+	t.Run("Valid", func(t *testing.T) {
+		r, err := CreateFromDevice(validDevice("my-device"), "project")
+		if err != nil {
+			t.Fatalf("CreateFromDevice(...): %s", err)
+		}
+		if r.Hostname != "my-device" || r.Plan != "c3.small.x86" || r.Metro != "sv" || r.ProjectID != "project" {
+			t.Errorf("CreateFromDevice(...): unexpected request %+v", r)
+		}
+	})
+	t.Run("Description", func(t *testing.T) {
+		description := "web server"
+		r, err := CreateFromDevice(validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+			p.Description = &description
+		}), "project")
+		if err != nil {
+			t.Fatalf("CreateFromDevice(...): %s", err)
+		}
+		if diff := cmp.Diff("web server", r.Description); diff != "" {
+			t.Errorf("CreateFromDevice(...): -want description, +got description:\n%s", diff)
+		}
+	})
+}
+
+func TestCombineUserData(t *testing.T) {
+	parts := []string{"#cloud-config\npackages: [git]", "#!/bin/sh\necho prod"}
+
+	cases := map[string]struct {
+		format    string
+		separator string
+		want      string
+	}{
+		"Concat": {
+			format:    v1alpha2.UserDataFormatConcat,
+			separator: "\n",
+			want:      "#cloud-config\npackages: [git]\n#!/bin/sh\necho prod",
+		},
+		"MIME": {
+			format: v1alpha2.UserDataFormatMIME,
+			want: "Content-Type: multipart/mixed; boundary=\"==CROSSPLANE-USERDATA==\"\r\nMIME-Version: 1.0\r\n\r\n" +
+				"--==CROSSPLANE-USERDATA==\r\n" +
+				"Content-Type: text/cloud-config; charset=\"utf-8\"\r\nMIME-Version: 1.0\r\n\r\n" +
+				"#cloud-config\npackages: [git]\r\n" +
+				"--==CROSSPLANE-USERDATA==\r\n" +
+				"Content-Type: text/x-shellscript; charset=\"utf-8\"\r\nMIME-Version: 1.0\r\n\r\n" +
+				"#!/bin/sh\necho prod\r\n" +
+				"--==CROSSPLANE-USERDATA==--\r\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := CombineUserData(parts, tc.format, tc.separator)
+			if err != nil {
+				t.Fatalf("CombineUserData(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("CombineUserData(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateObservationSSHKeys(t *testing.T) {
+	p := &packngo.Device{
+		SSHKeys: []packngo.SSHKey{
+			{ID: "project-key", Owner: packngo.Href{Href: "/metal/v1/projects/my-project"}},
+			{ID: "user-key", Owner: packngo.Href{Href: "/metal/v1/users/my-user"}},
+			{URL: "/metal/v1/ssh-keys/unexpanded-key"},
+		},
+	}
+
+	o, err := GenerateObservation(p)
+	if err != nil {
+		t.Fatalf("GenerateObservation(...): %s", err)
+	}
+	if diff := cmp.Diff([]string{"project-key", "user-key", "unexpanded-key"}, o.SSHKeys); diff != "" {
+		t.Errorf("GenerateObservation(...): -want SSH keys, +got SSH keys:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"project-key"}, o.ProjectSSHKeys); diff != "" {
+		t.Errorf("GenerateObservation(...): -want project SSH keys, +got project SSH keys:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"user-key"}, o.UserSSHKeys); diff != "" {
+		t.Errorf("GenerateObservation(...): -want user SSH keys, +got user SSH keys:\n%s", diff)
+	}
+}
+
+func TestCreateFromDeviceStorage(t *testing.T) {
+	storage := `{"disks":[{"device":"/dev/sda","wipeTable":true,"partitions":[{"label":"ROOT","number":1,"size":"0"}]}],` +
+		`"filesystems":[{"mount":{"device":"/dev/sda1","format":"ext4","point":"/","create":{"options":["-L","ROOT"]}}}]}`
+
+	t.Run("Valid", func(t *testing.T) {
+		d := validDevice("my-device", func(p *v1alpha2.DeviceParameters) { p.Storage = &storage })
+		r, err := CreateFromDevice(d, "project")
+		if err != nil {
+			t.Fatalf("CreateFromDevice(...): %s", err)
+		}
+		if r.Storage == nil || len(r.Storage.Disks) != 1 || r.Storage.Disks[0].Device != "/dev/sda" || len(r.Storage.Filesystems) != 1 {
+			t.Errorf("CreateFromDevice(...): unexpected storage %+v", r.Storage)
+		}
+	})
+
+	for name, invalid := range map[string]string{
+		"Malformed":    `{"disks": [`,
+		"UnknownField": `{"disk": []}`,
+	} {
+		invalid := invalid
+		t.Run(name, func(t *testing.T) {
+			d := validDevice("my-device", func(p *v1alpha2.DeviceParameters) { p.Storage = &invalid })
+			if _, err := CreateFromDevice(d, "project"); err == nil {
+				t.Errorf("CreateFromDevice(...): want error for storage %s", invalid)
+			}
+		})
+	}
+}
+
+func TestLateInitializeIPXE(t *testing.T) {
+	url, otherURL := "https://example.com/boot.ipxe", "https://example.com/other.ipxe"
+	alwaysPXE, neverPXE := true, false
+	cases := map[string]struct {
+		in     v1alpha2.DeviceParameters
+		device *packngo.Device
+		want   v1alpha2.DeviceParameters
+	}{
+		"ImportedCustomIPXE": {
+			device: &packngo.Device{
+				OS:            &packngo.OS{Slug: v1alpha2.OSCustomIPXE},
+				IPXEScriptURL: url,
+				AlwaysPXE:     true,
+			},
+			want: v1alpha2.DeviceParameters{OS: v1alpha2.OSCustomIPXE, IPXEScriptURL: &url, AlwaysPXE: &alwaysPXE},
+		},
+		"SpecPreserved": {
+			in: v1alpha2.DeviceParameters{
+				OS:            v1alpha2.OSCustomIPXE,
+				IPXEScriptURL: &otherURL,
+				AlwaysPXE:     &neverPXE,
+			},
+			device: &packngo.Device{
+				OS:            &packngo.OS{Slug: v1alpha2.OSCustomIPXE},
+				IPXEScriptURL: url,
+				AlwaysPXE:     true,
+			},
+			want: v1alpha2.DeviceParameters{
+				OS:            v1alpha2.OSCustomIPXE,
+				IPXEScriptURL: &otherURL,
+				AlwaysPXE:     &neverPXE,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			in := tc.in
+			LateInitialize(&in, tc.device)
+			if diff := cmp.Diff(tc.want.IPXEScriptURL, in.IPXEScriptURL); diff != "" {
+				t.Errorf("LateInitialize(...): -want ipxeScriptUrl, +got ipxeScriptUrl:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.AlwaysPXE, in.AlwaysPXE); diff != "" {
+				t.Errorf("LateInitialize(...): -want alwaysPXE, +got alwaysPXE:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.OS, in.OS); diff != "" {
+				t.Errorf("LateInitialize(...): -want operatingSystem, +got operatingSystem:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreateFromDeviceNoPublicIP(t *testing.T) {
+	noPublicIP := true
+	d := validDevice("my-device", func(p *v1alpha2.DeviceParameters) { p.NoPublicIP = &noPublicIP })
+	r, err := CreateFromDevice(d, "project")
+	if err != nil {
+		t.Fatalf("CreateFromDevice(...): %s", err)
+	}
+	want := []packngo.IPAddressCreateRequest{{AddressFamily: 4, Public: false}}
+	if diff := cmp.Diff(want, r.IPAddresses); diff != "" {
+		t.Errorf("CreateFromDevice(...): -want IP addresses, +got IP addresses:\n%s", diff)
+	}
+	if r.PublicIPv4SubnetSize != 0 {
+		t.Errorf("CreateFromDevice(...): want no public IPv4 subnet size, got %d", r.PublicIPv4SubnetSize)
+	}
+
+	// Devices with public addresses leave the addresses to the API
+	r, err = CreateFromDevice(validDevice("my-device"), "project")
+	if err != nil {
+		t.Fatalf("CreateFromDevice(...): %s", err)
+	}
+	if len(r.IPAddresses) != 0 {
+		t.Errorf("CreateFromDevice(...): want no IP addresses, got %+v", r.IPAddresses)
+	}
+}
+
+func TestCreateFromDeviceIPAddresses(t *testing.T) {
+	d := validDevice("my-device", func(p *v1alpha2.DeviceParameters) {
+		p.IPAddresses = []v1alpha2.IPAddress{
+			{AddressFamily: 4, Public: true, CIDR: 29, Reservations: []string{"reservation-1"}},
+			{AddressFamily: 4, Public: false},
+		}
+	})
+	r, err := CreateFromDevice(d, "project")
+	if err != nil {
+		t.Fatalf("CreateFromDevice(...): %s", err)
+	}
+	want := []packngo.IPAddressCreateRequest{
+		{AddressFamily: 4, Public: true, CIDR: 29, Reservations: []string{"reservation-1"}},
+		{AddressFamily: 4, Public: false},
+	}
+	if diff := cmp.Diff(want, r.IPAddresses); diff != "" {
+		t.Errorf("CreateFromDevice(...): -want IP addresses, +got IP addresses:\n%s", diff)
+	}
+}
+
+func TestGenerateObservationIPAddresses(t *testing.T) {
+	p := &packngo.Device{
+		Network: []*packngo.IPAddressAssignment{
+			{IpAddressCommon: packngo.IpAddressCommon{Address: "198.51.100.2", CIDR: 29, AddressFamily: 4, Public: true, Management: true}},
+			nil,
+			{IpAddressCommon: packngo.IpAddressCommon{Address: "10.0.0.3", CIDR: 30, AddressFamily: 4, Network: "10.0.0.0"}},
+		},
+	}
+
+	o, err := GenerateObservation(p)
+	if err != nil {
+		t.Fatalf("GenerateObservation(...): %s", err)
+	}
+	want := []v1alpha2.DeviceIPAddress{
+		{Address: "198.51.100.2", CIDR: 29, AddressFamily: 4, Public: true, Management: true},
+		{Address: "10.0.0.3", CIDR: 30, AddressFamily: 4},
+	}
+	if diff := cmp.Diff(want, o.IPAddresses); diff != "" {
+		t.Errorf("GenerateObservation(...): -want IP addresses, +got IP addresses:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"10.0.0.0/30"}, o.PrivateNetworks); diff != "" {
+		t.Errorf("GenerateObservation(...): -want private networks, +got private networks:\n%s", diff)
+	}
+}
+
+func TestGenerateObservationStorage(t *testing.T) {
+	p := &packngo.Device{
+		Network: []*packngo.IPAddressAssignment{
+			{IpAddressCommon: packngo.IpAddressCommon{Address: "147.75.0.2", Network: "147.75.0.0", CIDR: 31, AddressFamily: 4, Public: true, Management: true}},
+			{IpAddressCommon: packngo.IpAddressCommon{Address: "10.0.0.3", Network: "10.0.0.2", CIDR: 31, AddressFamily: 4, Management: true}},
+			{IpAddressCommon: packngo.IpAddressCommon{Address: "10.1.0.1", Network: "10.1.0.0", CIDR: 29, AddressFamily: 4}},
+		},
+		Volumes: []*packngo.Volume{
+			{ID: "volume-1"},
+			{Href: "/metal/v1/storage/volume-2"},
+		},
+	}
+
+	o, err := GenerateObservation(p)
+	if err != nil {
+		t.Fatalf("GenerateObservation(...): %s", err)
+	}
+	if diff := cmp.Diff("10.0.0.3", o.PrivateIPv4); diff != "" {
+		t.Errorf("GenerateObservation(...): -want private IPv4, +got private IPv4:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"10.0.0.2/31", "10.1.0.0/29"}, o.PrivateNetworks); diff != "" {
+		t.Errorf("GenerateObservation(...): -want private networks, +got private networks:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"volume-1", "volume-2"}, o.Volumes); diff != "" {
+		t.Errorf("GenerateObservation(...): -want volumes, +got volumes:\n%s", diff)
+	}
+
+	if !o.PublicIP {
+		t.Errorf("GenerateObservation(...): want public IP")
+	}
+
+	// Devices without public addresses, private networks or storage leave
+	// them empty
+	o, err = GenerateObservation(&packngo.Device{})
+	if err != nil {
+		t.Fatalf("GenerateObservation(...): %s", err)
+	}
+	if o.PublicIP || o.PrivateIPv4 != "" || o.PrivateNetworks != nil || o.Volumes != nil {
+		t.Errorf("GenerateObservation(...): want no public IP, private networks or volumes, got %+v", o)
+	}
+}
+
+func TestCreateFromDeviceFacilities(t *testing.T) {
+	cases := map[string]struct {
+		pm   parametersModifier
+		want []string
+	}{
+		"Metro": {
+			pm: func(p *v1alpha2.DeviceParameters) {},
+		},
+		"Facility": {
+			pm: func(p *v1alpha2.DeviceParameters) {
+				p.Metro = ""
+				p.Facility = "sv15"
+			},
+			want: []string{"sv15"},
+		},
+		"Facilities": {
+			pm: func(p *v1alpha2.DeviceParameters) {
+				p.Metro = ""
+				p.Facilities = []string{"sv15", "sv16", "da11"}
+			},
+			want: []string{"sv15", "sv16", "da11"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := CreateFromDevice(validDevice("my-device", tc.pm), "project")
+			if err != nil {
+				t.Fatalf("CreateFromDevice(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want, r.Facility); diff != "" {
+				t.Errorf("CreateFromDevice(...): -want facilities, +got facilities:\n%s", diff)
+			}
+		})
+	}
+
+	// The facility chosen from the candidates is observed
+	o, err := GenerateObservation(&packngo.Device{Facility: &packngo.Facility{Code: "sv16"}})
+	if err != nil {
+		t.Fatalf("GenerateObservation(...): %s", err)
+	}
+	if o.Facility != "sv16" {
+		t.Errorf("GenerateObservation(...): want facility %q, got %q", "sv16", o.Facility)
+	}
+}
+
+// listClient is a Client that only lists Devices
+type listClient struct {
+	Client
+	list func(projectID string, listOpt *packngo.ListOptions) ([]packngo.Device, *packngo.Response, error)
+}
+
+func (c *listClient) List(projectID string, listOpt *packngo.ListOptions) ([]packngo.Device, *packngo.Response, error) {
+	return c.list(projectID, listOpt)
+}
+
+func TestGetConnectionDetailsPublicIPv4(t *testing.T) {
+	address := func(address string, family int, public, management bool) *packngo.IPAddressAssignment {
+		return &packngo.IPAddressAssignment{IpAddressCommon: packngo.IpAddressCommon{
+			Address:       address,
+			AddressFamily: family,
+			Public:        public,
+			Management:    management,
+		}}
+	}
+
+	// The elastic address is listed before the management address to show
+	// that management addresses are indexed first
+	d := &packngo.Device{Network: []*packngo.IPAddressAssignment{
+		address("198.51.100.2", 4, true, false),
+		address("198.51.100.1", 4, true, true),
+		address("10.0.0.1", 4, false, true),
+		address("2001:db8::1", 6, true, true),
+	}}
+
+	got := GetConnectionDetails(d)
+	want := map[string]string{
+		ConnectionPublicIPv4Key: "198.51.100.1",
+		"publicIPv4-0":          "198.51.100.1",
+		"publicIPv4-1":          "198.51.100.2",
+	}
+	for key, value := range want {
+		if diff := cmp.Diff(value, string(got[key])); diff != "" {
+			t.Errorf("GetConnectionDetails(...): -want %s, +got:\n%s", key, diff)
+		}
+	}
+	if _, ok := got["publicIPv4-2"]; ok {
+		t.Errorf("GetConnectionDetails(...): want no publicIPv4-2 key, got %q", got["publicIPv4-2"])
+	}
+}
+
+func TestGetConnectionDetailsNoPublicIP(t *testing.T) {
+	d := &packngo.Device{
+		RootPassword: "password",
+		Network: []*packngo.IPAddressAssignment{{IpAddressCommon: packngo.IpAddressCommon{
+			Address:       "10.0.0.1",
+			AddressFamily: 4,
+			Management:    true,
+		}}},
+	}
+
+	want := managed.ConnectionDetails{ConnectionPrivateIPv4Key: []byte("10.0.0.1")}
+	if diff := cmp.Diff(want, GetConnectionDetails(d)); diff != "" {
+		t.Errorf("GetConnectionDetails(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestDecodeData(t *testing.T) {
+	type want struct {
+		data string
+		err  error
+	}
+
+	cases := map[string]struct {
+		data     string
+		encoding string
+		want     want
+	}{
+		"Default": {
+			data: "#cloud-config",
+			want: want{data: "#cloud-config"},
+		},
+		"Plain": {
+			data:     "I2Nsb3VkLWNvbmZpZw==",
+			encoding: v1alpha2.DataEncodingPlain,
+			want:     want{data: "I2Nsb3VkLWNvbmZpZw=="},
+		},
+		"Base64": {
+			data:     "I2Nsb3VkLWNvbmZpZw==",
+			encoding: v1alpha2.DataEncodingBase64,
+			want:     want{data: "#cloud-config"},
+		},
+		"WrappedBase64": {
+			data:     "I2Nsb3Vk\nLWNvbmZp\nZw==\n",
+			encoding: v1alpha2.DataEncodingBase64,
+			want:     want{data: "#cloud-config"},
+		},
+		"InvalidBase64": {
+			data:     "#cloud-config",
+			encoding: v1alpha2.DataEncodingBase64,
+			want:     want{err: errors.Wrap(base64.CorruptInputError(0), errDecodeBase64)},
+		},
+		"UnknownEncoding": {
+			data:     "#cloud-config",
+			encoding: "gzip",
+			want:     want{err: errors.New(`encoding "gzip" is not supported, use "plain" or "base64"`)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			data, err := DecodeData(tc.data, tc.encoding)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("DecodeData(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.data, data); diff != "" {
+				t.Errorf("DecodeData(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestListByTag(t *testing.T) {
+	// devices whose other fields mention the tag may be found by the search
+	description := "adopt"
+	pages := [][]packngo.Device{
+		{
+			{ID: "web-1", Hostname: "web-1", Tags: []string{"adopt", "web"}},
+			{ID: "db-1", Hostname: "db-1", Tags: []string{"adopt-later"}, Description: &description},
+		},
+		{
+			{ID: "web-2", Hostname: "web-2", Tags: []string{"adopt"}},
+			{ID: "untagged", Hostname: "untagged"},
+			{ID: "deprovisioning", Hostname: "web-3", Tags: []string{"adopt"}, State: v1alpha2.StateDeprovisioning},
+			{ID: "terminated", Hostname: "web-4", Tags: []string{"adopt"}, SpotInstance: true, TerminationTime: &packngo.Timestamp{Time: time.Now().Add(-time.Hour)}},
+		},
+	}
+
+	var listed []int
+	c := &listClient{list: func(projectID string, listOpt *packngo.ListOptions) ([]packngo.Device, *packngo.Response, error) {
+		if projectID != "project" || listOpt.Search != "adopt" {
+			t.Errorf("List(...): unexpected project %q and search %q", projectID, listOpt.Search)
+		}
+		listed = append(listed, listOpt.Page)
+		if listOpt.Page < len(pages) {
+			listOpt.Meta.Next = &packngo.Href{Href: "/projects/project/devices?page=2"}
+		}
+		return pages[listOpt.Page-1], nil, nil
+	}}
+
+	got, err := ListByTag(c, "project", "adopt")
+	if err != nil {
+		t.Fatalf("ListByTag(...): %s", err)
+	}
+	want := []ListedDevice{{ID: "web-1", Hostname: "web-1"}, {ID: "web-2", Hostname: "web-2"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ListByTag(...): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]int{1, 2}, listed); diff != "" {
+		t.Errorf("ListByTag(...): -want pages, +got pages:\n%s", diff)
+	}
+}
+
+func TestMissingVLANs(t *testing.T) {
+	attached := func(ids ...string) []packngo.VirtualNetwork {
+		nets := make([]packngo.VirtualNetwork, len(ids))
+		for i, id := range ids {
+			nets[i] = packngo.VirtualNetwork{Href: "/virtual-networks/" + id}
+		}
+		return nets
+	}
+	withVLANs := func(networkType string, vlans ...string) parametersModifier {
+		return func(p *v1alpha2.DeviceParameters) {
+			p.NetworkType = &networkType
+			p.VLANs = vlans
+		}
+	}
+	// bonded returns a Device with bonded ports, the Device is layer3 when
+	// it has management addresses and layer2-bonded otherwise.
+	bonded := func(management bool, vlans ...string) *packngo.Device {
+		return &packngo.Device{
+			NetworkPorts: []packngo.Port{
+				{Name: "bond0", Type: "NetworkBondPort", Data: packngo.PortData{Bonded: true}, AttachedVirtualNetworks: attached(vlans...)},
+				{Name: "eth0", Type: "NetworkPort", Data: packngo.PortData{Bonded: true}},
+			},
+			Network: []*packngo.IPAddressAssignment{{
+				IpAddressCommon: packngo.IpAddressCommon{Management: management},
+			}},
+		}
+	}
+
+	cases := map[string]struct {
+		device *v1alpha2.Device
+		p      *packngo.Device
+		want   []string
+	}{
+		"NoVLANs": {
+			device: validDevice("my-device"),
+			p:      bonded(true),
+		},
+		"NotConverted": {
+			device: validDevice("my-device", withVLANs(packngo.NetworkTypeL2Bonded, "a", "b")),
+			p:      bonded(true),
+			want:   []string{"a", "b"},
+		},
+		"PartiallyAttached": {
+			device: validDevice("my-device", withVLANs(packngo.NetworkTypeL2Bonded, "a", "b")),
+			p:      bonded(false, "a", "c"),
+			want:   []string{"b"},
+		},
+		"AttachedToEth1": {
+			device: validDevice("my-device", withVLANs(packngo.NetworkTypeHybrid, "a")),
+			p: &packngo.Device{
+				Plan: &packngo.Plan{Slug: "baremetal_1e"},
+				NetworkPorts: []packngo.Port{
+					{Name: "bond0", AttachedVirtualNetworks: attached("b")},
+					{Name: "eth1", AttachedVirtualNetworks: attached("a")},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := MissingVLANs(tc.device, tc.p)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("MissingVLANs(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -32,15 +32,49 @@ type MockClient struct {
 	MockUpdate func(deviceID string, createRequest *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error)
 	MockDelete func(deviceID string, force bool) (*packngo.Response, error)
 	MockGet    func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error)
+	MockList   func(projectID string, listOpt *packngo.ListOptions) ([]packngo.Device, *packngo.Response, error)
+
+	MockPowerOn  func(deviceID string) (*packngo.Response, error)
+	MockPowerOff func(deviceID string) (*packngo.Response, error)
+	MockLock     func(deviceID string) (*packngo.Response, error)
+	MockUnlock   func(deviceID string) (*packngo.Response, error)
+	MockReboot   func(deviceID string) (*packngo.Response, error)
 
 	// mock the PortsClient
 
 	MockDeviceToNetworkType func(deviceID string, networkType string) (*packngo.Device, error)
 	MockDeviceNetworkType   func(deviceID string) (string, error)
 	MockConvertDevice       func(*packngo.Device, string) error
+	MockAssign              func(*packngo.PortAssignRequest) (*packngo.Port, *packngo.Response, error)
+
+	// mock the ActionsClient
+
+	MockReinstall func(deviceID string, reinstallRequest *device.ReinstallRequest) (*packngo.Response, error)
+
+	// mock the StorageClient
+
+	MockGetIQN func(deviceID string) (string, *packngo.Response, error)
+
+	// mock the NetworkClient
+
+	MockGetNetworkPorts func(deviceID string) ([]device.NetworkPort, *packngo.Response, error)
+
+	// mock the HardwareReservationsClient
+
+	MockGetHardwareReservation  func(hardwareReservationID string) (*packngo.HardwareReservation, *packngo.Response, error)
+	MockMoveHardwareReservation func(hardwareReservationID, projectID string) (*packngo.HardwareReservation, *packngo.Response, error)
+
+	// mock the FacilitiesClient
+
+	MockListFacilities func(listOpt *packngo.ListOptions) ([]packngo.Facility, *packngo.Response, error)
+
+	// mock the OperatingSystemsClient
+
+	MockListOperatingSystems func() ([]packngo.OS, *packngo.Response, error)
 
 	MockGetProjectID  func(string) string
 	MockGetFacilityID func(string) string
+	MockGetMetro      func(string) string
 }
 
 // Create calls the MockClient's MockCreate function.
@@ -55,7 +89,7 @@ func (c *MockClient) Update(deviceID string, createRequest *packngo.DeviceUpdate
 
 // Delete calls the MockClient's MockDelete function.
 func (c *MockClient) Delete(deviceID string, force bool) (*packngo.Response, error) {
-	return c.MockDelete(deviceID, false)
+	return c.MockDelete(deviceID, force)
 }
 
 // Get calls the MockClient's MockGet function.
@@ -63,22 +97,57 @@ func (c *MockClient) Get(deviceID string, options *packngo.GetOptions) (*packngo
 	return c.MockGet(deviceID, options)
 }
 
+// List calls the MockClient's MockList function.
+func (c *MockClient) List(projectID string, listOpt *packngo.ListOptions) ([]packngo.Device, *packngo.Response, error) {
+	return c.MockList(projectID, listOpt)
+}
+
+// PowerOn calls the MockClient's MockPowerOn function.
+func (c *MockClient) PowerOn(deviceID string) (*packngo.Response, error) {
+	return c.MockPowerOn(deviceID)
+}
+
+// PowerOff calls the MockClient's MockPowerOff function.
+func (c *MockClient) PowerOff(deviceID string) (*packngo.Response, error) {
+	return c.MockPowerOff(deviceID)
+}
+
+// Lock calls the MockClient's MockLock function.
+func (c *MockClient) Lock(deviceID string) (*packngo.Response, error) {
+	return c.MockLock(deviceID)
+}
+
+// Unlock calls the MockClient's MockUnlock function.
+func (c *MockClient) Unlock(deviceID string) (*packngo.Response, error) {
+	return c.MockUnlock(deviceID)
+}
+
 // DeviceToNetworkType calls the MockClient's MockDeviceToNetworkType function.
 func (c *MockClient) DeviceToNetworkType(deviceID string, networkType string) (*packngo.Device, error) {
 	return c.MockDeviceToNetworkType(deviceID, networkType)
 }
 
+// Assign calls the MockClient's MockAssign function.
+func (c *MockClient) Assign(par *packngo.PortAssignRequest) (*packngo.Port, *packngo.Response, error) {
+	return c.MockAssign(par)
+}
+
 // DeviceNetworkType calls the MockClient's MockDeviceNetworkType function.
 func (c *MockClient) DeviceNetworkType(deviceID string) (string, error) {
 	return c.MockDeviceNetworkType(deviceID)
 }
 
-// GetFacilityID calls the MockClient's MockGet function.
+// GetFacilityID calls the MockClient's MockGetFacilityID function.
 func (c *MockClient) GetFacilityID(id string) string {
 	return c.MockGetFacilityID(id)
 }
 
-// GetProjectID calls the MockClient's MockGet function.
+// GetMetro calls the MockClient's MockGetMetro function.
+func (c *MockClient) GetMetro(metro string) string {
+	return c.MockGetMetro(metro)
+}
+
+// GetProjectID calls the MockClient's MockGetProjectID function.
 func (c *MockClient) GetProjectID(id string) string {
 	return c.MockGetProjectID(id)
 }
@@ -87,3 +156,46 @@ func (c *MockClient) GetProjectID(id string) string {
 func (c *MockClient) ConvertDevice(d *packngo.Device, networkType string) error {
 	return c.MockConvertDevice(d, networkType)
 }
+
+// Reboot calls the MockClient's MockReboot function.
+func (c *MockClient) Reboot(deviceID string) (*packngo.Response, error) {
+	return c.MockReboot(deviceID)
+}
+
+// Reinstall calls the MockClient's MockReinstall function.
+func (c *MockClient) Reinstall(deviceID string, reinstallRequest *device.ReinstallRequest) (*packngo.Response, error) {
+	return c.MockReinstall(deviceID, reinstallRequest)
+}
+
+// GetIQN calls the MockClient's MockGetIQN function.
+func (c *MockClient) GetIQN(deviceID string) (string, *packngo.Response, error) {
+	return c.MockGetIQN(deviceID)
+}
+
+// GetNetworkPorts calls the MockClient's MockGetNetworkPorts function.
+func (c *MockClient) GetNetworkPorts(deviceID string) ([]device.NetworkPort, *packngo.Response, error) {
+	return c.MockGetNetworkPorts(deviceID)
+}
+
+// GetHardwareReservation calls the MockClient's MockGetHardwareReservation
+// function.
+func (c *MockClient) GetHardwareReservation(hardwareReservationID string) (*packngo.HardwareReservation, *packngo.Response, error) {
+	return c.MockGetHardwareReservation(hardwareReservationID)
+}
+
+// MoveHardwareReservation calls the MockClient's MockMoveHardwareReservation
+// function.
+func (c *MockClient) MoveHardwareReservation(hardwareReservationID, projectID string) (*packngo.HardwareReservation, *packngo.Response, error) {
+	return c.MockMoveHardwareReservation(hardwareReservationID, projectID)
+}
+
+// ListFacilities calls the MockClient's MockListFacilities function.
+func (c *MockClient) ListFacilities(listOpt *packngo.ListOptions) ([]packngo.Facility, *packngo.Response, error) {
+	return c.MockListFacilities(listOpt)
+}
+
+// ListOperatingSystems calls the MockClient's MockListOperatingSystems
+// function.
+func (c *MockClient) ListOperatingSystems() ([]packngo.OS, *packngo.Response, error) {
+	return c.MockListOperatingSystems()
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+)
+
+// AnnotationKeyExternalNameStrategy is the annotation that sets the
+// ExternalNameStrategy of a managed resource
+const AnnotationKeyExternalNameStrategy = "metal.equinix.com/external-name-strategy"
+
+// An ExternalNameStrategy determines what the external name of a managed
+// resource identifies its external resource by
+type ExternalNameStrategy string
+
+// External name strategies.
+const (
+	// ExternalNameStrategyID names the external resource by its ID. This is
+	// the default.
+	ExternalNameStrategyID ExternalNameStrategy = "ID"
+
+	// ExternalNameStrategyHostname names the external resource by its
+	// hostname. The external resource is still identified by its ID, which
+	// is observed in the status of the managed resource.
+	ExternalNameStrategyHostname ExternalNameStrategy = "Hostname"
+)
+
+const (
+	errExternalNameStrategyFmt = "unknown external name strategy %q"
+)
+
+// GetExternalNameStrategy returns the ExternalNameStrategy of the managed
+// resource
+func GetExternalNameStrategy(mg resource.Managed) (ExternalNameStrategy, error) {
+	switch s := ExternalNameStrategy(mg.GetAnnotations()[AnnotationKeyExternalNameStrategy]); s {
+	case "", ExternalNameStrategyID:
+		return ExternalNameStrategyID, nil
+	case ExternalNameStrategyHostname:
+		return s, nil
+	default:
+		return "", errors.Errorf(errExternalNameStrategyFmt, s)
+	}
+}
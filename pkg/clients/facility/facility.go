@@ -0,0 +1,185 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package facility
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+const (
+	// DefaultCacheTTL is how long a listing of facilities is cached
+	DefaultCacheTTL = time.Hour
+
+	// maxSuggestionDistance is the largest number of edits between a
+	// requested facility code and a suggested one
+	maxSuggestionDistance = 2
+
+	errUnknownFacility    = "facility %q does not exist"
+	errUnknownFacilityFmt = "facility %q does not exist, did you mean %q?"
+	errListFacilities     = "cannot list facilities"
+)
+
+// Client implements the Equinix Metal API methods needed to list facilities
+// for the Equinix Metal Crossplane Provider
+type Client interface {
+	List(*packngo.ListOptions) ([]packngo.Facility, *packngo.Response, error)
+}
+
+// build-time test that the interface is implemented
+var _ Client = (&packngo.Client{}).Facilities
+
+// ListFunc lists facilities. It allows clients of other services to be used
+// as a facility Client.
+type ListFunc func(*packngo.ListOptions) ([]packngo.Facility, *packngo.Response, error)
+
+// List calls the ListFunc
+func (f ListFunc) List(listOpt *packngo.ListOptions) ([]packngo.Facility, *packngo.Response, error) {
+	return f(listOpt)
+}
+
+// NewClient returns a Client implementing the Equinix Metal API methods needed
+// to list facilities for the Equinix Metal Crossplane Provider
+func NewClient(ctx context.Context, config *clients.Credentials) (Client, error) {
+	client, err := clients.NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return client.Client.Facilities, nil
+}
+
+// Cache caches the codes of the facilities listed by the Equinix Metal API so
+// that requested facilities can be validated without listing facilities on
+// every reconcile. The codes are listed again once they expire.
+type Cache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	codes   map[string]struct{}
+	expires time.Time
+}
+
+// NewCache returns a Cache of facility codes that expire after the supplied
+// TTL
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, now: time.Now}
+}
+
+// Codes returns the codes of all facilities, listing them with the supplied
+// Client when the cached codes have expired. Expired codes are returned when
+// the facilities can not be listed.
+func (c *Cache) Codes(client Client) (map[string]struct{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.codes != nil && c.now().Before(c.expires) {
+		return c.codes, nil
+	}
+
+	facilities, _, err := client.List(nil)
+	if err != nil {
+		if c.codes != nil {
+			return c.codes, nil
+		}
+		return nil, errors.Wrap(err, errListFacilities)
+	}
+
+	c.codes = make(map[string]struct{}, len(facilities))
+	for _, f := range facilities {
+		c.codes[strings.ToLower(f.Code)] = struct{}{}
+	}
+	c.expires = c.now().Add(c.ttl)
+	return c.codes, nil
+}
+
+// Validate returns an error if no facility has the supplied code. The error
+// suggests the closest facility code when one is similar. Facilities are not
+// validated while they can not be listed, leaving the Equinix Metal API to
+// reject unknown facilities.
+func (c *Cache) Validate(client Client, code string) error {
+	codes, err := c.Codes(client)
+	if err != nil {
+		return nil
+	}
+	code = strings.ToLower(code)
+	if _, ok := codes[code]; ok {
+		return nil
+	}
+	if s := suggest(codes, code); s != "" {
+		return errors.Errorf(errUnknownFacilityFmt, code, s)
+	}
+	return errors.Errorf(errUnknownFacility, code)
+}
+
+// suggest returns the code closest to the supplied code, or an empty string
+// when none is similar
+func suggest(codes map[string]struct{}, code string) string {
+	candidates := make([]string, 0, len(codes))
+	for c := range codes {
+		candidates = append(candidates, c)
+	}
+	// suggest the same code of equally distant codes consistently
+	sort.Strings(candidates)
+
+	best, bestDistance := "", maxSuggestionDistance+1
+	for _, c := range candidates {
+		if d := distance(code, c); d < bestDistance {
+			best, bestDistance = c, d
+		}
+	}
+	return best
+}
+
+// distance returns the Levenshtein distance between a and b
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package facility
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestCacheValidate(t *testing.T) {
+	facilities := []packngo.Facility{{Code: "sv15"}, {Code: "sv16"}, {Code: "da11"}, {Code: "AM6"}}
+
+	cases := map[string]struct {
+		code string
+		want error
+	}{
+		"Exists":           {code: "sv15"},
+		"ExistsIgnoreCase": {code: "am6"},
+		"Typo":             {code: "sv51", want: errors.Errorf(errUnknownFacilityFmt, "sv51", "sv15")},
+		"Missing":          {code: "ny5", want: errors.Errorf(errUnknownFacility, "ny5")},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewCache(time.Hour)
+			list := ListFunc(func(*packngo.ListOptions) ([]packngo.Facility, *packngo.Response, error) {
+				return facilities, nil, nil
+			})
+			if diff := cmp.Diff(tc.want, c.Validate(list, tc.code), test.EquateErrors()); diff != "" {
+				t.Errorf("Validate(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCacheRefresh(t *testing.T) {
+	now := time.Now()
+	var lists int
+	var fail bool
+	list := ListFunc(func(*packngo.ListOptions) ([]packngo.Facility, *packngo.Response, error) {
+		lists++
+		if fail {
+			return nil, nil, errors.New("boom")
+		}
+		if lists == 1 {
+			return []packngo.Facility{{Code: "sv15"}}, nil, nil
+		}
+		return []packngo.Facility{{Code: "sv15"}, {Code: "ny5"}}, nil, nil
+	})
+
+	c := NewCache(time.Hour)
+	c.now = func() time.Time { return now }
+
+	if err := c.Validate(list, "sv15"); err != nil {
+		t.Fatalf("Validate(...): %v", err)
+	}
+	if err := c.Validate(list, "ny5"); err == nil {
+		t.Errorf("Validate(...): want ny5 to be unknown before the cache expires")
+	}
+	if lists != 1 {
+		t.Errorf("Validate(...): want 1 listing while cached, got %d", lists)
+	}
+
+	// expired codes are listed again
+	now = now.Add(2 * time.Hour)
+	if err := c.Validate(list, "ny5"); err != nil {
+		t.Errorf("Validate(...): want ny5 to be known once the cache expires, got %v", err)
+	}
+	if lists != 2 {
+		t.Errorf("Validate(...): want 2 listings once expired, got %d", lists)
+	}
+
+	// expired codes are used while facilities can not be listed
+	now = now.Add(2 * time.Hour)
+	fail = true
+	if err := c.Validate(list, "ny5"); err != nil {
+		t.Errorf("Validate(...): want expired codes used when listing fails, got %v", err)
+	}
+	if err := NewCache(time.Hour).Validate(list, "ny5"); err != nil {
+		t.Errorf("Validate(...): want no validation without codes, got %v", err)
+	}
+}
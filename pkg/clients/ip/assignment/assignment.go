@@ -0,0 +1,187 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assignment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/ip/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+const (
+	errUnmarshalDate         = "cannot unmarshal date"
+	errNoAddress             = "one of address or ipReservationId must be set"
+	errReservationNotReady   = "IP reservation %s has not been allocated addresses yet"
+	errReservationExhausted  = "IP reservation %s has no available /%d block of addresses"
+	errGetReservation        = "cannot get IP reservation"
+	errGetAvailableAddresses = "cannot get available addresses of IP reservation"
+
+	// ipv4Address and ipv6Address are the CIDR of a single address
+	ipv4Address = 32
+	ipv6Address = 128
+)
+
+// Client implements the Equinix Metal API methods needed to interact with
+// IPAssignments for the Equinix Metal Crossplane Provider
+type Client interface {
+	Assign(deviceID string, assignRequest *packngo.AddressStruct) (*packngo.IPAddressAssignment, *packngo.Response, error)
+	Unassign(assignmentID string) (*packngo.Response, error)
+	Get(assignmentID string, getOpt *packngo.GetOptions) (*packngo.IPAddressAssignment, *packngo.Response, error)
+}
+
+// build-time test that the interface is implemented
+var _ Client = (&packngo.Client{}).DeviceIPs
+
+// ClientWithDefaults is an interface that provides IPAssignment services, the
+// lookups of the IP Reservations addresses are assigned from, and provides
+// default values for common properties
+type ClientWithDefaults interface {
+	Client
+	GetReservation(reservationID string, getOpt *packngo.GetOptions) (*packngo.IPAddressReservation, *packngo.Response, error)
+	AvailableAddresses(reservationID string, r *packngo.AvailableRequest) ([]string, *packngo.Response, error)
+	clients.DefaultGetter
+}
+
+// CredentialedClient is a credentialed client to Equinix Metal IPAssignment
+// services
+type CredentialedClient struct {
+	Client
+	*clients.Credentials
+
+	ips packngo.ProjectIPService
+}
+
+var _ ClientWithDefaults = &CredentialedClient{}
+
+// NewClient returns a Client implementing the Equinix Metal API methods needed
+// to interact with IPAssignments for the Equinix Metal Crossplane Provider
+func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefaults, error) {
+	client, err := clients.NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	assignmentClient := CredentialedClient{
+		Client:      client.Client.DeviceIPs,
+		Credentials: client.Credentials,
+		ips:         client.Client.ProjectIPs,
+	}
+	assignmentClient.SetProjectID(config.ProjectID)
+	return assignmentClient, nil
+}
+
+// GetReservation gets the IP Reservation with the given ID
+func (c CredentialedClient) GetReservation(reservationID string, getOpt *packngo.GetOptions) (*packngo.IPAddressReservation, *packngo.Response, error) {
+	return c.ips.Get(reservationID, getOpt)
+}
+
+// AvailableAddresses lists the blocks of addresses of the IP Reservation that
+// can be assigned
+func (c CredentialedClient) AvailableAddresses(reservationID string, r *packngo.AvailableRequest) ([]string, *packngo.Response, error) {
+	return c.ips.AvailableAddresses(reservationID, r)
+}
+
+// SelectAddress returns the block of addresses to assign for the supplied
+// Kubernetes resource. This is the address of the resource or, when none is
+// set, the first available block of its reservation. An error is returned
+// while the reservation has no addresses, or no block is available. These
+// errors may be retried once the reservation is provisioned or addresses are
+// unassigned.
+func SelectAddress(c ClientWithDefaults, a *v1alpha1.IPAssignment) (string, error) {
+	p := a.Spec.ForProvider
+	if p.Address != "" {
+		return p.Address, nil
+	}
+	if p.IPReservationID == "" {
+		return "", errors.New(errNoAddress)
+	}
+
+	r, _, err := c.GetReservation(p.IPReservationID, nil)
+	if clients.IsNotFound(err) || (err == nil && r.Network == "") {
+		return "", errors.Errorf(errReservationNotReady, p.IPReservationID)
+	}
+	if err != nil {
+		return "", errors.Wrap(err, errGetReservation)
+	}
+
+	cidr := ipv4Address
+	if r.AddressFamily == 6 {
+		cidr = ipv6Address
+	}
+	if p.CIDR != nil {
+		cidr = *p.CIDR
+	}
+
+	available, _, err := c.AvailableAddresses(p.IPReservationID, &packngo.AvailableRequest{CIDR: cidr})
+	if err != nil {
+		return "", errors.Wrap(err, errGetAvailableAddresses)
+	}
+	if len(available) == 0 {
+		return "", errors.Errorf(errReservationExhausted, p.IPReservationID, cidr)
+	}
+	return available[0], nil
+}
+
+// Address returns the block of addresses of the assignment in CIDR notation
+func Address(ip *packngo.IPAddressAssignment) string {
+	return fmt.Sprintf("%s/%d", ip.Network, ip.CIDR)
+}
+
+// IsAssignedTo returns true if the addresses are assigned to the Device
+func IsAssignedTo(ip *packngo.IPAddressAssignment, deviceID string) bool {
+	return deviceID != "" && strings.HasSuffix(strings.TrimSuffix(ip.AssignedTo.Href, "/"), "/"+deviceID)
+}
+
+// GenerateObservation produces v1alpha1.IPAssignmentObservation from
+// packngo.IPAddressAssignment
+func GenerateObservation(ip *packngo.IPAddressAssignment) (v1alpha1.IPAssignmentObservation, error) {
+	observation := v1alpha1.IPAssignmentObservation{
+		ID:            ip.ID,
+		Href:          ip.Href,
+		Address:       ip.Address,
+		Network:       ip.Network,
+		Gateway:       ip.Gateway,
+		Netmask:       ip.Netmask,
+		CIDR:          ip.CIDR,
+		Public:        ip.Public,
+		AddressFamily: ip.AddressFamily,
+	}
+
+	if ip.Created != "" {
+		observation.CreatedAt = &metav1.Time{}
+		if err := observation.CreatedAt.UnmarshalText([]byte(ip.Created)); err != nil {
+			return v1alpha1.IPAssignmentObservation{}, errors.Wrap(err, errUnmarshalDate)
+		}
+	}
+
+	return observation, nil
+}
+
+// LateInitialize fills the empty address of the supplied Kubernetes resource
+// with the addresses that were assigned, so that the same addresses are
+// assigned should the assignment be recreated
+func LateInitialize(in *v1alpha1.IPAssignmentParameters, ip *packngo.IPAddressAssignment) {
+	if in.Address == "" {
+		in.Address = Address(ip)
+	}
+}
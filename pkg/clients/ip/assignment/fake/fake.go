@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/packethost/packngo"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/ip/assignment"
+)
+
+var _ assignment.ClientWithDefaults = &MockClient{}
+
+// MockClient is a fake implementation of packngo.Client.
+type MockClient struct {
+	MockGet                func(assignmentID string, getOpt *packngo.GetOptions) (*packngo.IPAddressAssignment, *packngo.Response, error)
+	MockAssign             func(deviceID string, assignRequest *packngo.AddressStruct) (*packngo.IPAddressAssignment, *packngo.Response, error)
+	MockUnassign           func(assignmentID string) (*packngo.Response, error)
+	MockGetReservation     func(reservationID string, getOpt *packngo.GetOptions) (*packngo.IPAddressReservation, *packngo.Response, error)
+	MockAvailableAddresses func(reservationID string, r *packngo.AvailableRequest) ([]string, *packngo.Response, error)
+
+	MockGetProjectID  func(string) string
+	MockGetFacilityID func(string) string
+	MockGetMetro      func(string) string
+}
+
+// Get calls the MockClient's MockGet function.
+func (c *MockClient) Get(assignmentID string, getOpt *packngo.GetOptions) (*packngo.IPAddressAssignment, *packngo.Response, error) {
+	return c.MockGet(assignmentID, getOpt)
+}
+
+// Assign calls the MockClient's MockAssign function.
+func (c *MockClient) Assign(deviceID string, assignRequest *packngo.AddressStruct) (*packngo.IPAddressAssignment, *packngo.Response, error) {
+	return c.MockAssign(deviceID, assignRequest)
+}
+
+// Unassign calls the MockClient's MockUnassign function.
+func (c *MockClient) Unassign(assignmentID string) (*packngo.Response, error) {
+	return c.MockUnassign(assignmentID)
+}
+
+// GetReservation calls the MockClient's MockGetReservation function.
+func (c *MockClient) GetReservation(reservationID string, getOpt *packngo.GetOptions) (*packngo.IPAddressReservation, *packngo.Response, error) {
+	return c.MockGetReservation(reservationID, getOpt)
+}
+
+// AvailableAddresses calls the MockClient's MockAvailableAddresses function.
+func (c *MockClient) AvailableAddresses(reservationID string, r *packngo.AvailableRequest) ([]string, *packngo.Response, error) {
+	return c.MockAvailableAddresses(reservationID, r)
+}
+
+// GetFacilityID calls the MockClient's MockGetFacilityID function.
+func (c *MockClient) GetFacilityID(id string) string {
+	return c.MockGetFacilityID(id)
+}
+
+// GetMetro calls the MockClient's MockGetMetro function.
+func (c *MockClient) GetMetro(metro string) string {
+	return c.MockGetMetro(metro)
+}
+
+// GetProjectID calls the MockClient's MockGetProjectID function.
+func (c *MockClient) GetProjectID(id string) string {
+	return c.MockGetProjectID(id)
+}
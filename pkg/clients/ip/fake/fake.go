@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/packethost/packngo"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/ip"
+)
+
+var _ ip.ClientWithDefaults = &MockClient{}
+
+// MockClient is a fake implementation of packngo.Client.
+type MockClient struct {
+	MockGet     func(reservationID string, getOpt *packngo.GetOptions) (*ip.Reservation, *packngo.Response, error)
+	MockRequest func(projectID string, ipReservationReq *packngo.IPReservationRequest) (*packngo.IPAddressReservation, *packngo.Response, error)
+	MockRemove  func(ipReservationID string) (*packngo.Response, error)
+
+	MockGetProjectID  func(string) string
+	MockGetFacilityID func(string) string
+	MockGetMetro      func(string) string
+}
+
+// Get calls the MockClient's MockGet function.
+func (c *MockClient) Get(reservationID string, getOpt *packngo.GetOptions) (*ip.Reservation, *packngo.Response, error) {
+	return c.MockGet(reservationID, getOpt)
+}
+
+// Request calls the MockClient's MockRequest function.
+func (c *MockClient) Request(projectID string, ipReservationReq *packngo.IPReservationRequest) (*packngo.IPAddressReservation, *packngo.Response, error) {
+	return c.MockRequest(projectID, ipReservationReq)
+}
+
+// Remove calls the MockClient's MockRemove function.
+func (c *MockClient) Remove(ipReservationID string) (*packngo.Response, error) {
+	return c.MockRemove(ipReservationID)
+}
+
+// GetFacilityID calls the MockClient's MockGetFacilityID function.
+func (c *MockClient) GetFacilityID(id string) string {
+	return c.MockGetFacilityID(id)
+}
+
+// GetMetro calls the MockClient's MockGetMetro function.
+func (c *MockClient) GetMetro(metro string) string {
+	return c.MockGetMetro(metro)
+}
+
+// GetProjectID calls the MockClient's MockGetProjectID function.
+func (c *MockClient) GetProjectID(id string) string {
+	return c.MockGetProjectID(id)
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ip
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"sort"
+
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/ip/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+const (
+	errUnmarshalDate  = "cannot unmarshal date"
+	errGlobalLocation = "facility and metro must not be set for global_ipv4 reservations"
+
+	ipBasePath = "ips"
+)
+
+// Reservation is a packngo.IPAddressReservation including the state of the
+// reservation, which is not offered by packngo
+type Reservation struct {
+	packngo.IPAddressReservation
+	State string `json:"state,omitempty"`
+}
+
+// Client implements the Equinix Metal API methods needed to interact with IP
+// Reservations for the Equinix Metal Crossplane Provider
+type Client interface {
+	Get(reservationID string, getOpt *packngo.GetOptions) (*Reservation, *packngo.Response, error)
+	Request(projectID string, ipReservationReq *packngo.IPReservationRequest) (*packngo.IPAddressReservation, *packngo.Response, error)
+	Remove(ipReservationID string) (*packngo.Response, error)
+}
+
+// build-time test that the interface is implemented
+var _ Client = &reservationsClient{}
+
+// ClientWithDefaults is an interface that provides IP Reservation services
+// and provides default values for common properties
+type ClientWithDefaults interface {
+	Client
+	clients.DefaultGetter
+}
+
+// CredentialedClient is a credentialed client to Equinix Metal IP Reservation
+// services
+type CredentialedClient struct {
+	Client
+	*clients.Credentials
+}
+
+var _ ClientWithDefaults = &CredentialedClient{}
+
+// reservationsClient uses packngo.ProjectIPService for all methods but Get,
+// which must also decode the reservation state
+type reservationsClient struct {
+	packngo.ProjectIPService
+	client *packngo.Client
+}
+
+// Get gets an IP Reservation by ID
+func (c *reservationsClient) Get(reservationID string, getOpt *packngo.GetOptions) (*Reservation, *packngo.Response, error) {
+	r := new(Reservation)
+	resp, err := c.client.DoRequest("GET", getOpt.WithQuery(path.Join(ipBasePath, reservationID)), nil, r)
+	if err != nil {
+		return nil, resp, err
+	}
+	return r, resp, nil
+}
+
+// NewClient returns a Client implementing the Equinix Metal API methods needed
+// to interact with IP Reservations for the Equinix Metal Crossplane Provider
+func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefaults, error) {
+	client, err := clients.NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	ipClient := CredentialedClient{
+		Client: &reservationsClient{
+			ProjectIPService: client.Client.ProjectIPs,
+			client:           client.Client,
+		},
+		Credentials: client.Credentials,
+	}
+	ipClient.SetProjectID(config.ProjectID)
+	return ipClient, nil
+}
+
+// CreateFromReservation return packngo.IPReservationRequest created from
+// Kubernetes
+func CreateFromReservation(r *v1alpha1.Reservation) *packngo.IPReservationRequest {
+	return &packngo.IPReservationRequest{
+		Type:        r.Spec.ForProvider.Type,
+		Quantity:    r.Spec.ForProvider.Quantity,
+		Description: emptyIfNil(r.Spec.ForProvider.Details),
+		Facility:    r.Spec.ForProvider.Facility,
+		Metro:       r.Spec.ForProvider.Metro,
+		Tags:        r.Spec.ForProvider.Tags,
+	}
+}
+
+// ValidateReservation returns an error if the parameters of the supplied
+// Reservation can not be requested
+func ValidateReservation(r *v1alpha1.Reservation) error {
+	p := r.Spec.ForProvider
+	if IsGlobal(p.Type) && (p.Facility != nil || p.Metro != nil) {
+		return errors.New(errGlobalLocation)
+	}
+	return nil
+}
+
+// IsGlobal returns true if reservations of the supplied type are global
+func IsGlobal(reservationType string) bool {
+	return reservationType == v1alpha1.ReservationTypeGlobalIPv4
+}
+
+func emptyIfNil(in *string) string {
+	if in == nil {
+		return ""
+	}
+	return *in
+}
+
+// GenerateObservation produces v1alpha1.ReservationObservation from
+// Reservation
+func GenerateObservation(ip *Reservation) (v1alpha1.ReservationObservation, error) {
+	observation := v1alpha1.ReservationObservation{
+		ID:      ip.ID,
+		Href:    ip.Href,
+		State:   ip.State,
+		Network: ip.Network,
+		Address: ip.Address,
+		Gateway: ip.Gateway,
+		Netmask: ip.Netmask,
+		CIDR:    ip.CIDR,
+		Public:  ip.Public,
+		Global:  ip.Global,
+	}
+
+	if ip.Facility != nil {
+		observation.Facility = ip.Facility.Code
+	}
+	if ip.Metro != nil {
+		observation.Metro = ip.Metro.Code
+	}
+
+	metros := map[string]struct{}{}
+	for _, a := range ip.Assignments {
+		if a == nil {
+			continue
+		}
+		observation.Addresses = append(observation.Addresses, fmt.Sprintf("%s/%d", a.Address, a.CIDR))
+		if a.Metro != nil && a.Metro.Code != "" {
+			metros[a.Metro.Code] = struct{}{}
+		}
+	}
+	for m := range metros {
+		observation.AssignedMetros = append(observation.AssignedMetros, m)
+	}
+	sort.Strings(observation.AssignedMetros)
+
+	if ip.Created != "" {
+		observation.CreatedAt = &metav1.Time{}
+		if err := observation.CreatedAt.UnmarshalText([]byte(ip.Created)); err != nil {
+			return v1alpha1.ReservationObservation{}, errors.Wrap(err, errUnmarshalDate)
+		}
+	}
+
+	return observation, nil
+}
+
+// IsPending returns true if the supplied IP Reservation has been requested
+// but is awaiting approval
+func IsPending(ip *Reservation) bool {
+	return ip.State == v1alpha1.ReservationStateRequested || ip.State == v1alpha1.ReservationStatePending
+}
+
+// UnassignedAddresses returns the number of IPv4 addresses of the observed
+// reservation that have not been assigned to devices. Reservations that have
+// not been observed have no unassigned addresses.
+func UnassignedAddresses(o v1alpha1.ReservationObservation) int {
+	if o.CIDR <= 0 || o.CIDR > 32 {
+		return 0
+	}
+	unassigned := 1 << uint(32-o.CIDR)
+	for _, a := range o.Addresses {
+		size := 1
+		if _, n, err := net.ParseCIDR(a); err == nil {
+			ones, bits := n.Mask.Size()
+			size = 1 << uint(bits-ones)
+		}
+		unassigned -= size
+	}
+	if unassigned < 0 {
+		return 0
+	}
+	return unassigned
+}
+
+// LateInitialize fills the empty fields in *v1alpha1.ReservationParameters
+// with the values seen in Reservation
+func LateInitialize(in *v1alpha1.ReservationParameters, ip *Reservation) {
+	if ip == nil {
+		return
+	}
+
+	// Global reservations have no location
+	global := IsGlobal(in.Type) || ip.Global
+	if ip.Facility != nil && in.Metro == nil && !global {
+		in.Facility = clients.LateInitializeStringPtr(in.Facility, &ip.Facility.Code)
+	}
+	if ip.Metro != nil && in.Facility == nil && !global {
+		in.Metro = clients.LateInitializeStringPtr(in.Metro, &ip.Metro.Code)
+	}
+	if ip.Project.Href != "" {
+		projectID := path.Base(ip.Project.Href)
+		in.ProjectID = clients.LateInitializeStringPtr(in.ProjectID, &projectID)
+	}
+	in.Details = clients.LateInitializeStringPtr(in.Details, ip.Description)
+
+	if in.Tags == nil {
+		in.Tags = ip.Tags
+	}
+}
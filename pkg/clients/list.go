@@ -0,0 +1,39 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"github.com/packethost/packngo"
+)
+
+// ListPerPage is the number of results requested for each page by ListAll
+const ListPerPage = 100
+
+// ListAll calls list for each page of results, starting with the first, until
+// the Meta of a page has no next page. The ListOptions passed to list request
+// a single page, so that packngo returns that page alone and records its Meta.
+func ListAll(list func(opts *packngo.ListOptions) error) error {
+	for page := 1; ; page++ {
+		opts := &packngo.ListOptions{Page: page, PerPage: ListPerPage}
+		if err := list(opts); err != nil {
+			return err
+		}
+		if opts.Meta.Next == nil {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestListAll(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	// pages are the mocked responses, the next page of each is recorded in
+	// its Meta.
+	type page struct {
+		names []string
+		next  *packngo.Href
+		err   error
+	}
+	type want struct {
+		names []string
+		pages []int
+		err   error
+	}
+
+	cases := map[string]struct {
+		pages []page
+		want  want
+	}{
+		"SinglePage": {
+			pages: []page{
+				{names: []string{"a", "b"}},
+			},
+			want: want{
+				names: []string{"a", "b"},
+				pages: []int{1},
+			},
+		},
+		"TwoPages": {
+			pages: []page{
+				{names: []string{"a", "b"}, next: &packngo.Href{Href: "/organizations?page=2"}},
+				{names: []string{"c"}},
+			},
+			want: want{
+				names: []string{"a", "b", "c"},
+				pages: []int{1, 2},
+			},
+		},
+		"FailedSecondPage": {
+			pages: []page{
+				{names: []string{"a", "b"}, next: &packngo.Href{Href: "/organizations?page=2"}},
+				{err: errBoom},
+			},
+			want: want{
+				names: []string{"a", "b"},
+				pages: []int{1, 2},
+				err:   errBoom,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var names []string
+			var pages []int
+			err := ListAll(func(opts *packngo.ListOptions) error {
+				if opts.PerPage != ListPerPage {
+					t.Errorf("list(...): want %d per page, got %d", ListPerPage, opts.PerPage)
+				}
+				pages = append(pages, opts.Page)
+				p := tc.pages[opts.Page-1]
+				names = append(names, p.names...)
+				opts.Meta.Next = p.next
+				return p.err
+			})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("ListAll(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.names, names); diff != "" {
+				t.Errorf("ListAll(...): -want names, +got names:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.pages, pages); diff != "" {
+				t.Errorf("ListAll(...): -want pages, +got pages:\n%s", diff)
+			}
+		})
+	}
+}
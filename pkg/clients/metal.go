@@ -23,9 +23,11 @@ import (
 	"net/http"
 	"strings"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/packethost/packngo"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -46,6 +48,10 @@ type Client struct {
 const (
 	errVirtualNetworkAlreadyContents = " already "
 	errVirtualNetworkAlreadyPrefix   = "Virtual network"
+
+	errGetAPIKeySecret = "cannot get API key Secret"
+	errInvalidBaseURL  = "cannot use Equinix Metal API base URL"
+	errEmptyAPIKeyFmt  = "key %q of Secret %s/%s does not hold an API key"
 )
 
 // NewCredentialsFromJSON parses JSON bytes returning an Equinix Metal Credentials configuration
@@ -63,8 +69,23 @@ func NewClient(ctx context.Context, config *Credentials) (*Client, error) {
 	if apiKey == "" {
 		return nil, fmt.Errorf("Invalid APIKey in credentials")
 	}
-	apiClient := packngo.NewClientWithAuth("crossplane", apiKey, nil)
-	apiClient.UserAgent = fmt.Sprintf("crossplane-provider-equinix-metal/%s %s", version.Version, apiClient.UserAgent)
+	httpClient := newHTTPClient(ctx, config)
+	apiClient := packngo.NewClientWithAuth("crossplane", apiKey, httpClient)
+	if config.BaseURL != "" {
+		// API paths are resolved relative to the base URL, which must end
+		// with a slash to keep its own path
+		baseURL := strings.TrimSuffix(config.BaseURL, "/") + "/"
+		c, err := packngo.NewClientWithBaseURL("crossplane", apiKey, httpClient, baseURL)
+		if err != nil {
+			return nil, errors.Wrap(err, errInvalidBaseURL)
+		}
+		apiClient = c
+	}
+	apiClient.UserAgent = fmt.Sprintf("%s %s", userAgent(config), apiClient.UserAgent)
+
+	if err := credentialsValidator.Validate(apiClient, apiKey); err != nil {
+		return nil, err
+	}
 
 	client := &Client{
 		Client:      apiClient,
@@ -74,6 +95,16 @@ func NewClient(ctx context.Context, config *Credentials) (*Client, error) {
 	return client, nil
 }
 
+// userAgent returns the product that identifies the provider in the User-Agent
+// header, the name and version of the provider unless the credentials
+// replace it
+func userAgent(config *Credentials) string {
+	if config.UserAgent != "" {
+		return config.UserAgent
+	}
+	return fmt.Sprintf("crossplane-provider-equinix-metal/%s", version.Version)
+}
+
 // GetAuthInfo returns the necessary authentication information that is
 // necessary to use when the controller connects to Equinix Metal API in order
 // to reconcile the managed resource.
@@ -109,15 +140,110 @@ func UseProviderConfig(ctx context.Context, c client.Client, mg resource.Managed
 	if pc.Spec.ProjectID != "" {
 		config.SetProjectID(pc.Spec.ProjectID)
 	}
-	return config, err
+	config.RetryPolicy = DefaultRetryPolicy()
+	if pc.Spec.MaxRetries != nil {
+		config.RetryPolicy.MaxRetries = *pc.Spec.MaxRetries
+	}
+	if pc.Spec.RetryBaseDelay != nil {
+		config.RetryPolicy.BaseDelay = pc.Spec.RetryBaseDelay.Duration
+	}
+	if pc.Spec.RequestTimeout != nil {
+		config.RequestTimeout = pc.Spec.RequestTimeout.Duration
+	}
+	config.DefaultTags = pc.Spec.DefaultTags
+	config.BaseURL = pc.Spec.BaseURL
+	config.UserAgent = pc.Spec.UserAgent
+	config.UniqueHostnames = pc.Spec.UniqueHostnames
+	if pc.Spec.DeprovisionWarningThreshold != nil {
+		config.DeprovisionWarningThreshold = pc.Spec.DeprovisionWarningThreshold.Duration
+	}
+	config.Kind = kindOf(mg)
+
+	// Resources may use an API key of their own, such as a project API key
+	if r, ok := mg.(APIKeySecretReferencer); ok && r.GetAPIKeySecretRef() != nil {
+		apiKey, err := getAPIKey(ctx, c, r.GetAPIKeySecretRef())
+		if err != nil {
+			return nil, errors.Wrap(err, errGetAPIKeySecret)
+		}
+		config.APIKey = apiKey
+	}
+	return config, nil
+}
+
+// APIKeySecretReferencer is implemented by managed resources that may use an
+// Equinix Metal API key of their own rather than the API key of their
+// ProviderConfig
+type APIKeySecretReferencer interface {
+	GetAPIKeySecretRef() *xpv1.SecretKeySelector
+}
+
+// getAPIKey returns the API key held by the referenced Secret key
+func getAPIKey(ctx context.Context, c client.Client, ref *xpv1.SecretKeySelector) (string, error) {
+	s := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+		return "", err
+	}
+	apiKey := strings.TrimSpace(string(s.Data[ref.Key]))
+	if apiKey == "" {
+		return "", errors.Errorf(errEmptyAPIKeyFmt, ref.Key, ref.Namespace, ref.Name)
+	}
+	return apiKey, nil
+}
+
+// statusCode returns the HTTP status code of the Equinix Metal API response
+// that caused err, or 0 if err was not caused by an API response
+func statusCode(err error) int {
+	if e, ok := errors.Cause(err).(*packngo.ErrorResponse); ok && e.Response != nil {
+		return e.Response.StatusCode
+	}
+	return 0
 }
 
 // IsNotFound returns true if error is not found
 func IsNotFound(err error) bool {
-	if e, ok := err.(*packngo.ErrorResponse); ok && e.Response != nil {
-		return e.Response.StatusCode == http.StatusNotFound
-	}
-	return false
+	return statusCode(err) == http.StatusNotFound
+}
+
+// IsConflict returns true if the API rejected a request because it conflicts
+// with the current state of a resource
+func IsConflict(err error) bool {
+	return statusCode(err) == http.StatusConflict
+}
+
+// IsUnauthorized returns true if the API rejected the credentials of a
+// request as invalid or expired
+func IsUnauthorized(err error) bool {
+	return statusCode(err) == http.StatusUnauthorized
+}
+
+// IsForbidden returns true if the API credentials are not permitted to
+// perform the request
+func IsForbidden(err error) bool {
+	return statusCode(err) == http.StatusForbidden
+}
+
+// IsRateLimited returns true if the API rejected a request because too many
+// requests have been made
+func IsRateLimited(err error) bool {
+	return statusCode(err) == http.StatusTooManyRequests
+}
+
+// IsUnprocessable returns true if the API understood a request but could not
+// fulfill it, for example because there is no capacity for a plan in the
+// requested location
+func IsUnprocessable(err error) bool {
+	return statusCode(err) == http.StatusUnprocessableEntity
+}
+
+// IsMethodNotAllowed returns true if the API does not allow the method of a
+// request for the resource
+func IsMethodNotAllowed(err error) bool {
+	return statusCode(err) == http.StatusMethodNotAllowed
+}
+
+// IsNotImplemented returns true if the API does not implement a request
+func IsNotImplemented(err error) bool {
+	return statusCode(err) == http.StatusNotImplemented
 }
 
 // IsAlreadyDone returns true if, during VLAN assignment operations, the API
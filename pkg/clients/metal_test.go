@@ -0,0 +1,311 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/version"
+)
+
+// apiError returns the error packngo produces for an Equinix Metal API
+// response with the supplied status code and body.
+func apiError(t *testing.T, status int, body string) error {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	defer srv.Close()
+
+	c, err := packngo.NewClientWithBaseURL("test", "token", srv.Client(), srv.URL+"/")
+	if err != nil {
+		t.Fatalf("NewClientWithBaseURL(...): %v", err)
+	}
+	_, _, err = c.Devices.Get("cool-device", nil)
+	if err == nil {
+		t.Fatalf("Devices.Get(...): expected an error for status %d", status)
+	}
+	return err
+}
+
+func TestErrorClassification(t *testing.T) {
+	type want struct {
+		notFound      bool
+		conflict      bool
+		forbidden     bool
+		rateLimited   bool
+		unprocessable bool
+		notAllowed    bool
+		notImpl       bool
+	}
+
+	cases := map[string]struct {
+		status int
+		body   string
+		wrap   bool
+		want   want
+	}{
+		"NotFound": {
+			status: http.StatusNotFound,
+			body:   `{"errors":["Not found"]}`,
+			want:   want{notFound: true},
+		},
+		"Conflict": {
+			status: http.StatusConflict,
+			body:   `{"errors":["Device is locked"]}`,
+			want:   want{conflict: true},
+		},
+		"Forbidden": {
+			status: http.StatusForbidden,
+			body:   `{"errors":["You are not authorized to view this device"]}`,
+			want:   want{forbidden: true},
+		},
+		"RateLimited": {
+			status: http.StatusTooManyRequests,
+			body:   `{"error":"Too many requests"}`,
+			want:   want{rateLimited: true},
+		},
+		"Unprocessable": {
+			status: http.StatusUnprocessableEntity,
+			body:   `{"errors":["The facility sv15 has no provisionable c3.small.x86 servers matching your criteria."]}`,
+			want:   want{unprocessable: true},
+		},
+		"WrappedUnprocessable": {
+			status: http.StatusUnprocessableEntity,
+			body:   `{"errors":["The facility sv15 has no provisionable c3.small.x86 servers matching your criteria."]}`,
+			wrap:   true,
+			want:   want{unprocessable: true},
+		},
+		"MethodNotAllowed": {
+			status: http.StatusMethodNotAllowed,
+			body:   `{"errors":["Method not allowed"]}`,
+			want:   want{notAllowed: true},
+		},
+		"WrappedNotImplemented": {
+			status: http.StatusNotImplemented,
+			body:   `{"errors":["Not implemented"]}`,
+			wrap:   true,
+			want:   want{notImpl: true},
+		},
+		"InternalServerError": {
+			status: http.StatusInternalServerError,
+			body:   `{"errors":["Oh snap, something went wrong!"]}`,
+			want:   want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := apiError(t, tc.status, tc.body)
+			if tc.wrap {
+				err = errors.Wrap(err, "cannot create Device")
+			}
+
+			got := want{
+				notFound:      IsNotFound(err),
+				conflict:      IsConflict(err),
+				forbidden:     IsForbidden(err),
+				rateLimited:   IsRateLimited(err),
+				unprocessable: IsUnprocessable(err),
+				notAllowed:    IsMethodNotAllowed(err),
+				notImpl:       IsNotImplemented(err),
+			}
+			if got != tc.want {
+				t.Errorf("%s: -want %+v, +got %+v", err, tc.want, got)
+			}
+		})
+	}
+
+	t.Run("NotAPIError", func(t *testing.T) {
+		err := errors.New("boom")
+		if IsNotFound(err) || IsConflict(err) || IsForbidden(err) || IsRateLimited(err) || IsUnprocessable(err) {
+			t.Errorf("%s: expected no classification for a non-API error", err)
+		}
+	})
+}
+
+func TestUseProviderConfigAPIKey(t *testing.T) {
+	secrets := map[string]map[string][]byte{
+		"provider": {"credentials": []byte(`{"apiKey":"provider-key","projectID":"provider-project"}`)},
+		"project":  {"token": []byte("project-key\n"), "empty": nil},
+	}
+
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *v1beta1.ProviderConfig:
+				o.Spec.Credentials = v1beta1.ProviderCredentials{
+					Source: xpv1.CredentialsSourceSecret,
+					CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+						SecretRef: &xpv1.SecretKeySelector{
+							SecretReference: xpv1.SecretReference{Namespace: "crossplane-system", Name: "provider"},
+							Key:             "credentials",
+						},
+					},
+				}
+			case *corev1.Secret:
+				data, ok := secrets[key.Name]
+				if !ok {
+					return kerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, key.Name)
+				}
+				o.Data = data
+			default:
+				return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+			}
+			return nil
+		},
+		MockCreate: test.NewMockCreateFn(nil),
+	}
+
+	ref := func(name, key string) *xpv1.SecretKeySelector {
+		return &xpv1.SecretKeySelector{
+			SecretReference: xpv1.SecretReference{Namespace: "team", Name: name},
+			Key:             key,
+		}
+	}
+
+	cases := map[string]struct {
+		ref     *xpv1.SecretKeySelector
+		want    string
+		wantErr bool
+	}{
+		"ProviderConfigAPIKey": {
+			want: "provider-key",
+		},
+		"ResourceAPIKey": {
+			ref:  ref("project", "token"),
+			want: "project-key",
+		},
+		"EmptyAPIKey": {
+			ref:     ref("project", "empty"),
+			wantErr: true,
+		},
+		"MissingSecret": {
+			ref:     ref("missing", "token"),
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := &v1alpha2.Device{}
+			d.SetProviderConfigReference(&xpv1.Reference{Name: "default"})
+			d.Spec.APIKeySecretRef = tc.ref
+
+			config, err := UseProviderConfig(context.Background(), kube, d)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("UseProviderConfig(...): want error %t, got %v", tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want, config.APIKey); diff != "" {
+				t.Errorf("UseProviderConfig(...): -want API key, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff("provider-project", config.ProjectID); diff != "" {
+				t.Errorf("UseProviderConfig(...): -want project, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewClientBaseURL(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	// the base URL is used with or without a trailing slash
+	for _, baseURL := range []string{srv.URL + "/metal/v1", srv.URL + "/metal/v1/"} {
+		paths = nil
+		c, err := NewClient(context.Background(), &Credentials{APIKey: "base-url-" + baseURL, BaseURL: baseURL})
+		if err != nil {
+			t.Fatalf("NewClient(...): %v", err)
+		}
+		if diff := cmp.Diff(srv.URL+"/metal/v1/", c.Client.BaseURL.String()); diff != "" {
+			t.Errorf("NewClient(...): -want base URL, +got:\n%s", diff)
+		}
+		// the API key is validated by the API at the base URL
+		if diff := cmp.Diff([]string{"/metal/v1/user"}, paths); diff != "" {
+			t.Errorf("NewClient(...): -want requests, +got:\n%s", diff)
+		}
+	}
+
+	if _, err := NewClient(context.Background(), &Credentials{APIKey: "key", BaseURL: "http://[::1"}); err == nil {
+		t.Errorf("NewClient(...): want error for invalid base URL")
+	}
+}
+
+func TestNewClientUserAgent(t *testing.T) {
+	var agents []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agents = append(agents, r.Header.Get("User-Agent"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	cases := map[string]struct {
+		userAgent string
+		want      string
+	}{
+		"Default": {
+			want: "crossplane-provider-equinix-metal/" + version.Version + " " + packngo.UserAgent,
+		},
+		"ProviderConfigUserAgent": {
+			userAgent: "my-platform/1.2",
+			want:      "my-platform/1.2 " + packngo.UserAgent,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			agents = nil
+			// the API key differs by case so that it is validated, and the
+			// header sent, by each case
+			_, err := NewClient(context.Background(), &Credentials{APIKey: "user-agent-" + name, BaseURL: srv.URL, UserAgent: tc.userAgent})
+			if err != nil {
+				t.Fatalf("NewClient(...): %v", err)
+			}
+			if diff := cmp.Diff([]string{tc.want}, agents); diff != "" {
+				t.Errorf("NewClient(...): -want User-Agent, +got:\n%s", diff)
+			}
+		})
+	}
+}
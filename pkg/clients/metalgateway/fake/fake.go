@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/packethost/packngo"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/metalgateway"
+)
+
+var _ metalgateway.ClientWithDefaults = &MockClient{}
+
+// MockClient is a fake implementation of the MetalGateway client.
+type MockClient struct {
+	MockCreate            func(projectID string, createRequest *metalgateway.CreateRequest) (*metalgateway.MetalGateway, *packngo.Response, error)
+	MockGet               func(gatewayID string) (*metalgateway.MetalGateway, *packngo.Response, error)
+	MockDelete            func(gatewayID string) (*packngo.Response, error)
+	MockGetVirtualNetwork func(vlanID string) (*packngo.VirtualNetwork, *packngo.Response, error)
+	MockGetIPReservation  func(reservationID string) (*packngo.IPAddressReservation, *packngo.Response, error)
+
+	MockGetProjectID  func(string) string
+	MockGetFacilityID func(string) string
+	MockGetMetro      func(string) string
+}
+
+// Create calls the MockClient's MockCreate function.
+func (c *MockClient) Create(projectID string, createRequest *metalgateway.CreateRequest) (*metalgateway.MetalGateway, *packngo.Response, error) {
+	return c.MockCreate(projectID, createRequest)
+}
+
+// Get calls the MockClient's MockGet function.
+func (c *MockClient) Get(gatewayID string) (*metalgateway.MetalGateway, *packngo.Response, error) {
+	return c.MockGet(gatewayID)
+}
+
+// Delete calls the MockClient's MockDelete function.
+func (c *MockClient) Delete(gatewayID string) (*packngo.Response, error) {
+	return c.MockDelete(gatewayID)
+}
+
+// GetVirtualNetwork calls the MockClient's MockGetVirtualNetwork function.
+func (c *MockClient) GetVirtualNetwork(vlanID string) (*packngo.VirtualNetwork, *packngo.Response, error) {
+	return c.MockGetVirtualNetwork(vlanID)
+}
+
+// GetIPReservation calls the MockClient's MockGetIPReservation function.
+func (c *MockClient) GetIPReservation(reservationID string) (*packngo.IPAddressReservation, *packngo.Response, error) {
+	return c.MockGetIPReservation(reservationID)
+}
+
+// GetFacilityID calls the MockClient's MockGetFacilityID function.
+func (c *MockClient) GetFacilityID(id string) string {
+	return c.MockGetFacilityID(id)
+}
+
+// GetMetro calls the MockClient's MockGetMetro function.
+func (c *MockClient) GetMetro(metro string) string {
+	return c.MockGetMetro(metro)
+}
+
+// GetProjectID calls the MockClient's MockGetProjectID function.
+func (c *MockClient) GetProjectID(id string) string {
+	return c.MockGetProjectID(id)
+}
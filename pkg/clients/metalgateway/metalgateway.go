@@ -0,0 +1,243 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metalgateway
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/metalgateway/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+const (
+	errUnmarshalDate           = "cannot unmarshal date"
+	errNoVirtualNetwork        = "a VirtualNetwork must be set"
+	errSubnetConflict          = "only one of ipReservationId or privateIpv4SubnetSize may be set"
+	errSubnetMissing           = "one of ipReservationId or privateIpv4SubnetSize must be set"
+	errVirtualNetworkNotReady  = "VirtualNetwork %s is not ready"
+	errIPReservationNotReady   = "IP reservation %s is not ready"
+	errGetVirtualNetwork       = "cannot get VirtualNetwork"
+	errGetIPReservation        = "cannot get IP reservation"
+	metalGatewayPathFmt        = "metal-gateways/%s"
+	projectMetalGatewayPathFmt = "projects/%s/metal-gateways"
+)
+
+// includes are the related resources returned along with a MetalGateway
+var includes = &packngo.GetOptions{Includes: []string{"ip_reservation"}}
+
+// MetalGateway is an Equinix Metal gateway
+type MetalGateway struct {
+	ID             string                        `json:"id"`
+	Href           string                        `json:"href,omitempty"`
+	State          string                        `json:"state,omitempty"`
+	VirtualNetwork *packngo.VirtualNetwork       `json:"virtual_network,omitempty"`
+	IPReservation  *packngo.IPAddressReservation `json:"ip_reservation,omitempty"`
+	CreatedAt      string                        `json:"created_at,omitempty"`
+	UpdatedAt      string                        `json:"updated_at,omitempty"`
+}
+
+// CreateRequest is the body of a MetalGateway creation
+type CreateRequest struct {
+	VirtualNetworkID      string `json:"virtual_network_id"`
+	IPReservationID       string `json:"ip_reservation_id,omitempty"`
+	PrivateIPv4SubnetSize int    `json:"private_ipv4_subnet_size,omitempty"`
+}
+
+// Client implements the Equinix Metal API methods needed to interact with
+// MetalGateways for the Equinix Metal Crossplane Provider. MetalGateways are
+// not offered by packngo, they are requested through the packngo request
+// helpers.
+type Client interface {
+	Create(projectID string, createRequest *CreateRequest) (*MetalGateway, *packngo.Response, error)
+	Get(gatewayID string) (*MetalGateway, *packngo.Response, error)
+	Delete(gatewayID string) (*packngo.Response, error)
+}
+
+// DependencyClient implements the Equinix Metal API methods needed to find
+// the VirtualNetwork and IP reservation of a MetalGateway
+type DependencyClient interface {
+	GetVirtualNetwork(vlanID string) (*packngo.VirtualNetwork, *packngo.Response, error)
+	GetIPReservation(reservationID string) (*packngo.IPAddressReservation, *packngo.Response, error)
+}
+
+// build-time test that the interface is implemented
+var _ Client = &gatewayClient{}
+var _ DependencyClient = &dependencyClient{}
+
+// ClientWithDefaults is an interface that provides MetalGateway services and
+// provides default values for common properties
+type ClientWithDefaults interface {
+	Client
+	DependencyClient
+	clients.DefaultGetter
+}
+
+// CredentialedClient is a credentialed client to Equinix Metal MetalGateway
+// services
+type CredentialedClient struct {
+	Client
+	DependencyClient
+	*clients.Credentials
+}
+
+var _ ClientWithDefaults = &CredentialedClient{}
+
+// gatewayClient requests MetalGateways through the packngo request helpers
+type gatewayClient struct {
+	client *packngo.Client
+}
+
+// Create a MetalGateway in a project
+func (c *gatewayClient) Create(projectID string, createRequest *CreateRequest) (*MetalGateway, *packngo.Response, error) {
+	gw := new(MetalGateway)
+	resp, err := c.client.DoRequest("POST", includes.WithQuery(fmt.Sprintf(projectMetalGatewayPathFmt, projectID)), createRequest, gw)
+	if err != nil {
+		return nil, resp, err
+	}
+	return gw, resp, nil
+}
+
+// Get a MetalGateway
+func (c *gatewayClient) Get(gatewayID string) (*MetalGateway, *packngo.Response, error) {
+	gw := new(MetalGateway)
+	resp, err := c.client.DoRequest("GET", includes.WithQuery(fmt.Sprintf(metalGatewayPathFmt, gatewayID)), nil, gw)
+	if err != nil {
+		return nil, resp, err
+	}
+	return gw, resp, nil
+}
+
+// Delete a MetalGateway
+func (c *gatewayClient) Delete(gatewayID string) (*packngo.Response, error) {
+	return c.client.DoRequest("DELETE", fmt.Sprintf(metalGatewayPathFmt, gatewayID), nil, nil)
+}
+
+// dependencyClient gets VirtualNetworks and IP reservations through their
+// packngo services
+type dependencyClient struct {
+	vlans packngo.ProjectVirtualNetworkService
+	ips   packngo.ProjectIPService
+}
+
+// GetVirtualNetwork gets a VirtualNetwork
+func (c *dependencyClient) GetVirtualNetwork(vlanID string) (*packngo.VirtualNetwork, *packngo.Response, error) {
+	return c.vlans.Get(vlanID, nil)
+}
+
+// GetIPReservation gets an IP reservation
+func (c *dependencyClient) GetIPReservation(reservationID string) (*packngo.IPAddressReservation, *packngo.Response, error) {
+	return c.ips.Get(reservationID, nil)
+}
+
+// NewClient returns a Client implementing the Equinix Metal API methods needed
+// to interact with MetalGateways for the Equinix Metal Crossplane Provider
+func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefaults, error) {
+	client, err := clients.NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	gatewayClient := CredentialedClient{
+		Client:           &gatewayClient{client: client.Client},
+		DependencyClient: &dependencyClient{vlans: client.Client.ProjectVirtualNetworks, ips: client.Client.ProjectIPs},
+		Credentials:      client.Credentials,
+	}
+	gatewayClient.SetProjectID(config.ProjectID)
+	return gatewayClient, nil
+}
+
+// CreateFromMetalGateway returns a CreateRequest created from Kubernetes
+func CreateFromMetalGateway(g *v1alpha1.MetalGateway) *CreateRequest {
+	p := g.Spec.ForProvider
+	r := &CreateRequest{
+		VirtualNetworkID: p.VirtualNetworkID,
+		IPReservationID:  p.IPReservationID,
+	}
+	if p.PrivateIPv4SubnetSize != nil {
+		r.PrivateIPv4SubnetSize = *p.PrivateIPv4SubnetSize
+	}
+	return r
+}
+
+// ValidateParameters returns an error if the supplied MetalGateway does not
+// set a VirtualNetwork and exactly one of an IP reservation or a private
+// subnet size
+func ValidateParameters(g *v1alpha1.MetalGateway) error {
+	p := g.Spec.ForProvider
+	switch {
+	case p.VirtualNetworkID == "":
+		return errors.New(errNoVirtualNetwork)
+	case p.IPReservationID != "" && p.PrivateIPv4SubnetSize != nil:
+		return errors.New(errSubnetConflict)
+	case p.IPReservationID == "" && p.PrivateIPv4SubnetSize == nil:
+		return errors.New(errSubnetMissing)
+	}
+	return nil
+}
+
+// ValidateDependencies returns an error if the VirtualNetwork, or the IP
+// reservation, of the supplied MetalGateway does not exist yet or has not
+// been allocated a subnet. The error may be retried until they are ready.
+func ValidateDependencies(c DependencyClient, g *v1alpha1.MetalGateway) error {
+	p := g.Spec.ForProvider
+	if _, _, err := c.GetVirtualNetwork(p.VirtualNetworkID); err != nil {
+		if clients.IsNotFound(err) {
+			return errors.Errorf(errVirtualNetworkNotReady, p.VirtualNetworkID)
+		}
+		return errors.Wrap(err, errGetVirtualNetwork)
+	}
+
+	if p.IPReservationID == "" {
+		return nil
+	}
+	ip, _, err := c.GetIPReservation(p.IPReservationID)
+	if clients.IsNotFound(err) || (err == nil && ip.Network == "") {
+		return errors.Errorf(errIPReservationNotReady, p.IPReservationID)
+	}
+	return errors.Wrap(err, errGetIPReservation)
+}
+
+// GenerateObservation produces v1alpha1.MetalGatewayObservation from
+// MetalGateway
+func GenerateObservation(gw *MetalGateway) (v1alpha1.MetalGatewayObservation, error) {
+	observation := v1alpha1.MetalGatewayObservation{
+		ID:    gw.ID,
+		Href:  gw.Href,
+		State: gw.State,
+	}
+
+	if ip := gw.IPReservation; ip != nil {
+		observation.IPReservationID = ip.ID
+		observation.Address = ip.Gateway
+		if ip.Network != "" {
+			observation.Subnet = fmt.Sprintf("%s/%d", ip.Network, ip.CIDR)
+		}
+	}
+
+	if gw.CreatedAt != "" {
+		observation.CreatedAt = &metav1.Time{}
+		if err := observation.CreatedAt.UnmarshalText([]byte(gw.CreatedAt)); err != nil {
+			return v1alpha1.MetalGatewayObservation{}, errors.Wrap(err, errUnmarshalDate)
+		}
+	}
+
+	return observation, nil
+}
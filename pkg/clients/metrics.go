@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// statusClassError labels requests that failed without a response
+const statusClassError = "error"
+
+var (
+	apiRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "equinix_metal",
+		Subsystem: "api",
+		Name:      "requests_total",
+		Help:      "Number of Equinix Metal API requests by managed resource kind, method and status class.",
+	}, []string{"kind", "method", "code"})
+
+	apiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "equinix_metal",
+		Subsystem: "api",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of Equinix Metal API requests by managed resource kind, method and status class.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"kind", "method", "code"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(apiRequests, apiRequestDuration)
+}
+
+// metricsTransport is an http.RoundTripper that records the number and
+// latency of requests
+type metricsTransport struct {
+	kind string
+	next http.RoundTripper
+}
+
+var _ http.RoundTripper = &metricsTransport{}
+
+// newMetricsTransport returns an http.RoundTripper that records the requests
+// made through next for the supplied managed resource kind
+func newMetricsTransport(next http.RoundTripper, kind string) http.RoundTripper {
+	return &metricsTransport{kind: kind, next: next}
+}
+
+// RoundTrip performs the request and records it
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+
+	code := statusClass(resp, err)
+	apiRequests.WithLabelValues(t.kind, req.Method, code).Inc()
+	apiRequestDuration.WithLabelValues(t.kind, req.Method, code).Observe(time.Since(start).Seconds())
+
+	return resp, err
+}
+
+// statusClass returns the class of the status code of the response, such as
+// 2xx, or error when the request failed without a response
+func statusClass(resp *http.Response, err error) string {
+	if err != nil || resp == nil {
+		return statusClassError
+	}
+	return strconv.Itoa(resp.StatusCode/100) + "xx"
+}
+
+// kindOf returns the kind of the supplied managed resource. Typed resources
+// read through a client may not have their kind set, the name of their type
+// is used instead.
+func kindOf(mg resource.Managed) string {
+	if k := mg.GetObjectKind().GroupVersionKind().Kind; k != "" {
+		return k
+	}
+	return reflect.Indirect(reflect.ValueOf(mg)).Type().Name()
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/packethost/packngo"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsTransport(t *testing.T) {
+	cases := map[string]struct {
+		status int
+		close  bool
+		want   string
+	}{
+		"Succeeded": {
+			status: http.StatusOK,
+			want:   "2xx",
+		},
+		"NotFound": {
+			status: http.StatusNotFound,
+			want:   "4xx",
+		},
+		"ServerError": {
+			status: http.StatusInternalServerError,
+			want:   "5xx",
+		},
+		"NoResponse": {
+			close: true,
+			want:  statusClassError,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer srv.Close()
+			if tc.close {
+				srv.Close()
+			}
+
+			kind := "Metrics" + name
+			c, err := packngo.NewClientWithBaseURL("test", "token", &http.Client{
+				Transport: newMetricsTransport(http.DefaultTransport, kind),
+			}, srv.URL+"/")
+			if err != nil {
+				t.Fatalf("NewClientWithBaseURL(...): %v", err)
+			}
+
+			_, _, _ = c.Projects.Get("project", nil)
+
+			if got := testutil.ToFloat64(apiRequests.WithLabelValues(kind, http.MethodGet, tc.want)); got != 1 {
+				t.Errorf("requests_total{code=%q}: want 1, got %v", tc.want, got)
+			}
+		})
+	}
+}
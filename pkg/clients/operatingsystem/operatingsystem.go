@@ -0,0 +1,145 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operatingsystem
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+const (
+	// DefaultCacheTTL is how long a listing of operating systems is cached
+	DefaultCacheTTL = time.Hour
+
+	errUnknownOS        = "operating system %q does not exist"
+	errIncompatiblePlan = "operating system %q is not available on plan %q, it is available on plans: %s"
+	errListOSs          = "cannot list operating systems"
+)
+
+// Client implements the Equinix Metal API methods needed to list operating
+// systems for the Equinix Metal Crossplane Provider
+type Client interface {
+	List() ([]packngo.OS, *packngo.Response, error)
+}
+
+// build-time test that the interface is implemented
+var _ Client = (&packngo.Client{}).OperatingSystems
+
+// ListFunc lists operating systems. It allows clients of other services to be
+// used as an operating system Client.
+type ListFunc func() ([]packngo.OS, *packngo.Response, error)
+
+// List calls the ListFunc
+func (f ListFunc) List() ([]packngo.OS, *packngo.Response, error) {
+	return f()
+}
+
+// NewClient returns a Client implementing the Equinix Metal API methods needed
+// to list operating systems for the Equinix Metal Crossplane Provider
+func NewClient(ctx context.Context, config *clients.Credentials) (Client, error) {
+	client, err := clients.NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return client.Client.OperatingSystems, nil
+}
+
+// Cache caches the operating systems listed by the Equinix Metal API, and the
+// plans each is provisionable on, so that requested operating systems can be
+// validated without listing them on every reconcile. The operating systems
+// are listed again once they expire.
+type Cache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	plans   map[string][]string
+	expires time.Time
+}
+
+// NewCache returns a Cache of operating systems that expire after the
+// supplied TTL
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, now: time.Now}
+}
+
+// Plans returns the plans each operating system is provisionable on, by
+// operating system slug, listing them with the supplied Client when the cached
+// operating systems have expired. Expired operating systems are returned when
+// they can not be listed.
+func (c *Cache) Plans(client Client) (map[string][]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.plans != nil && c.now().Before(c.expires) {
+		return c.plans, nil
+	}
+
+	oss, _, err := client.List()
+	if err != nil {
+		if c.plans != nil {
+			return c.plans, nil
+		}
+		return nil, errors.Wrap(err, errListOSs)
+	}
+
+	c.plans = make(map[string][]string, len(oss))
+	for _, os := range oss {
+		plans := make([]string, 0, len(os.ProvisionableOn))
+		for _, p := range os.ProvisionableOn {
+			plans = append(plans, strings.ToLower(p))
+		}
+		sort.Strings(plans)
+		c.plans[strings.ToLower(os.Slug)] = plans
+	}
+	c.expires = c.now().Add(c.ttl)
+	return c.plans, nil
+}
+
+// Validate returns an error if no operating system has the supplied slug, or
+// if the operating system is not provisionable on the supplied plan. The
+// error lists the plans the operating system is provisionable on. Operating
+// systems are not validated while they can not be listed, leaving the Equinix
+// Metal API to reject them.
+func (c *Cache) Validate(client Client, os, plan string) error {
+	all, err := c.Plans(client)
+	if err != nil {
+		return nil
+	}
+	os, plan = strings.ToLower(os), strings.ToLower(plan)
+	plans, ok := all[os]
+	if !ok {
+		return errors.Errorf(errUnknownOS, os)
+	}
+	// operating systems that list no plans are not restricted
+	if len(plans) == 0 || plan == "" {
+		return nil
+	}
+	i := sort.SearchStrings(plans, plan)
+	if i < len(plans) && plans[i] == plan {
+		return nil
+	}
+	return errors.Errorf(errIncompatiblePlan, os, plan, strings.Join(plans, ", "))
+}
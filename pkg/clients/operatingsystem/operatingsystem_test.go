@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operatingsystem
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestCacheValidate(t *testing.T) {
+	oss := []packngo.OS{
+		{Slug: "ubuntu_20_04", ProvisionableOn: []string{"c3.small.x86", "m3.large.x86"}},
+		{Slug: "windows_2019", ProvisionableOn: []string{"m3.large.x86"}},
+		{Slug: "custom_ipxe"},
+	}
+
+	cases := map[string]struct {
+		os   string
+		plan string
+		want error
+	}{
+		"Compatible":   {os: "ubuntu_20_04", plan: "c3.small.x86"},
+		"IgnoreCase":   {os: "Ubuntu_20_04", plan: "M3.Large.x86"},
+		"Unrestricted": {os: "custom_ipxe", plan: "c3.small.x86"},
+		"UnknownOS":    {os: "ubuntu_99_04", plan: "c3.small.x86", want: errors.Errorf(errUnknownOS, "ubuntu_99_04")},
+		"IncompatiblePlan": {
+			os:   "windows_2019",
+			plan: "c3.small.x86",
+			want: errors.New(`operating system "windows_2019" is not available on plan "c3.small.x86", it is available on plans: m3.large.x86`),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewCache(time.Hour)
+			list := ListFunc(func() ([]packngo.OS, *packngo.Response, error) { return oss, nil, nil })
+			if diff := cmp.Diff(tc.want, c.Validate(list, tc.os, tc.plan), test.EquateErrors()); diff != "" {
+				t.Errorf("Validate(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCacheRefresh(t *testing.T) {
+	now := time.Now()
+	var lists int
+	list := ListFunc(func() ([]packngo.OS, *packngo.Response, error) {
+		lists++
+		if lists > 2 {
+			return nil, nil, errors.New("boom")
+		}
+		return []packngo.OS{{Slug: "ubuntu_20_04"}}, nil, nil
+	})
+
+	c := NewCache(time.Hour)
+	c.now = func() time.Time { return now }
+	for i := 0; i < 3; i++ {
+		if err := c.Validate(list, "ubuntu_20_04", "c3.small.x86"); err != nil {
+			t.Fatalf("Validate(...): %v", err)
+		}
+	}
+	if lists != 1 {
+		t.Errorf("Validate(...): want 1 listing while cached, got %d", lists)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if err := c.Validate(list, "ubuntu_20_04", "c3.small.x86"); err != nil {
+		t.Fatalf("Validate(...): %v", err)
+	}
+	if lists != 2 {
+		t.Errorf("Validate(...): want 2 listings once expired, got %d", lists)
+	}
+
+	// expired operating systems are used while they can not be listed
+	now = now.Add(2 * time.Hour)
+	if err := c.Validate(list, "ubuntu_99_04", ""); err == nil {
+		t.Errorf("Validate(...): want expired operating systems used when listing fails")
+	}
+}
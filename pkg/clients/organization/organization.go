@@ -0,0 +1,130 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package organization
+
+import (
+	"context"
+
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/organization/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+const (
+	errUnmarshalDate         = "cannot unmarshal date"
+	errOrganizationNotFound  = "cannot find Organization named %q"
+	errOrganizationAmbiguous = "%d Organizations are named %q"
+)
+
+// Client implements the Equinix Metal API methods needed to interact with
+// Organizations for the Equinix Metal Crossplane Provider
+type Client interface {
+	List(listOpt *packngo.ListOptions) ([]packngo.Organization, *packngo.Response, error)
+}
+
+// build-time test that the interface is implemented
+var _ Client = (&packngo.Client{}).Organizations
+
+// ClientWithDefaults is an interface that provides Organization services and
+// provides default values for common properties
+type ClientWithDefaults interface {
+	Client
+	clients.DefaultGetter
+}
+
+// CredentialedClient is a credentialed client to Equinix Metal Organization
+// services
+type CredentialedClient struct {
+	Client
+	*clients.Credentials
+}
+
+var _ ClientWithDefaults = &CredentialedClient{}
+
+// NewClient returns a Client implementing the Equinix Metal API methods needed
+// to interact with Organizations for the Equinix Metal Crossplane Provider
+func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefaults, error) {
+	client, err := clients.NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	organizationClient := CredentialedClient{
+		Client:      client.Client.Organizations,
+		Credentials: client.Credentials,
+	}
+	organizationClient.SetProjectID(config.ProjectID)
+	return organizationClient, nil
+}
+
+// ListOrganizations returns every page of Organizations
+func ListOrganizations(c Client) ([]packngo.Organization, error) {
+	var orgs []packngo.Organization
+	err := clients.ListAll(func(opts *packngo.ListOptions) error {
+		page, _, err := c.List(opts)
+		orgs = append(orgs, page...)
+		return err
+	})
+	return orgs, err
+}
+
+// FindOrganization returns the only Organization that exactly matches name.
+// An error is returned when no Organization, or more than one, matches.
+func FindOrganization(orgs []packngo.Organization, name string) (*packngo.Organization, error) {
+	var found []packngo.Organization
+	for _, o := range orgs {
+		if o.Name == name {
+			found = append(found, o)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, errors.Errorf(errOrganizationNotFound, name)
+	case 1:
+		return &found[0], nil
+	}
+	return nil, errors.Errorf(errOrganizationAmbiguous, len(found), name)
+}
+
+// GenerateObservation produces v1alpha1.OrganizationObservation from
+// packngo.Organization
+func GenerateObservation(org *packngo.Organization) (v1alpha1.OrganizationObservation, error) {
+	observation := v1alpha1.OrganizationObservation{
+		ID:          org.ID,
+		Href:        org.URL,
+		Description: org.Description,
+		Website:     org.Website,
+	}
+
+	if org.Created != "" {
+		observation.CreatedAt = &metav1.Time{}
+		if err := observation.CreatedAt.UnmarshalText([]byte(org.Created)); err != nil {
+			return v1alpha1.OrganizationObservation{}, errors.Wrap(err, errUnmarshalDate)
+		}
+	}
+	if org.Updated != "" {
+		observation.UpdatedAt = &metav1.Time{}
+		if err := observation.UpdatedAt.UnmarshalText([]byte(org.Updated)); err != nil {
+			return v1alpha1.OrganizationObservation{}, errors.Wrap(err, errUnmarshalDate)
+		}
+	}
+
+	return observation, nil
+}
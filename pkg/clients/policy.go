@@ -0,0 +1,156 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+)
+
+// AnnotationKeyManagementPolicy is the annotation that sets the
+// ManagementPolicy of a managed resource
+const AnnotationKeyManagementPolicy = "metal.equinix.com/management-policy"
+
+// A ManagementPolicy determines which operations a controller may perform on
+// the external resource of a managed resource
+type ManagementPolicy string
+
+// Management policies.
+const (
+	// ManagementPolicyFullControl allows the external resource to be created,
+	// updated and deleted. This is the default.
+	ManagementPolicyFullControl ManagementPolicy = "FullControl"
+
+	// ManagementPolicyObserveOnly only observes an existing external
+	// resource, identified by its external name, to populate the status of
+	// the managed resource. The external resource is never created, updated
+	// or deleted.
+	ManagementPolicyObserveOnly ManagementPolicy = "ObserveOnly"
+)
+
+const (
+	errObserveOnlyNotExist = "cannot observe external resource: it does not exist and is not created when the management policy is ObserveOnly"
+	errManagementPolicyFmt = "unknown management policy %q"
+)
+
+// GetManagementPolicy returns the ManagementPolicy of the managed resource
+func GetManagementPolicy(mg resource.Managed) (ManagementPolicy, error) {
+	switch p := ManagementPolicy(mg.GetAnnotations()[AnnotationKeyManagementPolicy]); p {
+	case "", ManagementPolicyFullControl:
+		return ManagementPolicyFullControl, nil
+	case ManagementPolicyObserveOnly:
+		return p, nil
+	default:
+		return "", errors.Errorf(errManagementPolicyFmt, p)
+	}
+}
+
+// IsObserveOnly returns true if the external resource of the managed resource
+// must only be observed
+func IsObserveOnly(mg resource.Managed) bool {
+	p, _ := GetManagementPolicy(mg)
+	return p == ManagementPolicyObserveOnly
+}
+
+type managementPolicyConnecter struct {
+	managed.ExternalConnecter
+}
+
+// NewManagementPolicyConnecter wraps an ExternalConnecter so that the
+// ExternalClients it connects honor the ManagementPolicy of each managed
+// resource
+func NewManagementPolicyConnecter(c managed.ExternalConnecter) managed.ExternalConnecter {
+	return &managementPolicyConnecter{ExternalConnecter: c}
+}
+
+// Connect to the external resource, honoring the ManagementPolicy of the
+// managed resource
+func (c *managementPolicyConnecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	e, err := c.ExternalConnecter.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return NewManagementPolicyExternal(e), nil
+}
+
+type managementPolicyExternal struct {
+	managed.ExternalClient
+}
+
+// NewManagementPolicyExternal wraps an ExternalClient so that it only creates,
+// updates and deletes external resources when the ManagementPolicy of the
+// managed resource allows it
+func NewManagementPolicyExternal(e managed.ExternalClient) managed.ExternalClient {
+	return &managementPolicyExternal{ExternalClient: e}
+}
+
+// Observe the external resource. An ObserveOnly external resource that does
+// not exist is an error, rather than a cue to create it, and one that does
+// is always up to date so that it is never updated.
+func (e *managementPolicyExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	p, err := GetManagementPolicy(mg)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if p != ManagementPolicyObserveOnly {
+		return e.ExternalClient.Observe(ctx, mg)
+	}
+
+	// Report that the external resource no longer exists so that the
+	// managed resource may be deleted without deleting it.
+	if meta.WasDeleted(mg) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	if err != nil {
+		return o, err
+	}
+	if !o.ResourceExists {
+		return o, errors.New(errObserveOnlyNotExist)
+	}
+	o.ResourceUpToDate = true
+	return o, nil
+}
+
+// Create the external resource, unless the managed resource is ObserveOnly
+func (e *managementPolicyExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	if IsObserveOnly(mg) {
+		return managed.ExternalCreation{}, nil
+	}
+	return e.ExternalClient.Create(ctx, mg)
+}
+
+// Update the external resource, unless the managed resource is ObserveOnly
+func (e *managementPolicyExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	if IsObserveOnly(mg) {
+		return managed.ExternalUpdate{}, nil
+	}
+	return e.ExternalClient.Update(ctx, mg)
+}
+
+// Delete the external resource, unless the managed resource is ObserveOnly
+func (e *managementPolicyExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	if IsObserveOnly(mg) {
+		return nil
+	}
+	return e.ExternalClient.Delete(ctx, mg)
+}
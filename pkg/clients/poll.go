@@ -0,0 +1,188 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+)
+
+// DefaultPollInterval is how often managed resources that are up to date are
+// observed when no poll interval is configured
+const DefaultPollInterval = time.Minute
+
+const (
+	errPollInterval               = "poll interval must be positive, not %s"
+	errProviderConfigPollInterval = "cannot use poll interval of ProviderConfig %s"
+)
+
+// ValidatePollInterval returns an error if the poll interval is not positive
+func ValidatePollInterval(poll time.Duration) error {
+	if poll <= 0 {
+		return errors.Errorf(errPollInterval, poll)
+	}
+	return nil
+}
+
+// pollingReconciler requeues managed resources at the PollInterval of their
+// ProviderConfig
+type pollingReconciler struct {
+	reconcile.Reconciler
+
+	kube       client.Client
+	newManaged func() (runtime.Object, error)
+
+	failures   func(resource.Managed) int
+	maxBackoff time.Duration
+}
+
+// A PollingReconcilerOption configures a polling reconciler
+type PollingReconcilerOption func(*pollingReconciler)
+
+// WithFailureBackoff doubles the poll interval of managed resources for each
+// consecutive failure counted by the supplied function, up to the supplied
+// maximum, so that persistently failed resources are observed less often
+func WithFailureBackoff(failures func(resource.Managed) int, max time.Duration) PollingReconcilerOption {
+	return func(r *pollingReconciler) {
+		r.failures = failures
+		r.maxBackoff = max
+	}
+}
+
+// Backoff returns the poll interval doubled for each failure after the first,
+// up to max
+func Backoff(poll time.Duration, failures int, max time.Duration) time.Duration {
+	for i := 1; i < failures && poll < max; i++ {
+		poll *= 2
+	}
+	if poll > max {
+		return max
+	}
+	return poll
+}
+
+// NewPollingReconciler wraps a managed resource reconciler so that managed
+// resources are requeued at the PollInterval of their ProviderConfig, rather
+// than the poll interval of the wrapped reconciler, when one is configured
+func NewPollingReconciler(mgr ctrl.Manager, of resource.ManagedKind, r reconcile.Reconciler, o ...PollingReconcilerOption) reconcile.Reconciler {
+	pr := &pollingReconciler{
+		Reconciler: r,
+		kube:       mgr.GetClient(),
+		newManaged: func() (runtime.Object, error) { return mgr.GetScheme().New(schema.GroupVersionKind(of)) },
+	}
+	for _, opt := range o {
+		opt(pr)
+	}
+	return pr
+}
+
+// Reconcile the managed resource, overriding when it is next observed
+func (r *pollingReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	result, err := r.Reconciler.Reconcile(ctx, req)
+
+	// The managed reconciler also requeues after a short wait, without an
+	// error, when it fails to reconcile and while it waits for deletion.
+	// Only the delay after an up to date observation is overridden.
+	if err != nil || result.RequeueAfter <= 0 {
+		return result, err
+	}
+	mg := r.managed(ctx, req.NamespacedName)
+	if mg == nil || !isObserved(mg) {
+		return result, nil
+	}
+	poll, err := r.pollInterval(ctx, mg)
+	if err != nil {
+		return result, err
+	}
+	if poll > 0 {
+		result.RequeueAfter = poll
+	}
+	if r.failures != nil {
+		if n := r.failures(mg); n > 0 {
+			result.RequeueAfter = Backoff(result.RequeueAfter, n, r.maxBackoff)
+		}
+	}
+	return result, nil
+}
+
+// isObserved returns true if the last reconcile of the managed resource
+// succeeded without creating or deleting the external resource
+func isObserved(mg resource.Managed) bool {
+	if meta.WasDeleted(mg) {
+		return false
+	}
+	if s := mg.GetCondition(xpv1.TypeSynced); s.Status != corev1.ConditionTrue {
+		return false
+	}
+	switch mg.GetCondition(xpv1.TypeReady).Reason {
+	case xpv1.ReasonCreating, xpv1.ReasonDeleting:
+		return false
+	}
+	return true
+}
+
+// managed returns the reconciled managed resource, or nil if it can not be
+// read
+func (r *pollingReconciler) managed(ctx context.Context, nn types.NamespacedName) resource.Managed {
+	o, err := r.newManaged()
+	if err != nil {
+		return nil
+	}
+	mg, ok := o.(resource.Managed)
+	if !ok {
+		return nil
+	}
+	if err := r.kube.Get(ctx, nn, mg); err != nil {
+		return nil
+	}
+	return mg
+}
+
+// pollInterval returns the PollInterval of the ProviderConfig of the managed
+// resource, or zero if it has none. An error is returned if the PollInterval
+// is not positive.
+func (r *pollingReconciler) pollInterval(ctx context.Context, mg resource.Managed) (time.Duration, error) {
+	ref := mg.GetProviderConfigReference()
+	if ref == nil {
+		return 0, nil
+	}
+	pc := &v1beta1.ProviderConfig{}
+	if err := r.kube.Get(ctx, types.NamespacedName{Name: ref.Name}, pc); err != nil {
+		return 0, nil
+	}
+	if pc.Spec.PollInterval == nil {
+		return 0, nil
+	}
+	if err := ValidatePollInterval(pc.Spec.PollInterval.Duration); err != nil {
+		return 0, errors.Wrapf(err, errProviderConfigPollInterval, ref.Name)
+	}
+	return pc.Spec.PollInterval.Duration, nil
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+)
+
+func TestValidatePollInterval(t *testing.T) {
+	cases := map[string]struct {
+		poll    time.Duration
+		wantErr bool
+	}{
+		"Positive": {poll: time.Minute},
+		"Zero":     {poll: 0, wantErr: true},
+		"Negative": {poll: -time.Minute, wantErr: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := ValidatePollInterval(tc.poll); (err != nil) != tc.wantErr {
+				t.Errorf("ValidatePollInterval(%s): want error %t, got %v", tc.poll, tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPollingReconcilerReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	deleted := metav1.Now()
+
+	device := func(c ...xpv1.Condition) *v1alpha2.Device {
+		d := &v1alpha2.Device{}
+		d.SetName("cool-device")
+		d.SetProviderConfigReference(&xpv1.Reference{Name: "default"})
+		d.SetConditions(c...)
+		return d
+	}
+	deleting := device(xpv1.ReconcileSuccess(), xpv1.Deleting())
+	deleting.SetDeletionTimestamp(&deleted)
+
+	type want struct {
+		result reconcile.Result
+		err    error
+	}
+
+	cases := map[string]struct {
+		result   reconcile.Result
+		err      error
+		device   *v1alpha2.Device
+		poll     *metav1.Duration
+		failures int
+		want     want
+	}{
+		"UpToDate": {
+			result: reconcile.Result{RequeueAfter: time.Minute},
+			device: device(xpv1.ReconcileSuccess(), xpv1.Available()),
+			poll:   &metav1.Duration{Duration: 5 * time.Minute},
+			want:   want{result: reconcile.Result{RequeueAfter: 5 * time.Minute}},
+		},
+		"Failed": {
+			result:   reconcile.Result{RequeueAfter: time.Minute},
+			device:   device(xpv1.ReconcileSuccess(), xpv1.Unavailable()),
+			poll:     &metav1.Duration{Duration: 5 * time.Minute},
+			failures: 3,
+			want:     want{result: reconcile.Result{RequeueAfter: 20 * time.Minute}},
+		},
+		"NoPollInterval": {
+			result: reconcile.Result{RequeueAfter: time.Minute},
+			device: device(xpv1.ReconcileSuccess(), xpv1.Available()),
+			want:   want{result: reconcile.Result{RequeueAfter: time.Minute}},
+		},
+		"ReconcileError": {
+			result: reconcile.Result{RequeueAfter: 30 * time.Second},
+			device: device(xpv1.ReconcileError(errBoom), xpv1.Available()),
+			poll:   &metav1.Duration{Duration: 5 * time.Minute},
+			want:   want{result: reconcile.Result{RequeueAfter: 30 * time.Second}},
+		},
+		"Created": {
+			result: reconcile.Result{RequeueAfter: 30 * time.Second},
+			device: device(xpv1.ReconcileSuccess(), xpv1.Creating()),
+			poll:   &metav1.Duration{Duration: 5 * time.Minute},
+			want:   want{result: reconcile.Result{RequeueAfter: 30 * time.Second}},
+		},
+		"Deleting": {
+			result: reconcile.Result{RequeueAfter: 30 * time.Second},
+			device: deleting,
+			poll:   &metav1.Duration{Duration: 5 * time.Minute},
+			want:   want{result: reconcile.Result{RequeueAfter: 30 * time.Second}},
+		},
+		"NotPositivePollInterval": {
+			result: reconcile.Result{RequeueAfter: time.Minute},
+			device: device(xpv1.ReconcileSuccess(), xpv1.Available()),
+			poll:   &metav1.Duration{},
+			want: want{
+				result: reconcile.Result{RequeueAfter: time.Minute},
+				err:    errors.Wrapf(errors.Errorf(errPollInterval, time.Duration(0)), errProviderConfigPollInterval, "default"),
+			},
+		},
+		"WrappedReconcilerError": {
+			result: reconcile.Result{},
+			err:    errBoom,
+			device: device(xpv1.ReconcileSuccess(), xpv1.Available()),
+			poll:   &metav1.Duration{Duration: 5 * time.Minute},
+			want:   want{err: errBoom},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &pollingReconciler{
+				Reconciler: reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+					return tc.result, tc.err
+				}),
+				kube: &test.MockClient{
+					MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+						switch o := obj.(type) {
+						case *v1alpha2.Device:
+							tc.device.DeepCopyInto(o)
+						case *v1beta1.ProviderConfig:
+							o.Spec.PollInterval = tc.poll
+						}
+						return nil
+					},
+				},
+				newManaged: func() (runtime.Object, error) { return &v1alpha2.Device{}, nil },
+				failures:   func(resource.Managed) int { return tc.failures },
+				maxBackoff: time.Hour,
+			}
+
+			got, err := r.Reconcile(context.Background(), reconcile.Request{})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("r.Reconcile(): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("r.Reconcile(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	cases := map[string]struct {
+		poll     time.Duration
+		failures int
+		want     time.Duration
+	}{
+		"FirstFailure":  {poll: time.Minute, failures: 1, want: time.Minute},
+		"SecondFailure": {poll: time.Minute, failures: 2, want: 2 * time.Minute},
+		"FifthFailure":  {poll: time.Minute, failures: 5, want: 16 * time.Minute},
+		"Capped":        {poll: time.Minute, failures: 100, want: time.Hour},
+		"PollAboveMax":  {poll: 2 * time.Hour, failures: 1, want: time.Hour},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := Backoff(tc.poll, tc.failures, time.Hour); got != tc.want {
+				t.Errorf("Backoff(%s, %d, 1h): want %s, got %s", tc.poll, tc.failures, tc.want, got)
+			}
+		})
+	}
+}
@@ -30,8 +30,12 @@ type MockClient struct {
 	MockUnassign      func(*packngo.PortAssignRequest) (*packngo.Port, *packngo.Response, error)
 	MockGetPortByName func(string, string) (*packngo.Port, error)
 
+	MockAssignNative   func(*packngo.PortAssignRequest) (*packngo.Port, *packngo.Response, error)
+	MockUnassignNative func(string) (*packngo.Port, *packngo.Response, error)
+
 	MockGetProjectID  func(string) string
 	MockGetFacilityID func(string) string
+	MockGetMetro      func(string) string
 }
 
 // Assign calls the MockClient's MockAssign function.
@@ -44,17 +48,32 @@ func (c *MockClient) Unassign(p *packngo.PortAssignRequest) (*packngo.Port, *pac
 	return c.MockUnassign(p)
 }
 
+// AssignNative calls the MockClient's MockAssignNative function.
+func (c *MockClient) AssignNative(p *packngo.PortAssignRequest) (*packngo.Port, *packngo.Response, error) {
+	return c.MockAssignNative(p)
+}
+
+// UnassignNative calls the MockClient's MockUnassignNative function.
+func (c *MockClient) UnassignNative(portID string) (*packngo.Port, *packngo.Response, error) {
+	return c.MockUnassignNative(portID)
+}
+
 // GetPortByName calls the MockClient's MockGetPortByName function.
 func (c *MockClient) GetPortByName(deviceID string, name string) (*packngo.Port, error) {
 	return c.MockGetPortByName(deviceID, name)
 }
 
-// GetFacilityID calls the MockClient's MockGet function.
+// GetFacilityID calls the MockClient's MockGetFacilityID function.
 func (c *MockClient) GetFacilityID(id string) string {
 	return c.MockGetFacilityID(id)
 }
 
-// GetProjectID calls the MockClient's MockGet function.
+// GetMetro calls the MockClient's MockGetMetro function.
+func (c *MockClient) GetMetro(metro string) string {
+	return c.MockGetMetro(metro)
+}
+
+// GetProjectID calls the MockClient's MockGetProjectID function.
 func (c *MockClient) GetProjectID(id string) string {
 	return c.MockGetProjectID(id)
 }
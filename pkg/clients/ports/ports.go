@@ -18,6 +18,7 @@ package ports
 
 import (
 	"context"
+	"path"
 
 	"github.com/packethost/packngo"
 
@@ -29,6 +30,8 @@ import (
 type Client interface {
 	Assign(*packngo.PortAssignRequest) (*packngo.Port, *packngo.Response, error)
 	Unassign(*packngo.PortAssignRequest) (*packngo.Port, *packngo.Response, error)
+	AssignNative(*packngo.PortAssignRequest) (*packngo.Port, *packngo.Response, error)
+	UnassignNative(portID string) (*packngo.Port, *packngo.Response, error)
 	GetPortByName(string, string) (*packngo.Port, error)
 }
 
@@ -64,3 +67,42 @@ func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefa
 	portsClient.SetProjectID(config.ProjectID)
 	return portsClient, nil
 }
+
+// IsAttached returns true if the VirtualNetwork is attached to the port
+func IsAttached(port *packngo.Port, virtualNetworkID string) bool {
+	for _, net := range port.AttachedVirtualNetworks {
+		if path.Base(net.Href) == virtualNetworkID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNative returns true if the VirtualNetwork is the native VLAN of the port
+func IsNative(port *packngo.Port, virtualNetworkID string) bool {
+	native := port.NativeVirtualNetwork
+	return native != nil && (native.ID == virtualNetworkID || path.Base(native.Href) == virtualNetworkID)
+}
+
+// NativeVirtualNetworkID returns the ID of the native VLAN of the port, or an
+// empty string if the port has no native VLAN
+func NativeVirtualNetworkID(port *packngo.Port) string {
+	native := port.NativeVirtualNetwork
+	if native == nil {
+		return ""
+	}
+	if native.ID != "" {
+		return native.ID
+	}
+	return path.Base(native.Href)
+}
+
+// IsUpToDate returns true if the VirtualNetwork is the native VLAN of the port
+// exactly when it is requested to be native. The native VLAN is left as is
+// when it is not requested either way.
+func IsUpToDate(port *packngo.Port, virtualNetworkID string, native *bool) bool {
+	if native == nil {
+		return true
+	}
+	return IsNative(port, virtualNetworkID) == *native
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package project
+
+import (
+	"context"
+	"path"
+
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/project/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+const (
+	errUnmarshalDate = "cannot unmarshal date"
+)
+
+// Client implements the Equinix Metal API methods needed to interact with
+// Projects for the Equinix Metal Crossplane Provider
+type Client interface {
+	Get(projectID string, getOpt *packngo.GetOptions) (*packngo.Project, *packngo.Response, error)
+	Create(*packngo.ProjectCreateRequest) (*packngo.Project, *packngo.Response, error)
+	Update(string, *packngo.ProjectUpdateRequest) (*packngo.Project, *packngo.Response, error)
+	Delete(projectID string) (*packngo.Response, error)
+}
+
+// build-time test that the interface is implemented
+var _ Client = (&packngo.Client{}).Projects
+
+// ClientWithDefaults is an interface that provides Project services and
+// provides default values for common properties
+type ClientWithDefaults interface {
+	Client
+	clients.DefaultGetter
+}
+
+// CredentialedClient is a credentialed client to Equinix Metal Project services
+type CredentialedClient struct {
+	Client
+	*clients.Credentials
+}
+
+var _ ClientWithDefaults = &CredentialedClient{}
+
+// NewClient returns a Client implementing the Equinix Metal API methods needed
+// to interact with Projects for the Equinix Metal Crossplane Provider
+func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefaults, error) {
+	client, err := clients.NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	projectClient := CredentialedClient{
+		Client:      client.Client.Projects,
+		Credentials: client.Credentials,
+	}
+	projectClient.SetProjectID(config.ProjectID)
+	return projectClient, nil
+}
+
+// CreateFromProject return packngo.ProjectCreateRequest created from Kubernetes
+func CreateFromProject(p *v1alpha1.Project) *packngo.ProjectCreateRequest {
+	return &packngo.ProjectCreateRequest{
+		Name:            p.Spec.ForProvider.Name,
+		OrganizationID:  emptyIfNil(p.Spec.ForProvider.OrganizationID),
+		PaymentMethodID: emptyIfNil(p.Spec.ForProvider.PaymentMethodID),
+	}
+}
+
+func emptyIfNil(in *string) string {
+	if in == nil {
+		return ""
+	}
+	return *in
+}
+
+// GenerateObservation produces v1alpha1.ProjectObservation from
+// packngo.Project
+func GenerateObservation(project *packngo.Project) (v1alpha1.ProjectObservation, error) {
+	observation := v1alpha1.ProjectObservation{
+		ID: project.ID,
+	}
+
+	if project.Created != "" {
+		observation.CreatedAt = &metav1.Time{}
+		if err := observation.CreatedAt.UnmarshalText([]byte(project.Created)); err != nil {
+			return v1alpha1.ProjectObservation{}, errors.Wrap(err, errUnmarshalDate)
+		}
+	}
+	if project.Updated != "" {
+		observation.UpdatedAt = &metav1.Time{}
+		if err := observation.UpdatedAt.UnmarshalText([]byte(project.Updated)); err != nil {
+			return v1alpha1.ProjectObservation{}, errors.Wrap(err, errUnmarshalDate)
+		}
+	}
+
+	return observation, nil
+}
+
+// LateInitialize fills the empty fields in *v1alpha1.ProjectParameters with
+// the values seen in packngo.Project
+func LateInitialize(in *v1alpha1.ProjectParameters, project *packngo.Project) {
+	if project == nil {
+		return
+	}
+
+	if project.Organization.ID != "" {
+		in.OrganizationID = clients.LateInitializeStringPtr(in.OrganizationID, &project.Organization.ID)
+	}
+
+	// The payment method is only included in the project response as an href
+	if project.PaymentMethod.URL != "" {
+		paymentMethodID := path.Base(project.PaymentMethod.URL)
+		in.PaymentMethodID = clients.LateInitializeStringPtr(in.PaymentMethodID, &paymentMethodID)
+	}
+
+	in.BackendTransferEnabled = clients.LateInitializeBoolPtr(in.BackendTransferEnabled, &project.BackendTransfer)
+}
+
+// IsUpToDate returns true if the supplied Kubernetes resource does not differ
+// from the supplied Equinix Metal resource. It considers only fields that can be
+// modified in place without deleting and recreating the instance, which are
+// immutable.
+func IsUpToDate(p *v1alpha1.Project, project *packngo.Project) bool {
+	if p.Spec.ForProvider.Name != project.Name {
+		return false
+	}
+
+	if p.Spec.ForProvider.PaymentMethodID != nil && project.PaymentMethod.URL != "" &&
+		*p.Spec.ForProvider.PaymentMethodID != path.Base(project.PaymentMethod.URL) {
+		return false
+	}
+
+	if p.Spec.ForProvider.BackendTransferEnabled != nil &&
+		*p.Spec.ForProvider.BackendTransferEnabled != project.BackendTransfer {
+		return false
+	}
+
+	return true
+}
+
+// NewUpdateProjectRequest creates a request to update a project suitable for
+// use with the Equinix Metal API.
+func NewUpdateProjectRequest(p *v1alpha1.Project) *packngo.ProjectUpdateRequest {
+	return &packngo.ProjectUpdateRequest{
+		Name:            &p.Spec.ForProvider.Name,
+		PaymentMethodID: p.Spec.ForProvider.PaymentMethodID,
+		BackendTransfer: p.Spec.ForProvider.BackendTransferEnabled,
+	}
+}
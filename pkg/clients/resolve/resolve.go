@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package resolve resolves values referenced by keys of ConfigMaps and
+// Secrets.
+package resolve
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Supported KeyRef kinds
+const (
+	KindConfigMap = "ConfigMap"
+	KindSecret    = "Secret"
+)
+
+const (
+	errGetRef            = "cannot get required resource for reference"
+	errInvalidRefKind    = "invalid resource kind"
+	errRefKeyNotFoundFmt = "could not find reference key %q"
+)
+
+// KeyRef references a key of a ConfigMap or Secret
+type KeyRef struct {
+	Kind      string
+	Name      string
+	Namespace string
+	Key       string
+	Optional  bool
+}
+
+// ResolveKeyRef returns the value of the key referenced by ref. The
+// defaultKey is used when ref does not specify a key. An empty value is
+// returned when an Optional reference can not be resolved.
+// TODO(displague) use reference.NewAPIResolver when TypedReference is support
+func ResolveKeyRef(ctx context.Context, kube client.Reader, ref KeyRef, defaultKey string) (string, error) {
+	var value string
+	var ok bool
+	nsn := types.NamespacedName{
+		Name:      ref.Name,
+		Namespace: ref.Namespace,
+	}
+	key := ref.Key
+	if key == "" {
+		key = defaultKey
+	}
+
+	switch ref.Kind {
+	case KindConfigMap:
+		resource := &corev1.ConfigMap{}
+		err := kube.Get(ctx, nsn, resource)
+		if err != nil && !ref.Optional {
+			return "", errors.Wrap(err, errGetRef)
+		}
+
+		value, ok = resource.Data[key]
+	case KindSecret:
+		resource := &corev1.Secret{}
+		err := kube.Get(ctx, nsn, resource)
+		if err != nil && !ref.Optional {
+			return "", errors.Wrap(err, errGetRef)
+		}
+		var bytes []byte
+		bytes, ok = resource.Data[key]
+		value = string(bytes)
+	default:
+		return "", errors.Wrap(errors.New(errGetRef), errInvalidRefKind)
+	}
+
+	if !ok && !ref.Optional {
+		return "", errors.Wrapf(errors.New(errGetRef), errRefKeyNotFoundFmt, key)
+	}
+	return value, nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const (
+	namespace    = "cool-namespace"
+	resourceName = "cool-data"
+	defaultKey   = "cloud-init"
+	value        = "#cloud-config"
+)
+
+func TestResolveKeyRef(t *testing.T) {
+	notFound := kerrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, resourceName)
+
+	type args struct {
+		kube       client.Reader
+		ref        KeyRef
+		defaultKey string
+	}
+	type want struct {
+		value string
+		err   error
+	}
+
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"ConfigMap": {
+			args: args{
+				kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+					if diff := cmp.Diff(client.ObjectKey{Namespace: namespace, Name: resourceName}, key); diff != "" {
+						t.Errorf("Get(...): -want key, +got key:\n%s", diff)
+					}
+					obj.(*corev1.ConfigMap).Data = map[string]string{defaultKey: value}
+					return nil
+				}},
+				ref:        KeyRef{Kind: KindConfigMap, Name: resourceName, Namespace: namespace},
+				defaultKey: defaultKey,
+			},
+			want: want{value: value},
+		},
+		"Secret": {
+			args: args{
+				kube: &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					obj.(*corev1.Secret).Data = map[string][]byte{"custom": []byte(value)}
+					return nil
+				}},
+				ref:        KeyRef{Kind: KindSecret, Name: resourceName, Namespace: namespace, Key: "custom"},
+				defaultKey: defaultKey,
+			},
+			want: want{value: value},
+		},
+		"MissingKeyOptional": {
+			args: args{
+				kube:       &test.MockClient{MockGet: test.NewMockGetFn(notFound)},
+				ref:        KeyRef{Kind: KindConfigMap, Name: resourceName, Namespace: namespace, Optional: true},
+				defaultKey: defaultKey,
+			},
+			want: want{value: ""},
+		},
+		"MissingKey": {
+			args: args{
+				kube:       &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+				ref:        KeyRef{Kind: KindConfigMap, Name: resourceName, Namespace: namespace},
+				defaultKey: defaultKey,
+			},
+			want: want{err: errors.Wrapf(errors.New(errGetRef), errRefKeyNotFoundFmt, defaultKey)},
+		},
+		"FailedToGetResource": {
+			args: args{
+				kube:       &test.MockClient{MockGet: test.NewMockGetFn(notFound)},
+				ref:        KeyRef{Kind: KindSecret, Name: resourceName, Namespace: namespace},
+				defaultKey: defaultKey,
+			},
+			want: want{err: errors.Wrap(notFound, errGetRef)},
+		},
+		"InvalidKind": {
+			args: args{
+				kube:       &test.MockClient{},
+				ref:        KeyRef{Kind: "Pod", Name: resourceName, Namespace: namespace},
+				defaultKey: defaultKey,
+			},
+			want: want{err: errors.Wrap(errors.New(errGetRef), errInvalidRefKind)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveKeyRef(context.Background(), tc.args.kube, tc.args.ref, tc.args.defaultKey)
+			if diff := cmp.Diff(tc.want.value, got); diff != "" {
+				t.Errorf("ResolveKeyRef(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("ResolveKeyRef(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is the number of times a rate limited or failed
+	// request is retried when no RetryPolicy is configured
+	DefaultMaxRetries = 5
+
+	// DefaultRetryBaseDelay is the delay before the first retry when no
+	// RetryPolicy is configured
+	DefaultRetryBaseDelay = time.Second
+
+	// maxRetryDelay caps the exponential backoff between retries
+	maxRetryDelay = 30 * time.Second
+)
+
+// RetryPolicy configures how Equinix Metal API requests that were rate limited
+// (429) or failed with a server error (5xx) are retried. Requests that may not
+// be repeated safely, such as those creating resources, are only retried when
+// rate limited or when the API is unavailable (503).
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+
+	// BaseDelay is the delay before the first retry. The delay is doubled
+	// for each subsequent retry unless the API responds with Retry-After.
+	BaseDelay time.Duration
+}
+
+// DefaultRetryPolicy returns the RetryPolicy used when none is configured
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries: DefaultMaxRetries,
+		BaseDelay:  DefaultRetryBaseDelay,
+	}
+}
+
+// retryTransport is an http.RoundTripper that retries rate limited and failed
+// requests with exponential backoff
+type retryTransport struct {
+	next   http.RoundTripper
+	policy RetryPolicy
+}
+
+var _ http.RoundTripper = &retryTransport{}
+
+// newRetryTransport returns an http.RoundTripper that retries requests made
+// through next according to the supplied RetryPolicy
+func newRetryTransport(next http.RoundTripper, policy *RetryPolicy) http.RoundTripper {
+	if policy == nil {
+		policy = DefaultRetryPolicy()
+	}
+	return &retryTransport{next: next, policy: *policy}
+}
+
+// RoundTrip performs the request, retrying it while the response is retryable
+// and retries remain
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		r := req
+		if attempt > 0 {
+			// the body of the previous attempt has been consumed
+			if req.Body != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				r = req.Clone(req.Context())
+				r.Body = body
+			}
+		}
+
+		resp, err := t.next.RoundTrip(r)
+		if err != nil || attempt >= t.policy.MaxRetries || !isRetryable(req, resp) {
+			return resp, err
+		}
+		if req.Body != nil && req.GetBody == nil {
+			return resp, err
+		}
+
+		delay := t.retryDelay(resp, attempt)
+
+		// drain the body so that the connection can be reused
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryDelay returns the delay requested by the Retry-After header of the
+// response or the exponential backoff delay for the attempt, either of which
+// is capped at maxRetryDelay
+func (t *retryTransport) retryDelay(resp *http.Response, attempt int) time.Duration {
+	if after := resp.Header.Get("Retry-After"); after != "" {
+		if seconds, err := strconv.Atoi(after); err == nil && seconds >= 0 {
+			if seconds > int(maxRetryDelay/time.Second) {
+				return maxRetryDelay
+			}
+			return time.Duration(seconds) * time.Second
+		}
+		if at, err := http.ParseTime(after); err == nil {
+			d := time.Until(at)
+			switch {
+			case d <= 0:
+				return 0
+			case d > maxRetryDelay:
+				return maxRetryDelay
+			}
+			return d
+		}
+	}
+
+	delay := t.policy.BaseDelay << uint(attempt)
+	if delay <= 0 || delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
+
+// isRetryable returns true for rate limited and unavailable responses, and for
+// server error responses to idempotent requests. Other server errors may be
+// returned after a request took effect, so repeating a request that is not
+// idempotent could, for example, create a resource twice.
+func isRetryable(req *http.Request, resp *http.Response) bool {
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode == http.StatusServiceUnavailable:
+		return true
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return isIdempotent(req.Method)
+	}
+	return false
+}
+
+// isIdempotent returns true for request methods that may be repeated without
+// changing the result of the first request
+func isIdempotent(method string) bool {
+	switch method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	cases := map[string]struct {
+		method   string
+		status   int
+		attempts int
+	}{
+		"GetServerError": {
+			method:   http.MethodGet,
+			status:   http.StatusBadGateway,
+			attempts: 2,
+		},
+		"DeleteServerError": {
+			method:   http.MethodDelete,
+			status:   http.StatusGatewayTimeout,
+			attempts: 2,
+		},
+		"PostServerError": {
+			method:   http.MethodPost,
+			status:   http.StatusBadGateway,
+			attempts: 1,
+		},
+		"PostUnavailable": {
+			method:   http.MethodPost,
+			status:   http.StatusServiceUnavailable,
+			attempts: 2,
+		},
+		"PostRateLimited": {
+			method:   http.MethodPost,
+			status:   http.StatusTooManyRequests,
+			attempts: 2,
+		},
+		"GetNotFound": {
+			method:   http.MethodGet,
+			status:   http.StatusNotFound,
+			attempts: 1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			attempts := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				w.WriteHeader(tc.status)
+			}))
+			defer srv.Close()
+
+			c := &http.Client{Transport: newRetryTransport(http.DefaultTransport, &RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond})}
+			req, err := http.NewRequest(tc.method, srv.URL, strings.NewReader("{}"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := c.Do(req)
+			if err != nil {
+				t.Fatalf("c.Do(...): %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if resp.StatusCode != tc.status {
+				t.Errorf("c.Do(...): want status %d, got %d", tc.status, resp.StatusCode)
+			}
+			if attempts != tc.attempts {
+				t.Errorf("c.Do(...): want %d attempts, got %d", tc.attempts, attempts)
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	cases := map[string]struct {
+		after   string
+		attempt int
+		want    time.Duration
+	}{
+		"RetryAfterSeconds": {
+			after: "3",
+			want:  3 * time.Second,
+		},
+		"RetryAfterSecondsCapped": {
+			after: strconv.Itoa(int(maxRetryDelay/time.Second) * 10),
+			want:  maxRetryDelay,
+		},
+		"RetryAfterDateCapped": {
+			after: time.Now().Add(time.Hour).UTC().Format(http.TimeFormat),
+			want:  maxRetryDelay,
+		},
+		"RetryAfterDatePassed": {
+			after: time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat),
+			want:  0,
+		},
+		"Backoff": {
+			attempt: 2,
+			want:    4 * time.Second,
+		},
+		"BackoffCapped": {
+			attempt: 10,
+			want:    maxRetryDelay,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rt := &retryTransport{policy: *DefaultRetryPolicy()}
+			resp := &http.Response{Header: http.Header{}}
+			if tc.after != "" {
+				resp.Header.Set("Retry-After", tc.after)
+			}
+			if got := rt.retryDelay(resp, tc.attempt); got != tc.want {
+				t.Errorf("rt.retryDelay(...): want %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package price
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/spotmarket/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+const (
+	errFacilityMetroConflict = "facility %q and metro %q can not both be set"
+	errFacilityMetroMissing  = "one of facility or metro must be set"
+	errPriceNotFound         = "no spot market price for plan %q in %q"
+)
+
+// Client implements the Equinix Metal API methods needed to interact with
+// Spot Market prices for the Equinix Metal Crossplane Provider
+type Client interface {
+	PricesByFacility() (packngo.PriceMap, *packngo.Response, error)
+	PricesByMetro() (packngo.PriceMap, *packngo.Response, error)
+}
+
+// build-time test that the interface is implemented
+var _ Client = (&packngo.Client{}).SpotMarket
+
+// ClientWithDefaults is an interface that provides Spot Market price services
+// and provides default values for common properties
+type ClientWithDefaults interface {
+	Client
+	clients.DefaultGetter
+}
+
+// CredentialedClient is a credentialed client to Equinix Metal Spot Market
+// price services
+type CredentialedClient struct {
+	Client
+	*clients.Credentials
+}
+
+var _ ClientWithDefaults = &CredentialedClient{}
+
+// NewClient returns a Client implementing the Equinix Metal API methods needed
+// to interact with Spot Market prices for the Equinix Metal Crossplane
+// Provider
+func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefaults, error) {
+	client, err := clients.NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	priceClient := CredentialedClient{
+		Client:      client.Client.SpotMarket,
+		Credentials: client.Credentials,
+	}
+	priceClient.SetProjectID(config.ProjectID)
+	return priceClient, nil
+}
+
+// GetPrice returns the current Spot Market price per hour of the plan in the
+// facility or metro of the supplied SpotMarketPrice
+func GetPrice(c Client, p *v1alpha1.SpotMarketPrice) (float64, error) {
+	facility, metro := p.Spec.ForProvider.Facility, p.Spec.ForProvider.Metro
+
+	var prices packngo.PriceMap
+	var location string
+	var err error
+	switch {
+	case facility != nil && metro != nil:
+		return 0, errors.Errorf(errFacilityMetroConflict, *facility, *metro)
+	case facility != nil:
+		location = *facility
+		prices, _, err = c.PricesByFacility()
+	case metro != nil:
+		location = *metro
+		prices, _, err = c.PricesByMetro()
+	default:
+		return 0, errors.New(errFacilityMetroMissing)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	price, ok := prices[location][p.Spec.ForProvider.Plan]
+	if !ok {
+		return 0, errors.Errorf(errPriceNotFound, p.Spec.ForProvider.Plan, location)
+	}
+	return price, nil
+}
+
+// GenerateObservation produces v1alpha1.SpotMarketPriceObservation from a
+// Spot Market price
+func GenerateObservation(price float64) (v1alpha1.SpotMarketPriceObservation, error) {
+	q, err := resource.ParseQuantity(strconv.FormatFloat(price, 'f', -1, 64))
+	if err != nil {
+		return v1alpha1.SpotMarketPriceObservation{}, err
+	}
+	return v1alpha1.SpotMarketPriceObservation{Price: &q}, nil
+}
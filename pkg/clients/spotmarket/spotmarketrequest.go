@@ -0,0 +1,204 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spotmarket
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/spotmarket/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	devicesclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/device"
+)
+
+// Client implements the Equinix Metal API methods needed to interact with
+// Spot Market Requests for the Equinix Metal Crossplane Provider
+type Client interface {
+	Get(requestID string, getOpt *packngo.GetOptions) (*packngo.SpotMarketRequest, *packngo.Response, error)
+	Create(createRequest *packngo.SpotMarketRequestCreateRequest, projectID string) (*packngo.SpotMarketRequest, *packngo.Response, error)
+	Delete(requestID string, forceDelete bool) (*packngo.Response, error)
+}
+
+// build-time test that the interface is implemented
+var _ Client = (&packngo.Client{}).SpotMarketRequests
+
+// ClientWithDefaults is an interface that provides Spot Market Request
+// services and provides default values for common properties
+type ClientWithDefaults interface {
+	Client
+	clients.DefaultGetter
+}
+
+// CredentialedClient is a credentialed client to Equinix Metal Spot Market
+// Request services
+type CredentialedClient struct {
+	Client
+	*clients.Credentials
+}
+
+var _ ClientWithDefaults = &CredentialedClient{}
+
+// NewClient returns a Client implementing the Equinix Metal API methods needed
+// to interact with Spot Market Requests for the Equinix Metal Crossplane
+// Provider
+func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefaults, error) {
+	client, err := clients.NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	spotMarketClient := CredentialedClient{
+		Client:      client.Client.SpotMarketRequests,
+		Credentials: client.Credentials,
+	}
+	spotMarketClient.SetProjectID(config.ProjectID)
+	return spotMarketClient, nil
+}
+
+const (
+	errFacilitiesMetroConflict = "facilities %q and metro %q can not both be set"
+)
+
+// ValidateLocation returns an error if the SpotMarketRequest sets both
+// facilities and a metro, which the API does not accept together
+func ValidateLocation(r *v1alpha1.SpotMarketRequest) error {
+	p := r.Spec.ForProvider
+	if len(p.Facilities) > 0 && emptyIfNil(p.Metro) != "" {
+		return errors.Errorf(errFacilitiesMetroConflict, strings.Join(p.Facilities, ", "), *p.Metro)
+	}
+	return nil
+}
+
+// CreateFromSpotMarketRequest return packngo.SpotMarketRequestCreateRequest
+// created from Kubernetes
+func CreateFromSpotMarketRequest(r *v1alpha1.SpotMarketRequest) *packngo.SpotMarketRequestCreateRequest {
+	p := r.Spec.ForProvider
+	i := p.InstanceParameters
+
+	// The Equinix Metal API accepts bids in dollars with a precision of cents
+	maxBidPrice := float64(p.MaxBidPrice.MilliValue()) / 1000
+
+	create := &packngo.SpotMarketRequestCreateRequest{
+		DevicesMin:  p.DevicesMin,
+		DevicesMax:  p.DevicesMax,
+		MaxBidPrice: maxBidPrice,
+		FacilityIDs: p.Facilities,
+		Metro:       emptyIfNil(p.Metro),
+		Parameters: packngo.SpotMarketRequestInstanceParameters{
+			Plan:            i.Plan,
+			OperatingSystem: i.OS,
+			Hostname:        emptyIfNil(i.Hostname),
+			Hostnames:       i.Hostnames,
+			Description:     emptyIfNil(i.Description),
+			BillingCycle:    emptyIfNil(i.BillingCycle),
+			UserData:        emptyIfNil(i.UserData),
+			CustomData:      emptyIfNil(i.CustomData),
+			Tags:            i.Tags,
+			Features:        i.Features,
+			ProjectSSHKeys:  i.ProjectSSHKeys,
+			UserSSHKeys:     i.UserSSHKeys,
+			Locked:          falseIfNil(i.Locked),
+			AlwaysPXE:       falseIfNil(i.AlwaysPXE),
+			IPXEScriptURL:   emptyIfNil(i.IPXEScriptURL),
+		},
+	}
+
+	if p.EndAt != nil {
+		create.EndAt = &packngo.Timestamp{Time: p.EndAt.Time}
+	}
+	if i.TerminationTime != nil {
+		create.Parameters.TerminationTime = &packngo.Timestamp{Time: i.TerminationTime.Time}
+	}
+
+	return create
+}
+
+func emptyIfNil(in *string) string {
+	if in == nil {
+		return ""
+	}
+	return *in
+}
+
+func falseIfNil(in *bool) bool {
+	if in == nil {
+		return false
+	}
+	return *in
+}
+
+// GenerateObservation produces v1alpha1.SpotMarketRequestObservation from
+// packngo.SpotMarketRequest
+func GenerateObservation(r *packngo.SpotMarketRequest) v1alpha1.SpotMarketRequestObservation {
+	observation := v1alpha1.SpotMarketRequestObservation{
+		ID:          r.ID,
+		Href:        r.Href,
+		DeviceCount: len(r.Devices),
+	}
+
+	for _, d := range r.Devices {
+		// Devices are only listed by href unless they are included
+		id := d.ID
+		if id == "" && d.Href != "" {
+			id = path.Base(d.Href)
+		}
+		observation.DeviceIDs = append(observation.DeviceIDs, id)
+		observation.Devices = append(observation.Devices, v1alpha1.SpotMarketRequestDevice{ID: id, State: d.State})
+	}
+
+	for _, f := range r.Facilities {
+		observation.Facilities = append(observation.Facilities, f.Code)
+	}
+	if r.Metro != nil {
+		observation.Metro = r.Metro.Code
+	}
+
+	return observation
+}
+
+// GetConnectionDetails returns the connection details of the first active
+// device provisioned by the request, or none until a device is active
+func GetConnectionDetails(r *packngo.SpotMarketRequest) managed.ConnectionDetails {
+	for i := range r.Devices {
+		if r.Devices[i].State == v1alpha2.StateActive {
+			return devicesclient.GetConnectionDetails(&r.Devices[i])
+		}
+	}
+	return nil
+}
+
+// LateInitialize fills the empty fields in
+// *v1alpha1.SpotMarketRequestParameters with the values seen in
+// packngo.SpotMarketRequest
+func LateInitialize(in *v1alpha1.SpotMarketRequestParameters, r *packngo.SpotMarketRequest) {
+	if r == nil {
+		return
+	}
+
+	if r.Metro != nil && in.Facilities == nil {
+		in.Metro = clients.LateInitializeStringPtr(in.Metro, &r.Metro.Code)
+	}
+	if r.Parameters.BillingCycle != "" {
+		in.InstanceParameters.BillingCycle = clients.LateInitializeStringPtr(in.InstanceParameters.BillingCycle, &r.Parameters.BillingCycle)
+	}
+}
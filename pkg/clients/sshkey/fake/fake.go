@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/packethost/packngo"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/sshkey"
+)
+
+var _ sshkey.ClientWithDefaults = &MockClient{}
+
+// MockClient is a fake implementation of packngo.Client.
+type MockClient struct {
+	MockGet    func(sshKeyID string, getOpt *packngo.GetOptions) (*packngo.SSHKey, *packngo.Response, error)
+	MockCreate func(createRequest *packngo.SSHKeyCreateRequest) (*packngo.SSHKey, *packngo.Response, error)
+	MockUpdate func(sshKeyID string, updateRequest *packngo.SSHKeyUpdateRequest) (*packngo.SSHKey, *packngo.Response, error)
+	MockDelete func(sshKeyID string) (*packngo.Response, error)
+
+	MockGetProjectID  func(string) string
+	MockGetFacilityID func(string) string
+	MockGetMetro      func(string) string
+}
+
+// Get calls the MockClient's MockGet function.
+func (c *MockClient) Get(sshKeyID string, getOpt *packngo.GetOptions) (*packngo.SSHKey, *packngo.Response, error) {
+	return c.MockGet(sshKeyID, getOpt)
+}
+
+// Create calls the MockClient's MockCreate function.
+func (c *MockClient) Create(createRequest *packngo.SSHKeyCreateRequest) (*packngo.SSHKey, *packngo.Response, error) {
+	return c.MockCreate(createRequest)
+}
+
+// Update calls the MockClient's MockUpdate function.
+func (c *MockClient) Update(sshKeyID string, updateRequest *packngo.SSHKeyUpdateRequest) (*packngo.SSHKey, *packngo.Response, error) {
+	return c.MockUpdate(sshKeyID, updateRequest)
+}
+
+// Delete calls the MockClient's MockDelete function.
+func (c *MockClient) Delete(sshKeyID string) (*packngo.Response, error) {
+	return c.MockDelete(sshKeyID)
+}
+
+// GetFacilityID calls the MockClient's MockGetFacilityID function.
+func (c *MockClient) GetFacilityID(id string) string {
+	return c.MockGetFacilityID(id)
+}
+
+// GetMetro calls the MockClient's MockGetMetro function.
+func (c *MockClient) GetMetro(metro string) string {
+	return c.MockGetMetro(metro)
+}
+
+// GetProjectID calls the MockClient's MockGetProjectID function.
+func (c *MockClient) GetProjectID(id string) string {
+	return c.MockGetProjectID(id)
+}
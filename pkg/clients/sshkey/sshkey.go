@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sshkey
+
+import (
+	"context"
+
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/sshkey/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+const (
+	errUnmarshalDate = "cannot unmarshal date"
+)
+
+// Client implements the Equinix Metal API methods needed to interact with
+// SSH Keys for the Equinix Metal Crossplane Provider
+type Client interface {
+	Get(sshKeyID string, getOpt *packngo.GetOptions) (*packngo.SSHKey, *packngo.Response, error)
+	Create(*packngo.SSHKeyCreateRequest) (*packngo.SSHKey, *packngo.Response, error)
+	Update(string, *packngo.SSHKeyUpdateRequest) (*packngo.SSHKey, *packngo.Response, error)
+	Delete(sshKeyID string) (*packngo.Response, error)
+}
+
+// build-time test that the interface is implemented
+var _ Client = (&packngo.Client{}).SSHKeys
+
+// ClientWithDefaults is an interface that provides SSH Key services and
+// provides default values for common properties
+type ClientWithDefaults interface {
+	Client
+	clients.DefaultGetter
+}
+
+// CredentialedClient is a credentialed client to Equinix Metal SSH Key services
+type CredentialedClient struct {
+	Client
+	*clients.Credentials
+}
+
+var _ ClientWithDefaults = &CredentialedClient{}
+
+// NewClient returns a Client implementing the Equinix Metal API methods needed
+// to interact with SSH Keys for the Equinix Metal Crossplane Provider
+func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefaults, error) {
+	client, err := clients.NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	sshKeyClient := CredentialedClient{
+		Client:      client.Client.SSHKeys,
+		Credentials: client.Credentials,
+	}
+	sshKeyClient.SetProjectID(config.ProjectID)
+	return sshKeyClient, nil
+}
+
+// CreateFromSSHKey return packngo.SSHKeyCreateRequest created from Kubernetes
+func CreateFromSSHKey(k *v1alpha1.SSHKey) *packngo.SSHKeyCreateRequest {
+	return &packngo.SSHKeyCreateRequest{
+		Label: k.Spec.ForProvider.Label,
+		Key:   k.Spec.ForProvider.Key,
+	}
+}
+
+// GenerateObservation produces v1alpha1.SSHKeyObservation from
+// packngo.SSHKey
+func GenerateObservation(key *packngo.SSHKey) (v1alpha1.SSHKeyObservation, error) {
+	observation := v1alpha1.SSHKeyObservation{
+		ID:          key.ID,
+		Fingerprint: key.FingerPrint,
+	}
+
+	if key.Created != "" {
+		observation.CreatedAt = &metav1.Time{}
+		if err := observation.CreatedAt.UnmarshalText([]byte(key.Created)); err != nil {
+			return v1alpha1.SSHKeyObservation{}, errors.Wrap(err, errUnmarshalDate)
+		}
+	}
+	if key.Updated != "" {
+		observation.UpdatedAt = &metav1.Time{}
+		if err := observation.UpdatedAt.UnmarshalText([]byte(key.Updated)); err != nil {
+			return v1alpha1.SSHKeyObservation{}, errors.Wrap(err, errUnmarshalDate)
+		}
+	}
+
+	return observation, nil
+}
+
+// IsUpToDate returns true if the supplied Kubernetes resource does not differ
+// from the supplied Equinix Metal resource. It considers only fields that can be
+// modified in place without deleting and recreating the instance.
+func IsUpToDate(k *v1alpha1.SSHKey, key *packngo.SSHKey) bool {
+	if k.Spec.ForProvider.Label != key.Label {
+		return false
+	}
+
+	return k.Spec.ForProvider.Key == key.Key
+}
+
+// NewUpdateSSHKeyRequest creates a request to update an SSH Key suitable for
+// use with the Equinix Metal API.
+func NewUpdateSSHKeyRequest(k *v1alpha1.SSHKey) *packngo.SSHKeyUpdateRequest {
+	return &packngo.SSHKeyUpdateRequest{
+		Label: &k.Spec.ForProvider.Label,
+		Key:   &k.Spec.ForProvider.Key,
+	}
+}
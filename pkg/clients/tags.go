@@ -0,0 +1,65 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"sort"
+	"strings"
+)
+
+// tagSeparator separates the key and value of a key=value tag
+const tagSeparator = "="
+
+// TagKey returns the key of a key=value tag, or the whole tag if it has no
+// value
+func TagKey(tag string) string {
+	return strings.SplitN(tag, tagSeparator, 2)[0]
+}
+
+// MergeTags returns the supplied tags followed by the default tags, as
+// key=value tags ordered by key. Default tags whose key is already used by a
+// supplied tag are left out, so that the supplied tags win. The supplied tags
+// are returned unchanged when there are no default tags.
+func MergeTags(tags []string, defaults map[string]string) []string {
+	if len(defaults) == 0 {
+		return tags
+	}
+
+	keys := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		keys[TagKey(t)] = true
+	}
+
+	defaultKeys := make([]string, 0, len(defaults))
+	for k := range defaults {
+		if !keys[k] {
+			defaultKeys = append(defaultKeys, k)
+		}
+	}
+	sort.Strings(defaultKeys)
+
+	merged := make([]string, 0, len(tags)+len(defaultKeys))
+	merged = append(merged, tags...)
+	for _, k := range defaultKeys {
+		if v := defaults[k]; v != "" {
+			merged = append(merged, k+tagSeparator+v)
+			continue
+		}
+		merged = append(merged, k)
+	}
+	return merged
+}
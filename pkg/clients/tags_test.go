@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMergeTags(t *testing.T) {
+	cases := map[string]struct {
+		tags     []string
+		defaults map[string]string
+		want     []string
+	}{
+		"NoDefaults": {
+			tags: []string{"web"},
+			want: []string{"web"},
+		},
+		"NoTags": {
+			defaults: map[string]string{"owner": "platform", "cluster": "prod"},
+			want:     []string{"cluster=prod", "owner=platform"},
+		},
+		"Merged": {
+			tags:     []string{"web", "env=staging"},
+			defaults: map[string]string{"owner": "platform", "managed": ""},
+			want:     []string{"web", "env=staging", "managed", "owner=platform"},
+		},
+		"UserTagsWin": {
+			tags:     []string{"owner=team-a", "cluster"},
+			defaults: map[string]string{"owner": "platform", "cluster": "prod"},
+			want:     []string{"owner=team-a", "cluster"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := MergeTags(tc.tags, tc.defaults)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("MergeTags(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultRequestTimeout is the time limit for each attempt of an Equinix
+// Metal API request when no timeout is configured
+const DefaultRequestTimeout = 30 * time.Second
+
+// contextTransport is an http.RoundTripper that cancels requests when the
+// context of the client is done. packngo does not accept a context, its
+// requests are made with the background context.
+type contextTransport struct {
+	ctx  context.Context
+	next http.RoundTripper
+}
+
+var _ http.RoundTripper = &contextTransport{}
+
+// newContextTransport returns an http.RoundTripper that makes the requests
+// made through next with the supplied context
+func newContextTransport(ctx context.Context, next http.RoundTripper) http.RoundTripper {
+	return &contextTransport{ctx: ctx, next: next}
+}
+
+// RoundTrip performs the request with the context of the transport
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.next.RoundTrip(req.WithContext(t.ctx))
+}
+
+// timeoutTransport is an http.RoundTripper that limits the time taken by each
+// request, including reading the response body
+type timeoutTransport struct {
+	timeout time.Duration
+	next    http.RoundTripper
+}
+
+var _ http.RoundTripper = &timeoutTransport{}
+
+// newTimeoutTransport returns an http.RoundTripper that cancels requests made
+// through next that take longer than the supplied timeout. The
+// DefaultRequestTimeout is used when the timeout is not positive.
+func newTimeoutTransport(next http.RoundTripper, timeout time.Duration) http.RoundTripper {
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+	return &timeoutTransport{timeout: timeout, next: next}
+}
+
+// RoundTrip performs the request, cancelling it once the timeout has passed
+// or the response body has been closed
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody cancels the context of a request when its response body is
+// closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the response body and cancels the context of its request
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// newHTTPClient returns an http.Client for the Equinix Metal API. Requests
+// are cancelled when the supplied context is done, each attempt is limited to
+// the RequestTimeout of the credentials, recorded in the API metrics and
+// retried according to their RetryPolicy when it fails.
+func newHTTPClient(ctx context.Context, config *Credentials) *http.Client {
+	transport := newTimeoutTransport(http.DefaultTransport, config.RequestTimeout)
+	transport = newMetricsTransport(transport, config.Kind)
+	transport = newRetryTransport(transport, config.RetryPolicy)
+	return &http.Client{
+		Transport: newContextTransport(ctx, transport),
+	}
+}
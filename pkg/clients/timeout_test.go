@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/packethost/packngo"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cases := map[string]struct {
+		ctx     context.Context
+		timeout time.Duration
+		delay   time.Duration
+		want    error
+	}{
+		"TimedOut": {
+			ctx:     context.Background(),
+			timeout: 50 * time.Millisecond,
+			delay:   time.Minute,
+			want:    context.DeadlineExceeded,
+		},
+		"Cancelled": {
+			ctx:     cancelled,
+			timeout: time.Minute,
+			delay:   time.Minute,
+			want:    context.Canceled,
+		},
+		"Completed": {
+			ctx:     context.Background(),
+			timeout: time.Minute,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			release := make(chan struct{})
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-time.After(tc.delay):
+				case <-release:
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"id":"project"}`))
+			}))
+			defer srv.Close()
+			defer close(release)
+
+			config := &Credentials{RequestTimeout: tc.timeout, RetryPolicy: &RetryPolicy{}}
+			c, err := packngo.NewClientWithBaseURL("test", "token", newHTTPClient(tc.ctx, config), srv.URL+"/")
+			if err != nil {
+				t.Fatalf("NewClientWithBaseURL(...): %v", err)
+			}
+
+			done := make(chan error, 1)
+			go func() {
+				_, _, err := c.Projects.Get("project", nil)
+				done <- err
+			}()
+
+			select {
+			case err := <-done:
+				if !errors.Is(err, tc.want) {
+					t.Errorf("Projects.Get(...): want error %v, got %v", tc.want, err)
+				}
+			case <-time.After(10 * time.Second):
+				t.Fatalf("Projects.Get(...): did not return")
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+)
+
+const (
+	// credentialsValidationTTL is how long the validation of an API key is
+	// cached
+	credentialsValidationTTL = 5 * time.Minute
+
+	errInvalidCredentials = "invalid or expired Equinix Metal API key"
+)
+
+// credentialsValidator validates the API keys of all clients
+var credentialsValidator = newValidator(credentialsValidationTTL)
+
+// validator validates Equinix Metal API keys before they are used, caching
+// the outcome so that the keys are not validated on every reconcile
+type validator struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	results map[[sha256.Size]byte]validation
+}
+
+// validation is the cached outcome of validating an API key
+type validation struct {
+	err     error
+	expires time.Time
+}
+
+// newValidator returns a validator that caches outcomes for the supplied TTL
+func newValidator(ttl time.Duration) *validator {
+	return &validator{
+		ttl:     ttl,
+		now:     time.Now,
+		results: map[[sha256.Size]byte]validation{},
+	}
+}
+
+// Validate returns an error if the Equinix Metal API rejects the supplied API
+// key as invalid or expired. Keys that may not get the current user, such as
+// project API keys, and requests that fail for other reasons are not
+// rejected. Those failures surface from the requests of the controller.
+func (v *validator) Validate(c *packngo.Client, apiKey string) error {
+	// only a hash of the API key is cached, along with the API it was
+	// validated by
+	key := sha256.Sum256([]byte(c.BaseURL.String() + "\x00" + apiKey))
+
+	v.mu.Lock()
+	r, ok := v.results[key]
+	v.mu.Unlock()
+	if ok && v.now().Before(r.expires) {
+		return r.err
+	}
+
+	_, _, err := c.Users.Current()
+	switch {
+	case IsUnauthorized(err):
+		err = errors.New(errInvalidCredentials)
+	case err == nil, IsForbidden(err):
+		err = nil
+	default:
+		// the outcome is unknown, validate the key again next time
+		return nil
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for k, r := range v.results {
+		if !v.now().Before(r.expires) {
+			delete(v.results, k)
+		}
+	}
+	v.results[key] = validation{err: err, expires: v.now().Add(v.ttl)}
+	return err
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+)
+
+func TestValidateCredentials(t *testing.T) {
+	cases := map[string]struct {
+		status int
+		want   error
+		calls  int
+	}{
+		"Valid": {
+			status: http.StatusOK,
+			calls:  1,
+		},
+		"InvalidOrExpired": {
+			status: http.StatusUnauthorized,
+			want:   errors.New(errInvalidCredentials),
+			calls:  1,
+		},
+		"ProjectAPIKey": {
+			status: http.StatusForbidden,
+			calls:  1,
+		},
+		"Unavailable": {
+			// the outcome is unknown and not cached
+			status: http.StatusServiceUnavailable,
+			calls:  2,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				calls++
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(`{"errors":["denied"]}`))
+			}))
+			defer srv.Close()
+
+			c, err := packngo.NewClientWithBaseURL("test", "token", srv.Client(), srv.URL+"/")
+			if err != nil {
+				t.Fatalf("NewClientWithBaseURL(...): %v", err)
+			}
+
+			v := newValidator(time.Minute)
+			for i := 0; i < 2; i++ {
+				if diff := cmp.Diff(tc.want, v.Validate(c, "token"), test.EquateErrors()); diff != "" {
+					t.Errorf("Validate(...): -want error, +got error:\n%s", diff)
+				}
+			}
+			if calls != tc.calls {
+				t.Errorf("Validate(...): want %d API calls, got %d", tc.calls, calls)
+			}
+		})
+	}
+
+	t.Run("Expired", func(t *testing.T) {
+		calls := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls++
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{}`))
+		}))
+		defer srv.Close()
+
+		c, err := packngo.NewClientWithBaseURL("test", "token", srv.Client(), srv.URL+"/")
+		if err != nil {
+			t.Fatalf("NewClientWithBaseURL(...): %v", err)
+		}
+
+		now := time.Now()
+		v := newValidator(time.Minute)
+		v.now = func() time.Time { return now }
+
+		_ = v.Validate(c, "token")
+		now = now.Add(2 * time.Minute)
+		_ = v.Validate(c, "token")
+		_ = v.Validate(c, "other")
+
+		if calls != 3 {
+			t.Errorf("Validate(...): want 3 API calls, got %d", calls)
+		}
+	})
+}
@@ -30,9 +30,13 @@ type MockClient struct {
 	MockCreate func(createRequest *packngo.VirtualNetworkCreateRequest) (*packngo.VirtualNetwork, *packngo.Response, error)
 	MockGet    func(vlanID string, getOpt *packngo.GetOptions) (*packngo.VirtualNetwork, *packngo.Response, error)
 	MockDelete func(virtualNetworkID string) (*packngo.Response, error)
+	MockUpdate func(vlanID string, updateRequest *vlan.UpdateRequest) (*packngo.VirtualNetwork, *packngo.Response, error)
+
+	MockGetAttachments func(vlanID string) (*vlan.Attachments, *packngo.Response, error)
 
 	MockGetProjectID  func(string) string
 	MockGetFacilityID func(string) string
+	MockGetMetro      func(string) string
 }
 
 // List calls the MockClient's MockList function.
@@ -50,17 +54,32 @@ func (c *MockClient) Delete(virtualNetworkID string) (*packngo.Response, error)
 	return c.MockDelete(virtualNetworkID)
 }
 
+// Update calls the MockClient's MockUpdate function.
+func (c *MockClient) Update(vlanID string, updateRequest *vlan.UpdateRequest) (*packngo.VirtualNetwork, *packngo.Response, error) {
+	return c.MockUpdate(vlanID, updateRequest)
+}
+
+// GetAttachments calls the MockClient's MockGetAttachments function.
+func (c *MockClient) GetAttachments(vlanID string) (*vlan.Attachments, *packngo.Response, error) {
+	return c.MockGetAttachments(vlanID)
+}
+
 // Get calls the MockClient's MockGet function.
 func (c *MockClient) Get(vlanID string, getOpt *packngo.GetOptions) (*packngo.VirtualNetwork, *packngo.Response, error) {
 	return c.MockGet(vlanID, getOpt)
 }
 
-// GetFacilityID calls the MockClient's MockGet function.
+// GetFacilityID calls the MockClient's MockGetFacilityID function.
 func (c *MockClient) GetFacilityID(id string) string {
 	return c.MockGetFacilityID(id)
 }
 
-// GetProjectID calls the MockClient's MockGet function.
+// GetMetro calls the MockClient's MockGetMetro function.
+func (c *MockClient) GetMetro(metro string) string {
+	return c.MockGetMetro(metro)
+}
+
+// GetProjectID calls the MockClient's MockGetProjectID function.
 func (c *MockClient) GetProjectID(id string) string {
 	return c.MockGetProjectID(id)
 }
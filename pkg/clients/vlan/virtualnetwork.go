@@ -18,16 +18,26 @@ package vlan
 
 import (
 	"context"
+	"fmt"
+	"path"
+	"strings"
 
 	"github.com/packethost/packngo"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/packethost/crossplane-provider-equinix-metal/apis/vlan/v1alpha1"
 	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
 )
 
 const (
-	errUnmarshalDate = "cannot unmarshal date"
+	errUnmarshalDate  = "cannot unmarshal date"
+	errMetroFacility  = "only one of metro or facility may be set"
+	errVXLANNeedMetro = "a VXLAN may only be requested for VirtualNetworks in a metro"
+	errVXLANInUseFmt  = "VXLAN %d is already in use by VirtualNetwork %s in metro %s"
+	errAttachedFmt    = "VirtualNetwork can not be deleted while it is attached to %s, detach them first"
+
+	virtualNetworkPathFmt = "virtual-networks/%s"
 )
 
 // Client implements the Equinix Metal API methods needed to interact with VirtualNetworks for
@@ -39,22 +49,85 @@ type Client interface {
 	Delete(virtualNetworkID string) (*packngo.Response, error)
 }
 
+// UpdateClient implements the Equinix Metal API VirtualNetwork updates that
+// are not offered by packngo.ProjectVirtualNetworkService
+type UpdateClient interface {
+	Update(vlanID string, updateRequest *UpdateRequest) (*packngo.VirtualNetwork, *packngo.Response, error)
+}
+
+// UpdateRequest is the body of a VirtualNetwork update
+type UpdateRequest struct {
+	Description *string `json:"description,omitempty"`
+}
+
+// AttachmentsClient implements the Equinix Metal API method that finds the
+// resources attached to a VirtualNetwork, which packngo does not offer
+type AttachmentsClient interface {
+	GetAttachments(vlanID string) (*Attachments, *packngo.Response, error)
+}
+
+// Attachments are the resources attached to a VirtualNetwork, which prevent
+// it from being deleted
+type Attachments struct {
+	Devices       []Attachment `json:"instances,omitempty"`
+	MetalGateways []Attachment `json:"metal_gateways,omitempty"`
+}
+
+// Attachment is a resource attached to a VirtualNetwork. The API may only
+// include a link to the resource.
+type Attachment struct {
+	ID   string `json:"id,omitempty"`
+	Href string `json:"href,omitempty"`
+}
+
 // build-time test that the interface is implemented
 var _ Client = (&packngo.Client{}).ProjectVirtualNetworks
+var _ UpdateClient = &updateClient{}
+var _ AttachmentsClient = &updateClient{}
 
 // ClientWithDefaults is an interface that provides VirtualNetwork services and
 // provides default values for common properties
 type ClientWithDefaults interface {
 	Client
+	UpdateClient
+	AttachmentsClient
 	clients.DefaultGetter
 }
 
 // CredentialedClient is a credentialed client to Equinix Metal VirtualNetwork services
 type CredentialedClient struct {
 	Client
+	UpdateClient
+	AttachmentsClient
 	*clients.Credentials
 }
 
+// updateClient updates VirtualNetworks, and finds their attachments, through
+// the packngo request helpers
+type updateClient struct {
+	client *packngo.Client
+}
+
+// Update modifies a VirtualNetwork
+func (c *updateClient) Update(vlanID string, updateRequest *UpdateRequest) (*packngo.VirtualNetwork, *packngo.Response, error) {
+	vlan := new(packngo.VirtualNetwork)
+	resp, err := c.client.DoRequest("PUT", fmt.Sprintf(virtualNetworkPathFmt, vlanID), updateRequest, vlan)
+	if err != nil {
+		return nil, resp, err
+	}
+	return vlan, resp, nil
+}
+
+// GetAttachments returns the resources attached to a VirtualNetwork
+func (c *updateClient) GetAttachments(vlanID string) (*Attachments, *packngo.Response, error) {
+	attachments := new(Attachments)
+	resp, err := c.client.DoRequest("GET", fmt.Sprintf(virtualNetworkPathFmt, vlanID), nil, attachments)
+	if err != nil {
+		return nil, resp, err
+	}
+	return attachments, resp, nil
+}
+
 var _ ClientWithDefaults = &CredentialedClient{}
 
 // NewClient returns a Client implementing the Equinix Metal API methods needed to
@@ -65,8 +138,10 @@ func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefa
 		return nil, err
 	}
 	vlanClient := CredentialedClient{
-		Client:      client.Client.ProjectVirtualNetworks,
-		Credentials: client.Credentials,
+		Client:            client.Client.ProjectVirtualNetworks,
+		UpdateClient:      &updateClient{client: client.Client},
+		AttachmentsClient: &updateClient{client: client.Client},
+		Credentials:       client.Credentials,
 	}
 	vlanClient.SetProjectID(config.ProjectID)
 	return vlanClient, nil
@@ -98,9 +173,12 @@ func GenerateObservation(vlan *packngo.VirtualNetwork) (v1alpha1.VirtualNetworkO
 		Href:         vlan.Href,
 		VXLAN:        vlan.VXLAN,
 		FacilityCode: vlan.FacilityCode,
+		MetroCode:    vlan.MetroCode,
+		AssignedTo:   projectID(vlan.Project),
 	}
 
-	if !observation.CreatedAt.IsZero() {
+	if vlan.CreatedAt != "" {
+		observation.CreatedAt = &metav1.Time{}
 		if err := observation.CreatedAt.UnmarshalText([]byte(vlan.CreatedAt)); err != nil {
 			return v1alpha1.VirtualNetworkObservation{}, errors.Wrap(err, errUnmarshalDate)
 		}
@@ -109,6 +187,83 @@ func GenerateObservation(vlan *packngo.VirtualNetwork) (v1alpha1.VirtualNetworkO
 	return observation, nil
 }
 
+// projectID returns the ID of the project a VirtualNetwork is assigned to. The
+// API may only include a link to the project.
+func projectID(p *packngo.Project) string {
+	if p == nil {
+		return ""
+	}
+	if p.ID != "" {
+		return p.ID
+	}
+	if p.URL != "" {
+		return path.Base(p.URL)
+	}
+	return ""
+}
+
+// ValidateLocation returns an error if the supplied VirtualNetwork requests
+// both a metro and a facility
+func ValidateLocation(d *v1alpha1.VirtualNetwork) error {
+	if d.Spec.ForProvider.Metro != "" && d.Spec.ForProvider.Facility != "" {
+		return errors.New(errMetroFacility)
+	}
+	return nil
+}
+
+// ValidateVXLAN returns an error if the VXLAN requested by the supplied
+// VirtualNetwork can not be used. A VXLAN may only be requested along with a
+// metro and must not be used by any of the supplied existing VirtualNetworks
+// in the same metro.
+func ValidateVXLAN(d *v1alpha1.VirtualNetwork, existing []packngo.VirtualNetwork) error {
+	p := d.Spec.ForProvider
+	if p.VXLAN == 0 {
+		return nil
+	}
+	if p.Metro == "" {
+		return errors.New(errVXLANNeedMetro)
+	}
+	for _, vlan := range existing {
+		metro := vlan.MetroCode
+		if metro == "" && vlan.Metro != nil {
+			metro = vlan.Metro.Code
+		}
+		if vlan.VXLAN == p.VXLAN && strings.EqualFold(metro, p.Metro) {
+			return errors.Errorf(errVXLANInUseFmt, p.VXLAN, vlan.ID, metro)
+		}
+	}
+	return nil
+}
+
+// ValidateDetached returns an error naming the resources attached to a
+// VirtualNetwork, which must be detached before it can be deleted
+func ValidateDetached(a *Attachments) error {
+	var attached []string
+	if ids := attachmentIDs(a.Devices); len(ids) > 0 {
+		attached = append(attached, "Devices "+strings.Join(ids, ", "))
+	}
+	if ids := attachmentIDs(a.MetalGateways); len(ids) > 0 {
+		attached = append(attached, "MetalGateways "+strings.Join(ids, ", "))
+	}
+	if len(attached) == 0 {
+		return nil
+	}
+	return errors.Errorf(errAttachedFmt, strings.Join(attached, " and "))
+}
+
+// attachmentIDs returns the IDs of the attached resources
+func attachmentIDs(attached []Attachment) []string {
+	ids := make([]string, 0, len(attached))
+	for _, a := range attached {
+		id := a.ID
+		if id == "" && a.Href != "" {
+			id = path.Base(a.Href)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
 // LateInitialize fills the empty fields in *v1alpha2.VirtualNetworkParameters with the
 // values seen in packngo.VirtualNetwork
 func LateInitialize(in *v1alpha1.VirtualNetworkParameters, vlan *packngo.VirtualNetwork) {
@@ -117,21 +272,33 @@ func LateInitialize(in *v1alpha1.VirtualNetworkParameters, vlan *packngo.Virtual
 	}
 
 	in.Description = clients.LateInitializeStringPtr(in.Description, &vlan.Description)
+
+	// VirtualNetworks created in a metro may still be reported in a facility
+	if in.Metro != "" {
+		in.Facility = clients.LateInitializeString(in.Facility, &vlan.FacilityCode)
+	}
 }
 
 // IsUpToDate returns true if the supplied Kubernetes resource does not differ
 // from the supplied Equinix Metal resource. It considers only fields that can be
-// modified in place without deleting and recreating the instance, which are
-// immutable.
+// modified in place without deleting and recreating the instance. The
+// description is the only such field, all others are immutable.
 func IsUpToDate(d *v1alpha1.VirtualNetwork, p *packngo.VirtualNetwork) bool {
-	if !nilOrEqualStr(&d.Spec.ForProvider.Facility, p.FacilityCode) {
-		return false
-	}
-	if !nilOrEqualStr(d.Spec.ForProvider.Description, p.Description) {
-		return false
+	return nilOrEqualStr(d.Spec.ForProvider.Description, p.Description)
+}
+
+// NewUpdateRequest creates a request to update a VirtualNetwork suitable for
+// use with the Equinix Metal API.
+func NewUpdateRequest(d *v1alpha1.VirtualNetwork) *UpdateRequest {
+	return &UpdateRequest{
+		Description: d.Spec.ForProvider.Description,
 	}
+}
 
-	return true
+// IsUpdateUnsupported returns true if the Equinix Metal API rejected an update
+// because VirtualNetworks can not be modified
+func IsUpdateUnsupported(err error) bool {
+	return clients.IsMethodNotAllowed(err) || clients.IsNotImplemented(err)
 }
 
 // nilOrEqualStr is true if a (aPtr) is non-nil and equal to b
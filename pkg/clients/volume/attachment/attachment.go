@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package attachment
+
+import (
+	"context"
+
+	"github.com/packethost/packngo"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/volume"
+)
+
+// Client implements the Equinix Metal API methods needed to interact with
+// VolumeAttachments for the Equinix Metal Crossplane Provider
+type Client interface {
+	Get(attachmentID string, getOpt *packngo.GetOptions) (*packngo.VolumeAttachment, *packngo.Response, error)
+	Create(volumeID, deviceID string) (*packngo.VolumeAttachment, *packngo.Response, error)
+	Delete(attachmentID string) (*packngo.Response, error)
+}
+
+// build-time test that the interface is implemented
+var _ Client = (&packngo.Client{}).VolumeAttachments
+
+// ClientWithDefaults is an interface that provides VolumeAttachment services,
+// the lookups of the attached Volumes and Devices, and provides default values
+// for common properties
+type ClientWithDefaults interface {
+	Client
+	GetVolume(volumeID string, getOpt *packngo.GetOptions) (*packngo.Volume, *packngo.Response, error)
+	GetDevice(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error)
+	clients.DefaultGetter
+}
+
+// CredentialedClient is a credentialed client to Equinix Metal VolumeAttachment
+// services
+type CredentialedClient struct {
+	Client
+	*clients.Credentials
+
+	volumes packngo.VolumeService
+	devices packngo.DeviceService
+}
+
+var _ ClientWithDefaults = &CredentialedClient{}
+
+// NewClient returns a Client implementing the Equinix Metal API methods needed
+// to interact with VolumeAttachments for the Equinix Metal Crossplane Provider
+func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefaults, error) {
+	client, err := clients.NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	attachmentClient := CredentialedClient{
+		Client:      client.Client.VolumeAttachments,
+		Credentials: client.Credentials,
+		volumes:     client.Client.Volumes,
+		devices:     client.Client.Devices,
+	}
+	attachmentClient.SetProjectID(config.ProjectID)
+	return attachmentClient, nil
+}
+
+// GetVolume gets the Volume with the given ID
+func (c CredentialedClient) GetVolume(volumeID string, getOpt *packngo.GetOptions) (*packngo.Volume, *packngo.Response, error) {
+	return c.volumes.Get(volumeID, getOpt)
+}
+
+// GetDevice gets the Device with the given ID
+func (c CredentialedClient) GetDevice(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+	return c.devices.Get(deviceID, getOpt)
+}
+
+// FindAttachment returns the attachment of the Volume to the Device, or nil if
+// the Volume is not attached to the Device
+func FindAttachment(v *packngo.Volume, deviceID string) *packngo.VolumeAttachment {
+	for _, a := range v.Attachments {
+		if a != nil && volume.AttachedDeviceID(a) == deviceID {
+			return a
+		}
+	}
+	return nil
+}
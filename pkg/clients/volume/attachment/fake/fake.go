@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/packethost/packngo"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/volume/attachment"
+)
+
+var _ attachment.ClientWithDefaults = &MockClient{}
+
+// MockClient is a fake implementation of packngo.Client.
+type MockClient struct {
+	MockGet       func(attachmentID string, getOpt *packngo.GetOptions) (*packngo.VolumeAttachment, *packngo.Response, error)
+	MockCreate    func(volumeID, deviceID string) (*packngo.VolumeAttachment, *packngo.Response, error)
+	MockDelete    func(attachmentID string) (*packngo.Response, error)
+	MockGetVolume func(volumeID string, getOpt *packngo.GetOptions) (*packngo.Volume, *packngo.Response, error)
+	MockGetDevice func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error)
+
+	MockGetProjectID  func(string) string
+	MockGetFacilityID func(string) string
+	MockGetMetro      func(string) string
+}
+
+// Get calls the MockClient's MockGet function.
+func (c *MockClient) Get(attachmentID string, getOpt *packngo.GetOptions) (*packngo.VolumeAttachment, *packngo.Response, error) {
+	return c.MockGet(attachmentID, getOpt)
+}
+
+// Create calls the MockClient's MockCreate function.
+func (c *MockClient) Create(volumeID, deviceID string) (*packngo.VolumeAttachment, *packngo.Response, error) {
+	return c.MockCreate(volumeID, deviceID)
+}
+
+// Delete calls the MockClient's MockDelete function.
+func (c *MockClient) Delete(attachmentID string) (*packngo.Response, error) {
+	return c.MockDelete(attachmentID)
+}
+
+// GetVolume calls the MockClient's MockGetVolume function.
+func (c *MockClient) GetVolume(volumeID string, getOpt *packngo.GetOptions) (*packngo.Volume, *packngo.Response, error) {
+	return c.MockGetVolume(volumeID, getOpt)
+}
+
+// GetDevice calls the MockClient's MockGetDevice function.
+func (c *MockClient) GetDevice(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+	return c.MockGetDevice(deviceID, getOpt)
+}
+
+// GetFacilityID calls the MockClient's MockGetFacilityID function.
+func (c *MockClient) GetFacilityID(id string) string {
+	return c.MockGetFacilityID(id)
+}
+
+// GetMetro calls the MockClient's MockGetMetro function.
+func (c *MockClient) GetMetro(metro string) string {
+	return c.MockGetMetro(metro)
+}
+
+// GetProjectID calls the MockClient's MockGetProjectID function.
+func (c *MockClient) GetProjectID(id string) string {
+	return c.MockGetProjectID(id)
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume
+
+import (
+	"context"
+	"path"
+
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/volume/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+const (
+	errUnmarshalDate = "cannot unmarshal date"
+)
+
+// Client implements the Equinix Metal API methods needed to interact with
+// Volumes for the Equinix Metal Crossplane Provider
+type Client interface {
+	Get(volumeID string, getOpt *packngo.GetOptions) (*packngo.Volume, *packngo.Response, error)
+	Create(createRequest *packngo.VolumeCreateRequest, projectID string) (*packngo.Volume, *packngo.Response, error)
+	Update(volumeID string, updateRequest *packngo.VolumeUpdateRequest) (*packngo.Volume, *packngo.Response, error)
+	Delete(volumeID string) (*packngo.Response, error)
+	Lock(volumeID string) (*packngo.Response, error)
+	Unlock(volumeID string) (*packngo.Response, error)
+}
+
+// build-time test that the interface is implemented
+var _ Client = (&packngo.Client{}).Volumes
+
+// ClientWithDefaults is an interface that provides Volume services and
+// provides default values for common properties
+type ClientWithDefaults interface {
+	Client
+	clients.DefaultGetter
+}
+
+// CredentialedClient is a credentialed client to Equinix Metal Volume services
+type CredentialedClient struct {
+	Client
+	*clients.Credentials
+}
+
+var _ ClientWithDefaults = &CredentialedClient{}
+
+// NewClient returns a Client implementing the Equinix Metal API methods needed
+// to interact with Volumes for the Equinix Metal Crossplane Provider
+func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefaults, error) {
+	client, err := clients.NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	volumeClient := CredentialedClient{
+		Client:      client.Client.Volumes,
+		Credentials: client.Credentials,
+	}
+	volumeClient.SetProjectID(config.ProjectID)
+	return volumeClient, nil
+}
+
+// CreateFromVolume return packngo.VolumeCreateRequest created from Kubernetes
+func CreateFromVolume(v *v1alpha1.Volume, facilityID string) *packngo.VolumeCreateRequest {
+	return &packngo.VolumeCreateRequest{
+		Size:         v.Spec.ForProvider.Size,
+		PlanID:       emptyIfNil(v.Spec.ForProvider.PlanID),
+		FacilityID:   facilityID,
+		BillingCycle: emptyIfNil(v.Spec.ForProvider.BillingCycle),
+		Description:  emptyIfNil(v.Spec.ForProvider.Description),
+		Locked:       v.Spec.ForProvider.Locked != nil && *v.Spec.ForProvider.Locked,
+	}
+}
+
+// FacilityOrEmpty returns the facility of the Volume or an empty string if
+// none was specified
+func FacilityOrEmpty(v *v1alpha1.Volume) string {
+	return emptyIfNil(v.Spec.ForProvider.Facility)
+}
+
+func emptyIfNil(in *string) string {
+	if in == nil {
+		return ""
+	}
+	return *in
+}
+
+// GenerateObservation produces v1alpha1.VolumeObservation from
+// packngo.Volume
+func GenerateObservation(volume *packngo.Volume) (v1alpha1.VolumeObservation, error) {
+	observation := v1alpha1.VolumeObservation{
+		ID:    volume.ID,
+		Href:  volume.Href,
+		Name:  volume.Name,
+		State: volume.State,
+	}
+
+	for _, a := range volume.Attachments {
+		if a == nil {
+			continue
+		}
+		observation.Attachments = append(observation.Attachments, v1alpha1.Attachment{
+			ID:       a.ID,
+			DeviceID: AttachedDeviceID(a),
+		})
+	}
+
+	if volume.Created != "" {
+		observation.CreatedAt = &metav1.Time{}
+		if err := observation.CreatedAt.UnmarshalText([]byte(volume.Created)); err != nil {
+			return v1alpha1.VolumeObservation{}, errors.Wrap(err, errUnmarshalDate)
+		}
+	}
+	if volume.Updated != "" {
+		observation.UpdatedAt = &metav1.Time{}
+		if err := observation.UpdatedAt.UnmarshalText([]byte(volume.Updated)); err != nil {
+			return v1alpha1.VolumeObservation{}, errors.Wrap(err, errUnmarshalDate)
+		}
+	}
+
+	return observation, nil
+}
+
+// AttachedDeviceID returns the ID of the Device of a VolumeAttachment. Volume
+// responses only include the Href of attached Devices.
+func AttachedDeviceID(a *packngo.VolumeAttachment) string {
+	if a.Device.ID != "" {
+		return a.Device.ID
+	}
+	if a.Device.Href != "" {
+		return path.Base(a.Device.Href)
+	}
+	return ""
+}
+
+// LateInitialize fills the empty fields in *v1alpha1.VolumeParameters with
+// the values seen in packngo.Volume
+func LateInitialize(in *v1alpha1.VolumeParameters, volume *packngo.Volume) {
+	if volume == nil {
+		return
+	}
+
+	if volume.Plan != nil {
+		in.PlanID = clients.LateInitializeStringPtr(in.PlanID, &volume.Plan.Slug)
+	}
+	if volume.Facility != nil {
+		in.Facility = clients.LateInitializeStringPtr(in.Facility, &volume.Facility.Code)
+	}
+	in.BillingCycle = clients.LateInitializeStringPtr(in.BillingCycle, &volume.BillingCycle)
+	in.Description = clients.LateInitializeStringPtr(in.Description, &volume.Description)
+	in.Locked = clients.LateInitializeBoolPtr(in.Locked, &volume.Locked)
+}
+
+// IsUpToDate returns true if the supplied Kubernetes resource does not differ
+// from the supplied Equinix Metal resource. It considers only fields that can
+// be modified in place without deleting and recreating the instance.
+func IsUpToDate(v *v1alpha1.Volume, volume *packngo.Volume) bool {
+	if v.Spec.ForProvider.Size != volume.Size {
+		return false
+	}
+	if volume.Plan != nil && !nilOrEqualStr(v.Spec.ForProvider.PlanID, volume.Plan.Slug) {
+		return false
+	}
+	if !nilOrEqualStr(v.Spec.ForProvider.BillingCycle, volume.BillingCycle) {
+		return false
+	}
+	if !nilOrEqualStr(v.Spec.ForProvider.Description, volume.Description) {
+		return false
+	}
+	return IsLockUpToDate(v, volume)
+}
+
+// IsLockUpToDate returns true if the supplied Equinix Metal resource is locked
+// or unlocked as desired by the supplied Kubernetes resource.
+func IsLockUpToDate(v *v1alpha1.Volume, volume *packngo.Volume) bool {
+	return v.Spec.ForProvider.Locked == nil || *v.Spec.ForProvider.Locked == volume.Locked
+}
+
+// nilOrEqualStr is true if a (aPtr) is nil or equal to b
+func nilOrEqualStr(aPtr *string, b string) bool {
+	return aPtr == nil || *aPtr == b
+}
+
+// NewUpdateVolumeRequest creates a request to update a volume suitable for use
+// with the Equinix Metal API. Locking is not part of the update request and is
+// handled separately.
+func NewUpdateVolumeRequest(v *v1alpha1.Volume) *packngo.VolumeUpdateRequest {
+	size := v.Spec.ForProvider.Size
+	return &packngo.VolumeUpdateRequest{
+		Size:         &size,
+		PlanID:       v.Spec.ForProvider.PlanID,
+		BillingCycle: v.Spec.ForProvider.BillingCycle,
+		Description:  v.Spec.ForProvider.Description,
+	}
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vrf
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/vrf/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+const (
+	errUnmarshalDate = "cannot unmarshal date"
+
+	vrfPathFmt        = "vrfs/%s"
+	projectVRFPathFmt = "projects/%s/vrfs"
+)
+
+// VRF is an Equinix Metal Virtual Routing and Forwarding instance
+type VRF struct {
+	ID          string           `json:"id"`
+	Href        string           `json:"href,omitempty"`
+	Name        string           `json:"name,omitempty"`
+	Description string           `json:"description,omitempty"`
+	LocalASN    int              `json:"local_asn,omitempty"`
+	IPRanges    []string         `json:"ip_ranges,omitempty"`
+	Metro       *packngo.Metro   `json:"metro,omitempty"`
+	Project     *packngo.Project `json:"project,omitempty"`
+	CreatedAt   string           `json:"created_at,omitempty"`
+	UpdatedAt   string           `json:"updated_at,omitempty"`
+}
+
+// CreateRequest is the body of a VRF creation
+type CreateRequest struct {
+	Name        string   `json:"name"`
+	Description *string  `json:"description,omitempty"`
+	Metro       string   `json:"metro"`
+	LocalASN    int      `json:"local_asn,omitempty"`
+	IPRanges    []string `json:"ip_ranges,omitempty"`
+}
+
+// UpdateRequest is the body of a VRF update
+type UpdateRequest struct {
+	Description *string   `json:"description,omitempty"`
+	IPRanges    *[]string `json:"ip_ranges,omitempty"`
+}
+
+// Client implements the Equinix Metal API methods needed to interact with
+// VRFs for the Equinix Metal Crossplane Provider. VRFs are not offered by
+// packngo, they are requested through the packngo request helpers.
+type Client interface {
+	Create(projectID string, createRequest *CreateRequest) (*VRF, *packngo.Response, error)
+	Get(vrfID string) (*VRF, *packngo.Response, error)
+	Update(vrfID string, updateRequest *UpdateRequest) (*VRF, *packngo.Response, error)
+	Delete(vrfID string) (*packngo.Response, error)
+}
+
+// build-time test that the interface is implemented
+var _ Client = &vrfClient{}
+
+// ClientWithDefaults is an interface that provides VRF services and provides
+// default values for common properties
+type ClientWithDefaults interface {
+	Client
+	clients.DefaultGetter
+}
+
+// CredentialedClient is a credentialed client to Equinix Metal VRF services
+type CredentialedClient struct {
+	Client
+	*clients.Credentials
+}
+
+var _ ClientWithDefaults = &CredentialedClient{}
+
+// vrfClient requests VRFs through the packngo request helpers
+type vrfClient struct {
+	client *packngo.Client
+}
+
+// Create a VRF in a project
+func (c *vrfClient) Create(projectID string, createRequest *CreateRequest) (*VRF, *packngo.Response, error) {
+	vrf := new(VRF)
+	resp, err := c.client.DoRequest("POST", fmt.Sprintf(projectVRFPathFmt, projectID), createRequest, vrf)
+	if err != nil {
+		return nil, resp, err
+	}
+	return vrf, resp, nil
+}
+
+// Get a VRF
+func (c *vrfClient) Get(vrfID string) (*VRF, *packngo.Response, error) {
+	vrf := new(VRF)
+	resp, err := c.client.DoRequest("GET", fmt.Sprintf(vrfPathFmt, vrfID), nil, vrf)
+	if err != nil {
+		return nil, resp, err
+	}
+	return vrf, resp, nil
+}
+
+// Update modifies a VRF
+func (c *vrfClient) Update(vrfID string, updateRequest *UpdateRequest) (*VRF, *packngo.Response, error) {
+	vrf := new(VRF)
+	resp, err := c.client.DoRequest("PUT", fmt.Sprintf(vrfPathFmt, vrfID), updateRequest, vrf)
+	if err != nil {
+		return nil, resp, err
+	}
+	return vrf, resp, nil
+}
+
+// Delete a VRF
+func (c *vrfClient) Delete(vrfID string) (*packngo.Response, error) {
+	return c.client.DoRequest("DELETE", fmt.Sprintf(vrfPathFmt, vrfID), nil, nil)
+}
+
+// NewClient returns a Client implementing the Equinix Metal API methods needed
+// to interact with VRFs for the Equinix Metal Crossplane Provider
+func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefaults, error) {
+	client, err := clients.NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	vrfClient := CredentialedClient{
+		Client:      &vrfClient{client: client.Client},
+		Credentials: client.Credentials,
+	}
+	vrfClient.SetProjectID(config.ProjectID)
+	return vrfClient, nil
+}
+
+// CreateFromVRF returns a CreateRequest created from Kubernetes
+func CreateFromVRF(v *v1alpha1.VRF) *CreateRequest {
+	p := v.Spec.ForProvider
+	r := &CreateRequest{
+		Name:        p.Name,
+		Description: p.Description,
+		Metro:       p.Metro,
+		IPRanges:    p.IPRanges,
+	}
+	if p.LocalASN != nil {
+		r.LocalASN = *p.LocalASN
+	}
+	return r
+}
+
+// NewUpdateRequest returns an UpdateRequest of the updatable parameters of
+// the supplied VRF
+func NewUpdateRequest(v *v1alpha1.VRF) *UpdateRequest {
+	r := &UpdateRequest{Description: v.Spec.ForProvider.Description}
+	if v.Spec.ForProvider.IPRanges != nil {
+		ranges := v.Spec.ForProvider.IPRanges
+		r.IPRanges = &ranges
+	}
+	return r
+}
+
+// GenerateObservation produces v1alpha1.VRFObservation from VRF
+func GenerateObservation(vrf *VRF) (v1alpha1.VRFObservation, error) {
+	observation := v1alpha1.VRFObservation{
+		ID:   vrf.ID,
+		Href: vrf.Href,
+	}
+
+	if vrf.Metro != nil {
+		observation.Metro = vrf.Metro.Code
+	}
+
+	if vrf.CreatedAt != "" {
+		observation.CreatedAt = &metav1.Time{}
+		if err := observation.CreatedAt.UnmarshalText([]byte(vrf.CreatedAt)); err != nil {
+			return v1alpha1.VRFObservation{}, errors.Wrap(err, errUnmarshalDate)
+		}
+	}
+	if vrf.UpdatedAt != "" {
+		observation.UpdatedAt = &metav1.Time{}
+		if err := observation.UpdatedAt.UnmarshalText([]byte(vrf.UpdatedAt)); err != nil {
+			return v1alpha1.VRFObservation{}, errors.Wrap(err, errUnmarshalDate)
+		}
+	}
+
+	return observation, nil
+}
+
+// LateInitialize fills the empty fields in *v1alpha1.VRFParameters with the
+// values seen in VRF
+func LateInitialize(in *v1alpha1.VRFParameters, vrf *VRF) {
+	if vrf == nil {
+		return
+	}
+	if vrf.Project != nil && in.ProjectID == "" {
+		in.ProjectID = vrf.Project.ID
+		if in.ProjectID == "" && vrf.Project.URL != "" {
+			in.ProjectID = path.Base(vrf.Project.URL)
+		}
+	}
+	in.Description = clients.LateInitializeStringPtr(in.Description, &vrf.Description)
+	if vrf.LocalASN != 0 {
+		in.LocalASN = clients.LateInitializeIntPtr(in.LocalASN, &vrf.LocalASN)
+	}
+	if len(in.IPRanges) == 0 {
+		in.IPRanges = vrf.IPRanges
+	}
+}
+
+// IsUpToDate returns true if the supplied Kubernetes resource does not differ
+// from the supplied Equinix Metal resource. Only the description and IP ranges
+// of a VRF can be updated.
+func IsUpToDate(v *v1alpha1.VRF, vrf *VRF) bool {
+	p := v.Spec.ForProvider
+	if p.Description != nil && *p.Description != vrf.Description {
+		return false
+	}
+	return equalRanges(p.IPRanges, vrf.IPRanges)
+}
+
+// equalRanges is true if a and b contain the same IP ranges, in any order
+func equalRanges(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	as := append([]string(nil), a...)
+	bs := append([]string(nil), b...)
+	sort.Strings(as)
+	sort.Strings(bs)
+	for i := range as {
+		if as[i] != bs[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batch
+
+import (
+	"context"
+	"time"
+
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/batch/v1alpha1"
+	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	packetclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	batchclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/batch"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errTrackPCUsage            = "cannot track ProviderConfig usage"
+	errGetProviderConfigSecret = "cannot get ProviderConfig Secret"
+	errNewClient               = "cannot create new DeviceBatch client"
+	errNotDeviceBatch          = "managed resource is not a DeviceBatch"
+	errGetDeviceBatch          = "cannot get DeviceBatch"
+	errCreateDeviceBatch       = "cannot create DeviceBatch"
+	errDeleteDeviceBatch       = "cannot delete DeviceBatch"
+	errUnexpectedBatches       = "expected 1 Batch to be created, not %d"
+)
+
+// SetupDeviceBatch adds a controller that reconciles DeviceBatches
+func SetupDeviceBatch(mgr ctrl.Manager, l logging.Logger, poll time.Duration) error {
+	name := managed.ControllerName(v1alpha1.DeviceBatchGroupKind)
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.DeviceBatchGroupVersionKind),
+		managed.WithExternalConnecter(clients.NewManagementPolicyConnecter(&connecter{
+			kube:  mgr.GetClient(),
+			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
+		})),
+		managed.WithInitializers(&managed.DefaultProviderConfig{}),
+		managed.WithConnectionPublishers(),
+		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+		managed.WithPollInterval(poll),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+	)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.DeviceBatch{}).
+		Complete(clients.NewPollingReconciler(mgr, resource.ManagedKind(v1alpha1.DeviceBatchGroupVersionKind), r))
+}
+
+type connecter struct {
+	kube        client.Client
+	usage       resource.Tracker
+	newClientFn func(ctx context.Context, config *clients.Credentials) (batchclient.ClientWithDefaults, error)
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.DeviceBatch); !ok {
+		return nil, errors.New(errNotDeviceBatch)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	newClientFn := batchclient.NewClient
+	if c.newClientFn != nil {
+		newClientFn = c.newClientFn
+	}
+	cfg, err := clients.GetAuthInfo(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetProviderConfigSecret)
+	}
+	client, err := newClientFn(ctx, cfg)
+
+	return &external{kube: c.kube, client: client, defaultTags: cfg.DefaultTags}, errors.Wrap(err, errNewClient)
+}
+
+type external struct {
+	kube   client.Client
+	client batchclient.ClientWithDefaults
+
+	// defaultTags of the ProviderConfig are merged into the tags of created
+	// resources
+	defaultTags map[string]string
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	b, ok := mg.(*v1alpha1.DeviceBatch)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotDeviceBatch)
+	}
+
+	// Observe the batch and the devices it has provisioned
+	batch, _, err := e.client.Get(meta.GetExternalName(b), (&packngo.GetOptions{}).Including("devices"))
+	if packetclient.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetDeviceBatch)
+	}
+
+	b.Status.AtProvider = batchclient.GenerateObservation(batch)
+
+	switch {
+	case b.Status.AtProvider.State == v1alpha1.StateFailed:
+		b.Status.SetConditions(xpv1.Unavailable())
+	case batchclient.IsProvisioned(b):
+		b.Status.SetConditions(xpv1.Available())
+	default:
+		b.Status.SetConditions(xpv1.Creating())
+	}
+
+	// NOTE: Batches can not be modified once created
+	o := managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: true,
+	}
+
+	return o, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	b, ok := mg.(*v1alpha1.DeviceBatch)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotDeviceBatch)
+	}
+
+	b.Status.SetConditions(xpv1.Creating())
+
+	projectID := e.client.GetProjectID(b.Spec.ForProvider.ProjectID)
+	create := batchclient.CreateFromDeviceBatch(b, projectID)
+	for i := range create.Batches {
+		create.Batches[i].Tags = clients.MergeTags(create.Batches[i].Tags, e.defaultTags)
+	}
+	batches, _, err := e.client.Create(projectID, create)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateDeviceBatch)
+	}
+	if len(batches) != 1 {
+		return managed.ExternalCreation{}, errors.Errorf(errUnexpectedBatches, len(batches))
+	}
+
+	b.Status.AtProvider.ID = batches[0].ID
+	meta.SetExternalName(b, batches[0].ID)
+
+	// The resource exists once it is created, so failing to persist its
+	// external name is not reported as a failed creation. The reconciler is
+	// asked to persist the external name instead, retrying as it does so.
+	if err := e.kube.Update(ctx, b); err != nil {
+		return managed.ExternalCreation{ExternalNameAssigned: true}, nil
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	// NOTE: DeviceBatch cannot be updated.
+	return managed.ExternalUpdate{}, nil
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	b, ok := mg.(*v1alpha1.DeviceBatch)
+	if !ok {
+		return errors.New(errNotDeviceBatch)
+	}
+	b.SetConditions(xpv1.Deleting())
+
+	deleteDevices := b.Spec.ForProvider.DeleteDevices != nil && *b.Spec.ForProvider.DeleteDevices
+	_, err := e.client.Delete(meta.GetExternalName(b), deleteDevices)
+	return errors.Wrap(resource.Ignore(packetclient.IsNotFound, err), errDeleteDeviceBatch)
+}
@@ -0,0 +1,528 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batch
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/batch/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/batch/fake"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const (
+	batchID   = "batch-id"
+	projectID = "project-id"
+)
+
+var errorBoom = errors.New("boom")
+
+type strange struct {
+	resource.Managed
+}
+
+type batchModifier func(*v1alpha1.DeviceBatch)
+
+func withExternalName(id string) batchModifier {
+	return func(b *v1alpha1.DeviceBatch) { meta.SetExternalName(b, id) }
+}
+
+func withDeleteDevices(d bool) batchModifier {
+	return func(b *v1alpha1.DeviceBatch) { b.Spec.ForProvider.DeleteDevices = &d }
+}
+
+func withConditions(c ...xpv1.Condition) batchModifier {
+	return func(b *v1alpha1.DeviceBatch) { b.Status.SetConditions(c...) }
+}
+
+func withObservation(o v1alpha1.DeviceBatchObservation) batchModifier {
+	return func(b *v1alpha1.DeviceBatch) { b.Status.AtProvider = o }
+}
+
+func deviceBatch(bm ...batchModifier) *v1alpha1.DeviceBatch {
+	b := &v1alpha1.DeviceBatch{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cool-batch"},
+		Spec: v1alpha1.DeviceBatchSpec{
+			ForProvider: v1alpha1.DeviceBatchParameters{
+				ProjectID: projectID,
+				Quantity:  2,
+				InstanceParameters: v1alpha1.DeviceBatchInstanceParameters{
+					Plan:       "c3.small.x86",
+					OS:         "ubuntu_20_04",
+					Hostname:   "worker-{{index}}",
+					Facilities: []string{"da11"},
+					Tags:       []string{"cool"},
+				},
+			},
+		},
+	}
+	for _, m := range bm {
+		m(b)
+	}
+	return b
+}
+
+// observed returns a batch in the supplied state whose devices are in the
+// supplied states
+func observed(state string, deviceStates ...string) *packngo.Batch {
+	b := &packngo.Batch{
+		ID:    batchID,
+		Href:  "/metal/v1/batches/" + batchID,
+		State: state,
+	}
+	for i, s := range deviceStates {
+		b.Devices = append(b.Devices, packngo.Device{ID: fmt.Sprintf("device-%d", i+1), State: s})
+	}
+	return b
+}
+
+var _ managed.ExternalClient = &external{}
+
+func TestObserve(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg          resource.Managed
+		observation managed.ExternalObservation
+		err         error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Provisioned": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.Batch, *packngo.Response, error) {
+					if id != batchID {
+						t.Errorf("MockGet: want %q, got %q", batchID, id)
+					}
+					return observed(v1alpha1.StateCompleted, "active", "active"), nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  deviceBatch(withExternalName(batchID)),
+			},
+			want: want{
+				mg: deviceBatch(
+					withExternalName(batchID),
+					withObservation(v1alpha1.DeviceBatchObservation{
+						ID:               batchID,
+						Href:             "/metal/v1/batches/" + batchID,
+						State:            v1alpha1.StateCompleted,
+						ProvisionedCount: 2,
+						DeviceIDs:        []string{"device-1", "device-2"},
+					}),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"Provisioning": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(string, *packngo.GetOptions) (*packngo.Batch, *packngo.Response, error) {
+					return observed("pending", "active", "provisioning"), nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  deviceBatch(withExternalName(batchID)),
+			},
+			want: want{
+				mg: deviceBatch(
+					withExternalName(batchID),
+					withObservation(v1alpha1.DeviceBatchObservation{
+						ID:               batchID,
+						Href:             "/metal/v1/batches/" + batchID,
+						State:            "pending",
+						ProvisionedCount: 1,
+						DeviceIDs:        []string{"device-1", "device-2"},
+					}),
+					withConditions(xpv1.Creating())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"Failed": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(string, *packngo.GetOptions) (*packngo.Batch, *packngo.Response, error) {
+					b := observed(v1alpha1.StateFailed)
+					b.ErrorMessages = []string{"no capacity"}
+					return b, nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  deviceBatch(withExternalName(batchID)),
+			},
+			want: want{
+				mg: deviceBatch(
+					withExternalName(batchID),
+					withObservation(v1alpha1.DeviceBatchObservation{
+						ID:            batchID,
+						Href:          "/metal/v1/batches/" + batchID,
+						State:         v1alpha1.StateFailed,
+						ErrorMessages: []string{"no capacity"},
+					}),
+					withConditions(xpv1.Unavailable())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"NotFound": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(string, *packngo.GetOptions) (*packngo.Batch, *packngo.Response, error) {
+					return nil, nil, packettest.NotFound()
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  deviceBatch(withExternalName(batchID)),
+			},
+			want: want{
+				mg:          deviceBatch(withExternalName(batchID)),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"FailedToGet": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(string, *packngo.GetOptions) (*packngo.Batch, *packngo.Response, error) {
+					return nil, nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  deviceBatch(withExternalName(batchID)),
+			},
+			want: want{
+				mg:  deviceBatch(withExternalName(batchID)),
+				err: errors.Wrap(errorBoom, errGetDeviceBatch),
+			},
+		},
+		"NotDeviceBatch": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotDeviceBatch),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := tc.client.Observe(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Observe(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.observation, o); diff != "" {
+				t.Errorf("tc.client.Observe(): -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg       resource.Managed
+		creation managed.ExternalCreation
+		err      error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Created": {
+			client: &external{
+				kube:        &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				defaultTags: map[string]string{"team": "infra"},
+				client: &fake.MockClient{
+					MockGetProjectID: func(id string) string { return id },
+					MockCreate: func(pID string, create *packngo.BatchCreateRequest) ([]packngo.Batch, *packngo.Response, error) {
+						if pID != projectID {
+							t.Errorf("MockCreate: want project %q, got %q", projectID, pID)
+						}
+						want := &packngo.BatchCreateRequest{Batches: []packngo.BatchCreateDevice{{
+							DeviceCreateRequest: packngo.DeviceCreateRequest{
+								Hostname:  "worker-{{index}}",
+								Plan:      "c3.small.x86",
+								Facility:  []string{"da11"},
+								OS:        "ubuntu_20_04",
+								ProjectID: projectID,
+								Tags:      []string{"cool", "team=infra"},
+							},
+							Quantity: 2,
+						}}}
+						if diff := cmp.Diff(want, create); diff != "" {
+							t.Errorf("MockCreate: -want, +got:\n%s", diff)
+						}
+						return []packngo.Batch{{ID: batchID}}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  deviceBatch(),
+			},
+			want: want{
+				mg: deviceBatch(
+					withExternalName(batchID),
+					withObservation(v1alpha1.DeviceBatchObservation{ID: batchID}),
+					withConditions(xpv1.Creating())),
+			},
+		},
+		"UnexpectedBatches": {
+			client: &external{client: &fake.MockClient{
+				MockGetProjectID: func(id string) string { return id },
+				MockCreate: func(string, *packngo.BatchCreateRequest) ([]packngo.Batch, *packngo.Response, error) {
+					return []packngo.Batch{{ID: batchID}, {ID: "other"}}, nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  deviceBatch(),
+			},
+			want: want{
+				mg:  deviceBatch(withConditions(xpv1.Creating())),
+				err: errors.Errorf(errUnexpectedBatches, 2),
+			},
+		},
+		"FailedToCreate": {
+			client: &external{client: &fake.MockClient{
+				MockGetProjectID: func(id string) string { return id },
+				MockCreate: func(string, *packngo.BatchCreateRequest) ([]packngo.Batch, *packngo.Response, error) {
+					return nil, nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  deviceBatch(),
+			},
+			want: want{
+				mg:  deviceBatch(withConditions(xpv1.Creating())),
+				err: errors.Wrap(errorBoom, errCreateDeviceBatch),
+			},
+		},
+		"FailedToRecordExternalName": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errorBoom)},
+				client: &fake.MockClient{
+					MockGetProjectID: func(id string) string { return id },
+					MockCreate: func(string, *packngo.BatchCreateRequest) ([]packngo.Batch, *packngo.Response, error) {
+						return []packngo.Batch{{ID: batchID}}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  deviceBatch(),
+			},
+			want: want{
+				mg: deviceBatch(
+					withExternalName(batchID),
+					withObservation(v1alpha1.DeviceBatchObservation{ID: batchID}),
+					withConditions(xpv1.Creating())),
+				creation: managed.ExternalCreation{ExternalNameAssigned: true},
+			},
+		},
+		"NotDeviceBatch": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotDeviceBatch),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, err := tc.client.Create(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Create(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.creation, c); diff != "" {
+				t.Errorf("tc.client.Create(): -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	// DeviceBatches are immutable, the fake panics if any API is called
+	e := &external{client: &fake.MockClient{}}
+
+	if _, err := e.Update(context.Background(), deviceBatch(withExternalName(batchID))); err != nil {
+		t.Errorf("e.Update(): %v", err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Deleted": {
+			client: &external{client: &fake.MockClient{
+				MockDelete: func(id string, removeDevices bool) (*packngo.Response, error) {
+					if id != batchID {
+						t.Errorf("MockDelete: want %q, got %q", batchID, id)
+					}
+					if removeDevices {
+						t.Errorf("MockDelete: want devices to be kept")
+					}
+					return nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  deviceBatch(withExternalName(batchID)),
+			},
+			want: want{
+				mg: deviceBatch(withExternalName(batchID), withConditions(xpv1.Deleting())),
+			},
+		},
+		"DeletedWithDevices": {
+			client: &external{client: &fake.MockClient{
+				MockDelete: func(_ string, removeDevices bool) (*packngo.Response, error) {
+					if !removeDevices {
+						t.Errorf("MockDelete: want devices to be removed")
+					}
+					return nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  deviceBatch(withExternalName(batchID), withDeleteDevices(true)),
+			},
+			want: want{
+				mg: deviceBatch(withExternalName(batchID), withDeleteDevices(true), withConditions(xpv1.Deleting())),
+			},
+		},
+		"AlreadyDeleted": {
+			client: &external{client: &fake.MockClient{
+				MockDelete: func(string, bool) (*packngo.Response, error) {
+					return nil, packettest.NotFound()
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  deviceBatch(withExternalName(batchID)),
+			},
+			want: want{
+				mg: deviceBatch(withExternalName(batchID), withConditions(xpv1.Deleting())),
+			},
+		},
+		"FailedToDelete": {
+			client: &external{client: &fake.MockClient{
+				MockDelete: func(string, bool) (*packngo.Response, error) {
+					return nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  deviceBatch(withExternalName(batchID)),
+			},
+			want: want{
+				mg:  deviceBatch(withExternalName(batchID), withConditions(xpv1.Deleting())),
+				err: errors.Wrap(errorBoom, errDeleteDeviceBatch),
+			},
+		},
+		"NotDeviceBatch": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotDeviceBatch),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.client.Delete(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Delete(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
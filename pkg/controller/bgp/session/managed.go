@@ -0,0 +1,216 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/bgp/v1alpha1"
+	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	packetclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	bgpclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/bgp"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errManagedUpdateFailed     = "cannot update BGPSession custom resource"
+	errTrackPCUsage            = "cannot track ProviderConfig usage"
+	errGetProviderConfigSecret = "cannot get ProviderConfig Secret"
+	errNewClient               = "cannot create new BGPSession client"
+	errNotBGPSession           = "managed resource is not a BGPSession"
+	errListBGPSessions         = "cannot list BGPSessions"
+	errListBGPNeighbors        = "cannot list BGP neighbors"
+	errCreateBGPSession        = "cannot create BGPSession"
+	errUpdateBGPSession        = "cannot recreate BGPSession"
+	errDeleteBGPSession        = "cannot delete BGPSession"
+)
+
+// SetupBGPSession adds a controller that reconciles BGPSessions
+func SetupBGPSession(mgr ctrl.Manager, l logging.Logger, poll time.Duration) error {
+	name := managed.ControllerName(v1alpha1.BGPSessionGroupKind)
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.BGPSessionGroupVersionKind),
+		managed.WithExternalConnecter(clients.NewManagementPolicyConnecter(&connecter{
+			kube:  mgr.GetClient(),
+			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
+		})),
+		managed.WithInitializers(&managed.DefaultProviderConfig{}),
+		managed.WithConnectionPublishers(),
+		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+		managed.WithPollInterval(poll),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+	)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.BGPSession{}).
+		Complete(clients.NewPollingReconciler(mgr, resource.ManagedKind(v1alpha1.BGPSessionGroupVersionKind), r))
+}
+
+type connecter struct {
+	kube        client.Client
+	usage       resource.Tracker
+	newClientFn func(ctx context.Context, config *clients.Credentials) (bgpclient.ClientWithDefaults, error)
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.BGPSession); !ok {
+		return nil, errors.New(errNotBGPSession)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	newClientFn := bgpclient.NewClient
+	if c.newClientFn != nil {
+		newClientFn = c.newClientFn
+	}
+	cfg, err := clients.GetAuthInfo(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetProviderConfigSecret)
+	}
+	client, err := newClientFn(ctx, cfg)
+
+	return &external{kube: c.kube, client: client}, errors.Wrap(err, errNewClient)
+}
+
+type external struct {
+	kube   client.Client
+	client bgpclient.ClientWithDefaults
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	s, ok := mg.(*v1alpha1.BGPSession)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotBGPSession)
+	}
+
+	// Observe the session of the device, adopting an existing session of the
+	// address family when the session has not been created by this resource
+	sessions, err := bgpclient.ListSessions(e.client, s.Spec.ForProvider.DeviceID)
+	if packetclient.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errListBGPSessions)
+	}
+
+	session := bgpclient.FindSession(sessions, meta.GetExternalName(s), s.Spec.ForProvider.AddressFamily)
+	if session == nil {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	current := s.Spec.ForProvider.DeepCopy()
+	bgpclient.LateInitialize(&s.Spec.ForProvider, session)
+	if !cmp.Equal(current, &s.Spec.ForProvider) || meta.GetExternalName(s) != session.ID {
+		meta.SetExternalName(s, session.ID)
+		if err := e.kube.Update(ctx, s); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errManagedUpdateFailed)
+		}
+	}
+
+	neighbors, _, err := e.client.ListBGPNeighbors(s.Spec.ForProvider.DeviceID, nil)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errListBGPNeighbors)
+	}
+
+	s.Status.AtProvider = bgpclient.GenerateObservation(session, bgpclient.FindNeighbor(neighbors, session.AddressFamily))
+	s.Status.SetConditions(xpv1.Available())
+
+	o := managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: bgpclient.IsUpToDate(s, session),
+	}
+
+	return o, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	s, ok := mg.(*v1alpha1.BGPSession)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotBGPSession)
+	}
+
+	s.Status.SetConditions(xpv1.Creating())
+
+	session, _, err := e.client.Create(s.Spec.ForProvider.DeviceID, bgpclient.CreateFromBGPSession(s))
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateBGPSession)
+	}
+
+	s.Status.AtProvider.ID = session.ID
+	meta.SetExternalName(s, session.ID)
+	if err := e.kube.Update(ctx, s); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errManagedUpdateFailed)
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	s, ok := mg.(*v1alpha1.BGPSession)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotBGPSession)
+	}
+
+	// BGP Sessions can not be modified, the session is replaced by a session
+	// with the desired address family and default route
+	if _, err := e.client.Delete(meta.GetExternalName(s)); resource.Ignore(packetclient.IsNotFound, err) != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateBGPSession)
+	}
+
+	session, _, err := e.client.Create(s.Spec.ForProvider.DeviceID, bgpclient.CreateFromBGPSession(s))
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateBGPSession)
+	}
+
+	s.Status.AtProvider.ID = session.ID
+	meta.SetExternalName(s, session.ID)
+	if err := e.kube.Update(ctx, s); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errManagedUpdateFailed)
+	}
+
+	return managed.ExternalUpdate{}, nil
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	s, ok := mg.(*v1alpha1.BGPSession)
+	if !ok {
+		return errors.New(errNotBGPSession)
+	}
+	s.SetConditions(xpv1.Deleting())
+
+	_, err := e.client.Delete(meta.GetExternalName(s))
+	return errors.Wrap(resource.Ignore(packetclient.IsNotFound, err), errDeleteBGPSession)
+}
@@ -0,0 +1,616 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/bgp/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/bgp/fake"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const (
+	sessionName = "my-cool-session"
+	sessionID   = "session-id"
+	deviceID    = "device-id"
+)
+
+var errorBoom = errors.New("boom")
+
+type strange struct {
+	resource.Managed
+}
+
+type sessionModifier func(*v1alpha1.BGPSession)
+
+func withExternalName(id string) sessionModifier {
+	return func(s *v1alpha1.BGPSession) { meta.SetExternalName(s, id) }
+}
+
+func withAddressFamily(f string) sessionModifier {
+	return func(s *v1alpha1.BGPSession) { s.Spec.ForProvider.AddressFamily = f }
+}
+
+func withDefaultRoute(r bool) sessionModifier {
+	return func(s *v1alpha1.BGPSession) { s.Spec.ForProvider.DefaultRoute = &r }
+}
+
+func withConditions(c ...xpv1.Condition) sessionModifier {
+	return func(s *v1alpha1.BGPSession) { s.Status.SetConditions(c...) }
+}
+
+func withObservation(o v1alpha1.BGPSessionObservation) sessionModifier {
+	return func(s *v1alpha1.BGPSession) { s.Status.AtProvider = o }
+}
+
+func bgpSession(sm ...sessionModifier) *v1alpha1.BGPSession {
+	s := &v1alpha1.BGPSession{
+		ObjectMeta: metav1.ObjectMeta{Name: sessionName},
+		Spec: v1alpha1.BGPSessionSpec{
+			ForProvider: v1alpha1.BGPSessionParameters{
+				DeviceID:      deviceID,
+				AddressFamily: v1alpha1.AddressFamilyIPv4,
+			},
+		},
+	}
+	for _, m := range sm {
+		m(s)
+	}
+	return s
+}
+
+// listing returns a client that lists the supplied sessions of the device
+// and an IPv4 neighbor
+func listing(t *testing.T, sessions ...packngo.BGPSession) *fake.MockClient {
+	return &fake.MockClient{
+		MockListBGPSessions: func(id string, opts *packngo.ListOptions) ([]packngo.BGPSession, *packngo.Response, error) {
+			if id != deviceID {
+				t.Errorf("MockListBGPSessions: want device %q, got %q", deviceID, id)
+			}
+			return sessions, nil, nil
+		},
+		MockListBGPNeighbors: func(id string, opts *packngo.ListOptions) ([]packngo.BGPNeighbor, *packngo.Response, error) {
+			return []packngo.BGPNeighbor{
+				{AddressFamily: 6, PeerAs: 65530},
+				{AddressFamily: 4, PeerAs: 65530, PeerIps: []string{"169.254.255.1"}, CustomerAs: 65000, CustomerIP: "10.0.0.2"},
+			}, nil, nil
+		},
+	}
+}
+
+var _ managed.ExternalClient = &external{}
+
+func TestObserve(t *testing.T) {
+	enabled, disabled := true, false
+	observation := v1alpha1.BGPSessionObservation{
+		ID:     sessionID,
+		Status: "up",
+		Peer: &v1alpha1.BGPPeerObservation{
+			PeerAS:     65530,
+			PeerIPs:    []string{"169.254.255.1"},
+			CustomerAS: 65000,
+			CustomerIP: "10.0.0.2",
+		},
+	}
+
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg          resource.Managed
+		observation managed.ExternalObservation
+		err         error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"UpToDate": {
+			client: &external{
+				client: listing(t,
+					packngo.BGPSession{ID: "other", AddressFamily: v1alpha1.AddressFamilyIPv6},
+					packngo.BGPSession{ID: sessionID, AddressFamily: v1alpha1.AddressFamilyIPv4, Status: "up", DefaultRoute: &enabled}),
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  bgpSession(withExternalName(sessionID), withDefaultRoute(true)),
+			},
+			want: want{
+				mg: bgpSession(
+					withExternalName(sessionID),
+					withDefaultRoute(true),
+					withObservation(observation),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"AdoptedSessionOfAddressFamily": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: listing(t,
+					packngo.BGPSession{ID: sessionID, AddressFamily: v1alpha1.AddressFamilyIPv4, Status: "up", DefaultRoute: &disabled}),
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  bgpSession(),
+			},
+			want: want{
+				mg: bgpSession(
+					withExternalName(sessionID),
+					withDefaultRoute(false),
+					withObservation(observation),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"DefaultRouteChanged": {
+			client: &external{
+				client: listing(t,
+					packngo.BGPSession{ID: sessionID, AddressFamily: v1alpha1.AddressFamilyIPv4, Status: "up", DefaultRoute: &disabled}),
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  bgpSession(withExternalName(sessionID), withDefaultRoute(true)),
+			},
+			want: want{
+				mg: bgpSession(
+					withExternalName(sessionID),
+					withDefaultRoute(true),
+					withObservation(observation),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+				},
+			},
+		},
+		"AddressFamilyChanged": {
+			client: &external{
+				client: listing(t,
+					packngo.BGPSession{ID: sessionID, AddressFamily: v1alpha1.AddressFamilyIPv4, Status: "up", DefaultRoute: &enabled}),
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  bgpSession(withExternalName(sessionID), withAddressFamily(v1alpha1.AddressFamilyIPv6), withDefaultRoute(true)),
+			},
+			want: want{
+				mg: bgpSession(
+					withExternalName(sessionID),
+					withAddressFamily(v1alpha1.AddressFamilyIPv6),
+					withDefaultRoute(true),
+					withObservation(observation),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+				},
+			},
+		},
+		"NoSession": {
+			client: &external{
+				client: listing(t,
+					packngo.BGPSession{ID: "other", AddressFamily: v1alpha1.AddressFamilyIPv6}),
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  bgpSession(withExternalName(sessionID)),
+			},
+			want: want{
+				mg:          bgpSession(withExternalName(sessionID)),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"DeviceNotFound": {
+			client: &external{
+				client: &fake.MockClient{
+					MockListBGPSessions: func(id string, opts *packngo.ListOptions) ([]packngo.BGPSession, *packngo.Response, error) {
+						return nil, nil, packettest.NotFound()
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  bgpSession(withExternalName(sessionID)),
+			},
+			want: want{
+				mg:          bgpSession(withExternalName(sessionID)),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"FailedToListSessions": {
+			client: &external{
+				client: &fake.MockClient{
+					MockListBGPSessions: func(id string, opts *packngo.ListOptions) ([]packngo.BGPSession, *packngo.Response, error) {
+						return nil, nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  bgpSession(withExternalName(sessionID)),
+			},
+			want: want{
+				mg:  bgpSession(withExternalName(sessionID)),
+				err: errors.Wrap(errorBoom, errListBGPSessions),
+			},
+		},
+		"FailedToListNeighbors": {
+			client: &external{
+				client: &fake.MockClient{
+					MockListBGPSessions: func(id string, opts *packngo.ListOptions) ([]packngo.BGPSession, *packngo.Response, error) {
+						return []packngo.BGPSession{{ID: sessionID, AddressFamily: v1alpha1.AddressFamilyIPv4, DefaultRoute: &enabled}}, nil, nil
+					},
+					MockListBGPNeighbors: func(id string, opts *packngo.ListOptions) ([]packngo.BGPNeighbor, *packngo.Response, error) {
+						return nil, nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  bgpSession(withExternalName(sessionID), withDefaultRoute(true)),
+			},
+			want: want{
+				mg:  bgpSession(withExternalName(sessionID), withDefaultRoute(true)),
+				err: errors.Wrap(errorBoom, errListBGPNeighbors),
+			},
+		},
+		"NotBGPSession": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotBGPSession),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := tc.client.Observe(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Observe(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.observation, o); diff != "" {
+				t.Errorf("tc.client.Observe(): -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Created": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockCreate: func(id string, request packngo.CreateBGPSessionRequest) (*packngo.BGPSession, *packngo.Response, error) {
+						if id != deviceID {
+							t.Errorf("MockCreate: want device %q, got %q", deviceID, id)
+						}
+						defaultRoute := true
+						want := packngo.CreateBGPSessionRequest{AddressFamily: v1alpha1.AddressFamilyIPv4, DefaultRoute: &defaultRoute}
+						if diff := cmp.Diff(want, request); diff != "" {
+							t.Errorf("MockCreate: -want, +got:\n%s", diff)
+						}
+						return &packngo.BGPSession{ID: sessionID}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  bgpSession(withDefaultRoute(true)),
+			},
+			want: want{
+				mg: bgpSession(
+					withDefaultRoute(true),
+					withExternalName(sessionID),
+					withObservation(v1alpha1.BGPSessionObservation{ID: sessionID}),
+					withConditions(xpv1.Creating())),
+			},
+		},
+		"FailedToCreate": {
+			client: &external{
+				client: &fake.MockClient{
+					MockCreate: func(id string, request packngo.CreateBGPSessionRequest) (*packngo.BGPSession, *packngo.Response, error) {
+						return nil, nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  bgpSession(),
+			},
+			want: want{
+				mg:  bgpSession(withConditions(xpv1.Creating())),
+				err: errors.Wrap(errorBoom, errCreateBGPSession),
+			},
+		},
+		"NotBGPSession": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotBGPSession),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.client.Create(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Create(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	type want struct {
+		mg    resource.Managed
+		err   error
+		calls []string
+	}
+
+	// calls records the order of the API calls made to replace the session
+	var calls []string
+
+	cases := map[string]struct {
+		client *fake.MockClient
+		want   want
+	}{
+		"Recreated": {
+			client: &fake.MockClient{
+				MockDelete: func(id string) (*packngo.Response, error) {
+					calls = append(calls, "delete "+id)
+					return nil, nil
+				},
+				MockCreate: func(id string, request packngo.CreateBGPSessionRequest) (*packngo.BGPSession, *packngo.Response, error) {
+					calls = append(calls, "create "+request.AddressFamily)
+					return &packngo.BGPSession{ID: "new-session"}, nil, nil
+				},
+			},
+			want: want{
+				mg: bgpSession(
+					withExternalName("new-session"),
+					withAddressFamily(v1alpha1.AddressFamilyIPv6),
+					withObservation(v1alpha1.BGPSessionObservation{ID: "new-session"})),
+				calls: []string{"delete " + sessionID, "create " + v1alpha1.AddressFamilyIPv6},
+			},
+		},
+		"RecreatedAfterDeletion": {
+			client: &fake.MockClient{
+				MockDelete: func(id string) (*packngo.Response, error) {
+					calls = append(calls, "delete "+id)
+					return nil, packettest.NotFound()
+				},
+				MockCreate: func(id string, request packngo.CreateBGPSessionRequest) (*packngo.BGPSession, *packngo.Response, error) {
+					calls = append(calls, "create "+request.AddressFamily)
+					return &packngo.BGPSession{ID: "new-session"}, nil, nil
+				},
+			},
+			want: want{
+				mg: bgpSession(
+					withExternalName("new-session"),
+					withAddressFamily(v1alpha1.AddressFamilyIPv6),
+					withObservation(v1alpha1.BGPSessionObservation{ID: "new-session"})),
+				calls: []string{"delete " + sessionID, "create " + v1alpha1.AddressFamilyIPv6},
+			},
+		},
+		"FailedToDelete": {
+			client: &fake.MockClient{
+				MockDelete: func(id string) (*packngo.Response, error) {
+					calls = append(calls, "delete "+id)
+					return nil, errorBoom
+				},
+			},
+			want: want{
+				mg:    bgpSession(withExternalName(sessionID), withAddressFamily(v1alpha1.AddressFamilyIPv6)),
+				err:   errors.Wrap(errorBoom, errUpdateBGPSession),
+				calls: []string{"delete " + sessionID},
+			},
+		},
+		"FailedToCreate": {
+			client: &fake.MockClient{
+				MockDelete: func(id string) (*packngo.Response, error) {
+					calls = append(calls, "delete "+id)
+					return nil, nil
+				},
+				MockCreate: func(id string, request packngo.CreateBGPSessionRequest) (*packngo.BGPSession, *packngo.Response, error) {
+					calls = append(calls, "create "+request.AddressFamily)
+					return nil, nil, errorBoom
+				},
+			},
+			want: want{
+				mg:    bgpSession(withExternalName(sessionID), withAddressFamily(v1alpha1.AddressFamilyIPv6)),
+				err:   errors.Wrap(errorBoom, errUpdateBGPSession),
+				calls: []string{"delete " + sessionID, "create " + v1alpha1.AddressFamilyIPv6},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			calls = nil
+			e := &external{
+				kube:   &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: tc.client,
+			}
+			mg := bgpSession(withExternalName(sessionID), withAddressFamily(v1alpha1.AddressFamilyIPv6))
+			_, err := e.Update(context.Background(), mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("e.Update(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.calls, calls); diff != "" {
+				t.Errorf("calls: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Deleted": {
+			client: &external{
+				client: &fake.MockClient{
+					MockDelete: func(id string) (*packngo.Response, error) {
+						if id != sessionID {
+							t.Errorf("MockDelete: want session %q, got %q", sessionID, id)
+						}
+						return nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  bgpSession(withExternalName(sessionID)),
+			},
+			want: want{
+				mg: bgpSession(withExternalName(sessionID), withConditions(xpv1.Deleting())),
+			},
+		},
+		"AlreadyDeleted": {
+			client: &external{
+				client: &fake.MockClient{
+					MockDelete: func(id string) (*packngo.Response, error) {
+						return nil, packettest.NotFound()
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  bgpSession(withExternalName(sessionID)),
+			},
+			want: want{
+				mg: bgpSession(withExternalName(sessionID), withConditions(xpv1.Deleting())),
+			},
+		},
+		"FailedToDelete": {
+			client: &external{
+				client: &fake.MockClient{
+					MockDelete: func(id string) (*packngo.Response, error) {
+						return nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  bgpSession(withExternalName(sessionID)),
+			},
+			want: want{
+				mg:  bgpSession(withExternalName(sessionID), withConditions(xpv1.Deleting())),
+				err: errors.Wrap(errorBoom, errDeleteBGPSession),
+			},
+		},
+		"NotBGPSession": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotBGPSession),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.client.Delete(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Delete(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bgpconfig
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/bgpconfig/v1alpha1"
+	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	packetclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	bgpconfigclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/bgpconfig"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errManagedUpdateFailed     = "cannot update BGPConfig custom resource"
+	errTrackPCUsage            = "cannot track ProviderConfig usage"
+	errGetProviderConfigSecret = "cannot get ProviderConfig Secret"
+	errNewClient               = "cannot create new BGPConfig client"
+	errNotBGPConfig            = "managed resource is not a BGPConfig"
+	errGetBGPConfig            = "cannot get BGPConfig"
+	errCreateBGPConfig         = "cannot create BGPConfig"
+)
+
+// SetupBGPConfig adds a controller that reconciles BGPConfigs
+func SetupBGPConfig(mgr ctrl.Manager, l logging.Logger, poll time.Duration) error {
+	name := managed.ControllerName(v1alpha1.BGPConfigGroupKind)
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.BGPConfigGroupVersionKind),
+		managed.WithExternalConnecter(clients.NewManagementPolicyConnecter(&connecter{
+			kube:  mgr.GetClient(),
+			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
+		})),
+		managed.WithInitializers(&managed.DefaultProviderConfig{}),
+		managed.WithConnectionPublishers(),
+		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+		managed.WithPollInterval(poll),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+	)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.BGPConfig{}).
+		Complete(clients.NewPollingReconciler(mgr, resource.ManagedKind(v1alpha1.BGPConfigGroupVersionKind), r))
+}
+
+type connecter struct {
+	kube        client.Client
+	usage       resource.Tracker
+	newClientFn func(ctx context.Context, config *clients.Credentials) (bgpconfigclient.ClientWithDefaults, error)
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.BGPConfig); !ok {
+		return nil, errors.New(errNotBGPConfig)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	newClientFn := bgpconfigclient.NewClient
+	if c.newClientFn != nil {
+		newClientFn = c.newClientFn
+	}
+	cfg, err := clients.GetAuthInfo(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetProviderConfigSecret)
+	}
+	client, err := newClientFn(ctx, cfg)
+
+	return &external{kube: c.kube, client: client}, errors.Wrap(err, errNewClient)
+}
+
+type external struct {
+	kube   client.Client
+	client bgpconfigclient.ClientWithDefaults
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	c, ok := mg.(*v1alpha1.BGPConfig)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotBGPConfig)
+	}
+
+	// Observe the BGP configuration of the project. Projects without BGP
+	// enabled are treated as if the BGPConfig does not exist.
+	config, _, err := e.client.Get(e.client.GetProjectID(c.Spec.ForProvider.ProjectID), nil)
+	if packetclient.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetBGPConfig)
+	}
+	if !bgpconfigclient.IsEnabled(config) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	c.Status.AtProvider = bgpconfigclient.GenerateObservation(config)
+
+	// Global deployments are not usable until they are approved
+	if bgpconfigclient.IsRequested(config) {
+		c.Status.SetConditions(xpv1.Creating())
+	} else {
+		c.Status.SetConditions(xpv1.Available())
+	}
+
+	// NOTE: BGP configuration can not be modified once enabled
+	o := managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: true,
+	}
+
+	return o, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	c, ok := mg.(*v1alpha1.BGPConfig)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotBGPConfig)
+	}
+
+	c.Status.SetConditions(xpv1.Creating())
+
+	// The BGP configuration is identified by its project
+	projectID := e.client.GetProjectID(c.Spec.ForProvider.ProjectID)
+	if _, err := e.client.Create(projectID, bgpconfigclient.CreateFromBGPConfig(c)); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateBGPConfig)
+	}
+
+	meta.SetExternalName(c, projectID)
+	if err := e.kube.Update(ctx, c); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errManagedUpdateFailed)
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	// NOTE: BGPConfig cannot be updated.
+	return managed.ExternalUpdate{}, nil
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	c, ok := mg.(*v1alpha1.BGPConfig)
+	if !ok {
+		return errors.New(errNotBGPConfig)
+	}
+
+	// NOTE: the Equinix Metal API does not support disabling BGP for a
+	// project, deleting a BGPConfig only removes the custom resource and BGP
+	// remains enabled.
+	c.SetConditions(xpv1.Deleting())
+	return nil
+}
@@ -0,0 +1,391 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bgpconfig
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/bgpconfig/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/bgpconfig/fake"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const (
+	configName = "my-cool-config"
+	projectID  = "project-id"
+)
+
+var errorBoom = errors.New("boom")
+
+type strange struct {
+	resource.Managed
+}
+
+type configModifier func(*v1alpha1.BGPConfig)
+
+func withExternalName(id string) configModifier {
+	return func(c *v1alpha1.BGPConfig) { meta.SetExternalName(c, id) }
+}
+
+func withProjectID(id string) configModifier {
+	return func(c *v1alpha1.BGPConfig) { c.Spec.ForProvider.ProjectID = id }
+}
+
+func withMD5(md5 string) configModifier {
+	return func(c *v1alpha1.BGPConfig) { c.Spec.ForProvider.MD5 = &md5 }
+}
+
+func withConditions(co ...xpv1.Condition) configModifier {
+	return func(c *v1alpha1.BGPConfig) { c.Status.SetConditions(co...) }
+}
+
+func withObservation(o v1alpha1.BGPConfigObservation) configModifier {
+	return func(c *v1alpha1.BGPConfig) { c.Status.AtProvider = o }
+}
+
+func bgpConfig(cm ...configModifier) *v1alpha1.BGPConfig {
+	c := &v1alpha1.BGPConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: configName},
+		Spec: v1alpha1.BGPConfigSpec{
+			ForProvider: v1alpha1.BGPConfigParameters{
+				DeploymentType: "local",
+				ASN:            65000,
+			},
+		},
+	}
+	for _, m := range cm {
+		m(c)
+	}
+	return c
+}
+
+// defaultProject returns the supplied project, or the project of the
+// ProviderConfig when none is supplied
+func defaultProject(id string) string {
+	if id != "" {
+		return id
+	}
+	return projectID
+}
+
+var _ managed.ExternalClient = &external{}
+
+func TestObserve(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg          resource.Managed
+		observation managed.ExternalObservation
+		err         error
+	}
+
+	// observed returns a client that observes the BGP configuration of the
+	// default project in the supplied status
+	observed := func(status string) *fake.MockClient {
+		return &fake.MockClient{
+			MockGetProjectID: defaultProject,
+			MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.BGPConfig, *packngo.Response, error) {
+				if id != projectID {
+					t.Errorf("MockGet: want project %q, got %q", projectID, id)
+				}
+				return &packngo.BGPConfig{ID: "config-id", Status: status, MaxPrefix: 10}, nil, nil
+			},
+		}
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Enabled": {
+			client: &external{client: observed(v1alpha1.StatusEnabled)},
+			args: args{
+				ctx: context.Background(),
+				mg:  bgpConfig(withExternalName(projectID)),
+			},
+			want: want{
+				mg: bgpConfig(
+					withExternalName(projectID),
+					withObservation(v1alpha1.BGPConfigObservation{ID: "config-id", Status: v1alpha1.StatusEnabled, MaxPrefix: 10}),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"AwaitingApproval": {
+			client: &external{client: observed(v1alpha1.StatusRequested)},
+			args: args{
+				ctx: context.Background(),
+				mg:  bgpConfig(withExternalName(projectID)),
+			},
+			want: want{
+				mg: bgpConfig(
+					withExternalName(projectID),
+					withObservation(v1alpha1.BGPConfigObservation{ID: "config-id", Status: v1alpha1.StatusRequested, MaxPrefix: 10}),
+					withConditions(xpv1.Creating())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"Disabled": {
+			client: &external{client: observed(v1alpha1.StatusDisabled)},
+			args: args{
+				ctx: context.Background(),
+				mg:  bgpConfig(),
+			},
+			want: want{
+				mg:          bgpConfig(),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"NotConfigured": {
+			client: &external{client: observed("")},
+			args: args{
+				ctx: context.Background(),
+				mg:  bgpConfig(),
+			},
+			want: want{
+				mg:          bgpConfig(),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"ProjectNotFound": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGetProjectID: defaultProject,
+					MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.BGPConfig, *packngo.Response, error) {
+						if id != "other-project" {
+							t.Errorf("MockGet: want project %q, got %q", "other-project", id)
+						}
+						return nil, nil, packettest.NotFound()
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  bgpConfig(withProjectID("other-project")),
+			},
+			want: want{
+				mg:          bgpConfig(withProjectID("other-project")),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"FailedToGetBGPConfig": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGetProjectID: defaultProject,
+					MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.BGPConfig, *packngo.Response, error) {
+						return nil, nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  bgpConfig(),
+			},
+			want: want{
+				mg:  bgpConfig(),
+				err: errors.Wrap(errorBoom, errGetBGPConfig),
+			},
+		},
+		"NotBGPConfig": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotBGPConfig),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := tc.client.Observe(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Observe(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.observation, o); diff != "" {
+				t.Errorf("tc.client.Observe(): -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Created": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockGetProjectID: defaultProject,
+					MockCreate: func(id string, request packngo.CreateBGPConfigRequest) (*packngo.Response, error) {
+						if id != projectID {
+							t.Errorf("MockCreate: want project %q, got %q", projectID, id)
+						}
+						want := packngo.CreateBGPConfigRequest{DeploymentType: "local", Asn: 65000, Md5: "secret"}
+						if diff := cmp.Diff(want, request); diff != "" {
+							t.Errorf("MockCreate: -want, +got:\n%s", diff)
+						}
+						return nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  bgpConfig(withMD5("secret")),
+			},
+			want: want{
+				mg: bgpConfig(
+					withMD5("secret"),
+					withExternalName(projectID),
+					withConditions(xpv1.Creating())),
+			},
+		},
+		"CreatedInProject": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockGetProjectID: defaultProject,
+					MockCreate: func(id string, request packngo.CreateBGPConfigRequest) (*packngo.Response, error) {
+						if id != "other-project" {
+							t.Errorf("MockCreate: want project %q, got %q", "other-project", id)
+						}
+						return nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  bgpConfig(withProjectID("other-project")),
+			},
+			want: want{
+				mg: bgpConfig(
+					withProjectID("other-project"),
+					withExternalName("other-project"),
+					withConditions(xpv1.Creating())),
+			},
+		},
+		"FailedToCreate": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGetProjectID: defaultProject,
+					MockCreate: func(id string, request packngo.CreateBGPConfigRequest) (*packngo.Response, error) {
+						return nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  bgpConfig(),
+			},
+			want: want{
+				mg:  bgpConfig(withConditions(xpv1.Creating())),
+				err: errors.Wrap(errorBoom, errCreateBGPConfig),
+			},
+		},
+		"NotBGPConfig": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotBGPConfig),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.client.Create(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Create(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	// BGP configuration can not be modified, updating it calls no API
+	e := &external{client: &fake.MockClient{}}
+	if _, err := e.Update(context.Background(), bgpConfig(withExternalName(projectID))); err != nil {
+		t.Errorf("e.Update(): %v", err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	// BGP can not be disabled, deleting a BGPConfig calls no API
+	e := &external{client: &fake.MockClient{}}
+	mg := bgpConfig(withExternalName(projectID))
+	if err := e.Delete(context.Background(), mg); err != nil {
+		t.Errorf("e.Delete(): %v", err)
+	}
+	if diff := cmp.Diff(bgpConfig(withExternalName(projectID), withConditions(xpv1.Deleting())), mg, test.EquateConditions()); diff != "" {
+		t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+	}
+
+	if err := e.Delete(context.Background(), &strange{}); err == nil || err.Error() != errNotBGPConfig {
+		t.Errorf("e.Delete(): want error %q, got %v", errNotBGPConfig, err)
+	}
+}
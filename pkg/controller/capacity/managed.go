@@ -0,0 +1,146 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacity
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/capacity/v1alpha1"
+	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	capacityclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/capacity"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errTrackPCUsage            = "cannot track ProviderConfig usage"
+	errGetProviderConfigSecret = "cannot get ProviderConfig Secret"
+	errNewClient               = "cannot create new CapacityCheck client"
+	errNotCapacityCheck        = "managed resource is not a CapacityCheck"
+	errCheckCapacity           = "cannot check capacity"
+)
+
+// SetupCapacityCheck adds a controller that reconciles CapacityChecks
+func SetupCapacityCheck(mgr ctrl.Manager, l logging.Logger, poll time.Duration) error {
+	name := managed.ControllerName(v1alpha1.CapacityCheckGroupKind)
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.CapacityCheckGroupVersionKind),
+		managed.WithExternalConnecter(clients.NewManagementPolicyConnecter(&connecter{
+			kube:  mgr.GetClient(),
+			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
+		})),
+		managed.WithInitializers(&managed.DefaultProviderConfig{}),
+		managed.WithConnectionPublishers(),
+		managed.WithPollInterval(poll),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+	)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.CapacityCheck{}).
+		Complete(clients.NewPollingReconciler(mgr, resource.ManagedKind(v1alpha1.CapacityCheckGroupVersionKind), r))
+}
+
+type connecter struct {
+	kube        client.Client
+	usage       resource.Tracker
+	newClientFn func(ctx context.Context, config *clients.Credentials) (capacityclient.ClientWithDefaults, error)
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.CapacityCheck); !ok {
+		return nil, errors.New(errNotCapacityCheck)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	newClientFn := capacityclient.NewClient
+	if c.newClientFn != nil {
+		newClientFn = c.newClientFn
+	}
+	cfg, err := clients.GetAuthInfo(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetProviderConfigSecret)
+	}
+	client, err := newClientFn(ctx, cfg)
+
+	return &external{kube: c.kube, client: client}, errors.Wrap(err, errNewClient)
+}
+
+type external struct {
+	kube   client.Client
+	client capacityclient.ClientWithDefaults
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	p, ok := mg.(*v1alpha1.CapacityCheck)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotCapacityCheck)
+	}
+
+	// Capacity checks are never deleted, report that the check does not
+	// exist so that the managed resource can be removed
+	if meta.WasDeleted(p) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	// Capacity is checked on every reconcile so that the observed
+	// availability stays current
+	servers, err := capacityclient.CheckCapacity(e.client, p)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errCheckCapacity)
+	}
+
+	p.Status.AtProvider = capacityclient.GenerateObservation(servers)
+	p.Status.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: true,
+	}, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	// NOTE: CapacityChecks are observe-only and are never created.
+	return managed.ExternalCreation{}, nil
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	// NOTE: CapacityChecks are observe-only and are never updated.
+	return managed.ExternalUpdate{}, nil
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	// NOTE: CapacityChecks are observe-only and are never deleted.
+	return nil
+}
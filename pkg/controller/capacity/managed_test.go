@@ -0,0 +1,291 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacity
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/capacity/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/capacity/fake"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var errorBoom = errors.New("boom")
+
+type strange struct {
+	resource.Managed
+}
+
+type checkModifier func(*v1alpha1.CapacityCheck)
+
+func withServers(s ...v1alpha1.ServerCapacity) checkModifier {
+	return func(c *v1alpha1.CapacityCheck) { c.Spec.ForProvider.Servers = s }
+}
+
+func withDeletionTimestamp(t time.Time) checkModifier {
+	return func(c *v1alpha1.CapacityCheck) {
+		deleted := metav1.NewTime(t)
+		c.SetDeletionTimestamp(&deleted)
+	}
+}
+
+func withConditions(c ...xpv1.Condition) checkModifier {
+	return func(cc *v1alpha1.CapacityCheck) { cc.Status.SetConditions(c...) }
+}
+
+func withObservation(o v1alpha1.CapacityCheckObservation) checkModifier {
+	return func(c *v1alpha1.CapacityCheck) { c.Status.AtProvider = o }
+}
+
+func capacityCheck(cm ...checkModifier) *v1alpha1.CapacityCheck {
+	c := &v1alpha1.CapacityCheck{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cool-check"},
+	}
+	for _, m := range cm {
+		m(c)
+	}
+	return c
+}
+
+func inFacility(plan, facility string, quantity int) v1alpha1.ServerCapacity {
+	return v1alpha1.ServerCapacity{Plan: plan, Facility: &facility, Quantity: quantity}
+}
+
+func inMetro(plan, metro string, quantity int) v1alpha1.ServerCapacity {
+	return v1alpha1.ServerCapacity{Plan: plan, Metro: &metro, Quantity: quantity}
+}
+
+// checking returns a check that reports the supplied availability for each
+// requested server
+func checking(t *testing.T, want []packngo.ServerInfo, available ...bool) func(*packngo.CapacityInput) (*packngo.CapacityInput, *packngo.Response, error) {
+	return func(input *packngo.CapacityInput) (*packngo.CapacityInput, *packngo.Response, error) {
+		if diff := cmp.Diff(want, input.Servers); diff != "" {
+			t.Errorf("check: -want, +got:\n%s", diff)
+		}
+		out := &packngo.CapacityInput{}
+		for i := range input.Servers {
+			out.Servers = append(out.Servers, packngo.ServerInfo{Available: available[i]})
+		}
+		return out, nil, nil
+	}
+}
+
+var _ managed.ExternalClient = &external{}
+
+func TestObserve(t *testing.T) {
+	deleted := time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC)
+	servers := []v1alpha1.ServerCapacity{
+		inFacility("c3.small.x86", "da11", 2),
+		inMetro("c3.medium.x86", "sv", 0),
+		inFacility("m3.large.x86", "ny5", 1),
+	}
+
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg          resource.Managed
+		observation managed.ExternalObservation
+		err         error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Available": {
+			client: &external{client: &fake.MockClient{
+				MockCheck: checking(t, []packngo.ServerInfo{
+					{Plan: "c3.small.x86", Facility: "da11", Quantity: 2},
+					{Plan: "m3.large.x86", Facility: "ny5", Quantity: 1},
+				}, true, true),
+				MockCheckMetros: checking(t, []packngo.ServerInfo{
+					{Plan: "c3.medium.x86", Metro: "sv", Quantity: 1},
+				}, true),
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  capacityCheck(withServers(servers...)),
+			},
+			want: want{
+				mg: capacityCheck(
+					withServers(servers...),
+					withObservation(v1alpha1.CapacityCheckObservation{
+						Available: true,
+						Servers: []v1alpha1.ServerAvailability{
+							{Plan: "c3.small.x86", Facility: "da11", Quantity: 2, Available: true},
+							{Plan: "c3.medium.x86", Metro: "sv", Quantity: 1, Available: true},
+							{Plan: "m3.large.x86", Facility: "ny5", Quantity: 1, Available: true},
+						},
+					}),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"Unavailable": {
+			client: &external{client: &fake.MockClient{
+				MockCheckMetros: checking(t, []packngo.ServerInfo{
+					{Plan: "c3.medium.x86", Metro: "sv", Quantity: 1},
+				}, false),
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  capacityCheck(withServers(inMetro("c3.medium.x86", "sv", 0))),
+			},
+			want: want{
+				mg: capacityCheck(
+					withServers(inMetro("c3.medium.x86", "sv", 0)),
+					withObservation(v1alpha1.CapacityCheckObservation{
+						Available: false,
+						Servers: []v1alpha1.ServerAvailability{
+							{Plan: "c3.medium.x86", Metro: "sv", Quantity: 1},
+						},
+					}),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"UnexpectedServers": {
+			client: &external{client: &fake.MockClient{
+				MockCheck: func(*packngo.CapacityInput) (*packngo.CapacityInput, *packngo.Response, error) {
+					return &packngo.CapacityInput{}, nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  capacityCheck(withServers(inFacility("c3.small.x86", "da11", 1))),
+			},
+			want: want{
+				mg:  capacityCheck(withServers(inFacility("c3.small.x86", "da11", 1))),
+				err: errors.Wrap(errors.Errorf("expected %d servers in the capacity check response, got %d", 1, 0), errCheckCapacity),
+			},
+		},
+		"NoServers": {
+			client: &external{client: &fake.MockClient{}},
+			args: args{
+				ctx: context.Background(),
+				mg:  capacityCheck(),
+			},
+			want: want{
+				mg:  capacityCheck(),
+				err: errors.Wrap(errors.New("at least one server must be set"), errCheckCapacity),
+			},
+		},
+		"NoLocation": {
+			client: &external{client: &fake.MockClient{}},
+			args: args{
+				ctx: context.Background(),
+				mg:  capacityCheck(withServers(v1alpha1.ServerCapacity{Plan: "c3.small.x86"})),
+			},
+			want: want{
+				mg:  capacityCheck(withServers(v1alpha1.ServerCapacity{Plan: "c3.small.x86"})),
+				err: errors.Wrap(errors.Errorf("one of facility or metro must be set for plan %q", "c3.small.x86"), errCheckCapacity),
+			},
+		},
+		"FailedToCheck": {
+			client: &external{client: &fake.MockClient{
+				MockCheck: func(*packngo.CapacityInput) (*packngo.CapacityInput, *packngo.Response, error) {
+					return nil, nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  capacityCheck(withServers(inFacility("c3.small.x86", "da11", 1))),
+			},
+			want: want{
+				mg:  capacityCheck(withServers(inFacility("c3.small.x86", "da11", 1))),
+				err: errors.Wrap(errorBoom, errCheckCapacity),
+			},
+		},
+		"Deleted": {
+			client: &external{client: &fake.MockClient{}},
+			args: args{
+				ctx: context.Background(),
+				mg:  capacityCheck(withServers(servers...), withDeletionTimestamp(deleted)),
+			},
+			want: want{
+				mg:          capacityCheck(withServers(servers...), withDeletionTimestamp(deleted)),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"NotCapacityCheck": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotCapacityCheck),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := tc.client.Observe(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Observe(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.observation, o); diff != "" {
+				t.Errorf("tc.client.Observe(): -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestObserveOnly(t *testing.T) {
+	// CapacityChecks are observe-only, the fake panics if any API is called
+	e := &external{client: &fake.MockClient{}}
+	ctx := context.Background()
+	c := capacityCheck(withServers(inMetro("c3.medium.x86", "sv", 1)))
+
+	if _, err := e.Create(ctx, c); err != nil {
+		t.Errorf("e.Create(): %v", err)
+	}
+	if _, err := e.Update(ctx, c); err != nil {
+		t.Errorf("e.Update(): %v", err)
+	}
+	if err := e.Delete(ctx, c); err != nil {
+		t.Errorf("e.Delete(): %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/connection/v1alpha1"
+	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	connectionclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/connection"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errManagedUpdateFailed     = "cannot update Connection custom resource"
+	errTrackPCUsage            = "cannot track ProviderConfig usage"
+	errGetProviderConfigSecret = "cannot get ProviderConfig Secret"
+	errNewClient               = "cannot create new Connection client"
+	errNotConnection           = "managed resource is not a Connection"
+	errGetConnection           = "cannot get Connection"
+	errCreateConnection        = "cannot create Connection"
+	errDeleteConnection        = "cannot delete Connection"
+	errInvalidLocation         = "cannot use Connection facility and metro"
+)
+
+// SetupConnection adds a controller that reconciles Connections
+func SetupConnection(mgr ctrl.Manager, l logging.Logger, poll time.Duration) error {
+	name := managed.ControllerName(v1alpha1.ConnectionGroupKind)
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.ConnectionGroupVersionKind),
+		managed.WithExternalConnecter(clients.NewManagementPolicyConnecter(&connecter{
+			kube:  mgr.GetClient(),
+			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
+		})),
+		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+		managed.WithConnectionPublishers(),
+		managed.WithPollInterval(poll),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+	)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.Connection{}).
+		Complete(clients.NewPollingReconciler(mgr, resource.ManagedKind(v1alpha1.ConnectionGroupVersionKind), r))
+}
+
+type connecter struct {
+	kube        client.Client
+	usage       resource.Tracker
+	newClientFn func(ctx context.Context, config *clients.Credentials) (connectionclient.ClientWithDefaults, error)
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.Connection); !ok {
+		return nil, errors.New(errNotConnection)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	newClientFn := connectionclient.NewClient
+	if c.newClientFn != nil {
+		newClientFn = c.newClientFn
+	}
+	cfg, err := clients.GetAuthInfo(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetProviderConfigSecret)
+	}
+	client, err := newClientFn(ctx, cfg)
+
+	return &external{kube: c.kube, client: client, defaultTags: cfg.DefaultTags}, errors.Wrap(err, errNewClient)
+}
+
+type external struct {
+	kube   client.Client
+	client connectionclient.ClientWithDefaults
+
+	// defaultTags of the ProviderConfig are merged into the tags of created
+	// resources
+	defaultTags map[string]string
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	c, ok := mg.(*v1alpha1.Connection)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotConnection)
+	}
+
+	conn, _, err := e.client.Get(meta.GetExternalName(c), nil)
+	if clients.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetConnection)
+	}
+
+	current := c.Spec.ForProvider.DeepCopy()
+	connectionclient.LateInitialize(&c.Spec.ForProvider, conn)
+	if !cmp.Equal(current, &c.Spec.ForProvider) {
+		if err := e.kube.Update(ctx, c); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errManagedUpdateFailed)
+		}
+	}
+
+	c.Status.AtProvider = connectionclient.GenerateObservation(conn)
+
+	switch c.Status.AtProvider.Status {
+	case v1alpha1.ConnectionStatusActive:
+		c.Status.SetConditions(xpv1.Available())
+	case v1alpha1.ConnectionStatusDeprovisioning:
+		c.Status.SetConditions(xpv1.Deleting())
+	default:
+		c.Status.SetConditions(xpv1.Creating())
+	}
+
+	// Connections can not be changed once they are created
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: true,
+	}, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	c, ok := mg.(*v1alpha1.Connection)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotConnection)
+	}
+
+	c.Status.SetConditions(xpv1.Creating())
+
+	if err := connectionclient.ValidateLocation(c); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errInvalidLocation)
+	}
+
+	create := connectionclient.CreateFromConnection(c)
+	create.Tags = clients.MergeTags(create.Tags, e.defaultTags)
+	conn, _, err := e.client.ProjectCreate(e.client.GetProjectID(c.Spec.ForProvider.ProjectID), create)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateConnection)
+	}
+
+	c.Status.AtProvider.ID = conn.ID
+	meta.SetExternalName(c, conn.ID)
+	if err := e.kube.Update(ctx, c); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errManagedUpdateFailed)
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	// NOTE: Connections can not be changed once they are created.
+	return managed.ExternalUpdate{}, nil
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	c, ok := mg.(*v1alpha1.Connection)
+	if !ok {
+		return errors.New(errNotConnection)
+	}
+	c.SetConditions(xpv1.Deleting())
+
+	// A Connection that is already deprovisioning is observed until it is
+	// gone rather than deleted again
+	if c.Status.AtProvider.Status == v1alpha1.ConnectionStatusDeprovisioning {
+		return nil
+	}
+
+	_, err := e.client.Delete(meta.GetExternalName(c))
+	return errors.Wrap(resource.Ignore(clients.IsNotFound, err), errDeleteConnection)
+}
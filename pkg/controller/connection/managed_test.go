@@ -0,0 +1,511 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/connection/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/connection/fake"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const (
+	connectionName = "my-cool-connection"
+	connectionID   = "connection-id"
+	projectID      = "project-id"
+)
+
+var errorBoom = errors.New("boom")
+
+type strange struct {
+	resource.Managed
+}
+
+type connectionModifier func(*v1alpha1.Connection)
+
+func withExternalName(id string) connectionModifier {
+	return func(c *v1alpha1.Connection) { meta.SetExternalName(c, id) }
+}
+
+func withSpeed(s int) connectionModifier {
+	return func(c *v1alpha1.Connection) { c.Spec.ForProvider.Speed = &s }
+}
+
+func withFacility(f string) connectionModifier {
+	return func(c *v1alpha1.Connection) { c.Spec.ForProvider.Facility = f }
+}
+
+func withConditions(cs ...xpv1.Condition) connectionModifier {
+	return func(c *v1alpha1.Connection) { c.Status.SetConditions(cs...) }
+}
+
+func withObservation(o v1alpha1.ConnectionObservation) connectionModifier {
+	return func(c *v1alpha1.Connection) { c.Status.AtProvider = o }
+}
+
+func connection(cm ...connectionModifier) *v1alpha1.Connection {
+	c := &v1alpha1.Connection{
+		ObjectMeta: metav1.ObjectMeta{Name: connectionName},
+		Spec: v1alpha1.ConnectionSpec{
+			ForProvider: v1alpha1.ConnectionParameters{
+				ProjectID:  projectID,
+				Name:       connectionName,
+				Type:       v1alpha1.ConnectionTypeShared,
+				Redundancy: v1alpha1.ConnectionRedundancyPrimary,
+				Metro:      "da",
+				Tags:       []string{"cool"},
+			},
+		},
+	}
+	for _, m := range cm {
+		m(c)
+	}
+	return c
+}
+
+// observed returns a connection in the supplied status
+func observed(status string) *packngo.Connection {
+	return &packngo.Connection{
+		ID:           connectionID,
+		Name:         connectionName,
+		Status:       status,
+		Speed:        50000000,
+		Token:        "service-token",
+		Metro:        &packngo.Metro{Code: "da"},
+		Organization: &packngo.Organization{ID: "organization-id"},
+		Ports: []packngo.ConnectionPort{
+			{ID: "port-id", Name: "primary", Role: packngo.ConnectionPortPrimary, Status: "active", LinkStatus: "up", Speed: 50000000},
+		},
+	}
+}
+
+func observation(status string) v1alpha1.ConnectionObservation {
+	return v1alpha1.ConnectionObservation{
+		ID:             connectionID,
+		Status:         status,
+		Metro:          "da",
+		OrganizationID: "organization-id",
+		Speed:          50000000,
+		ServiceToken:   "service-token",
+		Ports: []v1alpha1.ConnectionPortObservation{
+			{ID: "port-id", Name: "primary", Role: string(packngo.ConnectionPortPrimary), Status: "active", LinkStatus: "up", Speed: 50000000},
+		},
+	}
+}
+
+var _ managed.ExternalClient = &external{}
+
+func TestObserve(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg          resource.Managed
+		observation managed.ExternalObservation
+		err         error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Active": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.Connection, *packngo.Response, error) {
+						if id != connectionID {
+							t.Errorf("MockGet: want %q, got %q", connectionID, id)
+						}
+						return observed(v1alpha1.ConnectionStatusActive), nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  connection(withExternalName(connectionID)),
+			},
+			want: want{
+				mg: connection(
+					withExternalName(connectionID),
+					withSpeed(50000000),
+					withObservation(observation(v1alpha1.ConnectionStatusActive)),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"Provisioning": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(string, *packngo.GetOptions) (*packngo.Connection, *packngo.Response, error) {
+					return observed("requested"), nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  connection(withExternalName(connectionID), withSpeed(50000000)),
+			},
+			want: want{
+				mg: connection(
+					withExternalName(connectionID),
+					withSpeed(50000000),
+					withObservation(observation("requested")),
+					withConditions(xpv1.Creating())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"Deprovisioning": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(string, *packngo.GetOptions) (*packngo.Connection, *packngo.Response, error) {
+					return observed(v1alpha1.ConnectionStatusDeprovisioning), nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  connection(withExternalName(connectionID), withSpeed(50000000)),
+			},
+			want: want{
+				mg: connection(
+					withExternalName(connectionID),
+					withSpeed(50000000),
+					withObservation(observation(v1alpha1.ConnectionStatusDeprovisioning)),
+					withConditions(xpv1.Deleting())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"NotFound": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(string, *packngo.GetOptions) (*packngo.Connection, *packngo.Response, error) {
+					return nil, nil, packettest.NotFound()
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  connection(withExternalName(connectionID)),
+			},
+			want: want{
+				mg:          connection(withExternalName(connectionID)),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"FailedToGet": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(string, *packngo.GetOptions) (*packngo.Connection, *packngo.Response, error) {
+					return nil, nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  connection(withExternalName(connectionID)),
+			},
+			want: want{
+				mg:  connection(withExternalName(connectionID)),
+				err: errors.Wrap(errorBoom, errGetConnection),
+			},
+		},
+		"FailedToLateInitialize": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errorBoom)},
+				client: &fake.MockClient{
+					MockGet: func(string, *packngo.GetOptions) (*packngo.Connection, *packngo.Response, error) {
+						return observed(v1alpha1.ConnectionStatusActive), nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  connection(withExternalName(connectionID)),
+			},
+			want: want{
+				mg:  connection(withExternalName(connectionID), withSpeed(50000000)),
+				err: errors.Wrap(errorBoom, errManagedUpdateFailed),
+			},
+		},
+		"NotConnection": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotConnection),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := tc.client.Observe(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Observe(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.observation, o); diff != "" {
+				t.Errorf("tc.client.Observe(): -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Created": {
+			client: &external{
+				kube:        &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				defaultTags: map[string]string{"team": "infra"},
+				client: &fake.MockClient{
+					MockGetProjectID: func(id string) string { return id },
+					MockProjectCreate: func(pID string, createRequest *packngo.ConnectionCreateRequest) (*packngo.Connection, *packngo.Response, error) {
+						if pID != projectID {
+							t.Errorf("MockProjectCreate: want project %q, got %q", projectID, pID)
+						}
+						want := &packngo.ConnectionCreateRequest{
+							Name:       connectionName,
+							Type:       packngo.ConnectionType(v1alpha1.ConnectionTypeShared),
+							Redundancy: packngo.ConnectionRedundancy(v1alpha1.ConnectionRedundancyPrimary),
+							Metro:      "da",
+							Speed:      50000000,
+							Tags:       []string{"cool", "team=infra"},
+						}
+						if diff := cmp.Diff(want, createRequest); diff != "" {
+							t.Errorf("MockProjectCreate: -want, +got:\n%s", diff)
+						}
+						return &packngo.Connection{ID: connectionID}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  connection(withSpeed(50000000)),
+			},
+			want: want{
+				mg: connection(
+					withSpeed(50000000),
+					withExternalName(connectionID),
+					withObservation(v1alpha1.ConnectionObservation{ID: connectionID}),
+					withConditions(xpv1.Creating())),
+			},
+		},
+		"FacilityAndMetro": {
+			client: &external{client: &fake.MockClient{}},
+			args: args{
+				ctx: context.Background(),
+				mg:  connection(withFacility("da11")),
+			},
+			want: want{
+				mg:  connection(withFacility("da11"), withConditions(xpv1.Creating())),
+				err: errors.Wrap(errors.Errorf("facility %q and metro %q can not both be set", "da11", "da"), errInvalidLocation),
+			},
+		},
+		"FailedToCreate": {
+			client: &external{client: &fake.MockClient{
+				MockGetProjectID: func(id string) string { return id },
+				MockProjectCreate: func(string, *packngo.ConnectionCreateRequest) (*packngo.Connection, *packngo.Response, error) {
+					return nil, nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  connection(),
+			},
+			want: want{
+				mg:  connection(withConditions(xpv1.Creating())),
+				err: errors.Wrap(errorBoom, errCreateConnection),
+			},
+		},
+		"NotConnection": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotConnection),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.client.Create(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Create(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	// Connections are immutable, the fake panics if any API is called
+	e := &external{client: &fake.MockClient{}}
+
+	if _, err := e.Update(context.Background(), connection(withExternalName(connectionID))); err != nil {
+		t.Errorf("e.Update(): %v", err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Deleted": {
+			client: &external{client: &fake.MockClient{
+				MockDelete: func(id string) (*packngo.Response, error) {
+					if id != connectionID {
+						t.Errorf("MockDelete: want %q, got %q", connectionID, id)
+					}
+					return nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  connection(withExternalName(connectionID)),
+			},
+			want: want{
+				mg: connection(withExternalName(connectionID), withConditions(xpv1.Deleting())),
+			},
+		},
+		"Deprovisioning": {
+			client: &external{client: &fake.MockClient{}},
+			args: args{
+				ctx: context.Background(),
+				mg: connection(
+					withExternalName(connectionID),
+					withObservation(v1alpha1.ConnectionObservation{ID: connectionID, Status: v1alpha1.ConnectionStatusDeprovisioning})),
+			},
+			want: want{
+				mg: connection(
+					withExternalName(connectionID),
+					withObservation(v1alpha1.ConnectionObservation{ID: connectionID, Status: v1alpha1.ConnectionStatusDeprovisioning}),
+					withConditions(xpv1.Deleting())),
+			},
+		},
+		"AlreadyDeleted": {
+			client: &external{client: &fake.MockClient{
+				MockDelete: func(string) (*packngo.Response, error) {
+					return nil, packettest.NotFound()
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  connection(withExternalName(connectionID)),
+			},
+			want: want{
+				mg: connection(withExternalName(connectionID), withConditions(xpv1.Deleting())),
+			},
+		},
+		"FailedToDelete": {
+			client: &external{client: &fake.MockClient{
+				MockDelete: func(string) (*packngo.Response, error) {
+					return nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  connection(withExternalName(connectionID)),
+			},
+			want: want{
+				mg:  connection(withExternalName(connectionID), withConditions(xpv1.Deleting())),
+				err: errors.Wrap(errorBoom, errDeleteConnection),
+			},
+		},
+		"NotConnection": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotConnection),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.client.Delete(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Delete(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assignment
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/ip/v1alpha1"
+	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	packetclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	assignmentclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/ip/assignment"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errManagedUpdateFailed     = "cannot update IPAssignment custom resource"
+	errTrackPCUsage            = "cannot track ProviderConfig usage"
+	errGetProviderConfigSecret = "cannot get ProviderConfig Secret"
+	errGenObservation          = "cannot generate observation"
+	errNewClient               = "cannot create new IPAssignment client"
+	errNotIPAssignment         = "managed resource is not an IPAssignment"
+	errGetIPAssignment         = "cannot get IPAssignment"
+	errSelectAddress           = "cannot select addresses to assign"
+	errCreateIPAssignment      = "cannot create IPAssignment"
+	errDeleteIPAssignment      = "cannot delete IPAssignment"
+)
+
+// SetupIPAssignment adds a controller that reconciles IPAssignments
+func SetupIPAssignment(mgr ctrl.Manager, l logging.Logger, poll time.Duration) error {
+	name := managed.ControllerName(v1alpha1.IPAssignmentGroupKind)
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.IPAssignmentGroupVersionKind),
+		managed.WithExternalConnecter(clients.NewManagementPolicyConnecter(&connecter{
+			kube:  mgr.GetClient(),
+			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
+		})),
+		managed.WithInitializers(&managed.DefaultProviderConfig{}),
+		managed.WithConnectionPublishers(),
+		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+		managed.WithPollInterval(poll),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+	)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.IPAssignment{}).
+		Complete(clients.NewPollingReconciler(mgr, resource.ManagedKind(v1alpha1.IPAssignmentGroupVersionKind), r))
+}
+
+type connecter struct {
+	kube        client.Client
+	usage       resource.Tracker
+	newClientFn func(ctx context.Context, config *clients.Credentials) (assignmentclient.ClientWithDefaults, error)
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.IPAssignment); !ok {
+		return nil, errors.New(errNotIPAssignment)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	newClientFn := assignmentclient.NewClient
+	if c.newClientFn != nil {
+		newClientFn = c.newClientFn
+	}
+	cfg, err := clients.GetAuthInfo(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetProviderConfigSecret)
+	}
+	client, err := newClientFn(ctx, cfg)
+
+	return &external{kube: c.kube, client: client}, errors.Wrap(err, errNewClient)
+}
+
+type external struct {
+	kube   client.Client
+	client assignmentclient.ClientWithDefaults
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	a, ok := mg.(*v1alpha1.IPAssignment)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotIPAssignment)
+	}
+
+	if meta.GetExternalName(a) == "" {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	ip, _, err := e.client.Get(meta.GetExternalName(a), nil)
+	if packetclient.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetIPAssignment)
+	}
+
+	// Addresses that are no longer assigned to the Device, such as those of
+	// a deleted Device, must be assigned again
+	if !assignmentclient.IsAssignedTo(ip, a.Spec.ForProvider.DeviceID) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	current := a.Spec.ForProvider.DeepCopy()
+	assignmentclient.LateInitialize(&a.Spec.ForProvider, ip)
+	if !cmp.Equal(current, &a.Spec.ForProvider) {
+		if err := e.kube.Update(ctx, a); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errManagedUpdateFailed)
+		}
+	}
+
+	a.Status.AtProvider, err = assignmentclient.GenerateObservation(ip)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGenObservation)
+	}
+	a.Status.SetConditions(xpv1.Available())
+
+	// IPAssignments can not be modified
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: true,
+	}, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	a, ok := mg.(*v1alpha1.IPAssignment)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotIPAssignment)
+	}
+
+	a.Status.SetConditions(xpv1.Creating())
+
+	// An exhausted reservation returns an error here, which is retried
+	// until addresses are released or the reservation is resized
+	address, err := assignmentclient.SelectAddress(e.client, a)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errSelectAddress)
+	}
+
+	ip, _, err := e.client.Assign(a.Spec.ForProvider.DeviceID, &packngo.AddressStruct{Address: address})
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateIPAssignment)
+	}
+
+	a.Status.AtProvider.ID = ip.ID
+	meta.SetExternalName(a, ip.ID)
+	if err := e.kube.Update(ctx, a); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errManagedUpdateFailed)
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	// NOTE: IPAssignments are immutable and are never updated.
+	return managed.ExternalUpdate{}, nil
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	a, ok := mg.(*v1alpha1.IPAssignment)
+	if !ok {
+		return errors.New(errNotIPAssignment)
+	}
+	a.SetConditions(xpv1.Deleting())
+
+	_, err := e.client.Unassign(meta.GetExternalName(a))
+	return errors.Wrap(resource.Ignore(packetclient.IsNotFound, err), errDeleteIPAssignment)
+}
@@ -0,0 +1,522 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assignment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/ip/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/ip/assignment/fake"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const (
+	assignmentID  = "assignment-id"
+	deviceID      = "device-id"
+	reservationID = "reservation-id"
+	address       = "198.51.100.2/32"
+)
+
+var errorBoom = errors.New("boom")
+
+type strange struct {
+	resource.Managed
+}
+
+type assignmentModifier func(*v1alpha1.IPAssignment)
+
+func withExternalName(id string) assignmentModifier {
+	return func(a *v1alpha1.IPAssignment) { meta.SetExternalName(a, id) }
+}
+
+func withAddress(addr string) assignmentModifier {
+	return func(a *v1alpha1.IPAssignment) { a.Spec.ForProvider.Address = addr }
+}
+
+func withIPReservationID(id string) assignmentModifier {
+	return func(a *v1alpha1.IPAssignment) { a.Spec.ForProvider.IPReservationID = id }
+}
+
+func withConditions(c ...xpv1.Condition) assignmentModifier {
+	return func(a *v1alpha1.IPAssignment) { a.Status.SetConditions(c...) }
+}
+
+func withObservation(o v1alpha1.IPAssignmentObservation) assignmentModifier {
+	return func(a *v1alpha1.IPAssignment) { a.Status.AtProvider = o }
+}
+
+func ipAssignment(am ...assignmentModifier) *v1alpha1.IPAssignment {
+	a := &v1alpha1.IPAssignment{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cool-assignment"},
+		Spec: v1alpha1.IPAssignmentSpec{
+			ForProvider: v1alpha1.IPAssignmentParameters{DeviceID: deviceID},
+		},
+	}
+	for _, m := range am {
+		m(a)
+	}
+	return a
+}
+
+// observed returns an assignment of a single address to the supplied device
+func observed(device string) *packngo.IPAddressAssignment {
+	return &packngo.IPAddressAssignment{
+		IpAddressCommon: packngo.IpAddressCommon{
+			ID:            assignmentID,
+			Href:          "/metal/v1/ips/" + assignmentID,
+			Address:       "198.51.100.2",
+			Network:       "198.51.100.2",
+			Gateway:       "198.51.100.1",
+			Netmask:       "255.255.255.255",
+			CIDR:          32,
+			Public:        true,
+			AddressFamily: 4,
+			Created:       "2021-01-02T03:04:05Z",
+		},
+		AssignedTo: packngo.Href{Href: "/metal/v1/devices/" + device},
+	}
+}
+
+var _ managed.ExternalClient = &external{}
+
+func TestObserve(t *testing.T) {
+	created := metav1.NewTime(time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC))
+	observation := v1alpha1.IPAssignmentObservation{
+		ID:            assignmentID,
+		Href:          "/metal/v1/ips/" + assignmentID,
+		Address:       "198.51.100.2",
+		Network:       "198.51.100.2",
+		Gateway:       "198.51.100.1",
+		Netmask:       "255.255.255.255",
+		CIDR:          32,
+		Public:        true,
+		AddressFamily: 4,
+		CreatedAt:     &created,
+	}
+
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg          resource.Managed
+		observation managed.ExternalObservation
+		err         error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Assigned": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.IPAddressAssignment, *packngo.Response, error) {
+					if id != assignmentID {
+						t.Errorf("MockGet: want %q, got %q", assignmentID, id)
+					}
+					return observed(deviceID), nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  ipAssignment(withExternalName(assignmentID), withAddress(address)),
+			},
+			want: want{
+				mg: ipAssignment(
+					withExternalName(assignmentID),
+					withAddress(address),
+					withObservation(observation),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"LateInitialized": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockGet: func(string, *packngo.GetOptions) (*packngo.IPAddressAssignment, *packngo.Response, error) {
+						return observed(deviceID), nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  ipAssignment(withExternalName(assignmentID), withIPReservationID(reservationID)),
+			},
+			want: want{
+				mg: ipAssignment(
+					withExternalName(assignmentID),
+					withIPReservationID(reservationID),
+					withAddress(address),
+					withObservation(observation),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"AssignedToOtherDevice": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(string, *packngo.GetOptions) (*packngo.IPAddressAssignment, *packngo.Response, error) {
+					return observed("other-device"), nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  ipAssignment(withExternalName(assignmentID), withAddress(address)),
+			},
+			want: want{
+				mg:          ipAssignment(withExternalName(assignmentID), withAddress(address)),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"NoExternalName": {
+			client: &external{client: &fake.MockClient{}},
+			args: args{
+				ctx: context.Background(),
+				mg:  ipAssignment(withAddress(address)),
+			},
+			want: want{
+				mg:          ipAssignment(withAddress(address)),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"NotFound": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(string, *packngo.GetOptions) (*packngo.IPAddressAssignment, *packngo.Response, error) {
+					return nil, nil, packettest.NotFound()
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  ipAssignment(withExternalName(assignmentID)),
+			},
+			want: want{
+				mg:          ipAssignment(withExternalName(assignmentID)),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"FailedToGet": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(string, *packngo.GetOptions) (*packngo.IPAddressAssignment, *packngo.Response, error) {
+					return nil, nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  ipAssignment(withExternalName(assignmentID)),
+			},
+			want: want{
+				mg:  ipAssignment(withExternalName(assignmentID)),
+				err: errors.Wrap(errorBoom, errGetIPAssignment),
+			},
+		},
+		"NotIPAssignment": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotIPAssignment),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := tc.client.Observe(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Observe(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.observation, o); diff != "" {
+				t.Errorf("tc.client.Observe(): -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	assign := func(t *testing.T, want string) func(string, *packngo.AddressStruct) (*packngo.IPAddressAssignment, *packngo.Response, error) {
+		return func(dID string, assignRequest *packngo.AddressStruct) (*packngo.IPAddressAssignment, *packngo.Response, error) {
+			if dID != deviceID {
+				t.Errorf("MockAssign: want device %q, got %q", deviceID, dID)
+			}
+			if assignRequest.Address != want {
+				t.Errorf("MockAssign: want address %q, got %q", want, assignRequest.Address)
+			}
+			return &packngo.IPAddressAssignment{IpAddressCommon: packngo.IpAddressCommon{ID: assignmentID}}, nil, nil
+		}
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"AssignedAddress": {
+			client: &external{
+				kube:   &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{MockAssign: assign(t, address)},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  ipAssignment(withAddress(address)),
+			},
+			want: want{
+				mg: ipAssignment(
+					withAddress(address),
+					withExternalName(assignmentID),
+					withObservation(v1alpha1.IPAssignmentObservation{ID: assignmentID}),
+					withConditions(xpv1.Creating())),
+			},
+		},
+		"AssignedFromReservation": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockGetReservation: func(string, *packngo.GetOptions) (*packngo.IPAddressReservation, *packngo.Response, error) {
+						return &packngo.IPAddressReservation{IpAddressCommon: packngo.IpAddressCommon{
+							ID:            reservationID,
+							Network:       "198.51.100.0",
+							AddressFamily: 4,
+						}}, nil, nil
+					},
+					MockAvailableAddresses: func(id string, r *packngo.AvailableRequest) ([]string, *packngo.Response, error) {
+						if r.CIDR != 32 {
+							t.Errorf("MockAvailableAddresses: want CIDR %d, got %d", 32, r.CIDR)
+						}
+						return []string{address, "198.51.100.3/32"}, nil, nil
+					},
+					MockAssign: assign(t, address),
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  ipAssignment(withIPReservationID(reservationID)),
+			},
+			want: want{
+				mg: ipAssignment(
+					withIPReservationID(reservationID),
+					withExternalName(assignmentID),
+					withObservation(v1alpha1.IPAssignmentObservation{ID: assignmentID}),
+					withConditions(xpv1.Creating())),
+			},
+		},
+		"ReservationExhausted": {
+			client: &external{client: &fake.MockClient{
+				MockGetReservation: func(string, *packngo.GetOptions) (*packngo.IPAddressReservation, *packngo.Response, error) {
+					return &packngo.IPAddressReservation{IpAddressCommon: packngo.IpAddressCommon{Network: "198.51.100.0"}}, nil, nil
+				},
+				MockAvailableAddresses: func(string, *packngo.AvailableRequest) ([]string, *packngo.Response, error) {
+					return nil, nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  ipAssignment(withIPReservationID(reservationID)),
+			},
+			want: want{
+				mg:  ipAssignment(withIPReservationID(reservationID), withConditions(xpv1.Creating())),
+				err: errors.Wrap(errors.Errorf("IP reservation %s has no available /%d block of addresses", reservationID, 32), errSelectAddress),
+			},
+		},
+		"NoAddress": {
+			client: &external{client: &fake.MockClient{}},
+			args: args{
+				ctx: context.Background(),
+				mg:  ipAssignment(),
+			},
+			want: want{
+				mg:  ipAssignment(withConditions(xpv1.Creating())),
+				err: errors.Wrap(errors.New("one of address or ipReservationId must be set"), errSelectAddress),
+			},
+		},
+		"FailedToAssign": {
+			client: &external{client: &fake.MockClient{
+				MockAssign: func(string, *packngo.AddressStruct) (*packngo.IPAddressAssignment, *packngo.Response, error) {
+					return nil, nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  ipAssignment(withAddress(address)),
+			},
+			want: want{
+				mg:  ipAssignment(withAddress(address), withConditions(xpv1.Creating())),
+				err: errors.Wrap(errorBoom, errCreateIPAssignment),
+			},
+		},
+		"NotIPAssignment": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotIPAssignment),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.client.Create(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Create(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	// IPAssignments are immutable, the fake panics if any API is called
+	e := &external{client: &fake.MockClient{}}
+
+	if _, err := e.Update(context.Background(), ipAssignment(withExternalName(assignmentID))); err != nil {
+		t.Errorf("e.Update(): %v", err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Deleted": {
+			client: &external{client: &fake.MockClient{
+				MockUnassign: func(id string) (*packngo.Response, error) {
+					if id != assignmentID {
+						t.Errorf("MockUnassign: want %q, got %q", assignmentID, id)
+					}
+					return nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  ipAssignment(withExternalName(assignmentID)),
+			},
+			want: want{
+				mg: ipAssignment(withExternalName(assignmentID), withConditions(xpv1.Deleting())),
+			},
+		},
+		"AlreadyDeleted": {
+			client: &external{client: &fake.MockClient{
+				MockUnassign: func(string) (*packngo.Response, error) {
+					return nil, packettest.NotFound()
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  ipAssignment(withExternalName(assignmentID)),
+			},
+			want: want{
+				mg: ipAssignment(withExternalName(assignmentID), withConditions(xpv1.Deleting())),
+			},
+		},
+		"FailedToDelete": {
+			client: &external{client: &fake.MockClient{
+				MockUnassign: func(string) (*packngo.Response, error) {
+					return nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  ipAssignment(withExternalName(assignmentID)),
+			},
+			want: want{
+				mg:  ipAssignment(withExternalName(assignmentID), withConditions(xpv1.Deleting())),
+				err: errors.Wrap(errorBoom, errDeleteIPAssignment),
+			},
+		},
+		"NotIPAssignment": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotIPAssignment),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.client.Delete(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Delete(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservation
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/ip/v1alpha1"
+	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	packetclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	ipclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/ip"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errManagedUpdateFailed     = "cannot update Reservation custom resource"
+	errTrackPCUsage            = "cannot track ProviderConfig usage"
+	errGetProviderConfigSecret = "cannot get ProviderConfig Secret"
+	errGenObservation          = "cannot generate observation"
+	errNewClient               = "cannot create new Reservation client"
+	errNotReservation          = "managed resource is not a Reservation"
+	errGetReservation          = "cannot get Reservation"
+	errInvalidReservation      = "invalid Reservation parameters"
+	errCreateReservation       = "cannot create Reservation"
+	errDeleteReservation       = "cannot delete Reservation"
+)
+
+// SetupReservation adds a controller that reconciles Reservations
+func SetupReservation(mgr ctrl.Manager, l logging.Logger, poll time.Duration) error {
+	name := managed.ControllerName(v1alpha1.ReservationGroupKind)
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.ReservationGroupVersionKind),
+		managed.WithExternalConnecter(clients.NewManagementPolicyConnecter(&connecter{
+			kube:  mgr.GetClient(),
+			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
+		})),
+		managed.WithConnectionPublishers(),
+		managed.WithPollInterval(poll),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+	)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.Reservation{}).
+		Complete(clients.NewPollingReconciler(mgr, resource.ManagedKind(v1alpha1.ReservationGroupVersionKind), r))
+}
+
+type connecter struct {
+	kube        client.Client
+	usage       resource.Tracker
+	newClientFn func(ctx context.Context, config *clients.Credentials) (ipclient.ClientWithDefaults, error)
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.Reservation); !ok {
+		return nil, errors.New(errNotReservation)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	newClientFn := ipclient.NewClient
+	if c.newClientFn != nil {
+		newClientFn = c.newClientFn
+	}
+	cfg, err := clients.GetAuthInfo(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetProviderConfigSecret)
+	}
+	client, err := newClientFn(ctx, cfg)
+
+	return &external{kube: c.kube, client: client, defaultTags: cfg.DefaultTags}, errors.Wrap(err, errNewClient)
+}
+
+type external struct {
+	kube   client.Client
+	client ipclient.ClientWithDefaults
+
+	// defaultTags of the ProviderConfig are merged into the tags of created
+	// resources
+	defaultTags map[string]string
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	r, ok := mg.(*v1alpha1.Reservation)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotReservation)
+	}
+
+	// Observe IP reservation
+	ip, _, err := e.client.Get(meta.GetExternalName(r), nil)
+	if packetclient.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetReservation)
+	}
+
+	current := r.Spec.ForProvider.DeepCopy()
+	ipclient.LateInitialize(&r.Spec.ForProvider, ip)
+	if !cmp.Equal(current, &r.Spec.ForProvider) {
+		if err := e.kube.Update(ctx, r); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errManagedUpdateFailed)
+		}
+	}
+
+	r.Status.AtProvider, err = ipclient.GenerateObservation(ip)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGenObservation)
+	}
+
+	// Requests that need approval are not usable until they are approved
+	if ipclient.IsPending(ip) {
+		r.Status.SetConditions(xpv1.Creating())
+	} else {
+		r.Status.SetConditions(xpv1.Available())
+	}
+
+	// NOTE: Reservations cannot be updated, all parameters are immutable
+	o := managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: true,
+	}
+
+	return o, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	r, ok := mg.(*v1alpha1.Reservation)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotReservation)
+	}
+
+	r.Status.SetConditions(xpv1.Creating())
+
+	if err := ipclient.ValidateReservation(r); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errInvalidReservation)
+	}
+
+	projectID := e.client.GetProjectID(emptyIfNil(r.Spec.ForProvider.ProjectID))
+	create := ipclient.CreateFromReservation(r)
+	create.Tags = clients.MergeTags(create.Tags, e.defaultTags)
+	ip, _, err := e.client.Request(projectID, create)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateReservation)
+	}
+
+	r.Status.AtProvider.ID = ip.ID
+	meta.SetExternalName(r, ip.ID)
+	if err := e.kube.Update(ctx, r); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errManagedUpdateFailed)
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	// NOTE: Reservation cannot be updated.
+	return managed.ExternalUpdate{}, nil
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	r, ok := mg.(*v1alpha1.Reservation)
+	if !ok {
+		return errors.New(errNotReservation)
+	}
+	r.SetConditions(xpv1.Deleting())
+
+	_, err := e.client.Remove(meta.GetExternalName(r))
+	return errors.Wrap(resource.Ignore(packetclient.IsNotFound, err), errDeleteReservation)
+}
+
+func emptyIfNil(in *string) string {
+	if in == nil {
+		return ""
+	}
+	return *in
+}
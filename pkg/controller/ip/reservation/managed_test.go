@@ -0,0 +1,524 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/ip/v1alpha1"
+	ipclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/ip"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/ip/fake"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const (
+	reservationName = "my-cool-reservation"
+	reservationID   = "reservation-id"
+)
+
+var errorBoom = errors.New("boom")
+
+type strange struct {
+	resource.Managed
+}
+
+type reservationModifier func(*v1alpha1.Reservation)
+
+func withExternalName(id string) reservationModifier {
+	return func(r *v1alpha1.Reservation) { meta.SetExternalName(r, id) }
+}
+
+func withType(t string) reservationModifier {
+	return func(r *v1alpha1.Reservation) { r.Spec.ForProvider.Type = t }
+}
+
+func withMetro(m string) reservationModifier {
+	return func(r *v1alpha1.Reservation) { r.Spec.ForProvider.Metro = &m }
+}
+
+func withProjectID(id string) reservationModifier {
+	return func(r *v1alpha1.Reservation) { r.Spec.ForProvider.ProjectID = &id }
+}
+
+func withTags(tags ...string) reservationModifier {
+	return func(r *v1alpha1.Reservation) { r.Spec.ForProvider.Tags = tags }
+}
+
+func withConditions(c ...xpv1.Condition) reservationModifier {
+	return func(r *v1alpha1.Reservation) { r.Status.SetConditions(c...) }
+}
+
+func withObservation(o v1alpha1.ReservationObservation) reservationModifier {
+	return func(r *v1alpha1.Reservation) { r.Status.AtProvider = o }
+}
+
+func reservation(rm ...reservationModifier) *v1alpha1.Reservation {
+	r := &v1alpha1.Reservation{
+		ObjectMeta: metav1.ObjectMeta{Name: reservationName},
+		Spec: v1alpha1.ReservationSpec{
+			ForProvider: v1alpha1.ReservationParameters{
+				Type:     v1alpha1.ReservationTypePublicIPv4,
+				Quantity: 4,
+			},
+		},
+	}
+	for _, m := range rm {
+		m(r)
+	}
+	return r
+}
+
+// observed returns a reservation of four public addresses in the da metro
+// in the supplied state
+func observed(state string) *ipclient.Reservation {
+	return &ipclient.Reservation{
+		IPAddressReservation: packngo.IPAddressReservation{
+			IpAddressCommon: packngo.IpAddressCommon{
+				ID:      reservationID,
+				Address: "198.51.100.0",
+				CIDR:    30,
+				Public:  true,
+				Project: packngo.Href{Href: "/metal/v1/projects/project"},
+				Metro:   &packngo.Metro{Code: "da"},
+				Tags:    []string{"cool"},
+			},
+			Facility: &packngo.Facility{Code: "da11"},
+		},
+		State: state,
+	}
+}
+
+var _ managed.ExternalClient = &external{}
+
+func TestObserve(t *testing.T) {
+	observation := v1alpha1.ReservationObservation{
+		ID:       reservationID,
+		State:    "created",
+		Facility: "da11",
+		Metro:    "da",
+		Address:  "198.51.100.0",
+		CIDR:     30,
+		Public:   true,
+	}
+
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg          resource.Managed
+		observation managed.ExternalObservation
+		err         error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Available": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockGet: func(id string, getOpt *packngo.GetOptions) (*ipclient.Reservation, *packngo.Response, error) {
+						if id != reservationID {
+							t.Errorf("MockGet: want reservation %q, got %q", reservationID, id)
+						}
+						return observed("created"), nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  reservation(withExternalName(reservationID), withMetro("da")),
+			},
+			want: want{
+				mg: reservation(
+					withExternalName(reservationID),
+					withMetro("da"),
+					withProjectID("project"),
+					withTags("cool"),
+					withObservation(observation),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"AwaitingApproval": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGet: func(id string, getOpt *packngo.GetOptions) (*ipclient.Reservation, *packngo.Response, error) {
+						return observed(v1alpha1.ReservationStatePending), nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  reservation(withExternalName(reservationID), withMetro("da"), withProjectID("project"), withTags("cool")),
+			},
+			want: want{
+				mg: reservation(
+					withExternalName(reservationID),
+					withMetro("da"),
+					withProjectID("project"),
+					withTags("cool"),
+					withObservation(func() v1alpha1.ReservationObservation {
+						o := observation
+						o.State = v1alpha1.ReservationStatePending
+						return o
+					}()),
+					withConditions(xpv1.Creating())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"NotFound": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGet: func(id string, getOpt *packngo.GetOptions) (*ipclient.Reservation, *packngo.Response, error) {
+						return nil, nil, packettest.NotFound()
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  reservation(withExternalName(reservationID)),
+			},
+			want: want{
+				mg:          reservation(withExternalName(reservationID)),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"FailedToGetReservation": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGet: func(id string, getOpt *packngo.GetOptions) (*ipclient.Reservation, *packngo.Response, error) {
+						return nil, nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  reservation(withExternalName(reservationID)),
+			},
+			want: want{
+				mg:  reservation(withExternalName(reservationID)),
+				err: errors.Wrap(errorBoom, errGetReservation),
+			},
+		},
+		"NotReservation": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotReservation),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := tc.client.Observe(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Observe(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.observation, o); diff != "" {
+				t.Errorf("tc.client.Observe(): -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Created": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockGetProjectID: func(id string) string {
+						if id != "" {
+							return id
+						}
+						return "default-project"
+					},
+					MockRequest: func(projectID string, req *packngo.IPReservationRequest) (*packngo.IPAddressReservation, *packngo.Response, error) {
+						if projectID != "project" {
+							t.Errorf("MockRequest: want project %q, got %q", "project", projectID)
+						}
+						metro := "da"
+						want := &packngo.IPReservationRequest{
+							Type:     v1alpha1.ReservationTypePublicIPv4,
+							Quantity: 4,
+							Metro:    &metro,
+							Tags:     []string{"cool", "team=metal"},
+						}
+						if diff := cmp.Diff(want, req); diff != "" {
+							t.Errorf("MockRequest: -want, +got:\n%s", diff)
+						}
+						return &packngo.IPAddressReservation{IpAddressCommon: packngo.IpAddressCommon{ID: reservationID}}, nil, nil
+					},
+				},
+				defaultTags: map[string]string{"team": "metal"},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  reservation(withMetro("da"), withProjectID("project"), withTags("cool")),
+			},
+			want: want{
+				mg: reservation(
+					withMetro("da"),
+					withProjectID("project"),
+					withTags("cool"),
+					withExternalName(reservationID),
+					withObservation(v1alpha1.ReservationObservation{ID: reservationID}),
+					withConditions(xpv1.Creating())),
+			},
+		},
+		"CreatedInDefaultProject": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockGetProjectID: func(id string) string {
+						if id != "" {
+							return id
+						}
+						return "default-project"
+					},
+					MockRequest: func(projectID string, req *packngo.IPReservationRequest) (*packngo.IPAddressReservation, *packngo.Response, error) {
+						if projectID != "default-project" {
+							t.Errorf("MockRequest: want project %q, got %q", "default-project", projectID)
+						}
+						return &packngo.IPAddressReservation{IpAddressCommon: packngo.IpAddressCommon{ID: reservationID}}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  reservation(withType(v1alpha1.ReservationTypeGlobalIPv4)),
+			},
+			want: want{
+				mg: reservation(
+					withType(v1alpha1.ReservationTypeGlobalIPv4),
+					withExternalName(reservationID),
+					withObservation(v1alpha1.ReservationObservation{ID: reservationID}),
+					withConditions(xpv1.Creating())),
+			},
+		},
+		"GlobalInMetro": {
+			client: &external{
+				client: &fake.MockClient{
+					MockRequest: func(projectID string, req *packngo.IPReservationRequest) (*packngo.IPAddressReservation, *packngo.Response, error) {
+						t.Errorf("MockRequest: called for an invalid reservation")
+						return nil, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  reservation(withType(v1alpha1.ReservationTypeGlobalIPv4), withMetro("da")),
+			},
+			want: want{
+				mg:  reservation(withType(v1alpha1.ReservationTypeGlobalIPv4), withMetro("da"), withConditions(xpv1.Creating())),
+				err: errors.Wrap(errors.New("facility and metro must not be set for global_ipv4 reservations"), errInvalidReservation),
+			},
+		},
+		"FailedToRequest": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGetProjectID: func(string) string { return "project" },
+					MockRequest: func(projectID string, req *packngo.IPReservationRequest) (*packngo.IPAddressReservation, *packngo.Response, error) {
+						return nil, nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  reservation(withMetro("da")),
+			},
+			want: want{
+				mg:  reservation(withMetro("da"), withConditions(xpv1.Creating())),
+				err: errors.Wrap(errorBoom, errCreateReservation),
+			},
+		},
+		"NotReservation": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotReservation),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.client.Create(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Create(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	// Reservations are immutable, updating them calls no API
+	e := &external{client: &fake.MockClient{}}
+	if _, err := e.Update(context.Background(), reservation(withExternalName(reservationID))); err != nil {
+		t.Errorf("e.Update(): %v", err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Deleted": {
+			client: &external{
+				client: &fake.MockClient{
+					MockRemove: func(id string) (*packngo.Response, error) {
+						if id != reservationID {
+							t.Errorf("MockRemove: want reservation %q, got %q", reservationID, id)
+						}
+						return nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  reservation(withExternalName(reservationID)),
+			},
+			want: want{
+				mg: reservation(withExternalName(reservationID), withConditions(xpv1.Deleting())),
+			},
+		},
+		"AlreadyDeleted": {
+			client: &external{
+				client: &fake.MockClient{
+					MockRemove: func(id string) (*packngo.Response, error) {
+						return nil, packettest.NotFound()
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  reservation(withExternalName(reservationID)),
+			},
+			want: want{
+				mg: reservation(withExternalName(reservationID), withConditions(xpv1.Deleting())),
+			},
+		},
+		"FailedToDelete": {
+			client: &external{
+				client: &fake.MockClient{
+					MockRemove: func(id string) (*packngo.Response, error) {
+						return nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  reservation(withExternalName(reservationID)),
+			},
+			want: want{
+				mg:  reservation(withExternalName(reservationID), withConditions(xpv1.Deleting())),
+				err: errors.Wrap(errorBoom, errDeleteReservation),
+			},
+		},
+		"NotReservation": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotReservation),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.client.Delete(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Delete(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
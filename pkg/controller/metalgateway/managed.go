@@ -0,0 +1,200 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metalgateway
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/metalgateway/v1alpha1"
+	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	gatewayclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/metalgateway"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errManagedUpdateFailed     = "cannot update MetalGateway custom resource"
+	errTrackPCUsage            = "cannot track ProviderConfig usage"
+	errGetProviderConfigSecret = "cannot get ProviderConfig Secret"
+	errGenObservation          = "cannot generate observation"
+	errNewClient               = "cannot create new MetalGateway client"
+	errNotMetalGateway         = "managed resource is not a MetalGateway"
+	errGetMetalGateway         = "cannot get MetalGateway"
+	errCreateMetalGateway      = "cannot create MetalGateway"
+	errDeleteMetalGateway      = "cannot delete MetalGateway"
+	errInvalidParameters       = "invalid MetalGateway parameters"
+	errDependenciesNotReady    = "MetalGateway dependencies are not ready"
+)
+
+// SetupMetalGateway adds a controller that reconciles MetalGateways
+func SetupMetalGateway(mgr ctrl.Manager, l logging.Logger, poll time.Duration) error {
+	name := managed.ControllerName(v1alpha1.MetalGatewayGroupKind)
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.MetalGatewayGroupVersionKind),
+		managed.WithExternalConnecter(clients.NewManagementPolicyConnecter(&connecter{
+			kube:  mgr.GetClient(),
+			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
+		})),
+		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+		managed.WithConnectionPublishers(),
+		managed.WithPollInterval(poll),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+	)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.MetalGateway{}).
+		Complete(clients.NewPollingReconciler(mgr, resource.ManagedKind(v1alpha1.MetalGatewayGroupVersionKind), r))
+}
+
+type connecter struct {
+	kube        client.Client
+	usage       resource.Tracker
+	newClientFn func(ctx context.Context, config *clients.Credentials) (gatewayclient.ClientWithDefaults, error)
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.MetalGateway); !ok {
+		return nil, errors.New(errNotMetalGateway)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	newClientFn := gatewayclient.NewClient
+	if c.newClientFn != nil {
+		newClientFn = c.newClientFn
+	}
+	cfg, err := clients.GetAuthInfo(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetProviderConfigSecret)
+	}
+	client, err := newClientFn(ctx, cfg)
+
+	return &external{kube: c.kube, client: client}, errors.Wrap(err, errNewClient)
+}
+
+type external struct {
+	kube   client.Client
+	client gatewayclient.ClientWithDefaults
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	g, ok := mg.(*v1alpha1.MetalGateway)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotMetalGateway)
+	}
+
+	gw, _, err := e.client.Get(meta.GetExternalName(g))
+	if clients.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetMetalGateway)
+	}
+
+	g.Status.AtProvider, err = gatewayclient.GenerateObservation(gw)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGenObservation)
+	}
+
+	switch g.Status.AtProvider.State {
+	case v1alpha1.StateReady, v1alpha1.StateActive:
+		g.Status.SetConditions(xpv1.Available())
+	case v1alpha1.StateDeleting:
+		g.Status.SetConditions(xpv1.Deleting())
+	default:
+		g.Status.SetConditions(xpv1.Creating())
+	}
+
+	// MetalGateways can not be updated, every parameter is immutable
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: true,
+	}, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	g, ok := mg.(*v1alpha1.MetalGateway)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotMetalGateway)
+	}
+
+	g.Status.SetConditions(xpv1.Creating())
+
+	if err := gatewayclient.ValidateParameters(g); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errInvalidParameters)
+	}
+
+	// The VirtualNetwork and IP reservation may still be provisioning. The
+	// returned error is retried on the next reconcile.
+	if err := gatewayclient.ValidateDependencies(e.client, g); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errDependenciesNotReady)
+	}
+
+	gw, _, err := e.client.Create(e.client.GetProjectID(g.Spec.ForProvider.ProjectID), gatewayclient.CreateFromMetalGateway(g))
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateMetalGateway)
+	}
+
+	g.Status.AtProvider.ID = gw.ID
+	meta.SetExternalName(g, gw.ID)
+	if err := e.kube.Update(ctx, g); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errManagedUpdateFailed)
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	if _, ok := mg.(*v1alpha1.MetalGateway); !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotMetalGateway)
+	}
+
+	// MetalGateways can not be updated
+	return managed.ExternalUpdate{}, nil
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	g, ok := mg.(*v1alpha1.MetalGateway)
+	if !ok {
+		return errors.New(errNotMetalGateway)
+	}
+	g.SetConditions(xpv1.Deleting())
+
+	if g.Status.AtProvider.State == v1alpha1.StateDeleting {
+		return nil
+	}
+
+	_, err := e.client.Delete(meta.GetExternalName(g))
+	return errors.Wrap(resource.Ignore(clients.IsNotFound, err), errDeleteMetalGateway)
+}
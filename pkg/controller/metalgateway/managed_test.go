@@ -0,0 +1,558 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metalgateway
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/metalgateway/v1alpha1"
+	gatewayclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/metalgateway"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/metalgateway/fake"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const (
+	gatewayID     = "gateway-id"
+	projectID     = "project-id"
+	vlanID        = "vlan-id"
+	reservationID = "reservation-id"
+)
+
+var errorBoom = errors.New("boom")
+
+type strange struct {
+	resource.Managed
+}
+
+type gatewayModifier func(*v1alpha1.MetalGateway)
+
+func withExternalName(id string) gatewayModifier {
+	return func(g *v1alpha1.MetalGateway) { meta.SetExternalName(g, id) }
+}
+
+func withIPReservationID(id string) gatewayModifier {
+	return func(g *v1alpha1.MetalGateway) { g.Spec.ForProvider.IPReservationID = id }
+}
+
+func withPrivateIPv4SubnetSize(s int) gatewayModifier {
+	return func(g *v1alpha1.MetalGateway) { g.Spec.ForProvider.PrivateIPv4SubnetSize = &s }
+}
+
+func withConditions(c ...xpv1.Condition) gatewayModifier {
+	return func(g *v1alpha1.MetalGateway) { g.Status.SetConditions(c...) }
+}
+
+func withObservation(o v1alpha1.MetalGatewayObservation) gatewayModifier {
+	return func(g *v1alpha1.MetalGateway) { g.Status.AtProvider = o }
+}
+
+func metalGateway(gm ...gatewayModifier) *v1alpha1.MetalGateway {
+	g := &v1alpha1.MetalGateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cool-gateway"},
+		Spec: v1alpha1.MetalGatewaySpec{
+			ForProvider: v1alpha1.MetalGatewayParameters{
+				ProjectID:        projectID,
+				VirtualNetworkID: vlanID,
+			},
+		},
+	}
+	for _, m := range gm {
+		m(g)
+	}
+	return g
+}
+
+// observed returns a gateway in the supplied state that routes to a private
+// subnet
+func observed(state string) *gatewayclient.MetalGateway {
+	return &gatewayclient.MetalGateway{
+		ID:    gatewayID,
+		Href:  "/metal/v1/metal-gateways/" + gatewayID,
+		State: state,
+		IPReservation: &packngo.IPAddressReservation{IpAddressCommon: packngo.IpAddressCommon{
+			ID:      reservationID,
+			Network: "10.0.0.0",
+			Gateway: "10.0.0.1",
+			CIDR:    29,
+		}},
+		CreatedAt: "2021-01-02T03:04:05Z",
+	}
+}
+
+func reservation(network string) *packngo.IPAddressReservation {
+	return &packngo.IPAddressReservation{IpAddressCommon: packngo.IpAddressCommon{ID: reservationID, Network: network}}
+}
+
+var _ managed.ExternalClient = &external{}
+
+func TestObserve(t *testing.T) {
+	created := metav1.NewTime(time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC))
+	observation := func(state string) v1alpha1.MetalGatewayObservation {
+		return v1alpha1.MetalGatewayObservation{
+			ID:              gatewayID,
+			Href:            "/metal/v1/metal-gateways/" + gatewayID,
+			State:           state,
+			IPReservationID: reservationID,
+			Address:         "10.0.0.1",
+			Subnet:          "10.0.0.0/29",
+			CreatedAt:       &created,
+		}
+	}
+
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg          resource.Managed
+		observation managed.ExternalObservation
+		err         error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Ready": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(id string) (*gatewayclient.MetalGateway, *packngo.Response, error) {
+					if id != gatewayID {
+						t.Errorf("MockGet: want %q, got %q", gatewayID, id)
+					}
+					return observed(v1alpha1.StateReady), nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  metalGateway(withExternalName(gatewayID), withPrivateIPv4SubnetSize(8)),
+			},
+			want: want{
+				mg: metalGateway(
+					withExternalName(gatewayID),
+					withPrivateIPv4SubnetSize(8),
+					withObservation(observation(v1alpha1.StateReady)),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"Provisioning": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(string) (*gatewayclient.MetalGateway, *packngo.Response, error) {
+					return observed("provisioning"), nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  metalGateway(withExternalName(gatewayID), withPrivateIPv4SubnetSize(8)),
+			},
+			want: want{
+				mg: metalGateway(
+					withExternalName(gatewayID),
+					withPrivateIPv4SubnetSize(8),
+					withObservation(observation("provisioning")),
+					withConditions(xpv1.Creating())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"Deleting": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(string) (*gatewayclient.MetalGateway, *packngo.Response, error) {
+					return observed(v1alpha1.StateDeleting), nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  metalGateway(withExternalName(gatewayID), withPrivateIPv4SubnetSize(8)),
+			},
+			want: want{
+				mg: metalGateway(
+					withExternalName(gatewayID),
+					withPrivateIPv4SubnetSize(8),
+					withObservation(observation(v1alpha1.StateDeleting)),
+					withConditions(xpv1.Deleting())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"NotFound": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(string) (*gatewayclient.MetalGateway, *packngo.Response, error) {
+					return nil, nil, packettest.NotFound()
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  metalGateway(withExternalName(gatewayID)),
+			},
+			want: want{
+				mg:          metalGateway(withExternalName(gatewayID)),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"FailedToGet": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(string) (*gatewayclient.MetalGateway, *packngo.Response, error) {
+					return nil, nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  metalGateway(withExternalName(gatewayID)),
+			},
+			want: want{
+				mg:  metalGateway(withExternalName(gatewayID)),
+				err: errors.Wrap(errorBoom, errGetMetalGateway),
+			},
+		},
+		"NotMetalGateway": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotMetalGateway),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := tc.client.Observe(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Observe(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.observation, o); diff != "" {
+				t.Errorf("tc.client.Observe(): -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	getVirtualNetwork := func(id string) (*packngo.VirtualNetwork, *packngo.Response, error) {
+		return &packngo.VirtualNetwork{ID: id}, nil, nil
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"CreatedWithPrivateSubnet": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockGetProjectID:      func(id string) string { return id },
+					MockGetVirtualNetwork: getVirtualNetwork,
+					MockCreate: func(pID string, createRequest *gatewayclient.CreateRequest) (*gatewayclient.MetalGateway, *packngo.Response, error) {
+						if pID != projectID {
+							t.Errorf("MockCreate: want project %q, got %q", projectID, pID)
+						}
+						want := &gatewayclient.CreateRequest{VirtualNetworkID: vlanID, PrivateIPv4SubnetSize: 8}
+						if diff := cmp.Diff(want, createRequest); diff != "" {
+							t.Errorf("MockCreate: -want, +got:\n%s", diff)
+						}
+						return &gatewayclient.MetalGateway{ID: gatewayID}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  metalGateway(withPrivateIPv4SubnetSize(8)),
+			},
+			want: want{
+				mg: metalGateway(
+					withPrivateIPv4SubnetSize(8),
+					withExternalName(gatewayID),
+					withObservation(v1alpha1.MetalGatewayObservation{ID: gatewayID}),
+					withConditions(xpv1.Creating())),
+			},
+		},
+		"CreatedWithIPReservation": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockGetProjectID:      func(id string) string { return id },
+					MockGetVirtualNetwork: getVirtualNetwork,
+					MockGetIPReservation: func(string) (*packngo.IPAddressReservation, *packngo.Response, error) {
+						return reservation("10.0.0.0"), nil, nil
+					},
+					MockCreate: func(_ string, createRequest *gatewayclient.CreateRequest) (*gatewayclient.MetalGateway, *packngo.Response, error) {
+						want := &gatewayclient.CreateRequest{VirtualNetworkID: vlanID, IPReservationID: reservationID}
+						if diff := cmp.Diff(want, createRequest); diff != "" {
+							t.Errorf("MockCreate: -want, +got:\n%s", diff)
+						}
+						return &gatewayclient.MetalGateway{ID: gatewayID}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  metalGateway(withIPReservationID(reservationID)),
+			},
+			want: want{
+				mg: metalGateway(
+					withIPReservationID(reservationID),
+					withExternalName(gatewayID),
+					withObservation(v1alpha1.MetalGatewayObservation{ID: gatewayID}),
+					withConditions(xpv1.Creating())),
+			},
+		},
+		"InvalidParameters": {
+			client: &external{client: &fake.MockClient{}},
+			args: args{
+				ctx: context.Background(),
+				mg:  metalGateway(withIPReservationID(reservationID), withPrivateIPv4SubnetSize(8)),
+			},
+			want: want{
+				mg: metalGateway(
+					withIPReservationID(reservationID),
+					withPrivateIPv4SubnetSize(8),
+					withConditions(xpv1.Creating())),
+				err: errors.Wrap(errors.New("only one of ipReservationId or privateIpv4SubnetSize may be set"), errInvalidParameters),
+			},
+		},
+		"VirtualNetworkNotReady": {
+			client: &external{client: &fake.MockClient{
+				MockGetVirtualNetwork: func(string) (*packngo.VirtualNetwork, *packngo.Response, error) {
+					return nil, nil, packettest.NotFound()
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  metalGateway(withPrivateIPv4SubnetSize(8)),
+			},
+			want: want{
+				mg:  metalGateway(withPrivateIPv4SubnetSize(8), withConditions(xpv1.Creating())),
+				err: errors.Wrap(errors.Errorf("VirtualNetwork %s is not ready", vlanID), errDependenciesNotReady),
+			},
+		},
+		"IPReservationNotReady": {
+			client: &external{client: &fake.MockClient{
+				MockGetVirtualNetwork: getVirtualNetwork,
+				MockGetIPReservation: func(string) (*packngo.IPAddressReservation, *packngo.Response, error) {
+					return reservation(""), nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  metalGateway(withIPReservationID(reservationID)),
+			},
+			want: want{
+				mg:  metalGateway(withIPReservationID(reservationID), withConditions(xpv1.Creating())),
+				err: errors.Wrap(errors.Errorf("IP reservation %s is not ready", reservationID), errDependenciesNotReady),
+			},
+		},
+		"FailedToCreate": {
+			client: &external{client: &fake.MockClient{
+				MockGetProjectID:      func(id string) string { return id },
+				MockGetVirtualNetwork: getVirtualNetwork,
+				MockCreate: func(string, *gatewayclient.CreateRequest) (*gatewayclient.MetalGateway, *packngo.Response, error) {
+					return nil, nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  metalGateway(withPrivateIPv4SubnetSize(8)),
+			},
+			want: want{
+				mg:  metalGateway(withPrivateIPv4SubnetSize(8), withConditions(xpv1.Creating())),
+				err: errors.Wrap(errorBoom, errCreateMetalGateway),
+			},
+		},
+		"NotMetalGateway": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotMetalGateway),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.client.Create(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Create(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	// MetalGateways are immutable, the fake panics if any API is called
+	e := &external{client: &fake.MockClient{}}
+
+	if _, err := e.Update(context.Background(), metalGateway(withExternalName(gatewayID))); err != nil {
+		t.Errorf("e.Update(): %v", err)
+	}
+	if _, err := e.Update(context.Background(), &strange{}); err == nil {
+		t.Errorf("e.Update(): want error for a resource that is not a MetalGateway")
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Deleted": {
+			client: &external{client: &fake.MockClient{
+				MockDelete: func(id string) (*packngo.Response, error) {
+					if id != gatewayID {
+						t.Errorf("MockDelete: want %q, got %q", gatewayID, id)
+					}
+					return nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  metalGateway(withExternalName(gatewayID)),
+			},
+			want: want{
+				mg: metalGateway(withExternalName(gatewayID), withConditions(xpv1.Deleting())),
+			},
+		},
+		"AlreadyDeleting": {
+			client: &external{client: &fake.MockClient{}},
+			args: args{
+				ctx: context.Background(),
+				mg: metalGateway(
+					withExternalName(gatewayID),
+					withObservation(v1alpha1.MetalGatewayObservation{ID: gatewayID, State: v1alpha1.StateDeleting})),
+			},
+			want: want{
+				mg: metalGateway(
+					withExternalName(gatewayID),
+					withObservation(v1alpha1.MetalGatewayObservation{ID: gatewayID, State: v1alpha1.StateDeleting}),
+					withConditions(xpv1.Deleting())),
+			},
+		},
+		"AlreadyDeleted": {
+			client: &external{client: &fake.MockClient{
+				MockDelete: func(string) (*packngo.Response, error) {
+					return nil, packettest.NotFound()
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  metalGateway(withExternalName(gatewayID)),
+			},
+			want: want{
+				mg: metalGateway(withExternalName(gatewayID), withConditions(xpv1.Deleting())),
+			},
+		},
+		"FailedToDelete": {
+			client: &external{client: &fake.MockClient{
+				MockDelete: func(string) (*packngo.Response, error) {
+					return nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  metalGateway(withExternalName(gatewayID)),
+			},
+			want: want{
+				mg:  metalGateway(withExternalName(gatewayID), withConditions(xpv1.Deleting())),
+				err: errors.Wrap(errorBoom, errDeleteMetalGateway),
+			},
+		},
+		"NotMetalGateway": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotMetalGateway),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.client.Delete(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Delete(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
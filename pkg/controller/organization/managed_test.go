@@ -0,0 +1,251 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package organization
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/organization/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/organization/fake"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const (
+	organizationName = "My Cool Organization"
+	organizationID   = "organization-id"
+)
+
+var errorBoom = errors.New("boom")
+
+type strange struct {
+	resource.Managed
+}
+
+type organizationModifier func(*v1alpha1.Organization)
+
+func withDeletionTimestamp(t time.Time) organizationModifier {
+	return func(o *v1alpha1.Organization) {
+		deleted := metav1.NewTime(t)
+		o.SetDeletionTimestamp(&deleted)
+	}
+}
+
+func withConditions(c ...xpv1.Condition) organizationModifier {
+	return func(o *v1alpha1.Organization) { o.Status.SetConditions(c...) }
+}
+
+func withObservation(ob v1alpha1.OrganizationObservation) organizationModifier {
+	return func(o *v1alpha1.Organization) { o.Status.AtProvider = ob }
+}
+
+func organization(om ...organizationModifier) *v1alpha1.Organization {
+	o := &v1alpha1.Organization{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cool-organization"},
+		Spec: v1alpha1.OrganizationSpec{
+			ForProvider: v1alpha1.OrganizationParameters{Name: organizationName},
+		},
+	}
+	for _, m := range om {
+		m(o)
+	}
+	return o
+}
+
+// listing returns a client that lists the supplied Organizations
+func listing(orgs ...packngo.Organization) *fake.MockClient {
+	return &fake.MockClient{
+		MockList: func(listOpt *packngo.ListOptions) ([]packngo.Organization, *packngo.Response, error) {
+			return orgs, nil, nil
+		},
+	}
+}
+
+var _ managed.ExternalClient = &external{}
+
+func TestObserve(t *testing.T) {
+	created := metav1.NewTime(time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC))
+	deleted := time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC)
+
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg          resource.Managed
+		observation managed.ExternalObservation
+		err         error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Found": {
+			client: &external{
+				client: listing(
+					packngo.Organization{ID: "other", Name: "My Other Organization"},
+					packngo.Organization{
+						ID:          organizationID,
+						Name:        organizationName,
+						URL:         "/metal/v1/organizations/" + organizationID,
+						Description: "cool",
+						Website:     "https://example.com",
+						Created:     "2021-01-02T03:04:05Z",
+					}),
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  organization(),
+			},
+			want: want{
+				mg: organization(
+					withObservation(v1alpha1.OrganizationObservation{
+						ID:          organizationID,
+						Href:        "/metal/v1/organizations/" + organizationID,
+						Description: "cool",
+						Website:     "https://example.com",
+						CreatedAt:   &created,
+					}),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"NotFound": {
+			client: &external{
+				client: listing(packngo.Organization{ID: "other", Name: "My Other Organization"}),
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  organization(),
+			},
+			want: want{
+				mg:  organization(),
+				err: errors.Wrap(errors.Errorf("cannot find Organization named %q", organizationName), errFindOrganization),
+			},
+		},
+		"Ambiguous": {
+			client: &external{
+				client: listing(
+					packngo.Organization{ID: organizationID, Name: organizationName},
+					packngo.Organization{ID: "other", Name: organizationName}),
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  organization(),
+			},
+			want: want{
+				mg:  organization(),
+				err: errors.Wrap(errors.Errorf("2 Organizations are named %q", organizationName), errFindOrganization),
+			},
+		},
+		"FailedToList": {
+			client: &external{
+				client: &fake.MockClient{
+					MockList: func(listOpt *packngo.ListOptions) ([]packngo.Organization, *packngo.Response, error) {
+						return nil, nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  organization(),
+			},
+			want: want{
+				mg:  organization(),
+				err: errors.Wrap(errorBoom, errListOrganizations),
+			},
+		},
+		"Deleted": {
+			client: &external{
+				client: &fake.MockClient{
+					MockList: func(listOpt *packngo.ListOptions) ([]packngo.Organization, *packngo.Response, error) {
+						t.Errorf("MockList: called for a deleted Organization")
+						return nil, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  organization(withDeletionTimestamp(deleted)),
+			},
+			want: want{
+				mg:          organization(withDeletionTimestamp(deleted)),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"NotOrganization": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotOrganization),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := tc.client.Observe(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Observe(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.observation, o); diff != "" {
+				t.Errorf("tc.client.Observe(): -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestObserveOnly(t *testing.T) {
+	// Organizations are observe-only, the fake panics if any API is called
+	e := &external{client: &fake.MockClient{}}
+	ctx := context.Background()
+
+	if _, err := e.Create(ctx, organization()); err != nil {
+		t.Errorf("e.Create(): %v", err)
+	}
+	if _, err := e.Update(ctx, organization()); err != nil {
+		t.Errorf("e.Update(): %v", err)
+	}
+	if err := e.Delete(ctx, organization()); err != nil {
+		t.Errorf("e.Delete(): %v", err)
+	}
+}
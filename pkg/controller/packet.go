@@ -17,24 +17,59 @@ limitations under the License.
 package controller
 
 import (
+	"time"
+
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/batch"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/bgp/session"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/bgpconfig"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/capacity"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/connection"
+	ipassignment "github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/ip/assignment"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/ip/reservation"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/metalgateway"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/organization"
 	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/ports/assignment"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/project"
 	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/server/device"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/spotmarket"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/spotmarket/price"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/sshkey"
 	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/vlan/virtualnetwork"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/volume"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/volume/attachment"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/vrf"
 )
 
 // Setup creates all Equinix Metal controllers with the supplied logger and adds them to
-// the supplied manager.
-func Setup(mgr ctrl.Manager, l logging.Logger) error {
-	for _, setup := range []func(ctrl.Manager, logging.Logger) error{
+// the supplied manager. Managed resources that are up to date are observed at
+// the supplied poll interval.
+func Setup(mgr ctrl.Manager, l logging.Logger, poll time.Duration) error {
+	for _, setup := range []func(ctrl.Manager, logging.Logger, time.Duration) error{
 		assignment.SetupAssignment,
+		batch.SetupDeviceBatch,
+		bgpconfig.SetupBGPConfig,
+		session.SetupBGPSession,
+		capacity.SetupCapacityCheck,
+		connection.SetupConnection,
 		device.SetupDevice,
+		ipassignment.SetupIPAssignment,
+		reservation.SetupReservation,
+		metalgateway.SetupMetalGateway,
+		organization.SetupOrganization,
+		project.SetupProject,
+		spotmarket.SetupSpotMarketRequest,
+		price.SetupSpotMarketPrice,
+		sshkey.SetupSSHKey,
 		virtualnetwork.SetupVirtualNetwork,
+		volume.SetupVolume,
+		attachment.SetupVolumeAttachment,
+		vrf.SetupVRF,
 	} {
-		if err := setup(mgr, l); err != nil {
+		if err := setup(mgr, l, poll); err != nil {
 			return err
 		}
 	}
@@ -18,7 +18,7 @@ package assignment
 
 import (
 	"context"
-	"path"
+	"time"
 
 	"github.com/packethost/packngo"
 	"github.com/pkg/errors"
@@ -47,22 +47,25 @@ const (
 	errNotAssignment           = "managed resource is not a Assignment"
 	errGetPort                 = "cannot get Port"
 	errCreateAssignment        = "cannot create Assignment"
+	errUpdateAssignment        = "cannot modify Assignment"
 	errDeleteAssignment        = "cannot delete Assignment"
+	errNativeInUseFmt          = "cannot make VirtualNetwork %s native, port %s already has native VirtualNetwork %s"
 )
 
 // SetupAssignment adds a controller that reconciles Assignments
-func SetupAssignment(mgr ctrl.Manager, l logging.Logger) error {
+func SetupAssignment(mgr ctrl.Manager, l logging.Logger, poll time.Duration) error {
 	name := managed.ControllerName(v1alpha1.AssignmentGroupKind)
 
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.AssignmentGroupVersionKind),
-		managed.WithExternalConnecter(&connecter{
+		managed.WithExternalConnecter(clients.NewManagementPolicyConnecter(&connecter{
 			kube:  mgr.GetClient(),
 			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
-		}),
+		})),
 		managed.WithInitializers(&managed.DefaultProviderConfig{}),
 		managed.WithConnectionPublishers(),
 		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+		managed.WithPollInterval(poll),
 		managed.WithLogger(l.WithValues("controller", name)),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 	)
@@ -70,7 +73,7 @@ func SetupAssignment(mgr ctrl.Manager, l logging.Logger) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		For(&v1alpha1.Assignment{}).
-		Complete(r)
+		Complete(clients.NewPollingReconciler(mgr, resource.ManagedKind(v1alpha1.AssignmentGroupVersionKind), r))
 }
 
 type connecter struct {
@@ -126,11 +129,10 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		ResourceUpToDate: true,
 	}
 
-	for _, net := range port.AttachedVirtualNetworks {
-		if path.Base(net.Href) == a.Spec.ForProvider.VirtualNetworkID {
-			a.Status.SetConditions(xpv1.Available())
-			o.ResourceExists = true
-		}
+	if portsclient.IsAttached(port, a.Spec.ForProvider.VirtualNetworkID) {
+		a.Status.SetConditions(xpv1.Available())
+		o.ResourceExists = true
+		o.ResourceUpToDate = portsclient.IsUpToDate(port, a.Spec.ForProvider.VirtualNetworkID, a.Spec.ForProvider.Native)
 	}
 
 	meta.SetExternalName(a, port.ID)
@@ -143,13 +145,65 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalCreation{}, errors.New(errNotAssignment)
 	}
 	a.Status.SetConditions(xpv1.Creating())
-	_, _, err := e.client.Assign(&packngo.PortAssignRequest{PortID: meta.GetExternalName(a), VirtualNetworkID: a.Spec.ForProvider.VirtualNetworkID})
-	return managed.ExternalCreation{}, errors.Wrap(resource.Ignore(packetclient.IsAlreadyDone, err), errCreateAssignment)
+	vlan := a.Spec.ForProvider.VirtualNetworkID
+
+	// A port has at most one native VLAN, the VLAN is not assigned while
+	// another is native so that it can not be assigned but never made native
+	if isNative(a) {
+		port, err := e.client.GetPortByName(a.Spec.ForProvider.DeviceID, a.Spec.ForProvider.Name)
+		if err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errGetPort)
+		}
+		if native := portsclient.NativeVirtualNetworkID(port); native != "" && native != vlan {
+			return managed.ExternalCreation{}, errors.Errorf(errNativeInUseFmt, vlan, port.ID, native)
+		}
+	}
+
+	req := &packngo.PortAssignRequest{PortID: meta.GetExternalName(a), VirtualNetworkID: vlan}
+	if _, _, err := e.client.Assign(req); resource.Ignore(packetclient.IsAlreadyDone, err) != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateAssignment)
+	}
+
+	// The native VLAN must be assigned to the port before it is made native
+	if isNative(a) {
+		_, _, err := e.client.AssignNative(req)
+		return managed.ExternalCreation{}, errors.Wrap(resource.Ignore(packetclient.IsAlreadyDone, err), errCreateAssignment)
+	}
+	return managed.ExternalCreation{}, nil
 }
 
 func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	// NOTE(hasheddan): Assignment cannot be updated.
-	return managed.ExternalUpdate{}, nil
+	a, ok := mg.(*v1alpha1.Assignment)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotAssignment)
+	}
+
+	// Only the native setting of an Assignment can be updated. The port is
+	// read again so that the native VLAN of another Assignment is neither
+	// replaced nor removed.
+	port, err := e.client.GetPortByName(a.Spec.ForProvider.DeviceID, a.Spec.ForProvider.Name)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errGetPort)
+	}
+	vlan := a.Spec.ForProvider.VirtualNetworkID
+	if portsclient.IsUpToDate(port, vlan, a.Spec.ForProvider.Native) {
+		return managed.ExternalUpdate{}, nil
+	}
+
+	// Assignments that do not request either are always up to date, so the
+	// native VLAN is only removed when explicitly requested
+	if !*a.Spec.ForProvider.Native {
+		_, _, err = e.client.UnassignNative(port.ID)
+		return managed.ExternalUpdate{}, errors.Wrap(resource.Ignore(packetclient.IsAlreadyDone, err), errUpdateAssignment)
+	}
+
+	// A port has at most one native VLAN, which must be demoted before
+	// another VLAN can be promoted
+	if native := portsclient.NativeVirtualNetworkID(port); native != "" {
+		return managed.ExternalUpdate{}, errors.Errorf(errNativeInUseFmt, vlan, port.ID, native)
+	}
+	_, _, err = e.client.AssignNative(&packngo.PortAssignRequest{PortID: port.ID, VirtualNetworkID: vlan})
+	return managed.ExternalUpdate{}, errors.Wrap(resource.Ignore(packetclient.IsAlreadyDone, err), errUpdateAssignment)
 }
 
 func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
@@ -158,6 +212,23 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 		return errors.New(errNotAssignment)
 	}
 	a.SetConditions(xpv1.Deleting())
-	_, _, err := e.client.Unassign(&packngo.PortAssignRequest{PortID: meta.GetExternalName(a), VirtualNetworkID: a.Spec.ForProvider.VirtualNetworkID})
+
+	// A native VLAN can not be unassigned from the port, it must first be
+	// removed as the native VLAN
+	port, err := e.client.GetPortByName(a.Spec.ForProvider.DeviceID, a.Spec.ForProvider.Name)
+	if err != nil {
+		return errors.Wrap(resource.Ignore(packetclient.IsNotFound, err), errGetPort)
+	}
+	if portsclient.IsNative(port, a.Spec.ForProvider.VirtualNetworkID) {
+		if _, _, err := e.client.UnassignNative(port.ID); resource.IgnoreAny(err, packetclient.IsNotFound, packetclient.IsAlreadyDone) != nil {
+			return errors.Wrap(err, errDeleteAssignment)
+		}
+	}
+
+	_, _, err = e.client.Unassign(&packngo.PortAssignRequest{PortID: meta.GetExternalName(a), VirtualNetworkID: a.Spec.ForProvider.VirtualNetworkID})
 	return errors.Wrap(resource.IgnoreAny(err, packetclient.IsNotFound, packetclient.IsAlreadyDone), errDeleteAssignment)
 }
+
+func isNative(a *v1alpha1.Assignment) bool {
+	return a.Spec.ForProvider.Native != nil && *a.Spec.ForProvider.Native
+}
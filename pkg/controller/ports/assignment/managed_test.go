@@ -0,0 +1,272 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assignment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/ports/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/ports/fake"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const (
+	deviceID = "device"
+	portID   = "port"
+	portName = "eth1"
+	vlanID   = "vlan"
+)
+
+var errorBoom = errors.New("boom")
+
+var _ managed.ExternalClient = &external{}
+
+func assignment(native bool) *v1alpha1.Assignment {
+	return &v1alpha1.Assignment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "assignment",
+			Annotations: map[string]string{meta.AnnotationKeyExternalName: portID},
+		},
+		Spec: v1alpha1.AssignmentSpec{
+			ForProvider: v1alpha1.AssignmentParameters{
+				DeviceID:         deviceID,
+				Name:             portName,
+				VirtualNetworkID: vlanID,
+				Native:           &native,
+			},
+		},
+	}
+}
+
+// unrequested returns an Assignment that does not request whether the
+// VirtualNetwork is native
+func unrequested() *v1alpha1.Assignment {
+	a := assignment(false)
+	a.Spec.ForProvider.Native = nil
+	return a
+}
+
+// port returns a port with the VLANs attached, the native VLAN is attached
+// as well when it is not empty.
+func port(native string, attached ...string) *packngo.Port {
+	p := &packngo.Port{ID: portID, Name: portName}
+	if native != "" {
+		attached = append(attached, native)
+		p.NativeVirtualNetwork = &packngo.VirtualNetwork{Href: "/virtual-networks/" + native}
+	}
+	for _, id := range attached {
+		p.AttachedVirtualNetworks = append(p.AttachedVirtualNetworks, packngo.VirtualNetwork{Href: "/virtual-networks/" + id})
+	}
+	return p
+}
+
+func TestObserve(t *testing.T) {
+	cases := map[string]struct {
+		mg   *v1alpha1.Assignment
+		port *packngo.Port
+		want managed.ExternalObservation
+	}{
+		"NotAttached": {
+			mg:   assignment(false),
+			port: port(""),
+			want: managed.ExternalObservation{ResourceUpToDate: true},
+		},
+		"UpToDate": {
+			mg:   assignment(false),
+			port: port("other", vlanID),
+			want: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+		},
+		"NotNative": {
+			mg:   assignment(true),
+			port: port("", vlanID),
+			want: managed.ExternalObservation{ResourceExists: true},
+		},
+		"StillNative": {
+			mg:   assignment(false),
+			port: port(vlanID),
+			want: managed.ExternalObservation{ResourceExists: true},
+		},
+		"NativeNotRequested": {
+			mg:   unrequested(),
+			port: port(vlanID),
+			want: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{client: &fake.MockClient{
+				MockGetPortByName: func(string, string) (*packngo.Port, error) { return tc.port, nil },
+			}}
+			got, err := e.Observe(context.Background(), tc.mg)
+			if err != nil {
+				t.Fatalf("e.Observe(): %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("e.Observe(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type want struct {
+		err      error
+		assigned []string
+		native   []string
+	}
+
+	cases := map[string]struct {
+		mg      *v1alpha1.Assignment
+		port    *packngo.Port
+		portErr error
+		want    want
+	}{
+		"Assigned": {
+			mg:   assignment(false),
+			port: port("other"),
+			want: want{assigned: []string{vlanID}},
+		},
+		"AssignedNative": {
+			mg:   assignment(true),
+			port: port(""),
+			want: want{assigned: []string{vlanID}, native: []string{vlanID}},
+		},
+		"AnotherIsNative": {
+			mg:   assignment(true),
+			port: port("other"),
+			want: want{err: errors.Errorf(errNativeInUseFmt, vlanID, portID, "other")},
+		},
+		"FailedToGetPort": {
+			mg:      assignment(true),
+			portErr: errorBoom,
+			want:    want{err: errors.Wrap(errorBoom, errGetPort)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var assigned, native []string
+			e := &external{client: &fake.MockClient{
+				MockGetPortByName: func(string, string) (*packngo.Port, error) { return tc.port, tc.portErr },
+				MockAssign: func(par *packngo.PortAssignRequest) (*packngo.Port, *packngo.Response, error) {
+					assigned = append(assigned, par.VirtualNetworkID)
+					return &packngo.Port{}, nil, nil
+				},
+				MockAssignNative: func(par *packngo.PortAssignRequest) (*packngo.Port, *packngo.Response, error) {
+					native = append(native, par.VirtualNetworkID)
+					return &packngo.Port{}, nil, nil
+				},
+			}}
+
+			_, err := e.Create(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("e.Create(): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.assigned, assigned); diff != "" {
+				t.Errorf("MockAssign: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.native, native); diff != "" {
+				t.Errorf("MockAssignNative: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	type want struct {
+		err      error
+		native   []string
+		unnative []string
+	}
+
+	cases := map[string]struct {
+		mg      *v1alpha1.Assignment
+		port    *packngo.Port
+		portErr error
+		want    want
+	}{
+		"Promoted": {
+			mg:   assignment(true),
+			port: port("", vlanID),
+			want: want{native: []string{vlanID}},
+		},
+		"PromotedWhileAnotherIsNative": {
+			mg:   assignment(true),
+			port: port("other", vlanID),
+			want: want{err: errors.Errorf(errNativeInUseFmt, vlanID, portID, "other")},
+		},
+		"Demoted": {
+			mg:   assignment(false),
+			port: port(vlanID),
+			want: want{unnative: []string{portID}},
+		},
+		"AnotherIsNative": {
+			mg:   assignment(false),
+			port: port("other", vlanID),
+		},
+		"NativeNotRequested": {
+			mg:   unrequested(),
+			port: port(vlanID),
+		},
+		"FailedToGetPort": {
+			mg:      assignment(true),
+			portErr: errorBoom,
+			want:    want{err: errors.Wrap(errorBoom, errGetPort)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var native, unnative []string
+			e := &external{client: &fake.MockClient{
+				MockGetPortByName: func(string, string) (*packngo.Port, error) { return tc.port, tc.portErr },
+				MockAssignNative: func(par *packngo.PortAssignRequest) (*packngo.Port, *packngo.Response, error) {
+					if par.PortID != portID {
+						t.Errorf("MockAssignNative: want port %q, got %q", portID, par.PortID)
+					}
+					native = append(native, par.VirtualNetworkID)
+					return &packngo.Port{}, nil, nil
+				},
+				MockUnassignNative: func(id string) (*packngo.Port, *packngo.Response, error) {
+					unnative = append(unnative, id)
+					return &packngo.Port{}, nil, nil
+				},
+			}}
+
+			_, err := e.Update(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("e.Update(): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.native, native); diff != "" {
+				t.Errorf("MockAssignNative: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.unnative, unnative); diff != "" {
+				t.Errorf("MockUnassignNative: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,595 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package project
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/project/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/project/fake"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const (
+	projectName = "my-cool-project"
+	projectID   = "project-id"
+)
+
+var errorBoom = errors.New("boom")
+
+type strange struct {
+	resource.Managed
+}
+
+type projectModifier func(*v1alpha1.Project)
+
+func withExternalName(id string) projectModifier {
+	return func(p *v1alpha1.Project) { meta.SetExternalName(p, id) }
+}
+
+func withOrganizationID(id string) projectModifier {
+	return func(p *v1alpha1.Project) { p.Spec.ForProvider.OrganizationID = &id }
+}
+
+func withPaymentMethodID(id string) projectModifier {
+	return func(p *v1alpha1.Project) { p.Spec.ForProvider.PaymentMethodID = &id }
+}
+
+func withBackendTransferEnabled(enabled bool) projectModifier {
+	return func(p *v1alpha1.Project) { p.Spec.ForProvider.BackendTransferEnabled = &enabled }
+}
+
+func withConditions(c ...xpv1.Condition) projectModifier {
+	return func(p *v1alpha1.Project) { p.Status.SetConditions(c...) }
+}
+
+func withObservation(o v1alpha1.ProjectObservation) projectModifier {
+	return func(p *v1alpha1.Project) { p.Status.AtProvider = o }
+}
+
+func project(pm ...projectModifier) *v1alpha1.Project {
+	p := &v1alpha1.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: projectName},
+		Spec: v1alpha1.ProjectSpec{
+			ForProvider: v1alpha1.ProjectParameters{Name: projectName},
+		},
+	}
+	for _, m := range pm {
+		m(p)
+	}
+	return p
+}
+
+var _ managed.ExternalClient = &external{}
+
+func TestObserve(t *testing.T) {
+	created := metav1.NewTime(time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg          resource.Managed
+		observation managed.ExternalObservation
+		err         error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"UpToDate": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.Project, *packngo.Response, error) {
+						if id != projectID {
+							t.Errorf("MockGet: want project %q, got %q", projectID, id)
+						}
+						return &packngo.Project{
+							ID:           projectID,
+							Name:         projectName,
+							Organization: packngo.Organization{ID: "organization"},
+							Created:      "2021-01-02T03:04:05Z",
+						}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withExternalName(projectID)),
+			},
+			want: want{
+				mg: project(
+					withExternalName(projectID),
+					withOrganizationID("organization"),
+					withBackendTransferEnabled(false),
+					withObservation(v1alpha1.ProjectObservation{ID: projectID, CreatedAt: &created}),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"LateInitializedPaymentMethod": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.Project, *packngo.Response, error) {
+						return &packngo.Project{
+							ID:              projectID,
+							Name:            projectName,
+							PaymentMethod:   packngo.PaymentMethod{URL: "/metal/v1/payment-methods/payment-method"},
+							BackendTransfer: true,
+						}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withExternalName(projectID)),
+			},
+			want: want{
+				mg: project(
+					withExternalName(projectID),
+					withPaymentMethodID("payment-method"),
+					withBackendTransferEnabled(true),
+					withObservation(v1alpha1.ProjectObservation{ID: projectID}),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"NameChanged": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.Project, *packngo.Response, error) {
+						return &packngo.Project{ID: projectID, Name: "my-old-project"}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withExternalName(projectID), withBackendTransferEnabled(false)),
+			},
+			want: want{
+				mg: project(
+					withExternalName(projectID),
+					withBackendTransferEnabled(false),
+					withObservation(v1alpha1.ProjectObservation{ID: projectID}),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+				},
+			},
+		},
+		"BackendTransferChanged": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.Project, *packngo.Response, error) {
+						return &packngo.Project{ID: projectID, Name: projectName}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withExternalName(projectID), withBackendTransferEnabled(true)),
+			},
+			want: want{
+				mg: project(
+					withExternalName(projectID),
+					withBackendTransferEnabled(true),
+					withObservation(v1alpha1.ProjectObservation{ID: projectID}),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+				},
+			},
+		},
+		"NotFound": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.Project, *packngo.Response, error) {
+						return nil, nil, packettest.NotFound()
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withExternalName(projectID)),
+			},
+			want: want{
+				mg:          project(withExternalName(projectID)),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"FailedToGetProject": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.Project, *packngo.Response, error) {
+						return nil, nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withExternalName(projectID)),
+			},
+			want: want{
+				mg:  project(withExternalName(projectID)),
+				err: errors.Wrap(errorBoom, errGetProject),
+			},
+		},
+		"FailedToLateInitialize": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errorBoom)},
+				client: &fake.MockClient{
+					MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.Project, *packngo.Response, error) {
+						return &packngo.Project{ID: projectID, Name: projectName}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withExternalName(projectID)),
+			},
+			want: want{
+				mg:  project(withExternalName(projectID), withBackendTransferEnabled(false)),
+				err: errors.Wrap(errorBoom, errManagedUpdateFailed),
+			},
+		},
+		"NotProject": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotProject),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := tc.client.Observe(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Observe(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.observation, o); diff != "" {
+				t.Errorf("tc.client.Observe(): -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg       resource.Managed
+		creation managed.ExternalCreation
+		err      error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Created": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockCreate: func(createRequest *packngo.ProjectCreateRequest) (*packngo.Project, *packngo.Response, error) {
+						want := &packngo.ProjectCreateRequest{
+							Name:            projectName,
+							OrganizationID:  "organization",
+							PaymentMethodID: "payment-method",
+						}
+						if diff := cmp.Diff(want, createRequest); diff != "" {
+							t.Errorf("MockCreate: -want, +got:\n%s", diff)
+						}
+						return &packngo.Project{ID: projectID}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withOrganizationID("organization"), withPaymentMethodID("payment-method")),
+			},
+			want: want{
+				mg: project(
+					withOrganizationID("organization"),
+					withPaymentMethodID("payment-method"),
+					withExternalName(projectID),
+					withObservation(v1alpha1.ProjectObservation{ID: projectID}),
+					withConditions(xpv1.Creating())),
+			},
+		},
+		"FailedToCreate": {
+			client: &external{
+				client: &fake.MockClient{
+					MockCreate: func(createRequest *packngo.ProjectCreateRequest) (*packngo.Project, *packngo.Response, error) {
+						return nil, nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(),
+			},
+			want: want{
+				mg:  project(withConditions(xpv1.Creating())),
+				err: errors.Wrap(errorBoom, errCreateProject),
+			},
+		},
+		"FailedToRecordExternalName": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errorBoom)},
+				client: &fake.MockClient{
+					MockCreate: func(createRequest *packngo.ProjectCreateRequest) (*packngo.Project, *packngo.Response, error) {
+						return &packngo.Project{ID: projectID}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(),
+			},
+			want: want{
+				mg: project(
+					withExternalName(projectID),
+					withObservation(v1alpha1.ProjectObservation{ID: projectID}),
+					withConditions(xpv1.Creating())),
+				creation: managed.ExternalCreation{ExternalNameAssigned: true},
+			},
+		},
+		"NotProject": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotProject),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, err := tc.client.Create(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Create(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.creation, c); diff != "" {
+				t.Errorf("tc.client.Create(): -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		err error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Updated": {
+			client: &external{
+				client: &fake.MockClient{
+					MockUpdate: func(id string, updateRequest *packngo.ProjectUpdateRequest) (*packngo.Project, *packngo.Response, error) {
+						if id != projectID {
+							t.Errorf("MockUpdate: want project %q, got %q", projectID, id)
+						}
+						name, paymentMethodID, backendTransfer := projectName, "payment-method", true
+						want := &packngo.ProjectUpdateRequest{
+							Name:            &name,
+							PaymentMethodID: &paymentMethodID,
+							BackendTransfer: &backendTransfer,
+						}
+						if diff := cmp.Diff(want, updateRequest); diff != "" {
+							t.Errorf("MockUpdate: -want, +got:\n%s", diff)
+						}
+						return &packngo.Project{ID: projectID}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withExternalName(projectID), withPaymentMethodID("payment-method"), withBackendTransferEnabled(true)),
+			},
+			want: want{},
+		},
+		"FailedToUpdate": {
+			client: &external{
+				client: &fake.MockClient{
+					MockUpdate: func(id string, updateRequest *packngo.ProjectUpdateRequest) (*packngo.Project, *packngo.Response, error) {
+						return nil, nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withExternalName(projectID)),
+			},
+			want: want{
+				err: errors.Wrap(errorBoom, errUpdateProject),
+			},
+		},
+		"NotProject": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				err: errors.New(errNotProject),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.client.Update(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Update(): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Deleted": {
+			client: &external{
+				client: &fake.MockClient{
+					MockDelete: func(id string) (*packngo.Response, error) {
+						if id != projectID {
+							t.Errorf("MockDelete: want project %q, got %q", projectID, id)
+						}
+						return nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withExternalName(projectID)),
+			},
+			want: want{
+				mg: project(withExternalName(projectID), withConditions(xpv1.Deleting())),
+			},
+		},
+		"AlreadyDeleted": {
+			client: &external{
+				client: &fake.MockClient{
+					MockDelete: func(id string) (*packngo.Response, error) {
+						return nil, packettest.NotFound()
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withExternalName(projectID)),
+			},
+			want: want{
+				mg: project(withExternalName(projectID), withConditions(xpv1.Deleting())),
+			},
+		},
+		"FailedToDelete": {
+			client: &external{
+				client: &fake.MockClient{
+					MockDelete: func(id string) (*packngo.Response, error) {
+						return nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withExternalName(projectID)),
+			},
+			want: want{
+				mg:  project(withExternalName(projectID), withConditions(xpv1.Deleting())),
+				err: errors.Wrap(errorBoom, errDeleteProject),
+			},
+		},
+		"NotProject": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotProject),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.client.Delete(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Delete(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -19,19 +19,26 @@ package device
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
-	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	ipv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/ip/v1alpha1"
 	v1alpha2 "github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
 	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
 	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
 	packetclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
 	devicesclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/device"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/facility"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/operatingsystem"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/resolve"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -51,36 +58,113 @@ const (
 	errNotDevice               = "managed resource is not a Device"
 	errGetDevice               = "cannot get Device"
 	errCreateDevice            = "cannot create Device"
+	errCreateDeviceRejected    = "cannot create Device: the request was rejected, the plan may lack capacity in the requested location; creation will be retried"
 	errUpdateDevice            = "cannot modify Device"
 	errDeleteDevice            = "cannot delete Device"
+	errReinstallDevice         = "cannot reinstall Device"
+	errRebootDevice            = "cannot reboot Device"
+	errAssignVLAN              = "cannot attach VLAN to Device"
+	errGetIQN                  = "cannot get Device IQN"
+	errGetNetworkPorts         = "cannot get Device network ports"
+	errListDevices             = "cannot list Devices"
+	errDuplicateHostnameFmt    = "cannot create Device, hostname %q is already used by Device %s of the project"
+	errHostnameAmbiguousFmt    = "%d Devices have the hostname %q"
+	errPowerDevice             = "cannot change Device power state"
+	errLockDevice              = "cannot lock Device"
+	errUnlockDevice            = "cannot unlock Device"
+	errGetReservation          = "cannot get Hardware Reservation"
+	errReservationConflict     = "cannot use Hardware Reservation"
+	errReleaseReservation      = "cannot move Hardware Reservation of deleted Device"
+	errInvalidIPXEScriptURL    = "cannot use iPXE script URL"
+	errInvalidHostname         = "cannot use Device hostname"
+	errInvalidDevice           = "invalid Device parameters"
+	errInvalidNetworkType      = "cannot use Device network type"
+	errFacilityMigratedFmt     = "Device was requested in facility %q but has been migrated to facility %q"
+	msgNotUpToDateFmt          = "Device differs from the external resource in fields: %s"
+	msgLateInitializedFmt      = "Device fields were late-initialized from the external resource: %s"
+	errDeviceFailed            = "Device has entered the failed state"
+	errDeviceFailedFmt         = "Device has been observed in the failed state %d times in a row"
+	errResolveUserDataRef      = "cannot resolve UserDataRef"
+	errResolveCustomDataRef    = "cannot resolve CustomDataRef"
+	errDataTooLargeFmt         = "%s of %s %s/%s key %q is %d bytes, larger than the limit of %d bytes"
+	errCombinedDataTooLargeFmt = "userdata combined from %d references is %d bytes, larger than the limit of %d bytes"
+	errResolveRefFmt           = "cannot resolve %s %s/%s"
+	errCombineUserData         = "cannot combine userdata"
+	errDecodeUserData          = "cannot decode userdata"
+	errDeprovisionStuckFmt     = "Device has been deprovisioning for %s since its deletion, longer than %s"
+	errResolveIPReservation    = "cannot resolve IPReservationRef"
+	errNoIPReservationSelected = "no IP Reservation matches IPReservationSelector"
+	errUseIPReservation        = "cannot use IP Reservation, creation will be retried"
+
+	userdataMapKey   = "cloud-init"
+	customdataMapKey = "customdata"
+
+	defaultUserDataSeparator = "\n"
+
+	// maxFailureBackoff is the longest interval between observations of a
+	// failed Device
+	maxFailureBackoff = time.Hour
+
+	// defaultDeprovisionWarningThreshold is how long a deleted Device may
+	// deprovision before it is reported, unless the ProviderConfig sets it
+	defaultDeprovisionWarningThreshold = 30 * time.Minute
+)
 
-	userdataMapKey = "cloud-init"
+// Event reasons.
+const (
+	reasonFacilityMigrated event.Reason = "FacilityMigrated"
+	reasonNotUpToDate      event.Reason = "NotUpToDate"
+	reasonLateInitialized  event.Reason = "LateInitialized"
+	reasonFailed           event.Reason = "DeviceFailed"
+	reasonFailedPersistent event.Reason = "DevicePersistentlyFailed"
+	reasonDeprovisionStuck event.Reason = "DeprovisionStuck"
 )
 
 // SetupDevice adds a controller that reconciles Devices
-func SetupDevice(mgr ctrl.Manager, l logging.Logger) error {
+func SetupDevice(mgr ctrl.Manager, l logging.Logger, poll time.Duration) error {
 	name := managed.ControllerName(v1alpha2.DeviceGroupKind)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
 
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha2.DeviceGroupVersionKind),
-		managed.WithExternalConnecter(&connecter{
-			kube:  mgr.GetClient(),
-			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
-		}),
+		managed.WithExternalConnecter(clients.NewManagementPolicyConnecter(&connecter{
+			kube:             mgr.GetClient(),
+			usage:            resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
+			record:           recorder,
+			facilities:       facility.NewCache(facility.DefaultCacheTTL),
+			operatingSystems: operatingsystem.NewCache(operatingsystem.DefaultCacheTTL),
+		})),
+		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+		managed.WithPollInterval(poll),
 		managed.WithLogger(l.WithValues("controller", name)),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithRecorder(recorder),
 	)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		For(&v1alpha2.Device{}).
-		Complete(r)
+		Complete(clients.NewPollingReconciler(mgr, resource.ManagedKind(v1alpha2.DeviceGroupVersionKind), r,
+			clients.WithFailureBackoff(failedObservations, maxFailureBackoff)))
 }
 
 type connecter struct {
-	kube        client.Client
-	usage       resource.Tracker
-	newClientFn func(ctx context.Context, config *clients.Credentials) (devicesclient.ClientWithDefaults, error)
+	kube             client.Client
+	usage            resource.Tracker
+	record           event.Recorder
+	facilities       *facility.Cache
+	operatingSystems *operatingsystem.Cache
+	newClientFn      func(ctx context.Context, config *clients.Credentials) (devicesclient.ClientWithDefaults, error)
+}
+
+// failedObservations returns the number of consecutive observations of a
+// Device in the failed state, the poll interval of failed Devices is doubled
+// for each
+func failedObservations(mg resource.Managed) int {
+	d, ok := mg.(*v1alpha2.Device)
+	if !ok {
+		return 0
+	}
+	return d.Status.AtProvider.FailedObservations
 }
 
 func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
@@ -103,12 +187,48 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	}
 	client, err := newClientFn(ctx, cfg)
 
-	return &external{kube: c.kube, client: client}, errors.Wrap(err, errNewClient)
+	record := c.record
+	if record == nil {
+		record = event.NewNopRecorder()
+	}
+
+	return &external{
+		kube:                        c.kube,
+		client:                      client,
+		record:                      record,
+		facilities:                  c.facilities,
+		operatingSystems:            c.operatingSystems,
+		defaultTags:                 cfg.DefaultTags,
+		uniqueHostnames:             cfg.UniqueHostnames,
+		deprovisionWarningThreshold: cfg.DeprovisionWarningThreshold,
+	}, errors.Wrap(err, errNewClient)
 }
 
 type external struct {
 	kube   client.Client
 	client devicesclient.ClientWithDefaults
+	record event.Recorder
+
+	// facilities caches the facility codes requested facilities are
+	// validated against, facilities are not validated when it is nil
+	facilities *facility.Cache
+
+	// operatingSystems caches the plans each operating system is
+	// provisionable on, operating systems are not validated when it is nil
+	operatingSystems *operatingsystem.Cache
+
+	// defaultTags of the ProviderConfig are merged into the tags of the
+	// Device
+	defaultTags map[string]string
+
+	// uniqueHostnames of the ProviderConfig prevents creating a Device with
+	// the hostname of another Device of the project
+	uniqueHostnames bool
+
+	// deprovisionWarningThreshold of the ProviderConfig is how long a deleted
+	// Device may deprovision before it is reported. The
+	// defaultDeprovisionWarningThreshold is used when it is not positive.
+	deprovisionWarningThreshold time.Duration
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) { //nolint:gocyclo
@@ -117,102 +237,324 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotDevice)
 	}
 
+	strategy, err := clients.GetExternalNameStrategy(d)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	// Devices named by their hostname are found by it until their ID is
+	// observed
+	id := meta.GetExternalName(d)
+	if strategy == clients.ExternalNameStrategyHostname {
+		id = d.Status.AtProvider.ID
+		if id == "" {
+			if id, err = e.findByHostname(d); err != nil || id == "" {
+				return managed.ExternalObservation{ResourceExists: false}, err
+			}
+		}
+	}
+
 	// Observe device
-	device, _, err := e.client.Get(meta.GetExternalName(d), nil)
+	device, _, err := e.client.Get(id, devicesclient.ObserveOptions)
 	if packetclient.IsNotFound(err) {
-		return managed.ExternalObservation{ResourceExists: false}, nil
+		return managed.ExternalObservation{ResourceExists: false}, e.releaseHardwareReservation(d)
 	}
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetDevice)
 	}
 
-	current := d.Spec.ForProvider.DeepCopy()
-	devicesclient.LateInitialize(&d.Spec.ForProvider, device)
-	if !cmp.Equal(current, &d.Spec.ForProvider) {
+	// Spot instances are terminated by the spot market rather than deleted,
+	// a terminated instance is reported as not existing so that it is
+	// recreated
+	if devicesclient.IsSpotTerminated(device) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	// The late-initialized fields are reported to explain why the spec of a
+	// Device changed after it was applied
+	if fields := devicesclient.LateInitialize(&d.Spec.ForProvider, device); len(fields) > 0 {
 		if err := e.kube.Update(ctx, d); err != nil {
 			return managed.ExternalObservation{}, errors.Wrap(err, errManagedUpdateFailed)
 		}
+		e.record.Event(d, event.Normal(reasonLateInitialized, fmt.Sprintf(msgLateInitializedFmt, strings.Join(fields, ", "))))
 	}
 
+	// The last reboot token, failure count and start of the deletion are only
+	// known to the controller and must survive each new observation, as must the IQN
+	// which is looked up once
+	lastRebootToken := d.Status.AtProvider.LastRebootToken
+	lastAction := d.Status.AtProvider.LastAction
+	deletionStartedAt := d.Status.AtProvider.DeletionStartedAt
+	failures := d.Status.AtProvider.FailedObservations
+	iqn := d.Status.AtProvider.IQN
 	d.Status.AtProvider, err = devicesclient.GenerateObservation(device)
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errGenObservation)
 	}
+	d.Status.AtProvider.LastRebootToken = lastRebootToken
+	d.Status.AtProvider.LastAction = lastAction
+	d.Status.AtProvider.DeletionStartedAt = deletionStartedAt
+	d.Status.AtProvider.IQN = iqn
+	if iqn == "" && d.Status.AtProvider.State == v1alpha2.StateActive {
+		if d.Status.AtProvider.IQN, _, err = e.client.GetIQN(id); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errGetIQN)
+		}
+	}
+	// The VRFs and interconnections of attached VLANs are only looked up for
+	// devices with attached VLANs
+	if devicesclient.HasAttachedVirtualNetworks(device) {
+		ports, _, err := e.client.GetNetworkPorts(id)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errGetNetworkPorts)
+		}
+		d.Status.AtProvider.NetworkAttachments = devicesclient.GenerateNetworkAttachments(ports)
+	}
+	if d.Status.AtProvider.State == v1alpha2.StateFailed {
+		d.Status.AtProvider.FailedObservations = failures + 1
+	}
+
+	// Deprovisioning can take many minutes, a deleted device that remains
+	// deprovisioning for longer than expected is reported as possibly stuck
+	if elapsed, stuck := e.deprovisionElapsed(d); stuck {
+		e.record.Event(d, event.Warning(reasonDeprovisionStuck,
+			errors.Errorf(errDeprovisionStuckFmt, elapsed.Round(time.Second), e.deprovisionThreshold())))
+	}
+
+	// Facility is immutable, a device that was migrated to another facility
+	// is reported rather than updated or recreated
+	if devicesclient.IsFacilityMigrated(d, device) {
+		e.record.Event(d, event.Warning(reasonFacilityMigrated,
+			errors.Errorf(errFacilityMigratedFmt, strings.Join(devicesclient.RequestedFacilities(d), ", "), d.Status.AtProvider.Facility)))
+	}
 
 	// Set Device status and bindable
 	switch d.Status.AtProvider.State {
 	case v1alpha2.StateActive:
 		d.Status.SetConditions(xpv1.Available())
-	case v1alpha2.StateProvisioning:
-		d.Status.SetConditions(xpv1.Creating())
-	case v1alpha2.StateQueued,
-		v1alpha2.StateDeprovisioning,
-		v1alpha2.StateFailed,
-		v1alpha2.StateInactive,
-		v1alpha2.StatePoweringOff,
+	case v1alpha2.StateProvisioning,
 		v1alpha2.StateReinstalling:
+		creating := xpv1.Creating()
+		if msg := devicesclient.ProvisioningMessage(device); msg != "" {
+			creating = creating.WithMessage(msg)
+		}
+		d.Status.SetConditions(creating)
+	case v1alpha2.StateInactive,
+		v1alpha2.StatePoweringOff:
+		// Devices that were intentionally powered off remain available
+		if devicesclient.IsPoweredOffAsDesired(d) {
+			d.Status.SetConditions(xpv1.Available())
+			break
+		}
 		d.Status.SetConditions(xpv1.Unavailable())
+	case v1alpha2.StateQueued,
+		v1alpha2.StateDeprovisioning:
+		d.Status.SetConditions(xpv1.Unavailable())
+	case v1alpha2.StateFailed:
+		e.observeFailed(d)
+	}
+
+	desired, err := e.resolveDevice(ctx, d)
+	if err != nil {
+		return managed.ExternalObservation{}, err
 	}
 
-	upToDate, networkTypeUpToDate := devicesclient.IsUpToDate(d, device)
+	// The differing fields are reported to ease diagnosing Devices that are
+	// updated on every reconcile
+	diff := devicesclient.Diff(desired, device)
+	if len(diff.Fields) > 0 {
+		e.record.Event(d, event.Normal(reasonNotUpToDate, fmt.Sprintf(msgNotUpToDateFmt, diff)))
+	}
 
 	o := managed.ExternalObservation{
 		ResourceExists:    true,
-		ResourceUpToDate:  upToDate && networkTypeUpToDate,
+		ResourceUpToDate:  len(diff.Fields) == 0 && !devicesclient.IsFailureReinstallDue(d),
 		ConnectionDetails: devicesclient.GetConnectionDetails(device),
 	}
 
 	return o, nil
 }
 
-// resolveUserDataRefs returns a userdata string fetched from the referenced userdata resource
-// TODO(displague) use reference.NewAPIResolver when TypedReference is support
-func (e *external) resolveUserDataRefs(ctx context.Context, d *v1alpha2.Device) (string, error) { //nolint:gocyclo
-	errGetUserDataRef := "cannot get required resource for UserDataRef"
-	errInvalidRefKind := "invalid resource kind"
-	errRefKeyNotFoundFmt := "could not find UserDataRef key %q"
-
-	ref := d.Spec.ForProvider.UserDataRef
-	var userdata string
-	var ok bool
-	nsn := types.NamespacedName{
-		Name:      ref.Name,
-		Namespace: ref.Namespace,
+// validateDataSize returns an error naming the reference if the data resolved
+// from it is too large to be accepted by the API
+func validateDataSize(field string, ref *v1alpha2.DataKeySelector, defaultKey, data string) error {
+	if len(data) <= devicesclient.MaxUserDataSize {
+		return nil
 	}
 	key := ref.Key
 	if key == "" {
-		key = userdataMapKey
+		key = defaultKey
 	}
+	return errors.Errorf(errDataTooLargeFmt, field, ref.Kind, ref.Namespace, ref.Name, key, len(data), devicesclient.MaxUserDataSize)
+}
+
+// keyRef converts a DataKeySelector into a reference that can be resolved
+func keyRef(s *v1alpha2.DataKeySelector) resolve.KeyRef {
+	return resolve.KeyRef{
+		Kind:      s.Kind,
+		Name:      s.Name,
+		Namespace: s.Namespace,
+		Key:       s.Key,
+		Optional:  s.Optional,
+	}
+}
 
-	switch ref.Kind {
-	case "ConfigMap":
-		resource := &corev1.ConfigMap{}
-		err := e.kube.Get(ctx, nsn, resource)
-		if err != nil && !ref.Optional {
-			return "", errors.Wrap(err, errGetUserDataRef)
+// deviceID returns the ID of the Device. Devices named by their hostname are
+// identified by the ID observed in their status.
+func deviceID(d *v1alpha2.Device) string {
+	if s, _ := clients.GetExternalNameStrategy(d); s == clients.ExternalNameStrategyHostname {
+		return d.Status.AtProvider.ID
+	}
+	return meta.GetExternalName(d)
+}
+
+// findByHostname returns the ID of the Device of the project whose hostname is
+// the external name, or an empty ID if there is none
+func (e *external) findByHostname(d *v1alpha2.Device) (string, error) {
+	hostname := meta.GetExternalName(d)
+	devices, err := devicesclient.ListByHostname(e.client, e.client.GetProjectID(d.Spec.ForProvider.ProjectID), hostname)
+	if err != nil {
+		return "", errors.Wrap(err, errListDevices)
+	}
+	switch len(devices) {
+	case 0:
+		return "", nil
+	case 1:
+		return devices[0].ID, nil
+	default:
+		return "", errors.Errorf(errHostnameAmbiguousFmt, len(devices), hostname)
+	}
+}
+
+// validateFacilities returns an error if a facility requested by the Device
+// does not exist
+func (e *external) validateFacilities(d *v1alpha2.Device) error {
+	if e.facilities == nil {
+		return nil
+	}
+	errs := []error{}
+	for _, f := range devicesclient.RequestedFacilities(d) {
+		if f == v1alpha2.FacilityAny {
+			continue
 		}
+		errs = append(errs, e.facilities.Validate(facility.ListFunc(e.client.ListFacilities), f))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// validateOperatingSystem returns an error if the operating system requested
+// by the Device does not exist or is not available on its plan
+func (e *external) validateOperatingSystem(d *v1alpha2.Device) error {
+	if e.operatingSystems == nil || d.Spec.ForProvider.OS == "" {
+		return nil
+	}
+	return e.operatingSystems.Validate(operatingsystem.ListFunc(e.client.ListOperatingSystems), d.Spec.ForProvider.OS, d.Spec.ForProvider.Plan)
+}
 
-		userdata, ok = resource.Data[key]
-	case "Secret":
-		resource := &corev1.Secret{}
-		err := e.kube.Get(ctx, nsn, resource)
-		if err != nil && !ref.Optional {
-			return "", errors.Wrap(err, errGetUserDataRef)
+// observeFailed reports a Device that was observed in the failed state. An
+// event is emitted when the Device first fails, and again when it has failed
+// for as many observations as the threshold of its FailurePolicy.
+func (e *external) observeFailed(d *v1alpha2.Device) {
+	failures := d.Status.AtProvider.FailedObservations
+	if failures == 1 {
+		e.record.Event(d, event.Warning(reasonFailed, errors.New(errDeviceFailed)))
+	}
+	if !devicesclient.IsPersistentlyFailed(d) {
+		d.Status.SetConditions(xpv1.Unavailable())
+		return
+	}
+	msg := fmt.Sprintf(errDeviceFailedFmt, failures)
+	d.Status.SetConditions(xpv1.Unavailable().WithMessage(msg))
+	if failures == devicesclient.FailureThreshold(d) {
+		e.record.Event(d, event.Warning(reasonFailedPersistent, errors.New(msg)))
+	}
+}
+
+// resolveUserDataRefs returns the userdata combined from the UserDataRef and
+// UserDataRefs of the Device parameters, in order. Optional references that
+// can not be resolved are skipped.
+func (e *external) resolveUserDataRefs(ctx context.Context, p v1alpha2.DeviceParameters) (string, error) {
+	refs := p.UserDataRefs
+	if p.UserDataRef != nil {
+		refs = append([]v1alpha2.DataKeySelector{*p.UserDataRef}, refs...)
+	}
+
+	parts := make([]string, 0, len(refs))
+	for i := range refs {
+		ref := &refs[i]
+		userdata, err := resolve.ResolveKeyRef(ctx, e.kube, keyRef(ref), userdataMapKey)
+		if err == nil {
+			userdata, err = devicesclient.DecodeData(userdata, ref.Encoding)
 		}
-		var bytes []byte
-		bytes, ok = resource.Data[key]
-		userdata = string(bytes)
-	default:
-		return "", errors.Wrap(errors.New(errGetUserDataRef), errInvalidRefKind)
+		if err != nil {
+			return "", errors.Wrapf(err, errResolveRefFmt, ref.Kind, ref.Namespace, ref.Name)
+		}
+		if err := validateDataSize("userdata", ref, userdataMapKey, userdata); err != nil {
+			return "", err
+		}
+		if userdata == "" {
+			continue
+		}
+		parts = append(parts, userdata)
 	}
 
-	if !ok && !ref.Optional {
-		err := errors.Wrap(fmt.Errorf(errGetUserDataRef), fmt.Sprintf(errRefKeyNotFoundFmt, key))
-		return "", err
+	separator := defaultUserDataSeparator
+	if p.UserDataSeparator != nil {
+		separator = *p.UserDataSeparator
+	}
+	format := v1alpha2.UserDataFormatConcat
+	if p.UserDataFormat != nil {
+		format = *p.UserDataFormat
+	}
+	userdata, err := devicesclient.CombineUserData(parts, format, separator)
+	if err != nil {
+		return "", errors.Wrap(err, errCombineUserData)
+	}
+	if len(userdata) > devicesclient.MaxUserDataSize {
+		return "", errors.Errorf(errCombinedDataTooLargeFmt, len(refs), len(userdata), devicesclient.MaxUserDataSize)
 	}
 	return userdata, nil
 }
 
+// resolveDevice returns a copy of the Device with any UserDataRef,
+// UserDataRefs and CustomDataRef resolved into UserData and CustomData, any
+// encoded data decoded, and the default tags merged into its tags
+func (e *external) resolveDevice(ctx context.Context, d *v1alpha2.Device) (*v1alpha2.Device, error) {
+	resolved := d.DeepCopy()
+
+	// Default tags are desired of the Device as if they were in its spec,
+	// keeping them when its tags are updated
+	resolved.Spec.ForProvider.Tags = clients.MergeTags(d.Spec.ForProvider.Tags, e.defaultTags)
+
+	if userdata := d.Spec.ForProvider.UserData; userdata != nil && d.Spec.ForProvider.UserDataEncoding != nil {
+		decoded, err := devicesclient.DecodeData(*userdata, *d.Spec.ForProvider.UserDataEncoding)
+		if err != nil {
+			return nil, errors.Wrap(err, errDecodeUserData)
+		}
+		resolved.Spec.ForProvider.UserData = &decoded
+	}
+	if d.Spec.ForProvider.UserDataRef != nil || len(d.Spec.ForProvider.UserDataRefs) > 0 {
+		userdata, err := e.resolveUserDataRefs(ctx, d.Spec.ForProvider)
+		if err != nil {
+			return nil, errors.Wrap(err, errResolveUserDataRef)
+		}
+		resolved.Spec.ForProvider.UserData = &userdata
+	}
+	if ref := d.Spec.ForProvider.CustomDataRef; ref != nil {
+		customdata, err := resolve.ResolveKeyRef(ctx, e.kube, keyRef(ref), customdataMapKey)
+		if err == nil {
+			customdata, err = devicesclient.DecodeData(customdata, ref.Encoding)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errResolveCustomDataRef)
+		}
+		if err := validateDataSize("customdata", ref, customdataMapKey, customdata); err != nil {
+			return nil, errors.Wrap(err, errResolveCustomDataRef)
+		}
+		resolved.Spec.ForProvider.CustomData = &customdata
+	}
+	return resolved, nil
+}
+
 func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 	d, ok := mg.(*v1alpha2.Device)
 	if !ok {
@@ -221,29 +563,139 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	d.Status.SetConditions(xpv1.Creating())
 
-	createDev := d.DeepCopy()
+	createDev, err := e.resolveDevice(ctx, d)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	if p := createDev.Spec.ForProvider; p.IPReservationRef != nil || p.IPReservationSelector != nil {
+		reservation, err := e.resolveIPReservation(ctx, d)
+		if err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errResolveIPReservation)
+		}
+		// Reservations awaiting approval or without unassigned addresses
+		// may become usable, so creation is retried rather than failed
+		if err := devicesclient.AddIPReservation(createDev, reservation); err != nil {
+			d.Status.SetConditions(xpv1.Creating().WithMessage(errUseIPReservation))
+			return managed.ExternalCreation{}, errors.Wrap(err, errUseIPReservation)
+		}
+	}
+
+	if err := devicesclient.ValidateDeviceParameters(createDev, e.validateFacilities, e.validateOperatingSystem); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errInvalidDevice)
+	}
+
+	if devicesclient.IsSpecificHardwareReservation(createDev) {
+		reservation, _, err := e.client.GetHardwareReservation(*createDev.Spec.ForProvider.HardwareReservationID)
+		if err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errGetReservation)
+		}
+		if err := devicesclient.ValidateHardwareReservation(createDev, reservation); err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errReservationConflict)
+		}
+	}
+
+	// Use the metro, or facility, of the credentials when no location is set
+	if createDev.Spec.ForProvider.Facility == "" && createDev.Spec.ForProvider.Metro == "" && len(createDev.Spec.ForProvider.Facilities) == 0 {
+		createDev.Spec.ForProvider.Metro = e.client.GetMetro(packetclient.CredentialMetro)
+		if createDev.Spec.ForProvider.Metro == "" {
+			createDev.Spec.ForProvider.Facility = e.client.GetFacilityID(packetclient.CredentialFacilityID)
+		}
+	}
+
+	projectID := e.client.GetProjectID(createDev.Spec.ForProvider.ProjectID)
 
-	if d.Spec.ForProvider.UserDataRef != nil {
-		userdata, err := e.resolveUserDataRefs(ctx, d)
+	// Devices are tagged with the UID of the managed resource when they are
+	// created. A Device already tagged was created by an earlier reconcile
+	// that failed to record its external name, and is adopted rather than
+	// created again. Nothing is created when adopting, so failing to record
+	// the external name is returned and adoption is retried.
+	createTag := devicesclient.CreateTag(d)
+	if createTag != "" {
+		created, err := devicesclient.ListByTag(e.client, projectID, createTag)
 		if err != nil {
-			return managed.ExternalCreation{}, err
+			return managed.ExternalCreation{}, errors.Wrap(err, errListDevices)
 		}
-		createDev.Spec.ForProvider.UserData = &userdata
+		if len(created) > 0 {
+			return managed.ExternalCreation{}, e.recordExternalName(ctx, d, created[0].ID, created[0].Hostname)
+		}
+		createDev.Spec.ForProvider.Tags = append(createDev.Spec.ForProvider.Tags, createTag)
+	}
+
+	create, err := devicesclient.CreateFromDevice(createDev, projectID)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errInvalidDevice)
 	}
 
-	create := devicesclient.CreateFromDevice(createDev, e.client.GetProjectID(packetclient.CredentialProjectID))
+	if e.uniqueHostnames {
+		existing, err := devicesclient.ListByHostname(e.client, projectID, create.Hostname)
+		if err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errListDevices)
+		}
+		if len(existing) > 0 {
+			return managed.ExternalCreation{}, errors.Errorf(errDuplicateHostnameFmt, create.Hostname, existing[0].ID)
+		}
+	}
 	device, _, err := e.client.Create(create)
+	if packetclient.IsUnprocessable(err) {
+		d.Status.SetConditions(xpv1.Creating().WithMessage(errCreateDeviceRejected))
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateDeviceRejected)
+	}
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreateDevice)
 	}
 
-	d.Status.AtProvider.ID = device.ID
-	meta.SetExternalName(d, device.ID)
-	if err := e.kube.Update(ctx, d); err != nil {
-		return managed.ExternalCreation{}, errors.Wrap(err, errManagedUpdateFailed)
+	// The Device exists once it is created, so failing to persist its
+	// external name is not reported as a failed creation. The reconciler is
+	// asked to persist the external name, retrying as it does so, and a
+	// Device whose external name is lost is found by its create tag.
+	creation := managed.ExternalCreation{ConnectionDetails: devicesclient.GetConnectionDetails(device)}
+	if err := e.recordExternalName(ctx, d, device.ID, create.Hostname); err != nil {
+		creation.ExternalNameAssigned = true
+	}
+
+	// New devices are not rebooted for the token they were created with
+	if t := d.Spec.ForProvider.RebootToken; t != nil {
+		d.Status.AtProvider.LastRebootToken = *t
 	}
 
-	return managed.ExternalCreation{ConnectionDetails: devicesclient.GetConnectionDetails(device)}, nil
+	return creation, nil
+}
+
+// resolveIPReservation returns the IP Reservation referenced, or else selected,
+// by the Device. The first of the selected reservations is used.
+func (e *external) resolveIPReservation(ctx context.Context, d *v1alpha2.Device) (*ipv1alpha1.Reservation, error) {
+	if ref := d.Spec.ForProvider.IPReservationRef; ref != nil {
+		r := &ipv1alpha1.Reservation{}
+		if err := e.kube.Get(ctx, types.NamespacedName{Name: ref.Name}, r); err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+
+	sel := d.Spec.ForProvider.IPReservationSelector
+	l := &ipv1alpha1.ReservationList{}
+	if err := e.kube.List(ctx, l, client.MatchingLabels(sel.MatchLabels)); err != nil {
+		return nil, err
+	}
+	for i := range l.Items {
+		if sel.MatchControllerRef && !meta.HaveSameController(d, &l.Items[i]) {
+			continue
+		}
+		return &l.Items[i], nil
+	}
+	return nil, errors.New(errNoIPReservationSelected)
+}
+
+// recordExternalName records the ID, or the hostname when Devices are named by
+// their hostname, of the Device created for the managed resource
+func (e *external) recordExternalName(ctx context.Context, d *v1alpha2.Device, id, hostname string) error {
+	d.Status.AtProvider.ID = id
+	meta.SetExternalName(d, id)
+	if s, _ := clients.GetExternalNameStrategy(d); s == clients.ExternalNameStrategyHostname {
+		meta.SetExternalName(d, hostname)
+	}
+	return errors.Wrap(e.kube.Update(ctx, d), errManagedUpdateFailed)
 }
 
 func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
@@ -254,22 +706,180 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	// NOTE(hasheddan): we must get the device again to see what type of update
 	// we need to make
-	device, _, err := e.client.Get(meta.GetExternalName(d), nil)
+	device, _, err := e.client.Get(deviceID(d), nil)
 	if err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, errGetDevice)
 	}
 
+	desired, err := e.resolveDevice(ctx, d)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	if err := devicesclient.ValidateIPXEScriptURL(desired); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errInvalidIPXEScriptURL)
+	}
+
+	if err := devicesclient.ValidateHostname(desired); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errInvalidHostname)
+	}
+
+	if err := devicesclient.ValidateSpotTerminationProtection(desired); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errInvalidDevice)
+	}
+
+	if err := devicesclient.ValidateSpotPriceMax(desired, device); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errInvalidDevice)
+	}
+
 	// NOTE(hasheddan): if the update is for the network type we return early
-	// and do any updates on subsequent reconciles
-	if _, n := devicesclient.IsUpToDate(d, device); !n && d.Spec.ForProvider.NetworkType != nil {
-		_, err := e.client.DeviceToNetworkType(meta.GetExternalName(d), *d.Spec.ForProvider.NetworkType)
+	// and do any updates on subsequent reconciles. Network types the plan
+	// does not support can never be converted to, the error is returned
+	// until the network type is changed.
+	if _, n := devicesclient.IsUpToDate(desired, device); !n && d.Spec.ForProvider.NetworkType != nil {
+		if err := devicesclient.ValidateNetworkType(desired, device); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errInvalidNetworkType)
+		}
+		// Ports can not be converted until the device is provisioned, the
+		// conversion is retried on a later reconcile
+		if !devicesclient.IsNetworkTypeConvertible(device) {
+			return managed.ExternalUpdate{}, nil
+		}
+		_, err := e.client.DeviceToNetworkType(deviceID(d), *d.Spec.ForProvider.NetworkType)
 		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateDevice)
 	}
-	_, _, err = e.client.Update(meta.GetExternalName(d), devicesclient.NewUpdateDeviceRequest(d))
 
-	// TODO(displague): use "reinstall" action if userdata changed, after updating the resource
+	// VLANs are attached once the network type has been converted, those
+	// already attached to the port, including any not listed, are left as is
+	if port := devicesclient.VLANPort(desired, device); port != nil && devicesclient.IsNetworkTypeConvertible(device) {
+		for _, vlan := range devicesclient.MissingVLANs(desired, device) {
+			if _, _, err := e.client.Assign(&packngo.PortAssignRequest{PortID: port.ID, VirtualNetworkID: vlan}); err != nil {
+				return managed.ExternalUpdate{}, errors.Wrap(err, errAssignVLAN)
+			}
+		}
+	}
 
-	return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateDevice)
+	device, err = e.updateDevice(d, desired, device)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	if !devicesclient.IsLockUpToDate(desired, device) {
+		if err := e.setLocked(deviceID(d), *desired.Spec.ForProvider.Locked); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+		action := v1alpha2.DeviceActionUnlock
+		if *desired.Spec.ForProvider.Locked {
+			action = v1alpha2.DeviceActionLock
+		}
+		recordAction(d, action)
+	}
+
+	if !devicesclient.IsPowerStateUpToDate(desired, device) {
+		if err := e.setPowerState(deviceID(d), *desired.Spec.ForProvider.PowerState); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errPowerDevice)
+		}
+		action := v1alpha2.DeviceActionPowerOff
+		if *desired.Spec.ForProvider.PowerState == v1alpha2.PowerStateOn {
+			action = v1alpha2.DeviceActionPowerOn
+		}
+		recordAction(d, action)
+	}
+
+	// Userdata is only read by the device during provisioning, so the
+	// updated userdata is applied by reinstalling the device. Persistently
+	// failed devices are reinstalled when their FailurePolicy requests it.
+	reinstall := !devicesclient.IsUserDataUpToDate(desired, device) || devicesclient.IsFailureReinstallDue(desired)
+	if reinstall {
+		if _, err := e.client.Reinstall(deviceID(d), devicesclient.NewReinstallRequest(desired)); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errReinstallDevice)
+		}
+		// The userdata is updated only once the reinstall was requested, so
+		// that a failed reinstall is retried by a later reconcile
+		if !devicesclient.IsUserDataUpToDate(desired, device) {
+			if _, _, err := e.client.Update(deviceID(d), devicesclient.NewUserDataUpdateRequest(desired)); err != nil {
+				return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateDevice)
+			}
+		}
+		d.Status.SetConditions(xpv1.Creating())
+		d.Status.AtProvider.FailedObservations = 0
+		recordAction(d, v1alpha2.DeviceActionReinstall)
+	}
+
+	// A reinstall reboots the device, satisfying any requested reboot. The
+	// token is recorded so that the device is rebooted only once for it.
+	if devicesclient.IsRebootRequested(desired) {
+		if !reinstall {
+			if _, err := e.client.Reboot(deviceID(d)); err != nil {
+				return managed.ExternalUpdate{}, errors.Wrap(err, errRebootDevice)
+			}
+			recordAction(d, v1alpha2.DeviceActionReboot)
+		}
+		d.Status.AtProvider.LastRebootToken = *desired.Spec.ForProvider.RebootToken
+	}
+
+	return managed.ExternalUpdate{}, nil
+}
+
+// updateDevice updates the device, returning the device the update was made
+// to. The device may change state between being fetched and being updated,
+// such as while it is provisioning, so an update rejected as unprocessable is
+// retried once with a request built for the device fetched again, unless the
+// device fetched again no longer needs to be updated.
+func (e *external) updateDevice(d, desired *v1alpha2.Device, device *packngo.Device) (*packngo.Device, error) {
+	_, _, err := e.client.Update(deviceID(d), devicesclient.NewUpdateDeviceRequest(desired, device))
+	if !packetclient.IsUnprocessable(err) {
+		return device, errors.Wrap(err, errUpdateDevice)
+	}
+	device, _, err = e.client.Get(deviceID(d), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetDevice)
+	}
+	if !devicesclient.IsUpdateRequired(desired, device) {
+		return device, nil
+	}
+	_, _, err = e.client.Update(deviceID(d), devicesclient.NewUpdateDeviceRequest(desired, device))
+	return device, errors.Wrap(err, errUpdateDevice)
+}
+
+// setLocked locks or unlocks the device
+func (e *external) setLocked(id string, locked bool) error {
+	if locked {
+		_, err := e.client.Lock(id)
+		return errors.Wrap(err, errLockDevice)
+	}
+	_, err := e.client.Unlock(id)
+	return errors.Wrap(err, errUnlockDevice)
+}
+
+// recordAction records the action last performed on the Device
+func recordAction(d *v1alpha2.Device, action string) {
+	d.Status.AtProvider.LastAction = &v1alpha2.DeviceAction{Type: action, Time: metav1.Now()}
+}
+
+// setPowerState powers the device on or off
+func (e *external) setPowerState(id, powerState string) error {
+	var err error
+	switch powerState {
+	case v1alpha2.PowerStateOn:
+		_, err = e.client.PowerOn(id)
+	case v1alpha2.PowerStateOff:
+		_, err = e.client.PowerOff(id)
+	}
+	return err
+}
+
+// releaseHardwareReservation moves the Hardware Reservation of a deleted Device
+// to its release project. Reservations can not be moved while they are used
+// by a Device, so they are moved once the Device is observed to be gone.
+func (e *external) releaseHardwareReservation(d *v1alpha2.Device) error {
+	projectID := d.Spec.ForProvider.HardwareReservationReleaseProjectID
+	reservationID := d.Status.AtProvider.HardwareReservationID
+	if !meta.WasDeleted(d) || projectID == nil || *projectID == "" || reservationID == "" {
+		return nil
+	}
+	_, _, err := e.client.MoveHardwareReservation(reservationID, *projectID)
+	return errors.Wrap(err, errReleaseReservation)
 }
 
 func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
@@ -279,6 +889,53 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 	}
 	d.SetConditions(xpv1.Deleting())
 
-	_, err := e.client.Delete(meta.GetExternalName(d), false)
-	return errors.Wrap(resource.Ignore(packetclient.IsNotFound, err), errDeleteDevice)
+	// The device was observed to be deleted already, it is not deleted again
+	// while the finalizer waits for it to be gone
+	if d.Status.AtProvider.State == v1alpha2.StateDeprovisioning {
+		recordDeletionStarted(d)
+		return nil
+	}
+
+	// Locked devices can not be deleted, so the device is unlocked first
+	if d.Status.AtProvider.Locked {
+		if _, err := e.client.Unlock(deviceID(d)); resource.Ignore(packetclient.IsNotFound, err) != nil {
+			return errors.Wrap(err, errUnlockDevice)
+		}
+	}
+
+	force := d.Spec.ForProvider.ForceDelete != nil && *d.Spec.ForProvider.ForceDelete
+	if _, err := e.client.Delete(deviceID(d), force); resource.Ignore(packetclient.IsNotFound, err) != nil {
+		return errors.Wrap(err, errDeleteDevice)
+	}
+	recordDeletionStarted(d)
+	return nil
+}
+
+// recordDeletionStarted records when the deletion of the Device started,
+// unless it has been recorded already
+func recordDeletionStarted(d *v1alpha2.Device) {
+	if d.Status.AtProvider.DeletionStartedAt == nil {
+		now := metav1.Now()
+		d.Status.AtProvider.DeletionStartedAt = &now
+	}
+}
+
+// deprovisionThreshold returns how long a deleted Device may deprovision
+// before it is reported
+func (e *external) deprovisionThreshold() time.Duration {
+	if e.deprovisionWarningThreshold > 0 {
+		return e.deprovisionWarningThreshold
+	}
+	return defaultDeprovisionWarningThreshold
+}
+
+// deprovisionElapsed returns how long ago the deletion of the Device started,
+// and whether the Device has been deprovisioning for longer than the threshold
+func (e *external) deprovisionElapsed(d *v1alpha2.Device) (time.Duration, bool) {
+	started := d.Status.AtProvider.DeletionStartedAt
+	if started == nil || d.Status.AtProvider.State != v1alpha2.StateDeprovisioning {
+		return 0, false
+	}
+	elapsed := time.Since(started.Time)
+	return elapsed, elapsed > e.deprovisionThreshold()
 }
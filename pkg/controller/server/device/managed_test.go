@@ -18,27 +18,38 @@ package device
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/packethost/packngo"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apiresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	ipv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/ip/v1alpha1"
 	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
 	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
 	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
 	devicesclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/device"
 	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/device/fake"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/facility"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/operatingsystem"
 	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -55,11 +66,22 @@ const (
 	providerSecretData = "{\"definitely\":\"json\"}"
 
 	connectionSecretName = "cool-connection-secret"
+
+	plan            = "m3.large.x86"
+	operatingSystem = "ubuntu_20_04"
 )
 
 var (
 	errorBoom = errors.New("boom")
 
+	errUnprocessable = &packngo.ErrorResponse{
+		Response: &http.Response{
+			StatusCode: http.StatusUnprocessableEntity,
+			Request:    &http.Request{Method: http.MethodPost, URL: &url.URL{Path: "/projects/cool/devices"}},
+		},
+		Errors: []string{"The facility sv15 has no provisionable c3.small.x86 servers matching your criteria."},
+	}
+
 	// Use layer2-individual as the default, empty packngo.Device{} will
 	// self-detect as layer2-individual based on port and bonding configuration.
 	// layer3, is the default for real new devices.
@@ -170,6 +192,131 @@ func withNetworkType(d *string) deviceModifier {
 	return func(i *v1alpha2.Device) { i.Spec.ForProvider.NetworkType = d }
 }
 
+func withUserData(u string) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.UserData = &u }
+}
+
+func withPowerState(p string) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.PowerState = &p }
+}
+
+func withHardwareReservationID(r string) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.HardwareReservationID = &r }
+}
+
+func withTags(t ...string) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.Tags = t }
+}
+
+func withDescription(d *string) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.Description = d }
+}
+
+func withHostname(h string) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.Hostname = &h }
+}
+
+func withUserDataRef(r *v1alpha2.DataKeySelector) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.UserDataRef = r }
+}
+
+func withRebootToken(token string) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.RebootToken = &token }
+}
+
+func withLastRebootToken(token string) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Status.AtProvider.LastRebootToken = token }
+}
+
+func withLastAction(action string) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Status.AtProvider.LastAction = &v1alpha2.DeviceAction{Type: action} }
+}
+
+// ignoreActionTime ignores when actions were performed, which is the time
+// they were recorded
+var ignoreActionTime = cmpopts.IgnoreFields(v1alpha2.DeviceAction{}, "Time")
+
+func withDeletionStartedAt(t time.Time) deviceModifier {
+	return func(i *v1alpha2.Device) {
+		started := metav1.NewTime(t)
+		i.Status.AtProvider.DeletionStartedAt = &started
+	}
+}
+
+// ignoreDeletionStartedAt ignores when deletions started, which is the time
+// they were requested
+var ignoreDeletionStartedAt = cmpopts.IgnoreFields(v1alpha2.DeviceObservation{}, "DeletionStartedAt")
+
+func withIPXEScriptURL(u string) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.IPXEScriptURL = &u }
+}
+
+func withOS(os string) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.OS = os }
+}
+
+func withAlwaysPXE(p *bool) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.AlwaysPXE = p }
+}
+
+func withLocation(facility, metro string) deviceModifier {
+	return func(i *v1alpha2.Device) {
+		i.Spec.ForProvider.Facility = facility
+		i.Spec.ForProvider.Metro = metro
+	}
+}
+
+func withFacilities(f ...string) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.Facilities = f }
+}
+
+func withFeatures(f map[string]string) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.Features = f }
+}
+
+func withTerminationTime(t time.Time) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.TerminationTime = &metav1.Time{Time: t} }
+}
+
+func withSpotTerminationProtection(p bool) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.SpotTerminationProtection = &p }
+}
+
+func withForceDelete(f bool) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.ForceDelete = &f }
+}
+
+func withManagementPolicy(p clients.ManagementPolicy) deviceModifier {
+	return func(i *v1alpha2.Device) {
+		meta.AddAnnotations(i, map[string]string{clients.AnnotationKeyManagementPolicy: string(p)})
+	}
+}
+
+func withObservedLocked(l bool) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Status.AtProvider.Locked = l }
+}
+
+func withObservedSSHKeys(k ...string) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Status.AtProvider.SSHKeys = k }
+}
+
+func withObservedSpotInstance(maxPrice string) deviceModifier {
+	return func(i *v1alpha2.Device) {
+		price := apiresource.MustParse(maxPrice)
+		i.Status.AtProvider.SpotInstance = true
+		i.Status.AtProvider.SpotPriceMax = &price
+	}
+}
+
+func withSpotInstance(maxPrice string) deviceModifier {
+	return func(i *v1alpha2.Device) {
+		spot := true
+		price := apiresource.MustParse(maxPrice)
+		i.Spec.ForProvider.SpotInstance = &spot
+		i.Spec.ForProvider.SpotPriceMax = &price
+	}
+}
+
 type initializerParams struct {
 	hostname, billingCycle, userdata, ipxeScriptURL string
 	locked                                          bool
@@ -203,6 +350,8 @@ func device(im ...deviceModifier) *v1alpha2.Device {
 				},
 			},
 			ForProvider: v1alpha2.DeviceParameters{
+				Plan:      plan,
+				OS:        operatingSystem,
 				AlwaysPXE: alwaysPXE,
 			},
 		},
@@ -215,10 +364,18 @@ func device(im ...deviceModifier) *v1alpha2.Device {
 	return i
 }
 
+func noIQN(_ string) (string, *packngo.Response, error) {
+	return "", nil, nil
+}
+
 func projectIDFromCredentials(_ string) string {
 	return "id-from-credentials"
 }
 
+func metroFromCredentials(_ string) string {
+	return "metro-from-credentials"
+}
+
 var _ managed.ExternalClient = &external{}
 var _ managed.ExternalConnecter = &connecter{}
 
@@ -417,10 +574,12 @@ func TestObserve(t *testing.T) {
 	}{
 		"ObservedDeviceAvailableNoUpdateNeeded": {
 			client: &external{
+				record: event.NewNopRecorder(),
 				kube: &test.MockClient{
 					MockUpdate: test.NewMockUpdateFn(nil),
 				},
 				client: &fake.MockClient{
+					MockGetIQN: noIQN,
 					MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
 						d := &packngo.Device{
 							State:        v1alpha2.StateActive,
@@ -449,17 +608,23 @@ func TestObserve(t *testing.T) {
 				},
 			},
 		},
-		"ObservedDeviceAvailableUpdateNeeded": {
+		"LateInitializedDefaults": {
 			client: &external{
+				record: event.NewNopRecorder(),
 				kube: &test.MockClient{
 					MockUpdate: test.NewMockUpdateFn(nil),
 				},
 				client: &fake.MockClient{
+					MockGetIQN: noIQN,
 					MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
 						d := &packngo.Device{
 							State:        v1alpha2.StateActive,
 							ProvisionPer: float32(100),
-							AlwaysPXE:    !*alwaysPXE,
+							AlwaysPXE:    *alwaysPXE,
+							BillingCycle: "hourly",
+							Locked:       true,
+							SpotInstance: true,
+							SpotPriceMax: 0.5,
 						}
 						return d, nil, nil
 					},
@@ -471,49 +636,54 @@ func TestObserve(t *testing.T) {
 			},
 			want: want{
 				mg: device(
-					withInitializerParams(initializerParams{}),
+					withInitializerParams(initializerParams{billingCycle: "hourly", locked: true}),
+					withSpotInstance("0.5"),
+					withObservedSpotInstance("0.5"),
+					withObservedLocked(true),
 					withConditions(xpv1.Available()),
 					withProvisionPer(float32(100)),
 					withNetworkType(&networkType),
 					withState(v1alpha2.StateActive)),
 				observation: managed.ExternalObservation{
 					ResourceExists:    true,
-					ResourceUpToDate:  false,
+					ResourceUpToDate:  true,
 					ConnectionDetails: managed.ConnectionDetails{},
 				},
 			},
 		},
-		"ObservedDeviceCreating": {
+		"LateInitializedImportedIPXE": {
 			client: &external{
+				record: event.NewNopRecorder(),
 				kube: &test.MockClient{
 					MockUpdate: test.NewMockUpdateFn(nil),
 				},
 				client: &fake.MockClient{
+					MockGetIQN: noIQN,
 					MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
 						d := &packngo.Device{
-							State:        v1alpha2.StateProvisioning,
-							ProvisionPer: float32(50),
-							AlwaysPXE:    *alwaysPXE,
+							State:         v1alpha2.StateActive,
+							ProvisionPer:  float32(100),
+							OS:            &packngo.OS{Slug: v1alpha2.OSCustomIPXE},
+							IPXEScriptURL: "https://example.com/boot.ipxe",
+							AlwaysPXE:     true,
 						}
 						return d, nil, nil
 					},
-					MockDeviceNetworkType: func(_ string) (string, error) {
-						return networkType, nil
-					},
 				},
 			},
 			args: args{
 				ctx: context.Background(),
-				mg:  device(),
+				mg:  device(withOS(""), withAlwaysPXE(nil)),
 			},
 			want: want{
 				mg: device(
-					withInitializerParams(initializerParams{}),
-					withConditions(xpv1.Creating()),
-					withProvisionPer(float32(50)),
+					withInitializerParams(initializerParams{ipxeScriptURL: "https://example.com/boot.ipxe"}),
+					withOS(v1alpha2.OSCustomIPXE),
+					withAlwaysPXE(alwaysPXE),
+					withConditions(xpv1.Available()),
+					withProvisionPer(float32(100)),
 					withNetworkType(&networkType),
-					withState(v1alpha2.StateProvisioning),
-				),
+					withState(v1alpha2.StateActive)),
 				observation: managed.ExternalObservation{
 					ResourceExists:    true,
 					ResourceUpToDate:  true,
@@ -521,19 +691,24 @@ func TestObserve(t *testing.T) {
 				},
 			},
 		},
-		"ObservedDeviceQueued": {
+		"ObservedSSHKeys": {
 			client: &external{
+				record: event.NewNopRecorder(),
 				kube: &test.MockClient{
 					MockUpdate: test.NewMockUpdateFn(nil),
 				},
 				client: &fake.MockClient{
+					MockGetIQN: noIQN,
 					MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
 						d := &packngo.Device{
-							State:        v1alpha2.StateQueued,
-							ProvisionPer: float32(50),
+							State:        v1alpha2.StateActive,
+							ProvisionPer: float32(100),
 							AlwaysPXE:    *alwaysPXE,
+							SSHKeys: []packngo.SSHKey{
+								{ID: "key-1"},
+								{URL: "/ssh-keys/key-2"},
+							},
 						}
-
 						return d, nil, nil
 					},
 				},
@@ -545,10 +720,11 @@ func TestObserve(t *testing.T) {
 			want: want{
 				mg: device(
 					withInitializerParams(initializerParams{}),
-					withConditions(xpv1.Unavailable()),
-					withProvisionPer(float32(50)),
+					withObservedSSHKeys("key-1", "key-2"),
+					withConditions(xpv1.Available()),
+					withProvisionPer(float32(100)),
 					withNetworkType(&networkType),
-					withState(v1alpha2.StateQueued)),
+					withState(v1alpha2.StateActive)),
 				observation: managed.ExternalObservation{
 					ResourceExists:    true,
 					ResourceUpToDate:  true,
@@ -556,103 +732,117 @@ func TestObserve(t *testing.T) {
 				},
 			},
 		},
-		"ObservedDeviceDoesNotExist": {
+		"TerminatedSpotInstance": {
 			client: &external{client: &fake.MockClient{
 				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
-					return nil, nil, &packngo.ErrorResponse{
-						Response: &http.Response{
-							StatusCode: http.StatusNotFound,
-						},
+					d := &packngo.Device{
+						State:           v1alpha2.StateActive,
+						SpotInstance:    true,
+						SpotPriceMax:    0.5,
+						TerminationTime: &packngo.Timestamp{Time: time.Now().Add(-time.Minute)},
 					}
+					return d, nil, nil
 				}},
 			},
 			args: args{
 				ctx: context.Background(),
-				mg:  device(),
+				mg:  device(withSpotInstance("0.5")),
 			},
 			want: want{
-				mg:          device(),
+				mg:          device(withSpotInstance("0.5")),
 				observation: managed.ExternalObservation{ResourceExists: false},
 			},
 		},
-		"NotDevice": {
-			client: &external{},
+		"ObservedDeviceAvailableUpdateNeeded": {
+			client: &external{
+				record: event.NewNopRecorder(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				client: &fake.MockClient{
+					MockGetIQN: noIQN,
+					MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+						d := &packngo.Device{
+							State:        v1alpha2.StateActive,
+							ProvisionPer: float32(100),
+							AlwaysPXE:    !*alwaysPXE,
+						}
+						return d, nil, nil
+					},
+				},
+			},
 			args: args{
 				ctx: context.Background(),
-				mg:  &strange{},
+				mg:  device(),
 			},
 			want: want{
-				mg:  &strange{},
-				err: errors.New(errNotDevice),
+				mg: device(
+					withInitializerParams(initializerParams{}),
+					withConditions(xpv1.Available()),
+					withProvisionPer(float32(100)),
+					withNetworkType(&networkType),
+					withState(v1alpha2.StateActive)),
+				observation: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
 			},
 		},
-		"FailedToGetDevice": {
-			client: &external{client: &fake.MockClient{
-				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
-					return nil, nil, errorBoom
-				}},
+		"ObservedDeviceCreating": {
+			client: &external{
+				record: event.NewNopRecorder(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				client: &fake.MockClient{
+					MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+						d := &packngo.Device{
+							State:        v1alpha2.StateProvisioning,
+							ProvisionPer: float32(50),
+							AlwaysPXE:    *alwaysPXE,
+						}
+						return d, nil, nil
+					},
+					MockDeviceNetworkType: func(_ string) (string, error) {
+						return networkType, nil
+					},
+				},
 			},
 			args: args{
 				ctx: context.Background(),
 				mg:  device(),
 			},
 			want: want{
-				mg:  device(),
-				err: errors.Wrap(errorBoom, errGetDevice),
+				mg: device(
+					withInitializerParams(initializerParams{}),
+					withConditions(xpv1.Creating().WithMessage("provisioning 50%")),
+					withProvisionPer(float32(50)),
+					withNetworkType(&networkType),
+					withState(v1alpha2.StateProvisioning),
+				),
+				observation: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
 			},
 		},
-	}
-
-	for name, tc := range cases {
-		t.Run(name, func(t *testing.T) {
-			got, err := tc.client.Observe(tc.args.ctx, tc.args.mg)
-
-			if diff := cmp.Diff(tc.want.observation, got, test.EquateErrors()); diff != "" {
-				t.Errorf("tc.client.Observe(): -want, +got:\n%s", diff)
-			}
-
-			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("tc.client.Observe(): -want error, +got error:\n%s", diff)
-			}
-
-			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions(), packettest.EquateQuantities()); diff != "" {
-				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
-			}
-		})
-	}
-}
-
-func TestCreate(t *testing.T) {
-	type args struct {
-		ctx context.Context
-		mg  resource.Managed
-	}
-	type want struct {
-		mg       resource.Managed
-		creation managed.ExternalCreation
-		err      error
-	}
-
-	cases := map[string]struct {
-		client managed.ExternalClient
-		args   args
-		want   want
-	}{
-		"CreatedInstance": {
+		"ObservedDeviceCreatingWithoutPercentage": {
 			client: &external{
+				record: event.NewNopRecorder(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
 				client: &fake.MockClient{
-					MockGetProjectID: projectIDFromCredentials,
-					MockCreate: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+					MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
 						d := &packngo.Device{
-							ID: deviceName,
+							State:     v1alpha2.StateProvisioning,
+							AlwaysPXE: *alwaysPXE,
 						}
-
 						return d, nil, nil
 					},
 				},
-				kube: &test.MockClient{
-					MockUpdate: test.NewMockUpdateFn(nil),
-				},
 			},
 			args: args{
 				ctx: context.Background(),
@@ -660,19 +850,1489 @@ func TestCreate(t *testing.T) {
 			},
 			want: want{
 				mg: device(
+					withInitializerParams(initializerParams{}),
 					withConditions(xpv1.Creating()),
-					withID(deviceName),
+					withProvisionPer(float32(0)),
+					withNetworkType(&networkType),
+					withState(v1alpha2.StateProvisioning),
 				),
-				creation: managed.ExternalCreation{
+				observation: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
 					ConnectionDetails: managed.ConnectionDetails{},
 				},
 			},
 		},
-		"NotDevice": {
-			client: &external{},
+		"ObservedDeviceReinstalling": {
+			client: &external{
+				record: event.NewNopRecorder(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				client: &fake.MockClient{
+					MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+						d := &packngo.Device{
+							State:        v1alpha2.StateReinstalling,
+							ProvisionPer: float32(50),
+							AlwaysPXE:    *alwaysPXE,
+						}
+						return d, nil, nil
+					},
+				},
+			},
 			args: args{
 				ctx: context.Background(),
-				mg:  &strange{},
+				mg:  device(),
+			},
+			want: want{
+				mg: device(
+					withInitializerParams(initializerParams{}),
+					withConditions(xpv1.Creating().WithMessage("reinstalling 50%")),
+					withProvisionPer(float32(50)),
+					withNetworkType(&networkType),
+					withState(v1alpha2.StateReinstalling),
+				),
+				observation: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"ObservedDevicePoweredOff": {
+			client: &external{
+				record: event.NewNopRecorder(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				client: &fake.MockClient{
+					MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+						d := &packngo.Device{
+							State:        v1alpha2.StateInactive,
+							ProvisionPer: float32(100),
+							AlwaysPXE:    *alwaysPXE,
+						}
+						return d, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withPowerState(v1alpha2.PowerStateOff)),
+			},
+			want: want{
+				mg: device(
+					withPowerState(v1alpha2.PowerStateOff),
+					withInitializerParams(initializerParams{}),
+					withConditions(xpv1.Available()),
+					withProvisionPer(float32(100)),
+					withNetworkType(&networkType),
+					withState(v1alpha2.StateInactive),
+				),
+				observation: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"ObservedDeviceQueued": {
+			client: &external{
+				record: event.NewNopRecorder(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				client: &fake.MockClient{
+					MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+						d := &packngo.Device{
+							State:        v1alpha2.StateQueued,
+							ProvisionPer: float32(50),
+							AlwaysPXE:    *alwaysPXE,
+						}
+
+						return d, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(),
+			},
+			want: want{
+				mg: device(
+					withInitializerParams(initializerParams{}),
+					withConditions(xpv1.Unavailable()),
+					withProvisionPer(float32(50)),
+					withNetworkType(&networkType),
+					withState(v1alpha2.StateQueued)),
+				observation: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"ObservedDeviceDoesNotExist": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+					return nil, nil, &packngo.ErrorResponse{
+						Response: &http.Response{
+							StatusCode: http.StatusNotFound,
+						},
+					}
+				}},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(),
+			},
+			want: want{
+				mg:          device(),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"NotDevice": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotDevice),
+			},
+		},
+		"FailedToGetDevice": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+					return nil, nil, errorBoom
+				}},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(),
+			},
+			want: want{
+				mg:  device(),
+				err: errors.Wrap(errorBoom, errGetDevice),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.client.Observe(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.observation, got, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Observe(): -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Observe(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions(), packettest.EquateQuantities()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestObserveConnectionDetails(t *testing.T) {
+	address := "198.51.100.1"
+	e := &external{
+		kube: &test.MockClient{
+			MockUpdate: test.NewMockUpdateFn(nil),
+		},
+		client: &fake.MockClient{
+			MockGetIQN: noIQN,
+			MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+				d := &packngo.Device{
+					State:     v1alpha2.StateActive,
+					AlwaysPXE: *alwaysPXE,
+					Network: []*packngo.IPAddressAssignment{{
+						IpAddressCommon: packngo.IpAddressCommon{
+							Address:       address,
+							AddressFamily: 4,
+							Public:        true,
+							Management:    true,
+						},
+					}},
+				}
+				return d, nil, nil
+			},
+		},
+	}
+
+	details := func(address string) managed.ConnectionDetails {
+		return managed.ConnectionDetails{
+			devicesclient.ConnectionPublicIPv4Key:     []byte(address),
+			"publicIPv4-0":                            []byte(address),
+			xpv1.ResourceCredentialsSecretEndpointKey: []byte(address),
+			xpv1.ResourceCredentialsSecretUserKey:     []byte("root"),
+			xpv1.ResourceCredentialsSecretPortKey:     []byte("22"),
+		}
+	}
+
+	mg := device()
+	for _, want := range []string{"198.51.100.1", "198.51.100.2"} {
+		address = want
+		got, err := e.Observe(context.Background(), mg)
+		if err != nil {
+			t.Fatalf("e.Observe(): %v", err)
+		}
+		if diff := cmp.Diff(details(want), got.ConnectionDetails); diff != "" {
+			t.Errorf("e.Observe(): -want connection details, +got:\n%s", diff)
+		}
+	}
+}
+
+// eventRecorder records the events it receives
+type eventRecorder struct {
+	events []event.Event
+}
+
+func (r *eventRecorder) Event(_ runtime.Object, e event.Event) { r.events = append(r.events, e) }
+
+func (r *eventRecorder) WithAnnotations(_ ...string) event.Recorder { return r }
+
+// except returns the recorded events other than those of the supplied reason
+func (r *eventRecorder) except(reason event.Reason) []event.Event {
+	var events []event.Event
+	for _, e := range r.events {
+		if e.Reason != reason {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+func TestObserveFacilityMigrated(t *testing.T) {
+	cases := map[string]struct {
+		facility   string
+		facilities []string
+		want       []event.Event
+	}{
+		"Migrated": {
+			facility: "sv15",
+			want: []event.Event{
+				event.Warning(reasonFacilityMigrated, errors.Errorf(errFacilityMigratedFmt, "sv15", "da11")),
+			},
+		},
+		"NotMigrated": {
+			facility: "da11",
+		},
+		"AnyFacility": {
+			facility: v1alpha2.FacilityAny,
+		},
+		"MigratedFromFacilities": {
+			facilities: []string{"sv15", "ny5"},
+			want: []event.Event{
+				event.Warning(reasonFacilityMigrated, errors.Errorf(errFacilityMigratedFmt, "sv15, ny5", "da11")),
+			},
+		},
+		"InFacilities": {
+			facilities: []string{"sv15", "da11"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			record := &eventRecorder{}
+			e := &external{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				client: &fake.MockClient{
+					MockGetIQN: noIQN,
+					MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+						d := &packngo.Device{
+							State:     v1alpha2.StateActive,
+							AlwaysPXE: *alwaysPXE,
+							Facility:  &packngo.Facility{Code: "da11"},
+						}
+						return d, nil, nil
+					},
+				},
+				record: record,
+			}
+
+			o, err := e.Observe(context.Background(), device(withLocation(tc.facility, ""), withFacilities(tc.facilities...)))
+			if err != nil {
+				t.Fatalf("e.Observe(): %v", err)
+			}
+			if !o.ResourceUpToDate {
+				t.Errorf("e.Observe(): want up to date despite facility %q", tc.facility)
+			}
+			if diff := cmp.Diff(tc.want, record.except(reasonLateInitialized)); diff != "" {
+				t.Errorf("e.Observe(): -want events, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestObserveUnsupportedNetworkType(t *testing.T) {
+	record := &eventRecorder{}
+	e := &external{
+		kube: &test.MockClient{
+			MockUpdate: test.NewMockUpdateFn(nil),
+		},
+		client: &fake.MockClient{
+			MockGetIQN: noIQN,
+			MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+				d := &packngo.Device{
+					State:     v1alpha2.StateActive,
+					AlwaysPXE: *alwaysPXE,
+					Plan:      &packngo.Plan{Slug: "baremetal_0"},
+				}
+				return d, nil, nil
+			},
+		},
+		record: record,
+	}
+
+	// The Device is not up to date so that Update reports the network type
+	// the plan does not support
+	to := packngo.NetworkTypeL2Bonded
+	o, err := e.Observe(context.Background(), device(withNetworkType(&to)))
+	if err != nil {
+		t.Fatalf("e.Observe(): %v", err)
+	}
+	if o.ResourceUpToDate {
+		t.Errorf("e.Observe(): want not up to date with an unsupported network type")
+	}
+	want := []event.Event{
+		event.Normal(reasonNotUpToDate, fmt.Sprintf(msgNotUpToDateFmt, devicesclient.FieldNetworkType)),
+	}
+	if diff := cmp.Diff(want, record.except(reasonLateInitialized)); diff != "" {
+		t.Errorf("e.Observe(): -want events, +got:\n%s", diff)
+	}
+}
+
+func TestObserveNotUpToDate(t *testing.T) {
+	cases := map[string]struct {
+		device *packngo.Device
+		want   []event.Event
+	}{
+		"UpToDate": {
+			device: &packngo.Device{
+				State:     v1alpha2.StateActive,
+				Hostname:  "managed",
+				AlwaysPXE: *alwaysPXE,
+				Tags:      []string{"managed"},
+			},
+		},
+		"Outdated": {
+			device: &packngo.Device{
+				State:     v1alpha2.StateActive,
+				Hostname:  "outdated",
+				AlwaysPXE: *alwaysPXE,
+				Tags:      []string{"outdated"},
+			},
+			want: []event.Event{
+				event.Normal(reasonNotUpToDate, fmt.Sprintf(msgNotUpToDateFmt, "hostname, tags")),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			record := &eventRecorder{}
+			e := &external{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				client: &fake.MockClient{
+					MockGetIQN: noIQN,
+					MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+						return tc.device, nil, nil
+					},
+				},
+				record: record,
+			}
+
+			o, err := e.Observe(context.Background(), device(withHostname("managed"), withTags("managed")))
+			if err != nil {
+				t.Fatalf("e.Observe(): %v", err)
+			}
+			if o.ResourceUpToDate != (tc.want == nil) {
+				t.Errorf("e.Observe(): want up to date %t, got %t", tc.want == nil, o.ResourceUpToDate)
+			}
+			if diff := cmp.Diff(tc.want, record.except(reasonLateInitialized)); diff != "" {
+				t.Errorf("e.Observe(): -want events, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestObserveLateInitialized(t *testing.T) {
+	observed := &packngo.Device{
+		State:        v1alpha2.StateActive,
+		Hostname:     "managed",
+		BillingCycle: "hourly",
+		AlwaysPXE:    *alwaysPXE,
+		Tags:         []string{"managed"},
+	}
+	hostname, billingCycle, observedNetworkType, empty := observed.Hostname, observed.BillingCycle, observed.GetNetworkType(), ""
+	locked := false
+	initialized := func(i *v1alpha2.Device) {
+		i.Spec.ForProvider.Hostname = &hostname
+		i.Spec.ForProvider.BillingCycle = &billingCycle
+		i.Spec.ForProvider.NetworkType = &observedNetworkType
+		i.Spec.ForProvider.IPXEScriptURL = &empty
+		i.Spec.ForProvider.UserData = &empty
+		i.Spec.ForProvider.Locked = &locked
+		i.Spec.ForProvider.Tags = []string{"managed"}
+	}
+	cases := map[string]struct {
+		device *v1alpha2.Device
+		want   []event.Event
+	}{
+		"LateInitialized": {
+			device: device(withHostname("managed"), withTags("managed")),
+			want: []event.Event{
+				event.Normal(reasonLateInitialized, fmt.Sprintf(msgLateInitializedFmt, "networkType, billingCycle, ipxeScriptUrl, userdata, locked")),
+			},
+		},
+		"AlreadyInitialized": {
+			device: device(initialized),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var updates int
+			record := &eventRecorder{}
+			e := &external{
+				kube: &test.MockClient{
+					MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+						updates++
+						return nil
+					},
+				},
+				client: &fake.MockClient{
+					MockGetIQN: noIQN,
+					MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+						return observed, nil, nil
+					},
+				},
+				record: record,
+			}
+
+			if _, err := e.Observe(context.Background(), tc.device); err != nil {
+				t.Fatalf("e.Observe(): %v", err)
+			}
+			if diff := cmp.Diff(tc.want, record.except(reasonNotUpToDate)); diff != "" {
+				t.Errorf("e.Observe(): -want events, +got:\n%s", diff)
+			}
+			if updates != len(tc.want) {
+				t.Errorf("e.Observe(): want %d updates, got %d", len(tc.want), updates)
+			}
+		})
+	}
+}
+
+func TestObserveFailed(t *testing.T) {
+	state := v1alpha2.StateFailed
+	var reinstalls int
+	record := &eventRecorder{}
+	e := &external{
+		kube: &test.MockClient{
+			MockUpdate: test.NewMockUpdateFn(nil),
+		},
+		client: &fake.MockClient{
+			MockGetIQN: noIQN,
+			MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+				return &packngo.Device{State: state, AlwaysPXE: *alwaysPXE}, nil, nil
+			},
+			MockUpdate: func(deviceID string, updateRequest *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
+				return &packngo.Device{}, nil, nil
+			},
+			MockReinstall: func(deviceID string, reinstallRequest *devicesclient.ReinstallRequest) (*packngo.Response, error) {
+				reinstalls++
+				return nil, nil
+			},
+		},
+		record: record,
+	}
+
+	threshold := 2
+	d := device(func(i *v1alpha2.Device) {
+		i.Spec.ForProvider.FailurePolicy = &v1alpha2.FailurePolicy{
+			Threshold: &threshold,
+			Action:    v1alpha2.FailureActionReinstall,
+		}
+	})
+
+	// The first failed observation is reported, the device is up to date
+	o, err := e.Observe(context.Background(), d)
+	if err != nil {
+		t.Fatalf("e.Observe(): %v", err)
+	}
+	if !o.ResourceUpToDate || d.Status.AtProvider.FailedObservations != 1 {
+		t.Errorf("e.Observe(): want up to date with 1 failure, got %t with %d", o.ResourceUpToDate, d.Status.AtProvider.FailedObservations)
+	}
+
+	// The threshold is reached, the device must be reinstalled
+	o, err = e.Observe(context.Background(), d)
+	if err != nil {
+		t.Fatalf("e.Observe(): %v", err)
+	}
+	if o.ResourceUpToDate || d.Status.AtProvider.FailedObservations != 2 {
+		t.Errorf("e.Observe(): want not up to date with 2 failures, got %t with %d", o.ResourceUpToDate, d.Status.AtProvider.FailedObservations)
+	}
+	want := []event.Event{
+		event.Warning(reasonFailed, errors.New(errDeviceFailed)),
+		event.Warning(reasonFailedPersistent, errors.Errorf(errDeviceFailedFmt, 2)),
+	}
+	if diff := cmp.Diff(want, record.except(reasonLateInitialized), test.EquateErrors()); diff != "" {
+		t.Errorf("e.Observe(): -want events, +got:\n%s", diff)
+	}
+
+	if _, err := e.Update(context.Background(), d); err != nil {
+		t.Fatalf("e.Update(): %v", err)
+	}
+	if reinstalls != 1 || d.Status.AtProvider.FailedObservations != 0 {
+		t.Errorf("e.Update(): want 1 reinstall and no failures, got %d with %d", reinstalls, d.Status.AtProvider.FailedObservations)
+	}
+
+	// Recovered devices are no longer counted as failed
+	state = v1alpha2.StateActive
+	if _, err := e.Observe(context.Background(), d); err != nil {
+		t.Fatalf("e.Observe(): %v", err)
+	}
+	if d.Status.AtProvider.FailedObservations != 0 {
+		t.Errorf("e.Observe(): want no failures, got %d", d.Status.AtProvider.FailedObservations)
+	}
+}
+
+func TestObserveIQN(t *testing.T) {
+	iqn := "iqn.2021-01.net.packet:device.abcd1234"
+	state := v1alpha2.StateProvisioning
+	var lookups int
+	e := &external{
+		kube: &test.MockClient{
+			MockUpdate: test.NewMockUpdateFn(nil),
+		},
+		client: &fake.MockClient{
+			MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+				return &packngo.Device{State: state, AlwaysPXE: *alwaysPXE}, nil, nil
+			},
+			MockGetIQN: func(deviceID string) (string, *packngo.Response, error) {
+				lookups++
+				return iqn, nil, nil
+			},
+		},
+		record: event.NewNopRecorder(),
+	}
+	d := device()
+
+	// The IQN is not looked up until the device is active
+	if _, err := e.Observe(context.Background(), d); err != nil {
+		t.Fatalf("e.Observe(): %v", err)
+	}
+	if lookups != 0 || d.Status.AtProvider.IQN != "" {
+		t.Errorf("e.Observe(): want no IQN while provisioning, got %q after %d lookups", d.Status.AtProvider.IQN, lookups)
+	}
+
+	// The IQN is looked up once and kept by later observations
+	state = v1alpha2.StateActive
+	for i := 0; i < 2; i++ {
+		if _, err := e.Observe(context.Background(), d); err != nil {
+			t.Fatalf("e.Observe(): %v", err)
+		}
+	}
+	if lookups != 1 || d.Status.AtProvider.IQN != iqn {
+		t.Errorf("e.Observe(): want IQN %q after 1 lookup, got %q after %d lookups", iqn, d.Status.AtProvider.IQN, lookups)
+	}
+}
+
+func TestObserveNetworkAttachments(t *testing.T) {
+	var ports []packngo.Port
+	var lookups int
+	e := &external{
+		kube: &test.MockClient{
+			MockUpdate: test.NewMockUpdateFn(nil),
+		},
+		client: &fake.MockClient{
+			MockGetIQN: noIQN,
+			MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+				return &packngo.Device{State: v1alpha2.StateActive, AlwaysPXE: *alwaysPXE, NetworkPorts: ports}, nil, nil
+			},
+			MockGetNetworkPorts: func(deviceID string) ([]devicesclient.NetworkPort, *packngo.Response, error) {
+				lookups++
+				vrf := devicesclient.AttachedMetalGateway{VRF: &devicesclient.AttachedVRF{ID: "vrf-id"}}
+				return []devicesclient.NetworkPort{
+					{Name: "bond0"},
+					{Name: "eth1", VirtualNetworks: []devicesclient.AttachedVirtualNetwork{
+						{ID: "vlan-a", VXLAN: 1000, MetalGateways: []devicesclient.AttachedMetalGateway{vrf}},
+						{ID: "vlan-b", VXLAN: 1001, AssignedToVirtualCircuit: true},
+					}},
+				}, nil, nil
+			},
+		},
+		record: event.NewNopRecorder(),
+	}
+	d := device()
+
+	// Devices without attached VLANs are not looked up
+	if _, err := e.Observe(context.Background(), d); err != nil {
+		t.Fatalf("e.Observe(): %v", err)
+	}
+	if lookups != 0 || d.Status.AtProvider.NetworkAttachments != nil {
+		t.Errorf("e.Observe(): want no network attachments, got %+v after %d lookups", d.Status.AtProvider.NetworkAttachments, lookups)
+	}
+
+	ports = []packngo.Port{{Name: "eth1", AttachedVirtualNetworks: []packngo.VirtualNetwork{{ID: "vlan-a"}, {ID: "vlan-b"}}}}
+	if _, err := e.Observe(context.Background(), d); err != nil {
+		t.Fatalf("e.Observe(): %v", err)
+	}
+	want := []v1alpha2.DeviceNetworkAttachment{
+		{Port: "eth1", VLANID: "vlan-a", VXLAN: 1000, VRFs: []string{"vrf-id"}},
+		{Port: "eth1", VLANID: "vlan-b", VXLAN: 1001, Interconnection: true},
+	}
+	if diff := cmp.Diff(want, d.Status.AtProvider.NetworkAttachments); diff != "" {
+		t.Errorf("e.Observe(): -want network attachments, +got:\n%s", diff)
+	}
+}
+
+func TestObserveReleaseHardwareReservation(t *testing.T) {
+	errBoom := errors.New("boom")
+	deleted := func(i *v1alpha2.Device) {
+		now := metav1.Now()
+		i.SetDeletionTimestamp(&now)
+	}
+	reserved := func(i *v1alpha2.Device) { i.Status.AtProvider.HardwareReservationID = "reservation" }
+	releasedTo := func(projectID string) deviceModifier {
+		return func(i *v1alpha2.Device) { i.Spec.ForProvider.HardwareReservationReleaseProjectID = &projectID }
+	}
+
+	cases := map[string]struct {
+		mg      *v1alpha2.Device
+		moveErr error
+		moves   []string
+		err     error
+	}{
+		"KeptByDefault": {
+			mg: device(deleted, reserved),
+		},
+		"KeptWhileNotDeleted": {
+			mg: device(reserved, releasedTo("other-project")),
+		},
+		"KeptWithoutReservation": {
+			mg: device(deleted, releasedTo("other-project")),
+		},
+		"Released": {
+			mg:    device(deleted, reserved, releasedTo("other-project")),
+			moves: []string{"reservation/other-project"},
+		},
+		"ReleaseFailed": {
+			mg:      device(deleted, reserved, releasedTo("other-project")),
+			moveErr: errBoom,
+			moves:   []string{"reservation/other-project"},
+			err:     errors.Wrap(errBoom, errReleaseReservation),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var moves []string
+			e := &external{client: &fake.MockClient{
+				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+					return nil, nil, &packngo.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+				},
+				MockMoveHardwareReservation: func(hardwareReservationID, projectID string) (*packngo.HardwareReservation, *packngo.Response, error) {
+					moves = append(moves, hardwareReservationID+"/"+projectID)
+					return &packngo.HardwareReservation{}, nil, tc.moveErr
+				},
+			}}
+
+			o, err := e.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("e.Observe(): -want error, +got error:\n%s", diff)
+			}
+			if o.ResourceExists {
+				t.Errorf("e.Observe(): want ResourceExists false")
+			}
+			if diff := cmp.Diff(tc.moves, moves); diff != "" {
+				t.Errorf("MockMoveHardwareReservation: -want moves, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestObserveHostnameExternalName(t *testing.T) {
+	byHostname := func(i *v1alpha2.Device) {
+		i.SetAnnotations(map[string]string{
+			meta.AnnotationKeyExternalName:            "my-host",
+			clients.AnnotationKeyExternalNameStrategy: string(clients.ExternalNameStrategyHostname),
+		})
+	}
+	withObservedID := func(id string) deviceModifier {
+		return func(i *v1alpha2.Device) { i.Status.AtProvider.ID = id }
+	}
+
+	cases := map[string]struct {
+		mg      *v1alpha2.Device
+		devices []packngo.Device
+		exists  bool
+		id      string
+		err     error
+	}{
+		"FoundByHostname": {
+			mg:      device(byHostname),
+			devices: []packngo.Device{{ID: "other", Hostname: "my-host-2"}, {ID: "found", Hostname: "my-host"}},
+			exists:  true,
+			id:      "found",
+		},
+		"ObservedID": {
+			mg:     device(byHostname, withObservedID("observed")),
+			exists: true,
+			id:     "observed",
+		},
+		"NotFound": {
+			mg:      device(byHostname),
+			devices: []packngo.Device{{ID: "other", Hostname: "my-host-2"}},
+		},
+		"Ambiguous": {
+			mg:      device(byHostname),
+			devices: []packngo.Device{{ID: "first", Hostname: "my-host"}, {ID: "second", Hostname: "my-host"}},
+			err:     errors.Errorf(errHostnameAmbiguousFmt, 2, "my-host"),
+		},
+		"UnknownStrategy": {
+			mg: device(func(i *v1alpha2.Device) {
+				meta.AddAnnotations(i, map[string]string{clients.AnnotationKeyExternalNameStrategy: "Serial"})
+			}),
+			err: errors.New(`unknown external name strategy "Serial"`),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				client: &fake.MockClient{
+					MockGetProjectID: projectIDFromCredentials,
+					MockList: func(projectID string, listOpt *packngo.ListOptions) ([]packngo.Device, *packngo.Response, error) {
+						if tc.devices == nil {
+							t.Errorf("MockList: called for a Device with an observed ID")
+						}
+						if listOpt.Search != "my-host" {
+							t.Errorf("MockList: want search for %q, got %q", "my-host", listOpt.Search)
+						}
+						return tc.devices, nil, nil
+					},
+					MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+						if deviceID != tc.id {
+							t.Errorf("MockGet: want ID %q, got %q", tc.id, deviceID)
+						}
+						return &packngo.Device{ID: deviceID, Hostname: "my-host", State: v1alpha2.StateProvisioning, AlwaysPXE: *alwaysPXE}, nil, nil
+					},
+				},
+				record: event.NewNopRecorder(),
+			}
+
+			o, err := e.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("e.Observe(): -want error, +got error:\n%s", diff)
+			}
+			if o.ResourceExists != tc.exists {
+				t.Errorf("e.Observe(): want exists %t, got %t", tc.exists, o.ResourceExists)
+			}
+			if tc.exists && tc.mg.Status.AtProvider.ID != tc.id {
+				t.Errorf("e.Observe(): want observed ID %q, got %q", tc.id, tc.mg.Status.AtProvider.ID)
+			}
+			if got := meta.GetExternalName(tc.mg); tc.exists && got != "my-host" {
+				t.Errorf("e.Observe(): want external name %q, got %q", "my-host", got)
+			}
+		})
+	}
+}
+
+func TestObserveDescription(t *testing.T) {
+	empty, managed := "", "managed"
+
+	cases := map[string]struct {
+		desired  *string
+		remote   *string
+		upToDate bool
+	}{
+		"NilDesiredNilRemote":        {desired: nil, remote: nil, upToDate: true},
+		"NilDesiredEmptyRemote":      {desired: nil, remote: &empty, upToDate: true},
+		"NilDesiredSetRemote":        {desired: nil, remote: &managed, upToDate: true},
+		"EmptyDesiredNilRemote":      {desired: &empty, remote: nil, upToDate: true},
+		"EmptyDesiredEmptyRemote":    {desired: &empty, remote: &empty, upToDate: true},
+		"EmptyDesiredSetRemote":      {desired: &empty, remote: &managed, upToDate: false},
+		"SetDesiredNilRemote":        {desired: &managed, remote: nil, upToDate: false},
+		"SetDesiredEmptyRemote":      {desired: &managed, remote: &empty, upToDate: false},
+		"SetDesiredEqualRemote":      {desired: &managed, remote: &managed, upToDate: true},
+		"SetDesiredDifferentPointer": {desired: &managed, remote: func() *string { s := "managed"; return &s }(), upToDate: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				client: &fake.MockClient{
+					MockGetIQN: noIQN,
+					MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+						return &packngo.Device{
+							State:       v1alpha2.StateActive,
+							AlwaysPXE:   *alwaysPXE,
+							Description: tc.remote,
+						}, nil, nil
+					},
+				},
+				record: event.NewNopRecorder(),
+			}
+
+			o, err := e.Observe(context.Background(), device(withDescription(tc.desired)))
+			if err != nil {
+				t.Fatalf("e.Observe(): %v", err)
+			}
+			if o.ResourceUpToDate != tc.upToDate {
+				t.Errorf("e.Observe(): want up to date %t, got %t", tc.upToDate, o.ResourceUpToDate)
+			}
+		})
+	}
+}
+
+func TestObserveOnly(t *testing.T) {
+	notFound := &packngo.ErrorResponse{
+		Response: &http.Response{
+			StatusCode: http.StatusNotFound,
+			Request:    &http.Request{Method: http.MethodGet, URL: &url.URL{Path: "/devices/" + deviceName}},
+		},
+	}
+
+	cases := map[string]struct {
+		get  func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error)
+		want managed.ExternalObservation
+		err  error
+	}{
+		"ExistingDevice": {
+			get: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+				// A different hostname would otherwise require an update.
+				return &packngo.Device{
+					State:     v1alpha2.StateActive,
+					Hostname:  "adopted",
+					AlwaysPXE: *alwaysPXE,
+				}, nil, nil
+			},
+			want: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  true,
+				ConnectionDetails: managed.ConnectionDetails{},
+			},
+		},
+		"MissingDevice": {
+			get: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+				return nil, nil, notFound
+			},
+			err: errors.New("cannot observe external resource: it does not exist and is not created when the management policy is ObserveOnly"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := clients.NewManagementPolicyExternal(&external{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				record: event.NewNopRecorder(),
+				client: &fake.MockClient{
+					MockGetIQN: noIQN,
+					MockGet:    tc.get,
+					MockCreate: func(*packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+						t.Errorf("client.Create(...): called for an ObserveOnly Device")
+						return nil, nil, nil
+					},
+					MockUpdate: func(string, *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
+						t.Errorf("client.Update(...): called for an ObserveOnly Device")
+						return nil, nil, nil
+					},
+					MockDelete: func(string, bool) (*packngo.Response, error) {
+						t.Errorf("client.Delete(...): called for an ObserveOnly Device")
+						return nil, nil
+					},
+				},
+			})
+			mg := device(withHostname("managed"), withManagementPolicy(clients.ManagementPolicyObserveOnly))
+
+			o, err := e.Observe(context.Background(), mg)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("e.Observe(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, o); diff != "" {
+				t.Errorf("e.Observe(...): -want, +got:\n%s", diff)
+			}
+
+			if _, err := e.Create(context.Background(), mg); err != nil {
+				t.Errorf("e.Create(...): %v", err)
+			}
+			if _, err := e.Update(context.Background(), mg); err != nil {
+				t.Errorf("e.Update(...): %v", err)
+			}
+			if err := e.Delete(context.Background(), mg); err != nil {
+				t.Errorf("e.Delete(...): %v", err)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	terminationTime := time.Now().Add(time.Hour).Truncate(time.Second)
+	userDataRef := &v1alpha2.DataKeySelector{
+		NamespacedName: v1alpha2.NamespacedName{Namespace: namespace, Name: "cool-userdata"},
+		Kind:           "ConfigMap",
+	}
+	base64UserDataRef := &v1alpha2.DataKeySelector{
+		NamespacedName: v1alpha2.NamespacedName{Namespace: namespace, Name: "cool-userdata"},
+		Kind:           "ConfigMap",
+		Encoding:       v1alpha2.DataEncodingBase64,
+	}
+	// cloudConfig is the userdata both encodings must decode to
+	cloudConfig := "#cloud-config\npackages: [git]"
+	withUserDataEncoding := func(encoding string) deviceModifier {
+		return func(i *v1alpha2.Device) { i.Spec.ForProvider.UserDataEncoding = &encoding }
+	}
+	createdWithUserData := func(userdata string) *fake.MockClient {
+		return &fake.MockClient{
+			MockGetProjectID: projectIDFromCredentials,
+			MockGetMetro:     metroFromCredentials,
+			MockCreate: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+				if diff := cmp.Diff(userdata, createRequest.UserData); diff != "" {
+					t.Errorf("MockCreate: -want userdata, +got:\n%s", diff)
+				}
+				return &packngo.Device{ID: deviceName}, nil, nil
+			},
+		}
+	}
+
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg       resource.Managed
+		creation managed.ExternalCreation
+		err      error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"CreatedInstance": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGetProjectID: projectIDFromCredentials,
+					MockGetMetro:     metroFromCredentials,
+					MockCreate: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+						d := &packngo.Device{
+							ID: deviceName,
+						}
+
+						return d, nil, nil
+					},
+				},
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(),
+			},
+			want: want{
+				mg: device(
+					withConditions(xpv1.Creating()),
+					withID(deviceName),
+				),
+				creation: managed.ExternalCreation{
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"FailedToRecordExternalName": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGetProjectID: projectIDFromCredentials,
+					MockGetMetro:     metroFromCredentials,
+					MockCreate: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+						return &packngo.Device{ID: deviceName}, nil, nil
+					},
+				},
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(errorBoom),
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(),
+			},
+			want: want{
+				mg: device(
+					withConditions(xpv1.Creating()),
+					withID(deviceName),
+				),
+				creation: managed.ExternalCreation{
+					ExternalNameAssigned: true,
+					ConnectionDetails:    managed.ConnectionDetails{},
+				},
+			},
+		},
+		"OversizedUserDataRef": {
+			client: &external{
+				client: &fake.MockClient{},
+				kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+					cm, ok := obj.(*corev1.ConfigMap)
+					if !ok {
+						return errorBoom
+					}
+					cm.Data = map[string]string{"cloud-init": strings.Repeat("a", devicesclient.MaxUserDataSize+1)}
+					return nil
+				}},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withUserDataRef(userDataRef)),
+			},
+			want: want{
+				mg: device(
+					withUserDataRef(userDataRef),
+					withConditions(xpv1.Creating()),
+				),
+				err: errors.Wrap(errors.New(`userdata of ConfigMap cool-namespace/cool-userdata key "cloud-init" is 65537 bytes, larger than the limit of 65536 bytes`), errResolveUserDataRef),
+			},
+		},
+		"UnknownFacility": {
+			client: &external{
+				client: &fake.MockClient{
+					MockListFacilities: func(listOpt *packngo.ListOptions) ([]packngo.Facility, *packngo.Response, error) {
+						return []packngo.Facility{{Code: "sv15"}, {Code: "da11"}}, nil, nil
+					},
+				},
+				facilities: facility.NewCache(time.Hour),
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withLocation("sv51", "")),
+			},
+			want: want{
+				mg: device(
+					withLocation("sv51", ""),
+					withConditions(xpv1.Creating()),
+				),
+				err: errors.Wrap(errors.New(`facility "sv51" does not exist, did you mean "sv15"?`), errInvalidDevice),
+			},
+		},
+		"IncompatibleOperatingSystem": {
+			client: &external{
+				client: &fake.MockClient{
+					MockListOperatingSystems: func() ([]packngo.OS, *packngo.Response, error) {
+						return []packngo.OS{{Slug: operatingSystem, ProvisionableOn: []string{"c3.small.x86", "n3.xlarge.x86"}}}, nil, nil
+					},
+				},
+				operatingSystems: operatingsystem.NewCache(time.Hour),
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(),
+			},
+			want: want{
+				mg: device(
+					withConditions(xpv1.Creating()),
+				),
+				err: errors.Wrap(errors.Errorf(`operating system %q is not available on plan %q, it is available on plans: c3.small.x86, n3.xlarge.x86`, operatingSystem, plan), errInvalidDevice),
+			},
+		},
+		"CreatedWithPlainUserData": {
+			client: &external{
+				client: createdWithUserData(cloudConfig),
+				kube:   &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withUserData(cloudConfig), withUserDataEncoding(v1alpha2.DataEncodingPlain)),
+			},
+			want: want{
+				mg: device(
+					withUserData(cloudConfig),
+					withUserDataEncoding(v1alpha2.DataEncodingPlain),
+					withConditions(xpv1.Creating()),
+					withID(deviceName),
+				),
+				creation: managed.ExternalCreation{
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"CreatedWithBase64UserData": {
+			client: &external{
+				client: createdWithUserData(cloudConfig),
+				kube:   &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withUserData(base64.StdEncoding.EncodeToString([]byte(cloudConfig))), withUserDataEncoding(v1alpha2.DataEncodingBase64)),
+			},
+			want: want{
+				mg: device(
+					withUserData(base64.StdEncoding.EncodeToString([]byte(cloudConfig))),
+					withUserDataEncoding(v1alpha2.DataEncodingBase64),
+					withConditions(xpv1.Creating()),
+					withID(deviceName),
+				),
+				creation: managed.ExternalCreation{
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"CreatedWithBase64UserDataRef": {
+			client: &external{
+				client: createdWithUserData(cloudConfig),
+				kube: &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						cm, ok := obj.(*corev1.ConfigMap)
+						if !ok {
+							return errorBoom
+						}
+						cm.Data = map[string]string{"cloud-init": base64.StdEncoding.EncodeToString([]byte(cloudConfig))}
+						return nil
+					},
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withUserDataRef(base64UserDataRef)),
+			},
+			want: want{
+				mg: device(
+					withUserDataRef(base64UserDataRef),
+					withConditions(xpv1.Creating()),
+					withID(deviceName),
+				),
+				creation: managed.ExternalCreation{
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"InvalidBase64UserData": {
+			client: &external{
+				client: &fake.MockClient{},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withUserData("#cloud-config"), withUserDataEncoding(v1alpha2.DataEncodingBase64)),
+			},
+			want: want{
+				mg: device(
+					withUserData("#cloud-config"),
+					withUserDataEncoding(v1alpha2.DataEncodingBase64),
+					withConditions(xpv1.Creating()),
+				),
+				err: errors.Wrap(errors.Wrap(base64.CorruptInputError(0), "data is not valid base64"), errDecodeUserData),
+			},
+		},
+		"CreatedWithDefaultTags": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGetProjectID: projectIDFromCredentials,
+					MockGetMetro:     metroFromCredentials,
+					MockCreate: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+						want := []string{"web", "owner=team-a", "cluster=prod"}
+						if diff := cmp.Diff(want, createRequest.Tags); diff != "" {
+							t.Errorf("MockCreate: -want tags, +got:\n%s", diff)
+						}
+						return &packngo.Device{ID: deviceName}, nil, nil
+					},
+				},
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				defaultTags: map[string]string{"owner": "platform", "cluster": "prod"},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withTags("web", "owner=team-a")),
+			},
+			want: want{
+				mg: device(
+					withTags("web", "owner=team-a"),
+					withConditions(xpv1.Creating()),
+					withID(deviceName),
+				),
+				creation: managed.ExternalCreation{
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"CreatedWithHostnameExternalName": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGetProjectID: projectIDFromCredentials,
+					MockGetMetro:     metroFromCredentials,
+					MockCreate: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+						return &packngo.Device{ID: "device-id", Hostname: createRequest.Hostname}, nil, nil
+					},
+				},
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: device(withHostname("my-host"), func(i *v1alpha2.Device) {
+					meta.AddAnnotations(i, map[string]string{clients.AnnotationKeyExternalNameStrategy: string(clients.ExternalNameStrategyHostname)})
+				}),
+			},
+			want: want{
+				mg: device(withHostname("my-host"), withConditions(xpv1.Creating()), func(i *v1alpha2.Device) {
+					meta.AddAnnotations(i, map[string]string{clients.AnnotationKeyExternalNameStrategy: string(clients.ExternalNameStrategyHostname)})
+					meta.SetExternalName(i, "my-host")
+					i.Status.AtProvider.ID = "device-id"
+				}),
+				creation: managed.ExternalCreation{
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"CreatedWithDefaultHostname": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGetProjectID: projectIDFromCredentials,
+					MockGetMetro:     metroFromCredentials,
+					MockCreate: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+						if diff := cmp.Diff(deviceName, createRequest.Hostname); diff != "" {
+							t.Errorf("MockCreate: -want hostname, +got:\n%s", diff)
+						}
+						return &packngo.Device{ID: deviceName}, nil, nil
+					},
+				},
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(),
+			},
+			want: want{
+				mg: device(
+					withConditions(xpv1.Creating()),
+					withID(deviceName),
+				),
+				creation: managed.ExternalCreation{
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"DuplicateHostname": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGetProjectID: projectIDFromCredentials,
+					MockGetMetro:     metroFromCredentials,
+					MockList: func(projectID string, listOpt *packngo.ListOptions) ([]packngo.Device, *packngo.Response, error) {
+						if listOpt.Search != deviceName {
+							t.Errorf("MockList: unexpected search %q", listOpt.Search)
+						}
+						return []packngo.Device{
+							{ID: "other-device", Hostname: deviceName + "-2"},
+							{ID: "existing-device", Hostname: deviceName},
+						}, nil, nil
+					},
+					MockCreate: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+						t.Errorf("MockCreate: called for a duplicate hostname")
+						return nil, nil, nil
+					},
+				},
+				uniqueHostnames: true,
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(),
+			},
+			want: want{
+				mg:  device(withConditions(xpv1.Creating())),
+				err: errors.Errorf(errDuplicateHostnameFmt, deviceName, "existing-device"),
+			},
+		},
+		"CreatedInFacilities": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGetProjectID: projectIDFromCredentials,
+					MockGetMetro: func(string) string {
+						t.Errorf("MockGetMetro: credential defaults used with facilities")
+						return ""
+					},
+					MockCreate: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+						if diff := cmp.Diff([]string{"sv15", "da11"}, createRequest.Facility); diff != "" {
+							t.Errorf("MockCreate: -want facilities, +got:\n%s", diff)
+						}
+						if createRequest.Metro != "" {
+							t.Errorf("MockCreate: want no metro, got %q", createRequest.Metro)
+						}
+						return &packngo.Device{ID: deviceName}, nil, nil
+					},
+				},
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withFacilities("sv15", "da11")),
+			},
+			want: want{
+				mg: device(
+					withFacilities("sv15", "da11"),
+					withConditions(xpv1.Creating()),
+					withID(deviceName),
+				),
+				creation: managed.ExternalCreation{
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"InvalidHostname": {
+			client: &external{client: &fake.MockClient{
+				MockGetProjectID: projectIDFromCredentials,
+				MockGetMetro:     metroFromCredentials,
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withHostname("not_valid")),
+			},
+			want: want{
+				mg:  device(withHostname("not_valid"), withConditions(xpv1.Creating())),
+				err: errors.Wrap(errors.New(`hostname "not_valid" must be at most 253 characters of dot separated labels of letters, digits and hyphens that do not begin or end with a hyphen`), errInvalidDevice),
+			},
+		},
+		"CreatedWithTerminationTime": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGetProjectID: projectIDFromCredentials,
+					MockGetMetro:     metroFromCredentials,
+					MockCreate: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+						if createRequest.TerminationTime == nil || !createRequest.TerminationTime.Time.Equal(terminationTime) {
+							t.Errorf("MockCreate: want termination time %s, got %v", terminationTime, createRequest.TerminationTime)
+						}
+						return &packngo.Device{ID: deviceName}, nil, nil
+					},
+				},
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withTerminationTime(terminationTime)),
+			},
+			want: want{
+				mg: device(
+					withTerminationTime(terminationTime),
+					withConditions(xpv1.Creating()),
+					withID(deviceName),
+				),
+				creation: managed.ExternalCreation{
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"PastTerminationTime": {
+			client: &external{client: &fake.MockClient{
+				MockGetProjectID: projectIDFromCredentials,
+				MockGetMetro:     metroFromCredentials,
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withTerminationTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))),
+			},
+			want: want{
+				mg: device(
+					withTerminationTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+					withConditions(xpv1.Creating()),
+				),
+				err: errors.Wrap(errors.New("termination time 2020-01-01T00:00:00Z must be in the future"), errInvalidDevice),
+			},
+		},
+		"CreatedWithFeatures": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGetProjectID: projectIDFromCredentials,
+					MockGetMetro:     metroFromCredentials,
+					MockCreate: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+						if diff := cmp.Diff(map[string]string{"tpm": "required"}, createRequest.Features); diff != "" {
+							t.Errorf("MockCreate: -want features, +got:\n%s", diff)
+						}
+						return &packngo.Device{ID: deviceName}, nil, nil
+					},
+				},
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withFeatures(map[string]string{"tpm": "required"})),
+			},
+			want: want{
+				mg: device(
+					withFeatures(map[string]string{"tpm": "required"}),
+					withConditions(xpv1.Creating()),
+					withID(deviceName),
+				),
+				creation: managed.ExternalCreation{
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"UnknownFeature": {
+			client: &external{client: &fake.MockClient{
+				MockGetProjectID: projectIDFromCredentials,
+				MockGetMetro:     metroFromCredentials,
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withFeatures(map[string]string{"tpm": "required", "gpu": "preferred"})),
+			},
+			want: want{
+				mg: device(
+					withFeatures(map[string]string{"tpm": "required", "gpu": "preferred"}),
+					withConditions(xpv1.Creating()),
+				),
+				err: errors.Wrap(errors.New(`feature "gpu" is not supported, use one of "tpm", "raid" or "txt"`), errInvalidDevice),
+			},
+		},
+		"InvalidFeatureRequirement": {
+			client: &external{client: &fake.MockClient{
+				MockGetProjectID: projectIDFromCredentials,
+				MockGetMetro:     metroFromCredentials,
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withFeatures(map[string]string{"tpm": "optional"})),
+			},
+			want: want{
+				mg: device(
+					withFeatures(map[string]string{"tpm": "optional"}),
+					withConditions(xpv1.Creating()),
+				),
+				err: errors.Wrap(errors.New(`feature "tpm" must be "required" or "preferred", not "optional"`), errInvalidDevice),
+			},
+		},
+		"NotDevice": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
 			},
 			want: want{
 				mg:  &strange{},
@@ -682,6 +2342,7 @@ func TestCreate(t *testing.T) {
 		"FailedToCreateDevice": {
 			client: &external{client: &fake.MockClient{
 				MockGetProjectID: projectIDFromCredentials,
+				MockGetMetro:     metroFromCredentials,
 				MockCreate: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
 					return nil, nil, errorBoom
 				},
@@ -696,6 +2357,90 @@ func TestCreate(t *testing.T) {
 				err: errors.Wrap(errorBoom, errCreateDevice),
 			},
 		},
+		"RejectedDevice": {
+			client: &external{client: &fake.MockClient{
+				MockGetProjectID: projectIDFromCredentials,
+				MockGetMetro:     metroFromCredentials,
+				MockCreate: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+					return nil, nil, errUnprocessable
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(),
+			},
+			want: want{
+				mg:  device(withConditions(xpv1.Creating().WithMessage(errCreateDeviceRejected))),
+				err: errors.Wrap(errUnprocessable, errCreateDeviceRejected),
+			},
+		},
+		"ConflictingHardwareReservation": {
+			client: &external{client: &fake.MockClient{
+				MockGetHardwareReservation: func(hardwareReservationID string) (*packngo.HardwareReservation, *packngo.Response, error) {
+					return &packngo.HardwareReservation{
+						ID:   hardwareReservationID,
+						Plan: packngo.Plan{Slug: "c3.small.x86"},
+					}, nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withHardwareReservationID("reservation")),
+			},
+			want: want{
+				mg: device(
+					withHardwareReservationID("reservation"),
+					withConditions(xpv1.Creating()),
+				),
+				err: errors.Wrap(errors.New(`hardware reservation reservation is for plan "c3.small.x86", not "m3.large.x86"`), errReservationConflict),
+			},
+		},
+		"ConflictingLocation": {
+			client: &external{client: &fake.MockClient{}},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withLocation("sv15", "sv")),
+			},
+			want: want{
+				mg: device(
+					withLocation("sv15", "sv"),
+					withConditions(xpv1.Creating()),
+				),
+				err: errors.Wrap(errors.New(`facility "sv15" and metro "sv" can not both be set`), errInvalidDevice),
+			},
+		},
+		"InvalidIPXEScriptURL": {
+			client: &external{client: &fake.MockClient{}},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withIPXEScriptURL("https://example.com/boot.ipxe")),
+			},
+			want: want{
+				mg: device(
+					withIPXEScriptURL("https://example.com/boot.ipxe"),
+					withConditions(xpv1.Creating()),
+				),
+				err: errors.Wrap(errors.New(`ipxeScriptUrl may only be set when operatingSystem is "custom_ipxe", not "ubuntu_20_04"`), errInvalidDevice),
+			},
+		},
+		"FailedToGetHardwareReservation": {
+			client: &external{client: &fake.MockClient{
+				MockGetHardwareReservation: func(hardwareReservationID string) (*packngo.HardwareReservation, *packngo.Response, error) {
+					return nil, nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withHardwareReservationID("reservation")),
+			},
+			want: want{
+				mg: device(
+					withHardwareReservationID("reservation"),
+					withConditions(xpv1.Creating()),
+				),
+				err: errors.Wrap(errorBoom, errGetReservation),
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -717,17 +2462,218 @@ func TestCreate(t *testing.T) {
 	}
 }
 
+func TestCreateAfterLostExternalName(t *testing.T) {
+	// devices are those created through the fake, which lists them by tag
+	var devices []packngo.Device
+	var kubeErr error
+	e := &external{
+		client: &fake.MockClient{
+			MockGetProjectID: projectIDFromCredentials,
+			MockGetMetro:     metroFromCredentials,
+			MockList: func(projectID string, listOpt *packngo.ListOptions) ([]packngo.Device, *packngo.Response, error) {
+				return devices, nil, nil
+			},
+			MockCreate: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+				d := packngo.Device{ID: fmt.Sprintf("device-%d", len(devices)), Hostname: createRequest.Hostname, Tags: createRequest.Tags}
+				devices = append(devices, d)
+				return &d, nil, nil
+			},
+		},
+		kube: &test.MockClient{
+			MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error { return kubeErr },
+		},
+	}
+
+	d := device(withTags("web"))
+	d.SetUID("uid")
+	meta.SetExternalName(d, "")
+
+	// The reconcile fails to record the external name of the created device,
+	// and asks the reconciler to record it
+	kubeErr = errorBoom
+	creation, err := e.Create(context.Background(), d)
+	if err != nil {
+		t.Fatalf("e.Create(): %v", err)
+	}
+	if !creation.ExternalNameAssigned {
+		t.Errorf("e.Create(): want external name assigned")
+	}
+	if diff := cmp.Diff("device-0", meta.GetExternalName(d)); diff != "" {
+		t.Errorf("e.Create(): -want external name, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"web", "crossplane-uid=uid"}, devices[0].Tags); diff != "" {
+		t.Errorf("e.Create(): -want tags, +got:\n%s", diff)
+	}
+
+	// The external name is lost when the reconciler also fails to record it.
+	// The retried reconcile adopts the device rather than creating another,
+	// returning the error when it too fails to record the external name
+	d = device(withTags("web"))
+	d.SetUID("uid")
+	meta.SetExternalName(d, "")
+	_, err = e.Create(context.Background(), d)
+	if diff := cmp.Diff(errors.Wrap(errorBoom, errManagedUpdateFailed), err, test.EquateErrors()); diff != "" {
+		t.Errorf("e.Create(): -want error, +got error:\n%s", diff)
+	}
+	if len(devices) != 1 {
+		t.Errorf("e.Create(): want 1 device created, got %d", len(devices))
+	}
+
+	kubeErr = nil
+	d = device(withTags("web"))
+	d.SetUID("uid")
+	meta.SetExternalName(d, "")
+	if _, err := e.Create(context.Background(), d); err != nil {
+		t.Fatalf("e.Create(): %v", err)
+	}
+	if len(devices) != 1 {
+		t.Errorf("e.Create(): want 1 device created, got %d", len(devices))
+	}
+	if diff := cmp.Diff("device-0", meta.GetExternalName(d)); diff != "" {
+		t.Errorf("e.Create(): -want external name, +got:\n%s", diff)
+	}
+
+	// The tag is not part of the desired tags of the device
+	if diff := devicesclient.Diff(d, &devices[0]); diff.Has(devicesclient.FieldTags) {
+		t.Errorf("Diff(...): want tags up to date, got %s", diff)
+	}
+
+	// A device terminated by the spot market no longer exists, so another
+	// device is created rather than the terminated device being adopted
+	devices[0].SpotInstance = true
+	devices[0].State = v1alpha2.StateDeprovisioning
+	d = device(withTags("web"))
+	d.SetUID("uid")
+	meta.SetExternalName(d, "")
+	if _, err := e.Create(context.Background(), d); err != nil {
+		t.Fatalf("e.Create(): %v", err)
+	}
+	if len(devices) != 2 {
+		t.Errorf("e.Create(): want 2 devices created, got %d", len(devices))
+	}
+	if diff := cmp.Diff("device-1", meta.GetExternalName(d)); diff != "" {
+		t.Errorf("e.Create(): -want external name, +got:\n%s", diff)
+	}
+}
+
+func TestCreateIPReservation(t *testing.T) {
+	reservation := func(name, id string, cidr int, addresses ...string) ipv1alpha1.Reservation {
+		r := ipv1alpha1.Reservation{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"pool": name}}}
+		r.Status.AtProvider = ipv1alpha1.ReservationObservation{ID: id, CIDR: cidr, Addresses: addresses}
+		return r
+	}
+	reservations := []ipv1alpha1.Reservation{
+		reservation("available", "reservation-id", 29, "198.51.100.0/31"),
+		reservation("exhausted", "exhausted-id", 31, "198.51.100.8/31"),
+		reservation("requested", "", 0),
+	}
+	withRef := func(name string) deviceModifier {
+		return func(d *v1alpha2.Device) { d.Spec.ForProvider.IPReservationRef = &xpv1.Reference{Name: name} }
+	}
+	withSelector := func(name string) deviceModifier {
+		return func(d *v1alpha2.Device) {
+			d.Spec.ForProvider.IPReservationSelector = &xpv1.Selector{MatchLabels: map[string]string{"pool": name}}
+		}
+	}
+
+	cases := map[string]struct {
+		mg   *v1alpha2.Device
+		want []packngo.IPAddressCreateRequest
+		err  error
+	}{
+		"Referenced": {
+			mg: device(withRef("available")),
+			want: []packngo.IPAddressCreateRequest{
+				{AddressFamily: 4, Public: true, Reservations: []string{"reservation-id"}},
+				{AddressFamily: 4, Public: false},
+				{AddressFamily: 6, Public: true},
+			},
+		},
+		"Selected": {
+			mg: device(withSelector("available")),
+			want: []packngo.IPAddressCreateRequest{
+				{AddressFamily: 4, Public: true, Reservations: []string{"reservation-id"}},
+				{AddressFamily: 4, Public: false},
+				{AddressFamily: 6, Public: true},
+			},
+		},
+		"NoneSelected": {
+			mg:  device(withSelector("missing")),
+			err: errors.Wrap(errors.New(errNoIPReservationSelected), errResolveIPReservation),
+		},
+		"Exhausted": {
+			mg:  device(withRef("exhausted")),
+			err: errors.Wrap(errors.New(`IP reservation "exhausted" (exhausted-id) has no unassigned addresses`), errUseIPReservation),
+		},
+		"NotReady": {
+			mg:  device(withRef("requested")),
+			err: errors.Wrap(errors.New(`IP reservation "requested" has not been created yet`), errUseIPReservation),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []packngo.IPAddressCreateRequest
+			e := &external{
+				client: &fake.MockClient{
+					MockGetProjectID: projectIDFromCredentials,
+					MockGetMetro:     metroFromCredentials,
+					MockCreate: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+						got = createRequest.IPAddresses
+						return &packngo.Device{ID: deviceName}, nil, nil
+					},
+				},
+				kube: &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						for _, r := range reservations {
+							if r.GetName() == key.Name {
+								r.DeepCopyInto(obj.(*ipv1alpha1.Reservation))
+								return nil
+							}
+						}
+						return errorBoom
+					},
+					MockList: func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+						o := &client.ListOptions{}
+						o.ApplyOptions(opts)
+						l := list.(*ipv1alpha1.ReservationList)
+						for _, r := range reservations {
+							if o.LabelSelector.Matches(labels.Set(r.GetLabels())) {
+								l.Items = append(l.Items, r)
+							}
+						}
+						return nil
+					},
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+			}
+
+			_, err := e.Create(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("e.Create(): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("e.Create(): -want IP addresses, +got IP addresses:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestUpdate(t *testing.T) {
 	type args struct {
 		ctx context.Context
 		mg  resource.Managed
 	}
 	type want struct {
-		mg     resource.Managed
-		update managed.ExternalUpdate
-		err    error
+		mg      resource.Managed
+		update  managed.ExternalUpdate
+		err     error
+		updates int
 	}
 
+	// updates counts the calls made to MockUpdate by cases that track them
+	var updates int
+
 	cases := map[string]struct {
 		client managed.ExternalClient
 		args   args
@@ -739,58 +2685,206 @@ func TestUpdate(t *testing.T) {
 					return &packngo.Device{}, nil, nil
 				},
 				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
-					return &packngo.Device{}, nil, nil
+					return &packngo.Device{}, nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(),
+			},
+			want: want{
+				mg: device(withConditions()),
+			},
+		},
+		"UpdatedInstanceNetworkType": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+					d := &packngo.Device{}
+					target := packngo.NetworkTypeHybrid
+					d.Network = mockNetworkTypeConfigs[target].Network
+					d.NetworkPorts = mockNetworkTypeConfigs[target].NetworkPorts
+
+					return d, nil, nil
+				},
+				MockDeviceToNetworkType: func(deviceID string, networkType string) (*packngo.Device, error) {
+					return nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withNetworkType(&networkType)),
+			},
+			want: want{
+				mg: device(withNetworkType(&networkType), withConditions()),
+			},
+		},
+		"UpdatedInstance": {
+			client: &external{client: &fake.MockClient{
+				MockUpdate: func(deviceID string, createRequest *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
+					return &packngo.Device{}, nil, nil
+				},
+				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+					d := &packngo.Device{
+						AlwaysPXE: false,
+					}
+
+					return d, nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(),
+			},
+			want: want{
+				mg: device(withConditions()),
+			},
+		},
+		"UpdatedInstanceTags": {
+			client: &external{client: &fake.MockClient{
+				MockUpdate: func(deviceID string, updateRequest *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
+					updates++
+					if diff := cmp.Diff(&[]string{"b", "a", "c"}, updateRequest.Tags); diff != "" {
+						t.Errorf("MockUpdate(...): -want tags, +got tags:\n%s", diff)
+					}
+					return &packngo.Device{}, nil, nil
+				},
+				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+					return &packngo.Device{AlwaysPXE: *alwaysPXE, Tags: []string{"a", "b"}}, nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withTags("b", "a", "c")),
+			},
+			want: want{
+				mg:      device(withTags("b", "a", "c")),
+				updates: 1,
+			},
+		},
+		"UpdatedInstanceTagsKeepsCreateTag": {
+			client: &external{client: &fake.MockClient{
+				MockUpdate: func(deviceID string, updateRequest *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
+					updates++
+					if diff := cmp.Diff(&[]string{"b", "crossplane-uid=uid"}, updateRequest.Tags); diff != "" {
+						t.Errorf("MockUpdate(...): -want tags, +got tags:\n%s", diff)
+					}
+					return &packngo.Device{}, nil, nil
+				},
+				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+					return &packngo.Device{AlwaysPXE: *alwaysPXE, Tags: []string{"a", "crossplane-uid=uid"}}, nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withTags("b")),
+			},
+			want: want{
+				mg:      device(withTags("b")),
+				updates: 1,
+			},
+		},
+		"UpdatedInstanceHostname": {
+			client: &external{client: &fake.MockClient{
+				MockUpdate: func(deviceID string, updateRequest *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
+					updates++
+					if diff := cmp.Diff("renamed", *updateRequest.Hostname); diff != "" {
+						t.Errorf("MockUpdate(...): -want hostname, +got hostname:\n%s", diff)
+					}
+					return &packngo.Device{}, nil, nil
+				},
+				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+					return &packngo.Device{AlwaysPXE: *alwaysPXE, Hostname: "original"}, nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withHostname("renamed")),
+			},
+			want: want{
+				mg:      device(withHostname("renamed")),
+				updates: 1,
+			},
+		},
+		"ReinstalledInstanceUserData": {
+			client: &external{client: &fake.MockClient{
+				MockUpdate: func(deviceID string, createRequest *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
+					return &packngo.Device{}, nil, nil
+				},
+				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+					return &packngo.Device{AlwaysPXE: *alwaysPXE}, nil, nil
+				},
+				MockReinstall: func(deviceID string, reinstallRequest *devicesclient.ReinstallRequest) (*packngo.Response, error) {
+					return nil, nil
 				},
 			}},
 			args: args{
 				ctx: context.Background(),
-				mg:  device(),
+				mg:  device(withUserData("#cloud-config")),
 			},
 			want: want{
-				mg: device(withConditions()),
+				mg: device(withUserData("#cloud-config"), withConditions(xpv1.Creating()), withLastAction(v1alpha2.DeviceActionReinstall)),
 			},
 		},
-		"UpdatedInstanceNetworkType": {
+		"PoweredOffInstance": {
 			client: &external{client: &fake.MockClient{
+				MockUpdate: func(deviceID string, createRequest *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
+					return &packngo.Device{}, nil, nil
+				},
 				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
-					d := &packngo.Device{}
-					target := packngo.NetworkTypeHybrid
-					d.Network = mockNetworkTypeConfigs[target].Network
-					d.NetworkPorts = mockNetworkTypeConfigs[target].NetworkPorts
-
-					return d, nil, nil
+					return &packngo.Device{State: v1alpha2.StateActive, AlwaysPXE: *alwaysPXE}, nil, nil
 				},
-				MockDeviceToNetworkType: func(deviceID string, networkType string) (*packngo.Device, error) {
+				MockPowerOff: func(deviceID string) (*packngo.Response, error) {
 					return nil, nil
 				},
 			}},
 			args: args{
 				ctx: context.Background(),
-				mg:  device(withNetworkType(&networkType)),
+				mg:  device(withPowerState(v1alpha2.PowerStateOff)),
 			},
 			want: want{
-				mg: device(withNetworkType(&networkType), withConditions()),
+				mg: device(withPowerState(v1alpha2.PowerStateOff), withLastAction(v1alpha2.DeviceActionPowerOff)),
 			},
 		},
-		"UpdatedInstance": {
+		"FailedToPowerOnInstance": {
 			client: &external{client: &fake.MockClient{
 				MockUpdate: func(deviceID string, createRequest *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
 					return &packngo.Device{}, nil, nil
 				},
 				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
-					d := &packngo.Device{
-						AlwaysPXE: false,
-					}
-
-					return d, nil, nil
+					return &packngo.Device{State: v1alpha2.StateInactive, AlwaysPXE: *alwaysPXE}, nil, nil
+				},
+				MockPowerOn: func(deviceID string) (*packngo.Response, error) {
+					return nil, errorBoom
 				},
 			}},
 			args: args{
 				ctx: context.Background(),
-				mg:  device(),
+				mg:  device(withPowerState(v1alpha2.PowerStateOn)),
 			},
 			want: want{
-				mg: device(withConditions()),
+				mg:  device(withPowerState(v1alpha2.PowerStateOn)),
+				err: errors.Wrap(errorBoom, errPowerDevice),
+			},
+		},
+		"FailedToReinstallInstance": {
+			client: &external{client: &fake.MockClient{
+				MockUpdate: func(deviceID string, createRequest *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
+					return &packngo.Device{}, nil, nil
+				},
+				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+					return &packngo.Device{AlwaysPXE: *alwaysPXE}, nil, nil
+				},
+				MockReinstall: func(deviceID string, reinstallRequest *devicesclient.ReinstallRequest) (*packngo.Response, error) {
+					return nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withUserData("#cloud-config")),
+			},
+			want: want{
+				mg:  device(withUserData("#cloud-config")),
+				err: errors.Wrap(errorBoom, errReinstallDevice),
 			},
 		},
 		"NotCloudMemorystoreInstance": {
@@ -823,10 +2917,51 @@ func TestUpdate(t *testing.T) {
 				err: errors.Wrap(errorBoom, errUpdateDevice),
 			},
 		},
+		"SpotTerminationProtectionUnsupported": {
+			client: &external{client: &fake.MockClient{
+				MockUpdate: func(deviceID string, createRequest *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
+					t.Errorf("MockUpdate: called for a Device requesting spot termination protection")
+					return nil, nil, nil
+				},
+				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+					return &packngo.Device{SpotInstance: true}, nil, nil
+				},
+			}},
+
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withSpotTerminationProtection(true)),
+			},
+			want: want{
+				mg:  device(withSpotTerminationProtection(true)),
+				err: errors.Wrap(errors.New(`spotTerminationProtection is not supported by the Equinix Metal API, spot instances are terminated when the spot price exceeds spotPriceMax`), errInvalidDevice),
+			},
+		},
+		"SpotPriceMaxUnsupported": {
+			client: &external{client: &fake.MockClient{
+				MockUpdate: func(deviceID string, createRequest *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
+					t.Errorf("MockUpdate: called for a Device raising its spot price max")
+					return nil, nil, nil
+				},
+				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+					return &packngo.Device{SpotInstance: true, SpotPriceMax: 0.5}, nil, nil
+				},
+			}},
+
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withSpotInstance("0.75")),
+			},
+			want: want{
+				mg:  device(withSpotInstance("0.75")),
+				err: errors.Wrap(errors.New(`spotPriceMax can not be updated from 0.5 to 0.75, the Equinix Metal API does not support changing the bid of a spot instance`), errInvalidDevice),
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
+			updates = 0
 			got, err := tc.client.Update(tc.args.ctx, tc.args.mg)
 
 			if diff := cmp.Diff(tc.want.update, got, test.EquateErrors()); diff != "" {
@@ -837,23 +2972,309 @@ func TestUpdate(t *testing.T) {
 				t.Errorf("tc.client.Update(): -want error, +got error:\n%s", diff)
 			}
 
-			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions(), packettest.EquateQuantities()); diff != "" {
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions(), packettest.EquateQuantities(), ignoreActionTime); diff != "" {
 				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
 			}
+
+			if diff := cmp.Diff(tc.want.updates, updates); diff != "" {
+				t.Errorf("MockUpdate calls: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateNetworkType(t *testing.T) {
+	types := []string{
+		packngo.NetworkTypeL3,
+		packngo.NetworkTypeHybrid,
+		packngo.NetworkTypeL2Individual,
+		packngo.NetworkTypeL2Bonded,
+	}
+
+	// withPorts configures the ports and addresses of a device for the
+	// network type, layer2-individual devices have neither
+	withPorts := func(d *packngo.Device, from string) *packngo.Device {
+		d.Network = mockNetworkTypeConfigs[from].Network
+		d.NetworkPorts = mockNetworkTypeConfigs[from].NetworkPorts
+		return d
+	}
+
+	for _, from := range types {
+		for _, to := range types {
+			if from == to {
+				continue
+			}
+			from, to := from, to
+			t.Run(from+"To"+to, func(t *testing.T) {
+				if got := withPorts(&packngo.Device{}, from).GetNetworkType(); got != from {
+					t.Fatalf("mock device network type: want %q, got %q", from, got)
+				}
+
+				var converted []string
+				e := &external{client: &fake.MockClient{
+					MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+						return withPorts(&packngo.Device{State: v1alpha2.StateActive}, from), nil, nil
+					},
+					MockDeviceToNetworkType: func(deviceID string, networkType string) (*packngo.Device, error) {
+						converted = append(converted, networkType)
+						return withPorts(&packngo.Device{State: v1alpha2.StateActive}, networkType), nil
+					},
+				}}
+
+				if _, err := e.Update(context.Background(), device(withNetworkType(&to))); err != nil {
+					t.Fatalf("e.Update(): %v", err)
+				}
+				if diff := cmp.Diff([]string{to}, converted); diff != "" {
+					t.Errorf("e.Update(): -want conversions, +got:\n%s", diff)
+				}
+			})
+		}
+	}
+
+	t.Run("DeferredWhileProvisioning", func(t *testing.T) {
+		to := packngo.NetworkTypeL3
+		e := &external{client: &fake.MockClient{
+			MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+				return &packngo.Device{State: v1alpha2.StateProvisioning}, nil, nil
+			},
+		}}
+
+		if _, err := e.Update(context.Background(), device(withNetworkType(&to))); err != nil {
+			t.Errorf("e.Update(): %v", err)
+		}
+	})
+
+	t.Run("AttachedVLANsAfterConversion", func(t *testing.T) {
+		to := packngo.NetworkTypeHybrid
+		var assigned []packngo.PortAssignRequest
+		e := &external{client: &fake.MockClient{
+			MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+				d := withPorts(&packngo.Device{State: v1alpha2.StateActive}, to)
+				d.NetworkPorts = append([]packngo.Port{}, d.NetworkPorts...)
+				d.NetworkPorts[2].ID = "eth1-id"
+				d.NetworkPorts[2].AttachedVirtualNetworks = []packngo.VirtualNetwork{{Href: "/virtual-networks/vlan-a"}}
+				return d, nil, nil
+			},
+			MockAssign: func(par *packngo.PortAssignRequest) (*packngo.Port, *packngo.Response, error) {
+				assigned = append(assigned, *par)
+				return &packngo.Port{}, nil, nil
+			},
+			MockUpdate: func(deviceID string, updateRequest *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
+				return &packngo.Device{}, nil, nil
+			},
+		}}
+
+		d := device(withNetworkType(&to), func(d *v1alpha2.Device) {
+			d.Spec.ForProvider.VLANs = []string{"vlan-a", "vlan-b"}
+		})
+		if _, err := e.Update(context.Background(), d); err != nil {
+			t.Fatalf("e.Update(): %v", err)
+		}
+		want := []packngo.PortAssignRequest{{PortID: "eth1-id", VirtualNetworkID: "vlan-b"}}
+		if diff := cmp.Diff(want, assigned); diff != "" {
+			t.Errorf("e.Update(): -want assignments, +got:\n%s", diff)
+		}
+	})
+
+	t.Run("FixedPlanNetworkType", func(t *testing.T) {
+		to := packngo.NetworkTypeHybrid
+		e := &external{client: &fake.MockClient{
+			MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+				return &packngo.Device{State: v1alpha2.StateActive, Plan: &packngo.Plan{Slug: "baremetal_0"}}, nil, nil
+			},
+		}}
+
+		want := errors.Wrap(errors.New(`plan "baremetal_0" only supports network type "layer3", not "hybrid"`), errInvalidNetworkType)
+		_, err := e.Update(context.Background(), device(withNetworkType(&to)))
+		if diff := cmp.Diff(want, err, test.EquateErrors()); diff != "" {
+			t.Errorf("e.Update(): -want error, +got error:\n%s", diff)
+		}
+		if _, ok := errors.Cause(err).(*devicesclient.UnsupportedNetworkTypeError); !ok {
+			t.Errorf("e.Update(): want an UnsupportedNetworkTypeError, got %T", errors.Cause(err))
+		}
+	})
+}
+
+func TestUpdateReboot(t *testing.T) {
+	var reboots []string
+	e := &external{client: &fake.MockClient{
+		MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+			return &packngo.Device{State: v1alpha2.StateActive, AlwaysPXE: *alwaysPXE}, nil, nil
+		},
+		MockUpdate: func(deviceID string, updateRequest *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
+			return &packngo.Device{}, nil, nil
+		},
+		MockReboot: func(deviceID string) (*packngo.Response, error) {
+			reboots = append(reboots, deviceID)
+			return nil, nil
+		},
+	}}
+
+	d := device(withRebootToken("rollout-2"), withLastRebootToken("rollout-1"))
+	if !devicesclient.IsRebootRequested(d) {
+		t.Fatalf("IsRebootRequested(...): want a changed token to request a reboot")
+	}
+
+	// The changed token reboots the device on the first update only
+	for i := 0; i < 3; i++ {
+		if _, err := e.Update(context.Background(), d); err != nil {
+			t.Fatalf("e.Update(): %v", err)
+		}
+	}
+	if diff := cmp.Diff([]string{deviceName}, reboots); diff != "" {
+		t.Errorf("e.Update(): -want reboots, +got reboots:\n%s", diff)
+	}
+	if diff := cmp.Diff("rollout-2", d.Status.AtProvider.LastRebootToken); diff != "" {
+		t.Errorf("e.Update(): -want last reboot token, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(&v1alpha2.DeviceAction{Type: v1alpha2.DeviceActionReboot}, d.Status.AtProvider.LastAction, ignoreActionTime); diff != "" {
+		t.Errorf("e.Update(): -want last action, +got:\n%s", diff)
+	}
+	if devicesclient.IsRebootRequested(d) {
+		t.Errorf("IsRebootRequested(...): want no reboot once the token is recorded")
+	}
+
+	t.Run("NotRebootedWithoutToken", func(t *testing.T) {
+		reboots = nil
+		if _, err := e.Update(context.Background(), device()); err != nil {
+			t.Fatalf("e.Update(): %v", err)
+		}
+		if len(reboots) != 0 {
+			t.Errorf("e.Update(): want no reboots, got %d", len(reboots))
+		}
+	})
+}
+
+func TestUpdateConflict(t *testing.T) {
+	cases := map[string]struct {
+		errs      []error
+		refetched string
+		want      error
+		gets      int
+		updates   int
+	}{
+		"RetriedAfterConflict": {
+			errs:      []error{errUnprocessable, nil},
+			refetched: "original",
+			gets:      2,
+			updates:   2,
+		},
+		"RetriedOnce": {
+			errs:      []error{errUnprocessable, errUnprocessable},
+			refetched: "original",
+			want:      errors.Wrap(errUnprocessable, errUpdateDevice),
+			gets:      2,
+			updates:   2,
+		},
+		"UpToDateAfterConflict": {
+			errs:      []error{errUnprocessable},
+			refetched: "renamed",
+			gets:      2,
+			updates:   1,
+		},
+		"NotRetried": {
+			errs:    []error{errorBoom},
+			want:    errors.Wrap(errorBoom, errUpdateDevice),
+			gets:    1,
+			updates: 1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var gets, updates int
+			e := &external{client: &fake.MockClient{
+				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+					gets++
+					hostname := "original"
+					if gets > 1 {
+						hostname = tc.refetched
+					}
+					return &packngo.Device{State: v1alpha2.StateActive, AlwaysPXE: *alwaysPXE, Hostname: hostname}, nil, nil
+				},
+				MockUpdate: func(deviceID string, updateRequest *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
+					err := tc.errs[updates]
+					updates++
+					return &packngo.Device{}, nil, err
+				},
+			}}
+
+			_, err := e.Update(context.Background(), device(withHostname("renamed")))
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("e.Update(): -want error, +got error:\n%s", diff)
+			}
+			if gets != tc.gets || updates != tc.updates {
+				t.Errorf("e.Update(): want %d gets and %d updates, got %d and %d", tc.gets, tc.updates, gets, updates)
+			}
 		})
 	}
 }
 
+func TestUpdateUserDataReinstallFailed(t *testing.T) {
+	// remote is the device as known to the API, updated by the fake
+	remote := packngo.Device{State: v1alpha2.StateActive, AlwaysPXE: *alwaysPXE}
+	var reinstallErr error
+	e := &external{
+		kube:   &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+		record: event.NewNopRecorder(),
+		client: &fake.MockClient{
+			MockGetIQN: noIQN,
+			MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+				d := remote
+				return &d, nil, nil
+			},
+			MockUpdate: func(deviceID string, updateRequest *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
+				if updateRequest.UserData != nil {
+					remote.UserData = *updateRequest.UserData
+				}
+				return &remote, nil, nil
+			},
+			MockReinstall: func(deviceID string, reinstallRequest *devicesclient.ReinstallRequest) (*packngo.Response, error) {
+				return nil, reinstallErr
+			},
+		},
+	}
+
+	// The userdata is not updated when the device fails to be reinstalled,
+	// so the next reconcile still finds it outdated
+	reinstallErr = errorBoom
+	_, err := e.Update(context.Background(), device(withUserData("#cloud-config")))
+	if diff := cmp.Diff(errors.Wrap(errorBoom, errReinstallDevice), err, test.EquateErrors()); diff != "" {
+		t.Errorf("e.Update(): -want error, +got error:\n%s", diff)
+	}
+	o, err := e.Observe(context.Background(), device(withUserData("#cloud-config")))
+	if err != nil {
+		t.Fatalf("e.Observe(): %v", err)
+	}
+	if o.ResourceUpToDate {
+		t.Errorf("e.Observe(): want userdata not up to date after a failed reinstall")
+	}
+
+	// The userdata is updated once the device is reinstalled
+	reinstallErr = nil
+	if _, err := e.Update(context.Background(), device(withUserData("#cloud-config"))); err != nil {
+		t.Fatalf("e.Update(): %v", err)
+	}
+	if diff := cmp.Diff("#cloud-config", remote.UserData); diff != "" {
+		t.Errorf("e.Update(): -want userdata, +got userdata:\n%s", diff)
+	}
+}
+
 func TestDelete(t *testing.T) {
 	type args struct {
 		ctx context.Context
 		mg  resource.Managed
 	}
 	type want struct {
-		mg  resource.Managed
-		err error
+		mg      resource.Managed
+		err     error
+		calls   []string
+		started bool
 	}
 
+	// calls records the order of the API calls made by cases that track them
+	var calls []string
+
 	cases := map[string]struct {
 		client managed.ExternalClient
 		args   args
@@ -862,6 +3283,7 @@ func TestDelete(t *testing.T) {
 		"DeletedInstance": {
 			client: &external{client: &fake.MockClient{
 				MockDelete: func(deviceID string, force bool) (*packngo.Response, error) {
+					calls = append(calls, fmt.Sprintf("delete force=%t", force))
 					return nil, nil
 				}},
 			},
@@ -870,7 +3292,100 @@ func TestDelete(t *testing.T) {
 				mg:  device(),
 			},
 			want: want{
-				mg: device(withConditions(xpv1.Deleting())),
+				mg:      device(withConditions(xpv1.Deleting())),
+				calls:   []string{"delete force=false"},
+				started: true,
+			},
+		},
+		"ForceDeletedInstance": {
+			client: &external{client: &fake.MockClient{
+				MockDelete: func(deviceID string, force bool) (*packngo.Response, error) {
+					calls = append(calls, fmt.Sprintf("delete force=%t", force))
+					return nil, nil
+				}},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withForceDelete(true)),
+			},
+			want: want{
+				mg:      device(withForceDelete(true), withConditions(xpv1.Deleting())),
+				calls:   []string{"delete force=true"},
+				started: true,
+			},
+		},
+		"AlreadyDeprovisioning": {
+			client: &external{client: &fake.MockClient{
+				MockDelete: func(deviceID string, force bool) (*packngo.Response, error) {
+					calls = append(calls, "delete")
+					return nil, nil
+				}},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withState(v1alpha2.StateDeprovisioning)),
+			},
+			want: want{
+				mg:      device(withState(v1alpha2.StateDeprovisioning), withConditions(xpv1.Deleting())),
+				started: true,
+			},
+		},
+		"AlreadyStartedDeletion": {
+			client: &external{client: &fake.MockClient{
+				MockDelete: func(deviceID string, force bool) (*packngo.Response, error) {
+					calls = append(calls, "delete")
+					return nil, nil
+				}},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withState(v1alpha2.StateDeprovisioning), withDeletionStartedAt(time.Unix(0, 0))),
+			},
+			want: want{
+				mg:      device(withState(v1alpha2.StateDeprovisioning), withDeletionStartedAt(time.Unix(0, 0)), withConditions(xpv1.Deleting())),
+				started: true,
+			},
+		},
+		"UnlockedBeforeDelete": {
+			client: &external{client: &fake.MockClient{
+				MockUnlock: func(deviceID string) (*packngo.Response, error) {
+					calls = append(calls, "unlock")
+					return nil, nil
+				},
+				MockDelete: func(deviceID string, force bool) (*packngo.Response, error) {
+					calls = append(calls, "delete")
+					return nil, nil
+				}},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withObservedLocked(true)),
+			},
+			want: want{
+				mg:      device(withObservedLocked(true), withConditions(xpv1.Deleting())),
+				calls:   []string{"unlock", "delete"},
+				started: true,
+			},
+		},
+		"FailedToUnlockInstance": {
+			client: &external{client: &fake.MockClient{
+				MockUnlock: func(deviceID string) (*packngo.Response, error) {
+					calls = append(calls, "unlock")
+					return nil, errorBoom
+				},
+				MockDelete: func(deviceID string, force bool) (*packngo.Response, error) {
+					calls = append(calls, "delete")
+					return nil, nil
+				}},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withObservedLocked(true)),
+			},
+			want: want{
+				mg:    device(withObservedLocked(true), withConditions(xpv1.Deleting())),
+				err:   errors.Wrap(errorBoom, errUnlockDevice),
+				calls: []string{"unlock"},
 			},
 		},
 		"NotDeviceInstance": {
@@ -904,15 +3419,143 @@ func TestDelete(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
+			calls = nil
 			err := tc.client.Delete(tc.args.ctx, tc.args.mg)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("tc.client.Delete(): -want error, +got error:\n%s", diff)
 			}
 
-			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions(), packettest.EquateQuantities()); diff != "" {
+			// A deletion that started earlier keeps its start time
+			if d, ok := tc.want.mg.(*v1alpha2.Device); ok && d.Status.AtProvider.DeletionStartedAt != nil {
+				if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions(), packettest.EquateQuantities()); diff != "" {
+					t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+				}
+			} else if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions(), packettest.EquateQuantities(), ignoreDeletionStartedAt); diff != "" {
 				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
 			}
+
+			if d, ok := tc.args.mg.(*v1alpha2.Device); ok {
+				if started := d.Status.AtProvider.DeletionStartedAt != nil; started != tc.want.started {
+					t.Errorf("tc.client.Delete(): want deletion started %t, got %t", tc.want.started, started)
+				}
+			}
+
+			if diff := cmp.Diff(tc.want.calls, calls); diff != "" {
+				t.Errorf("calls: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestResolveUserDataRefs(t *testing.T) {
+	ref := func(kind, name string, optional bool) v1alpha2.DataKeySelector {
+		return v1alpha2.DataKeySelector{
+			NamespacedName: v1alpha2.NamespacedName{Namespace: namespace, Name: name},
+			Kind:           kind,
+			Optional:       optional,
+		}
+	}
+	separator := "\n---\n"
+
+	// base and env hold userdata, missing can not be found
+	kube := &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		switch o := obj.(type) {
+		case *corev1.ConfigMap:
+			if key.Name == "base" {
+				o.Data = map[string]string{"cloud-init": "#cloud-config\npackages: [git]"}
+				return nil
+			}
+		case *corev1.Secret:
+			if key.Name == "env" {
+				o.Data = map[string][]byte{"cloud-init": []byte("#!/bin/sh\necho prod")}
+				return nil
+			}
+		}
+		return errorBoom
+	}}
+
+	type want struct {
+		userdata string
+		err      error
+	}
+
+	cases := map[string]struct {
+		params v1alpha2.DeviceParameters
+		want   want
+	}{
+		"SingleRef": {
+			params: v1alpha2.DeviceParameters{
+				UserDataRef: &v1alpha2.DataKeySelector{
+					NamespacedName: v1alpha2.NamespacedName{Namespace: namespace, Name: "base"},
+					Kind:           "ConfigMap",
+				},
+			},
+			want: want{userdata: "#cloud-config\npackages: [git]"},
+		},
+		"OrderedConcatenation": {
+			params: v1alpha2.DeviceParameters{
+				UserDataRefs: []v1alpha2.DataKeySelector{
+					ref("Secret", "env", false),
+					ref("ConfigMap", "base", false),
+				},
+			},
+			want: want{userdata: "#!/bin/sh\necho prod\n#cloud-config\npackages: [git]"},
+		},
+		"UserDataRefFirst": {
+			params: v1alpha2.DeviceParameters{
+				UserDataRef: &v1alpha2.DataKeySelector{
+					NamespacedName: v1alpha2.NamespacedName{Namespace: namespace, Name: "base"},
+					Kind:           "ConfigMap",
+				},
+				UserDataRefs:      []v1alpha2.DataKeySelector{ref("Secret", "env", false)},
+				UserDataSeparator: &separator,
+			},
+			want: want{userdata: "#cloud-config\npackages: [git]\n---\n#!/bin/sh\necho prod"},
+		},
+		"OptionalMissingRefsSkipped": {
+			params: v1alpha2.DeviceParameters{
+				UserDataRefs: []v1alpha2.DataKeySelector{
+					ref("ConfigMap", "missing", true),
+					ref("ConfigMap", "base", false),
+					ref("Secret", "missing", true),
+					ref("Secret", "env", false),
+				},
+			},
+			want: want{userdata: "#cloud-config\npackages: [git]\n#!/bin/sh\necho prod"},
+		},
+		"AllOptionalRefsMissing": {
+			params: v1alpha2.DeviceParameters{
+				UserDataRefs: []v1alpha2.DataKeySelector{
+					ref("ConfigMap", "missing", true),
+					ref("Secret", "missing", true),
+				},
+			},
+			want: want{userdata: ""},
+		},
+		"RequiredRefMissing": {
+			params: v1alpha2.DeviceParameters{
+				UserDataRefs: []v1alpha2.DataKeySelector{
+					ref("ConfigMap", "base", false),
+					ref("Secret", "missing", false),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errorBoom, "cannot get required resource for reference"), "cannot resolve Secret cool-namespace/missing"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{kube: kube}
+			userdata, err := e.resolveUserDataRefs(context.Background(), tc.params)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("resolveUserDataRefs(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.userdata, userdata); diff != "" {
+				t.Errorf("resolveUserDataRefs(...): -want userdata, +got userdata:\n%s", diff)
+			}
 		})
 	}
 }
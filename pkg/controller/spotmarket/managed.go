@@ -0,0 +1,206 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spotmarket
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/spotmarket/v1alpha1"
+	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	packetclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	spotmarketclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/spotmarket"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errManagedUpdateFailed     = "cannot update SpotMarketRequest custom resource"
+	errTrackPCUsage            = "cannot track ProviderConfig usage"
+	errGetProviderConfigSecret = "cannot get ProviderConfig Secret"
+	errNewClient               = "cannot create new SpotMarketRequest client"
+	errNotSpotMarketRequest    = "managed resource is not a SpotMarketRequest"
+	errGetSpotMarketRequest    = "cannot get SpotMarketRequest"
+	errCreateSpotMarketRequest = "cannot create SpotMarketRequest"
+	errInvalidLocation         = "invalid SpotMarketRequest location"
+	errDeleteSpotMarketRequest = "cannot delete SpotMarketRequest"
+)
+
+// SetupSpotMarketRequest adds a controller that reconciles SpotMarketRequests
+func SetupSpotMarketRequest(mgr ctrl.Manager, l logging.Logger, poll time.Duration) error {
+	name := managed.ControllerName(v1alpha1.SpotMarketRequestGroupKind)
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.SpotMarketRequestGroupVersionKind),
+		managed.WithExternalConnecter(clients.NewManagementPolicyConnecter(&connecter{
+			kube:  mgr.GetClient(),
+			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
+		})),
+		managed.WithInitializers(&managed.DefaultProviderConfig{}),
+		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+		managed.WithPollInterval(poll),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+	)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.SpotMarketRequest{}).
+		Complete(clients.NewPollingReconciler(mgr, resource.ManagedKind(v1alpha1.SpotMarketRequestGroupVersionKind), r))
+}
+
+type connecter struct {
+	kube        client.Client
+	usage       resource.Tracker
+	newClientFn func(ctx context.Context, config *clients.Credentials) (spotmarketclient.ClientWithDefaults, error)
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.SpotMarketRequest); !ok {
+		return nil, errors.New(errNotSpotMarketRequest)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	newClientFn := spotmarketclient.NewClient
+	if c.newClientFn != nil {
+		newClientFn = c.newClientFn
+	}
+	cfg, err := clients.GetAuthInfo(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetProviderConfigSecret)
+	}
+	client, err := newClientFn(ctx, cfg)
+
+	return &external{kube: c.kube, client: client, defaultTags: cfg.DefaultTags}, errors.Wrap(err, errNewClient)
+}
+
+type external struct {
+	kube   client.Client
+	client spotmarketclient.ClientWithDefaults
+
+	// defaultTags of the ProviderConfig are merged into the tags of created
+	// resources
+	defaultTags map[string]string
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	r, ok := mg.(*v1alpha1.SpotMarketRequest)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotSpotMarketRequest)
+	}
+
+	// Observe the spot market request and the devices it has provisioned
+	request, _, err := e.client.Get(meta.GetExternalName(r), (&packngo.GetOptions{}).Including("devices", "facilities", "metro"))
+	if packetclient.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetSpotMarketRequest)
+	}
+
+	current := r.Spec.ForProvider.DeepCopy()
+	spotmarketclient.LateInitialize(&r.Spec.ForProvider, request)
+	if !cmp.Equal(current, &r.Spec.ForProvider) {
+		if err := e.kube.Update(ctx, r); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errManagedUpdateFailed)
+		}
+	}
+
+	r.Status.AtProvider = spotmarketclient.GenerateObservation(request)
+	r.Status.SetConditions(xpv1.Available())
+
+	// NOTE: Spot Market Requests can not be modified once created
+	o := managed.ExternalObservation{
+		ResourceExists:    true,
+		ResourceUpToDate:  true,
+		ConnectionDetails: spotmarketclient.GetConnectionDetails(request),
+	}
+
+	return o, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	r, ok := mg.(*v1alpha1.SpotMarketRequest)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotSpotMarketRequest)
+	}
+
+	r.Status.SetConditions(xpv1.Creating())
+
+	if err := spotmarketclient.ValidateLocation(r); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errInvalidLocation)
+	}
+
+	create := spotmarketclient.CreateFromSpotMarketRequest(r)
+
+	// Use the metro, or facility, of the credentials when no location is set
+	if len(create.FacilityIDs) == 0 && create.Metro == "" {
+		create.Metro = e.client.GetMetro(packetclient.CredentialMetro)
+		if create.Metro == "" {
+			if f := e.client.GetFacilityID(packetclient.CredentialFacilityID); f != "" {
+				create.FacilityIDs = []string{f}
+			}
+		}
+	}
+	create.Parameters.Tags = clients.MergeTags(create.Parameters.Tags, e.defaultTags)
+	request, _, err := e.client.Create(create, e.client.GetProjectID(r.Spec.ForProvider.ProjectID))
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateSpotMarketRequest)
+	}
+
+	r.Status.AtProvider.ID = request.ID
+	meta.SetExternalName(r, request.ID)
+	if err := e.kube.Update(ctx, r); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errManagedUpdateFailed)
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	// NOTE: SpotMarketRequest cannot be updated.
+	return managed.ExternalUpdate{}, nil
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	r, ok := mg.(*v1alpha1.SpotMarketRequest)
+	if !ok {
+		return errors.New(errNotSpotMarketRequest)
+	}
+	r.SetConditions(xpv1.Deleting())
+
+	// Force termination so that the devices of the request are not left
+	// running without a request to manage them
+	_, err := e.client.Delete(meta.GetExternalName(r), true)
+	return errors.Wrap(resource.Ignore(packetclient.IsNotFound, err), errDeleteSpotMarketRequest)
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spotmarket
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/spotmarket/v1alpha1"
+	devicesclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/device"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/spotmarket/fake"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const (
+	requestName = "my-cool-request"
+	requestID   = "request-id"
+)
+
+type requestModifier func(*v1alpha1.SpotMarketRequest)
+
+func withMetro(m string) requestModifier {
+	return func(r *v1alpha1.SpotMarketRequest) { r.Spec.ForProvider.Metro = &m }
+}
+
+func withFacilities(f ...string) requestModifier {
+	return func(r *v1alpha1.SpotMarketRequest) { r.Spec.ForProvider.Facilities = f }
+}
+
+func withConditions(c ...xpv1.Condition) requestModifier {
+	return func(r *v1alpha1.SpotMarketRequest) { r.Status.SetConditions(c...) }
+}
+
+func withID(id string) requestModifier {
+	return func(r *v1alpha1.SpotMarketRequest) {
+		r.Status.AtProvider.ID = id
+		meta.SetExternalName(r, id)
+	}
+}
+
+func request(rm ...requestModifier) *v1alpha1.SpotMarketRequest {
+	r := &v1alpha1.SpotMarketRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: requestName},
+		Spec: v1alpha1.SpotMarketRequestSpec{
+			ForProvider: v1alpha1.SpotMarketRequestParameters{
+				DevicesMin:  1,
+				DevicesMax:  2,
+				MaxBidPrice: resource.MustParse("0.5"),
+			},
+		},
+	}
+	for _, m := range rm {
+		m(r)
+	}
+	return r
+}
+
+func TestCreate(t *testing.T) {
+	// createdIn returns a client that creates a request in the supplied
+	// location, using the supplied metro and facility of the credentials
+	createdIn := func(metro string, facilities []string, credentialMetro, credentialFacility string) *fake.MockClient {
+		return &fake.MockClient{
+			MockGetProjectID:  func(string) string { return "project" },
+			MockGetMetro:      func(string) string { return credentialMetro },
+			MockGetFacilityID: func(string) string { return credentialFacility },
+			MockCreate: func(createRequest *packngo.SpotMarketRequestCreateRequest, projectID string) (*packngo.SpotMarketRequest, *packngo.Response, error) {
+				if diff := cmp.Diff(metro, createRequest.Metro); diff != "" {
+					t.Errorf("MockCreate: -want metro, +got:\n%s", diff)
+				}
+				if diff := cmp.Diff(facilities, createRequest.FacilityIDs); diff != "" {
+					t.Errorf("MockCreate: -want facilities, +got:\n%s", diff)
+				}
+				return &packngo.SpotMarketRequest{ID: requestID}, nil, nil
+			},
+		}
+	}
+
+	type want struct {
+		mg  *v1alpha1.SpotMarketRequest
+		err error
+	}
+
+	cases := map[string]struct {
+		client *fake.MockClient
+		mg     *v1alpha1.SpotMarketRequest
+		want   want
+	}{
+		"CreatedInMetro": {
+			client: createdIn("da", nil, "sv", "sv15"),
+			mg:     request(withMetro("da")),
+			want: want{
+				mg: request(withMetro("da"), withConditions(xpv1.Creating()), withID(requestID)),
+			},
+		},
+		"CreatedInFacilities": {
+			client: createdIn("", []string{"da11", "dc13"}, "sv", "sv15"),
+			mg:     request(withFacilities("da11", "dc13")),
+			want: want{
+				mg: request(withFacilities("da11", "dc13"), withConditions(xpv1.Creating()), withID(requestID)),
+			},
+		},
+		"CreatedInCredentialMetro": {
+			client: createdIn("sv", nil, "sv", "sv15"),
+			mg:     request(),
+			want: want{
+				mg: request(withConditions(xpv1.Creating()), withID(requestID)),
+			},
+		},
+		"CreatedInCredentialFacility": {
+			client: createdIn("", []string{"sv15"}, "", "sv15"),
+			mg:     request(),
+			want: want{
+				mg: request(withConditions(xpv1.Creating()), withID(requestID)),
+			},
+		},
+		"FacilitiesAndMetro": {
+			client: &fake.MockClient{
+				MockCreate: func(createRequest *packngo.SpotMarketRequestCreateRequest, projectID string) (*packngo.SpotMarketRequest, *packngo.Response, error) {
+					t.Errorf("MockCreate: called with facilities and a metro")
+					return nil, nil, nil
+				},
+			},
+			mg: request(withFacilities("da11"), withMetro("da")),
+			want: want{
+				mg:  request(withFacilities("da11"), withMetro("da"), withConditions(xpv1.Creating())),
+				err: errors.Wrap(errors.New(`facilities "da11" and metro "da" can not both be set`), errInvalidLocation),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{
+				kube:   &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: tc.client,
+			}
+			got, err := e.Create(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("e.Create(): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(managed.ExternalCreation{}, got); diff != "" {
+				t.Errorf("e.Create(): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.mg, test.EquateConditions(), packettest.EquateQuantities()); diff != "" {
+				t.Errorf("e.Create(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestObserve(t *testing.T) {
+	active := packngo.Device{
+		ID:    "active-device",
+		State: "active",
+		Network: []*packngo.IPAddressAssignment{{
+			IpAddressCommon: packngo.IpAddressCommon{Address: "198.51.100.2", AddressFamily: 4, Public: true, Management: true},
+		}},
+	}
+	provisioning := packngo.Device{ID: "provisioning-device", State: "provisioning"}
+
+	type want struct {
+		observation v1alpha1.SpotMarketRequestObservation
+		details     managed.ConnectionDetails
+	}
+
+	cases := map[string]struct {
+		devices []packngo.Device
+		want    want
+	}{
+		"NoDevices": {
+			want: want{
+				observation: v1alpha1.SpotMarketRequestObservation{ID: requestID},
+			},
+		},
+		"ProvisioningDevice": {
+			devices: []packngo.Device{provisioning},
+			want: want{
+				observation: v1alpha1.SpotMarketRequestObservation{
+					ID:          requestID,
+					DeviceCount: 1,
+					DeviceIDs:   []string{"provisioning-device"},
+					Devices:     []v1alpha1.SpotMarketRequestDevice{{ID: "provisioning-device", State: "provisioning"}},
+				},
+			},
+		},
+		"ActiveDevice": {
+			devices: []packngo.Device{provisioning, active},
+			want: want{
+				observation: v1alpha1.SpotMarketRequestObservation{
+					ID:          requestID,
+					DeviceCount: 2,
+					DeviceIDs:   []string{"provisioning-device", "active-device"},
+					Devices: []v1alpha1.SpotMarketRequestDevice{
+						{ID: "provisioning-device", State: "provisioning"},
+						{ID: "active-device", State: "active"},
+					},
+				},
+				details: devicesclient.GetConnectionDetails(&active),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.SpotMarketRequest, *packngo.Response, error) {
+						return &packngo.SpotMarketRequest{ID: requestID, Devices: tc.devices}, nil, nil
+					},
+				},
+			}
+			mg := request(withID(requestID))
+			o, err := e.Observe(context.Background(), mg)
+			if err != nil {
+				t.Fatalf("e.Observe(): %v", err)
+			}
+			if diff := cmp.Diff(tc.want.observation, mg.Status.AtProvider); diff != "" {
+				t.Errorf("e.Observe(): -want observation, +got observation:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.details, o.ConnectionDetails); diff != "" {
+				t.Errorf("e.Observe(): -want connection details, +got connection details:\n%s", diff)
+			}
+		})
+	}
+}
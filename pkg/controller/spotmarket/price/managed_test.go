@@ -0,0 +1,263 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package price
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/spotmarket/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/spotmarket/price/fake"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const plan = "c3.medium.x86"
+
+var errorBoom = errors.New("boom")
+
+type strange struct {
+	resource.Managed
+}
+
+type priceModifier func(*v1alpha1.SpotMarketPrice)
+
+func withFacility(f string) priceModifier {
+	return func(p *v1alpha1.SpotMarketPrice) { p.Spec.ForProvider.Facility = &f }
+}
+
+func withMetro(m string) priceModifier {
+	return func(p *v1alpha1.SpotMarketPrice) { p.Spec.ForProvider.Metro = &m }
+}
+
+func withDeletionTimestamp(t time.Time) priceModifier {
+	return func(p *v1alpha1.SpotMarketPrice) {
+		deleted := metav1.NewTime(t)
+		p.SetDeletionTimestamp(&deleted)
+	}
+}
+
+func withPrice(price string) priceModifier {
+	return func(p *v1alpha1.SpotMarketPrice) {
+		q := apiresource.MustParse(price)
+		p.Status.AtProvider.Price = &q
+	}
+}
+
+func withConditions(c ...xpv1.Condition) priceModifier {
+	return func(p *v1alpha1.SpotMarketPrice) { p.Status.SetConditions(c...) }
+}
+
+func spotMarketPrice(pm ...priceModifier) *v1alpha1.SpotMarketPrice {
+	p := &v1alpha1.SpotMarketPrice{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cool-price"},
+		Spec: v1alpha1.SpotMarketPriceSpec{
+			ForProvider: v1alpha1.SpotMarketPriceParameters{Plan: plan},
+		},
+	}
+	for _, m := range pm {
+		m(p)
+	}
+	return p
+}
+
+func prices(location string, price float64) func() (packngo.PriceMap, *packngo.Response, error) {
+	return func() (packngo.PriceMap, *packngo.Response, error) {
+		return packngo.PriceMap{
+			location: {plan: price, "m3.large.x86": 3.5},
+			"other":  {plan: 9.99},
+		}, nil, nil
+	}
+}
+
+var _ managed.ExternalClient = &external{}
+
+func TestObserve(t *testing.T) {
+	deleted := time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC)
+
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg          resource.Managed
+		observation managed.ExternalObservation
+		err         error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"PriceInFacility": {
+			client: &external{client: &fake.MockClient{
+				MockPricesByFacility: prices("da11", 2),
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  spotMarketPrice(withFacility("da11")),
+			},
+			want: want{
+				mg: spotMarketPrice(
+					withFacility("da11"),
+					withPrice("2"),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"PriceInMetro": {
+			client: &external{client: &fake.MockClient{
+				MockPricesByMetro: prices("da", 2),
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  spotMarketPrice(withMetro("da")),
+			},
+			want: want{
+				mg: spotMarketPrice(
+					withMetro("da"),
+					withPrice("2"),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"NoPrice": {
+			client: &external{client: &fake.MockClient{
+				MockPricesByMetro: prices("sv", 2),
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  spotMarketPrice(withMetro("da")),
+			},
+			want: want{
+				mg:  spotMarketPrice(withMetro("da")),
+				err: errors.Wrap(errors.Errorf("no spot market price for plan %q in %q", plan, "da"), errGetPrice),
+			},
+		},
+		"FacilityAndMetro": {
+			client: &external{client: &fake.MockClient{}},
+			args: args{
+				ctx: context.Background(),
+				mg:  spotMarketPrice(withFacility("da11"), withMetro("da")),
+			},
+			want: want{
+				mg:  spotMarketPrice(withFacility("da11"), withMetro("da")),
+				err: errors.Wrap(errors.Errorf("facility %q and metro %q can not both be set", "da11", "da"), errGetPrice),
+			},
+		},
+		"NoLocation": {
+			client: &external{client: &fake.MockClient{}},
+			args: args{
+				ctx: context.Background(),
+				mg:  spotMarketPrice(),
+			},
+			want: want{
+				mg:  spotMarketPrice(),
+				err: errors.Wrap(errors.New("one of facility or metro must be set"), errGetPrice),
+			},
+		},
+		"FailedToGetPrices": {
+			client: &external{client: &fake.MockClient{
+				MockPricesByFacility: func() (packngo.PriceMap, *packngo.Response, error) {
+					return nil, nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  spotMarketPrice(withFacility("da11")),
+			},
+			want: want{
+				mg:  spotMarketPrice(withFacility("da11")),
+				err: errors.Wrap(errorBoom, errGetPrice),
+			},
+		},
+		"Deleted": {
+			client: &external{client: &fake.MockClient{}},
+			args: args{
+				ctx: context.Background(),
+				mg:  spotMarketPrice(withFacility("da11"), withDeletionTimestamp(deleted)),
+			},
+			want: want{
+				mg:          spotMarketPrice(withFacility("da11"), withDeletionTimestamp(deleted)),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"NotSpotMarketPrice": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotSpotMarketPrice),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := tc.client.Observe(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Observe(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.observation, o); diff != "" {
+				t.Errorf("tc.client.Observe(): -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions(), packettest.EquateQuantities()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestObserveOnly(t *testing.T) {
+	// SpotMarketPrices are observe-only, the fake panics if any API is called
+	e := &external{client: &fake.MockClient{}}
+	ctx := context.Background()
+
+	if _, err := e.Create(ctx, spotMarketPrice(withFacility("da11"))); err != nil {
+		t.Errorf("e.Create(): %v", err)
+	}
+	if _, err := e.Update(ctx, spotMarketPrice(withFacility("da11"))); err != nil {
+		t.Errorf("e.Update(): %v", err)
+	}
+	if err := e.Delete(ctx, spotMarketPrice(withFacility("da11"))); err != nil {
+		t.Errorf("e.Delete(): %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sshkey
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/sshkey/v1alpha1"
+	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	packetclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	sshkeyclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/sshkey"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errManagedUpdateFailed     = "cannot update SSHKey custom resource"
+	errTrackPCUsage            = "cannot track ProviderConfig usage"
+	errGetProviderConfigSecret = "cannot get ProviderConfig Secret"
+	errGenObservation          = "cannot generate observation"
+	errNewClient               = "cannot create new SSHKey client"
+	errNotSSHKey               = "managed resource is not an SSHKey"
+	errGetSSHKey               = "cannot get SSHKey"
+	errCreateSSHKey            = "cannot create SSHKey"
+	errUpdateSSHKey            = "cannot modify SSHKey"
+	errDeleteSSHKey            = "cannot delete SSHKey"
+)
+
+// SetupSSHKey adds a controller that reconciles SSHKeys
+func SetupSSHKey(mgr ctrl.Manager, l logging.Logger, poll time.Duration) error {
+	name := managed.ControllerName(v1alpha1.SSHKeyGroupKind)
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.SSHKeyGroupVersionKind),
+		managed.WithExternalConnecter(clients.NewManagementPolicyConnecter(&connecter{
+			kube:  mgr.GetClient(),
+			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
+		})),
+		managed.WithConnectionPublishers(),
+		managed.WithPollInterval(poll),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+	)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.SSHKey{}).
+		Complete(clients.NewPollingReconciler(mgr, resource.ManagedKind(v1alpha1.SSHKeyGroupVersionKind), r))
+}
+
+type connecter struct {
+	kube        client.Client
+	usage       resource.Tracker
+	newClientFn func(ctx context.Context, config *clients.Credentials) (sshkeyclient.ClientWithDefaults, error)
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.SSHKey); !ok {
+		return nil, errors.New(errNotSSHKey)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	newClientFn := sshkeyclient.NewClient
+	if c.newClientFn != nil {
+		newClientFn = c.newClientFn
+	}
+	cfg, err := clients.GetAuthInfo(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetProviderConfigSecret)
+	}
+	client, err := newClientFn(ctx, cfg)
+
+	return &external{kube: c.kube, client: client}, errors.Wrap(err, errNewClient)
+}
+
+type external struct {
+	kube   client.Client
+	client sshkeyclient.ClientWithDefaults
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	k, ok := mg.(*v1alpha1.SSHKey)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotSSHKey)
+	}
+
+	// Observe SSH Key
+	key, _, err := e.client.Get(meta.GetExternalName(k), nil)
+	if packetclient.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetSSHKey)
+	}
+
+	k.Status.AtProvider, err = sshkeyclient.GenerateObservation(key)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGenObservation)
+	}
+
+	k.Status.SetConditions(xpv1.Available())
+
+	o := managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: sshkeyclient.IsUpToDate(k, key),
+	}
+
+	return o, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	k, ok := mg.(*v1alpha1.SSHKey)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotSSHKey)
+	}
+
+	k.Status.SetConditions(xpv1.Creating())
+
+	key, _, err := e.client.Create(sshkeyclient.CreateFromSSHKey(k))
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateSSHKey)
+	}
+
+	k.Status.AtProvider.ID = key.ID
+	meta.SetExternalName(k, key.ID)
+	if err := e.kube.Update(ctx, k); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errManagedUpdateFailed)
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	k, ok := mg.(*v1alpha1.SSHKey)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotSSHKey)
+	}
+
+	_, _, err := e.client.Update(meta.GetExternalName(k), sshkeyclient.NewUpdateSSHKeyRequest(k))
+	return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateSSHKey)
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	k, ok := mg.(*v1alpha1.SSHKey)
+	if !ok {
+		return errors.New(errNotSSHKey)
+	}
+	k.SetConditions(xpv1.Deleting())
+
+	_, err := e.client.Delete(meta.GetExternalName(k))
+	return errors.Wrap(resource.Ignore(packetclient.IsNotFound, err), errDeleteSSHKey)
+}
@@ -0,0 +1,522 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sshkey
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/sshkey/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/sshkey/fake"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const (
+	keyName   = "my-cool-key"
+	keyID     = "key-id"
+	keyLabel  = "cool"
+	publicKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFmLsOz0pDh6rnWwXjHmjTAWEbkKLM4s1Xzdpe4s0Bkk cool@example.com"
+)
+
+var errorBoom = errors.New("boom")
+
+type strange struct {
+	resource.Managed
+}
+
+type keyModifier func(*v1alpha1.SSHKey)
+
+func withExternalName(id string) keyModifier {
+	return func(k *v1alpha1.SSHKey) { meta.SetExternalName(k, id) }
+}
+
+func withLabel(l string) keyModifier {
+	return func(k *v1alpha1.SSHKey) { k.Spec.ForProvider.Label = l }
+}
+
+func withConditions(c ...xpv1.Condition) keyModifier {
+	return func(k *v1alpha1.SSHKey) { k.Status.SetConditions(c...) }
+}
+
+func withObservation(o v1alpha1.SSHKeyObservation) keyModifier {
+	return func(k *v1alpha1.SSHKey) { k.Status.AtProvider = o }
+}
+
+func sshKey(km ...keyModifier) *v1alpha1.SSHKey {
+	k := &v1alpha1.SSHKey{
+		ObjectMeta: metav1.ObjectMeta{Name: keyName},
+		Spec: v1alpha1.SSHKeySpec{
+			ForProvider: v1alpha1.SSHKeyParameters{Label: keyLabel, Key: publicKey},
+		},
+	}
+	for _, m := range km {
+		m(k)
+	}
+	return k
+}
+
+var _ managed.ExternalClient = &external{}
+
+func TestObserve(t *testing.T) {
+	created := metav1.NewTime(time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg          resource.Managed
+		observation managed.ExternalObservation
+		err         error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"UpToDate": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.SSHKey, *packngo.Response, error) {
+						if id != keyID {
+							t.Errorf("MockGet: want key %q, got %q", keyID, id)
+						}
+						return &packngo.SSHKey{
+							ID:          keyID,
+							Label:       keyLabel,
+							Key:         publicKey,
+							FingerPrint: "fingerprint",
+							Created:     "2021-01-02T03:04:05Z",
+						}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  sshKey(withExternalName(keyID)),
+			},
+			want: want{
+				mg: sshKey(
+					withExternalName(keyID),
+					withObservation(v1alpha1.SSHKeyObservation{ID: keyID, Fingerprint: "fingerprint", CreatedAt: &created}),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"LabelChanged": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.SSHKey, *packngo.Response, error) {
+						return &packngo.SSHKey{ID: keyID, Label: keyLabel, Key: publicKey}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  sshKey(withExternalName(keyID), withLabel("cooler")),
+			},
+			want: want{
+				mg: sshKey(
+					withExternalName(keyID),
+					withLabel("cooler"),
+					withObservation(v1alpha1.SSHKeyObservation{ID: keyID}),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+				},
+			},
+		},
+		"KeyChanged": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.SSHKey, *packngo.Response, error) {
+						return &packngo.SSHKey{ID: keyID, Label: keyLabel, Key: "ssh-ed25519 AAAA old@example.com"}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  sshKey(withExternalName(keyID)),
+			},
+			want: want{
+				mg: sshKey(
+					withExternalName(keyID),
+					withObservation(v1alpha1.SSHKeyObservation{ID: keyID}),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+				},
+			},
+		},
+		"NotFound": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.SSHKey, *packngo.Response, error) {
+						return nil, nil, packettest.NotFound()
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  sshKey(withExternalName(keyID)),
+			},
+			want: want{
+				mg:          sshKey(withExternalName(keyID)),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"FailedToGetSSHKey": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.SSHKey, *packngo.Response, error) {
+						return nil, nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  sshKey(withExternalName(keyID)),
+			},
+			want: want{
+				mg:  sshKey(withExternalName(keyID)),
+				err: errors.Wrap(errorBoom, errGetSSHKey),
+			},
+		},
+		"NotSSHKey": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotSSHKey),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := tc.client.Observe(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Observe(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.observation, o); diff != "" {
+				t.Errorf("tc.client.Observe(): -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Created": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockCreate: func(createRequest *packngo.SSHKeyCreateRequest) (*packngo.SSHKey, *packngo.Response, error) {
+						want := &packngo.SSHKeyCreateRequest{Label: keyLabel, Key: publicKey}
+						if diff := cmp.Diff(want, createRequest); diff != "" {
+							t.Errorf("MockCreate: -want, +got:\n%s", diff)
+						}
+						return &packngo.SSHKey{ID: keyID}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  sshKey(),
+			},
+			want: want{
+				mg: sshKey(
+					withExternalName(keyID),
+					withObservation(v1alpha1.SSHKeyObservation{ID: keyID}),
+					withConditions(xpv1.Creating())),
+			},
+		},
+		"FailedToCreate": {
+			client: &external{
+				client: &fake.MockClient{
+					MockCreate: func(createRequest *packngo.SSHKeyCreateRequest) (*packngo.SSHKey, *packngo.Response, error) {
+						return nil, nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  sshKey(),
+			},
+			want: want{
+				mg:  sshKey(withConditions(xpv1.Creating())),
+				err: errors.Wrap(errorBoom, errCreateSSHKey),
+			},
+		},
+		"FailedToRecordExternalName": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errorBoom)},
+				client: &fake.MockClient{
+					MockCreate: func(createRequest *packngo.SSHKeyCreateRequest) (*packngo.SSHKey, *packngo.Response, error) {
+						return &packngo.SSHKey{ID: keyID}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  sshKey(),
+			},
+			want: want{
+				mg: sshKey(
+					withExternalName(keyID),
+					withObservation(v1alpha1.SSHKeyObservation{ID: keyID}),
+					withConditions(xpv1.Creating())),
+				err: errors.Wrap(errorBoom, errManagedUpdateFailed),
+			},
+		},
+		"NotSSHKey": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotSSHKey),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.client.Create(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Create(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		err error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Updated": {
+			client: &external{
+				client: &fake.MockClient{
+					MockUpdate: func(id string, updateRequest *packngo.SSHKeyUpdateRequest) (*packngo.SSHKey, *packngo.Response, error) {
+						if id != keyID {
+							t.Errorf("MockUpdate: want key %q, got %q", keyID, id)
+						}
+						label, key := "cooler", publicKey
+						want := &packngo.SSHKeyUpdateRequest{Label: &label, Key: &key}
+						if diff := cmp.Diff(want, updateRequest); diff != "" {
+							t.Errorf("MockUpdate: -want, +got:\n%s", diff)
+						}
+						return &packngo.SSHKey{ID: keyID}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  sshKey(withExternalName(keyID), withLabel("cooler")),
+			},
+			want: want{},
+		},
+		"FailedToUpdate": {
+			client: &external{
+				client: &fake.MockClient{
+					MockUpdate: func(id string, updateRequest *packngo.SSHKeyUpdateRequest) (*packngo.SSHKey, *packngo.Response, error) {
+						return nil, nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  sshKey(withExternalName(keyID)),
+			},
+			want: want{
+				err: errors.Wrap(errorBoom, errUpdateSSHKey),
+			},
+		},
+		"NotSSHKey": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				err: errors.New(errNotSSHKey),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.client.Update(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Update(): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Deleted": {
+			client: &external{
+				client: &fake.MockClient{
+					MockDelete: func(id string) (*packngo.Response, error) {
+						if id != keyID {
+							t.Errorf("MockDelete: want key %q, got %q", keyID, id)
+						}
+						return nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  sshKey(withExternalName(keyID)),
+			},
+			want: want{
+				mg: sshKey(withExternalName(keyID), withConditions(xpv1.Deleting())),
+			},
+		},
+		"AlreadyDeleted": {
+			client: &external{
+				client: &fake.MockClient{
+					MockDelete: func(id string) (*packngo.Response, error) {
+						return nil, packettest.NotFound()
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  sshKey(withExternalName(keyID)),
+			},
+			want: want{
+				mg: sshKey(withExternalName(keyID), withConditions(xpv1.Deleting())),
+			},
+		},
+		"FailedToDelete": {
+			client: &external{
+				client: &fake.MockClient{
+					MockDelete: func(id string) (*packngo.Response, error) {
+						return nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  sshKey(withExternalName(keyID)),
+			},
+			want: want{
+				mg:  sshKey(withExternalName(keyID), withConditions(xpv1.Deleting())),
+				err: errors.Wrap(errorBoom, errDeleteSSHKey),
+			},
+		},
+		"NotSSHKey": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotSSHKey),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.client.Delete(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Delete(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package virtualnetwork
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
@@ -48,20 +49,28 @@ const (
 	errNotVirtualNetwork       = "managed resource is not a VirtualNetwork"
 	errGetVirtualNetwork       = "cannot get VirtualNetwork"
 	errCreateVirtualNetwork    = "cannot create VirtualNetwork"
+	errListVirtualNetworks     = "cannot list VirtualNetworks"
+	errInvalidVXLAN            = "cannot use requested VXLAN"
+	errInvalidLocation         = "cannot use requested location"
+	errUpdateVirtualNetwork    = "cannot modify VirtualNetwork"
 	errDeleteVirtualNetwork    = "cannot delete VirtualNetwork"
+	errGetAttachments          = "cannot get VirtualNetwork attachments"
+	errDescriptionImmutable    = "the Equinix Metal API does not allow VirtualNetwork descriptions to be changed: restore the description or recreate the VirtualNetwork"
 )
 
 // SetupVirtualNetwork adds a controller that reconciles VirtualNetworks
-func SetupVirtualNetwork(mgr ctrl.Manager, l logging.Logger) error {
+func SetupVirtualNetwork(mgr ctrl.Manager, l logging.Logger, poll time.Duration) error {
 	name := managed.ControllerName(v1alpha1.VirtualNetworkGroupKind)
 
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.VirtualNetworkGroupVersionKind),
-		managed.WithExternalConnecter(&connecter{
+		managed.WithExternalConnecter(clients.NewManagementPolicyConnecter(&connecter{
 			kube:  mgr.GetClient(),
 			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
-		}),
+		})),
+		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 		managed.WithConnectionPublishers(),
+		managed.WithPollInterval(poll),
 		managed.WithLogger(l.WithValues("controller", name)),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 	)
@@ -69,7 +78,7 @@ func SetupVirtualNetwork(mgr ctrl.Manager, l logging.Logger) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		For(&v1alpha1.VirtualNetwork{}).
-		Complete(r)
+		Complete(clients.NewPollingReconciler(mgr, resource.ManagedKind(v1alpha1.VirtualNetworkGroupVersionKind), r))
 }
 
 type connecter struct {
@@ -151,7 +160,34 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	v.Status.SetConditions(xpv1.Creating())
 
-	create := vlanclient.CreateFromVirtualNetwork(v, e.client.GetProjectID(packetclient.CredentialProjectID))
+	if err := vlanclient.ValidateLocation(v); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errInvalidLocation)
+	}
+
+	projectID := e.client.GetProjectID(v.Spec.ForProvider.ProjectID)
+
+	// Use the metro, or facility, of the credentials when no location is set
+	createVLAN := v.DeepCopy()
+	if createVLAN.Spec.ForProvider.Facility == "" && createVLAN.Spec.ForProvider.Metro == "" {
+		createVLAN.Spec.ForProvider.Metro = e.client.GetMetro(packetclient.CredentialMetro)
+		if createVLAN.Spec.ForProvider.Metro == "" {
+			createVLAN.Spec.ForProvider.Facility = e.client.GetFacilityID(packetclient.CredentialFacilityID)
+		}
+	}
+
+	// Requested VXLANs are checked before creation so that a VXLAN taken by
+	// another VirtualNetwork is reported clearly.
+	if createVLAN.Spec.ForProvider.VXLAN != 0 {
+		vlans, _, err := e.client.List(projectID, nil)
+		if err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errListVirtualNetworks)
+		}
+		if err := vlanclient.ValidateVXLAN(createVLAN, vlans.VirtualNetworks); err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errInvalidVXLAN)
+		}
+	}
+
+	create := vlanclient.CreateFromVirtualNetwork(createVLAN, projectID)
 	vlan, _, err := e.client.Create(create)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreateVirtualNetwork)
@@ -167,8 +203,18 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 }
 
 func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	// NOTE(hasheddan): VirtualNetwork cannot be updated.
-	return managed.ExternalUpdate{}, nil
+	v, ok := mg.(*v1alpha1.VirtualNetwork)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotVirtualNetwork)
+	}
+
+	// Only the description of a VirtualNetwork can be updated. Rejected
+	// updates are reported rather than retried as though they may succeed.
+	_, _, err := e.client.Update(meta.GetExternalName(v), vlanclient.NewUpdateRequest(v))
+	if vlanclient.IsUpdateUnsupported(err) {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errDescriptionImmutable)
+	}
+	return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateVirtualNetwork)
 }
 
 func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
@@ -178,6 +224,19 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 	}
 	v.SetConditions(xpv1.Deleting())
 
-	_, err := e.client.Delete(meta.GetExternalName(v))
+	// The API refuses to delete a VirtualNetwork with attached resources, they
+	// are reported so the deletion can be retried once they are detached
+	attachments, _, err := e.client.GetAttachments(meta.GetExternalName(v))
+	if packetclient.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, errGetAttachments)
+	}
+	if err := vlanclient.ValidateDetached(attachments); err != nil {
+		return errors.Wrap(err, errDeleteVirtualNetwork)
+	}
+
+	_, err = e.client.Delete(meta.GetExternalName(v))
 	return errors.Wrap(resource.Ignore(packetclient.IsNotFound, err), errDeleteVirtualNetwork)
 }
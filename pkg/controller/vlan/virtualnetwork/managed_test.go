@@ -0,0 +1,657 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualnetwork
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/vlan/v1alpha1"
+	vlanclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/vlan"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/vlan/fake"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const (
+	vlanName = "my-cool-vlan"
+)
+
+var (
+	errorBoom = errors.New("boom")
+
+	errorMethodNotAllowed = &packngo.ErrorResponse{
+		Response: &http.Response{
+			Request:    &http.Request{Method: http.MethodPut, URL: &url.URL{Path: "/virtual-networks/" + vlanName}},
+			StatusCode: http.StatusMethodNotAllowed,
+		},
+	}
+)
+
+type vlanModifier func(*v1alpha1.VirtualNetwork)
+
+func withDescription(d string) vlanModifier {
+	return func(v *v1alpha1.VirtualNetwork) { v.Spec.ForProvider.Description = &d }
+}
+
+func withProjectID(id string) vlanModifier {
+	return func(v *v1alpha1.VirtualNetwork) { v.Spec.ForProvider.ProjectID = id }
+}
+
+func withMetro(m string) vlanModifier {
+	return func(v *v1alpha1.VirtualNetwork) { v.Spec.ForProvider.Metro = m }
+}
+
+func withFacility(f string) vlanModifier {
+	return func(v *v1alpha1.VirtualNetwork) { v.Spec.ForProvider.Facility = f }
+}
+
+func withVXLAN(x int) vlanModifier {
+	return func(v *v1alpha1.VirtualNetwork) { v.Spec.ForProvider.VXLAN = x }
+}
+
+func withConditions(c ...xpv1.Condition) vlanModifier {
+	return func(v *v1alpha1.VirtualNetwork) { v.Status.SetConditions(c...) }
+}
+
+func withObservedLocation(metro, facility string) vlanModifier {
+	return func(v *v1alpha1.VirtualNetwork) {
+		v.Status.AtProvider.MetroCode = metro
+		v.Status.AtProvider.FacilityCode = facility
+	}
+}
+
+func withID(id string) vlanModifier {
+	return func(v *v1alpha1.VirtualNetwork) { v.Status.AtProvider.ID = id }
+}
+
+func virtualNetwork(vm ...vlanModifier) *v1alpha1.VirtualNetwork {
+	v := &v1alpha1.VirtualNetwork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: vlanName,
+			Annotations: map[string]string{
+				meta.AnnotationKeyExternalName: vlanName,
+			},
+		},
+	}
+
+	for _, m := range vm {
+		m(v)
+	}
+
+	return v
+}
+
+var _ managed.ExternalClient = &external{}
+
+func TestObserve(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg          resource.Managed
+		observation managed.ExternalObservation
+		err         error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"DescriptionUpToDate": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGet: func(vlanID string, getOpt *packngo.GetOptions) (*packngo.VirtualNetwork, *packngo.Response, error) {
+						return &packngo.VirtualNetwork{ID: vlanName, Description: "cool"}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  virtualNetwork(withDescription("cool")),
+			},
+			want: want{
+				mg: virtualNetwork(
+					withDescription("cool"),
+					withID(vlanName),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"LateInitializedFacility": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockGet: func(vlanID string, getOpt *packngo.GetOptions) (*packngo.VirtualNetwork, *packngo.Response, error) {
+						return &packngo.VirtualNetwork{ID: vlanName, Description: "cool", MetroCode: "da", FacilityCode: "da11"}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  virtualNetwork(withDescription("cool"), withMetro("da")),
+			},
+			want: want{
+				mg: virtualNetwork(
+					withDescription("cool"),
+					withMetro("da"),
+					withFacility("da11"),
+					withObservedLocation("da", "da11"),
+					withID(vlanName),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"LateInitializedDescription": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockGet: func(vlanID string, getOpt *packngo.GetOptions) (*packngo.VirtualNetwork, *packngo.Response, error) {
+						return &packngo.VirtualNetwork{ID: vlanName, Description: "cool"}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  virtualNetwork(),
+			},
+			want: want{
+				mg: virtualNetwork(
+					withDescription("cool"),
+					withID(vlanName),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"DescriptionChanged": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGet: func(vlanID string, getOpt *packngo.GetOptions) (*packngo.VirtualNetwork, *packngo.Response, error) {
+						return &packngo.VirtualNetwork{ID: vlanName, Description: "cool"}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  virtualNetwork(withDescription("cooler")),
+			},
+			want: want{
+				mg: virtualNetwork(
+					withDescription("cooler"),
+					withID(vlanName),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := tc.client.Observe(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Observe(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.observation, o); diff != "" {
+				t.Errorf("tc.client.Observe(): -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		err error
+	}
+
+	existing := &packngo.VirtualNetworkListResponse{
+		VirtualNetworks: []packngo.VirtualNetwork{
+			{ID: "other", VXLAN: 1000, MetroCode: "da"},
+		},
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"CreatedWithVXLAN": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockGetProjectID: func(string) string { return "project" },
+					MockList: func(projectID string, listOpt *packngo.ListOptions) (*packngo.VirtualNetworkListResponse, *packngo.Response, error) {
+						return existing, nil, nil
+					},
+					MockCreate: func(createRequest *packngo.VirtualNetworkCreateRequest) (*packngo.VirtualNetwork, *packngo.Response, error) {
+						if diff := cmp.Diff(1001, createRequest.VXLAN); diff != "" {
+							t.Errorf("MockCreate: -want, +got:\n%s", diff)
+						}
+						return &packngo.VirtualNetwork{ID: vlanName}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  virtualNetwork(withMetro("da"), withVXLAN(1001)),
+			},
+			want: want{},
+		},
+		"CreatedInProject": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockGetProjectID: func(id string) string {
+						if id != "" {
+							return id
+						}
+						return "project"
+					},
+					MockCreate: func(createRequest *packngo.VirtualNetworkCreateRequest) (*packngo.VirtualNetwork, *packngo.Response, error) {
+						if diff := cmp.Diff("referenced-project", createRequest.ProjectID); diff != "" {
+							t.Errorf("MockCreate: -want, +got:\n%s", diff)
+						}
+						return &packngo.VirtualNetwork{ID: vlanName}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  virtualNetwork(withMetro("da"), withProjectID("referenced-project")),
+			},
+			want: want{},
+		},
+		"CreatedInDefaultProjectAndMetro": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockGetProjectID: func(id string) string {
+						if id != "" {
+							return id
+						}
+						return "default-project"
+					},
+					MockGetMetro: func(metro string) string {
+						if metro != "" {
+							return metro
+						}
+						return "da"
+					},
+					MockCreate: func(createRequest *packngo.VirtualNetworkCreateRequest) (*packngo.VirtualNetwork, *packngo.Response, error) {
+						want := &packngo.VirtualNetworkCreateRequest{ProjectID: "default-project", Metro: "da"}
+						if diff := cmp.Diff(want, createRequest); diff != "" {
+							t.Errorf("MockCreate: -want, +got:\n%s", diff)
+						}
+						return &packngo.VirtualNetwork{ID: vlanName}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  virtualNetwork(),
+			},
+			want: want{},
+		},
+		"CreatedInDefaultFacility": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockGetProjectID: func(string) string { return "default-project" },
+					MockGetMetro:     func(string) string { return "" },
+					MockGetFacilityID: func(facility string) string {
+						if facility != "" {
+							return facility
+						}
+						return "da11"
+					},
+					MockCreate: func(createRequest *packngo.VirtualNetworkCreateRequest) (*packngo.VirtualNetwork, *packngo.Response, error) {
+						want := &packngo.VirtualNetworkCreateRequest{ProjectID: "default-project", Facility: "da11"}
+						if diff := cmp.Diff(want, createRequest); diff != "" {
+							t.Errorf("MockCreate: -want, +got:\n%s", diff)
+						}
+						return &packngo.VirtualNetwork{ID: vlanName}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  virtualNetwork(),
+			},
+			want: want{},
+		},
+		"VXLANInUse": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGetProjectID: func(string) string { return "project" },
+					MockList: func(projectID string, listOpt *packngo.ListOptions) (*packngo.VirtualNetworkListResponse, *packngo.Response, error) {
+						return existing, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  virtualNetwork(withMetro("da"), withVXLAN(1000)),
+			},
+			want: want{
+				err: errors.Wrap(errors.New("VXLAN 1000 is already in use by VirtualNetwork other in metro da"), errInvalidVXLAN),
+			},
+		},
+		"VXLANWithoutMetro": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGetProjectID:  func(string) string { return "project" },
+					MockGetMetro:      func(string) string { return "" },
+					MockGetFacilityID: func(string) string { return "" },
+					MockList: func(projectID string, listOpt *packngo.ListOptions) (*packngo.VirtualNetworkListResponse, *packngo.Response, error) {
+						return existing, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  virtualNetwork(withVXLAN(1001)),
+			},
+			want: want{
+				err: errors.Wrap(errors.New("a VXLAN may only be requested for VirtualNetworks in a metro"), errInvalidVXLAN),
+			},
+		},
+		"MetroAndFacility": {
+			client: &external{
+				client: &fake.MockClient{},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  virtualNetwork(withMetro("da"), withFacility("da11")),
+			},
+			want: want{
+				err: errors.Wrap(errors.New("only one of metro or facility may be set"), errInvalidLocation),
+			},
+		},
+		"FailedToListVirtualNetworks": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGetProjectID: func(string) string { return "project" },
+					MockList: func(projectID string, listOpt *packngo.ListOptions) (*packngo.VirtualNetworkListResponse, *packngo.Response, error) {
+						return nil, nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  virtualNetwork(withMetro("da"), withVXLAN(1001)),
+			},
+			want: want{
+				err: errors.Wrap(errorBoom, errListVirtualNetworks),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.client.Create(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Create(): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreateDescriptionRoundTrip(t *testing.T) {
+	// created is the VirtualNetwork created through the fake, which returns
+	// it when observed
+	var created *packngo.VirtualNetwork
+	e := &external{
+		kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+		client: &fake.MockClient{
+			MockGetProjectID: func(string) string { return "project" },
+			MockCreate: func(createRequest *packngo.VirtualNetworkCreateRequest) (*packngo.VirtualNetwork, *packngo.Response, error) {
+				created = &packngo.VirtualNetwork{ID: vlanName, Description: createRequest.Description, MetroCode: createRequest.Metro}
+				return created, nil, nil
+			},
+			MockGet: func(vlanID string, getOpt *packngo.GetOptions) (*packngo.VirtualNetwork, *packngo.Response, error) {
+				return created, nil, nil
+			},
+		},
+	}
+
+	v := virtualNetwork(withDescription("cool"), withMetro("da"))
+	if _, err := e.Create(context.Background(), v); err != nil {
+		t.Fatalf("e.Create(): %v", err)
+	}
+	if diff := cmp.Diff("cool", created.Description); diff != "" {
+		t.Errorf("e.Create(): -want description, +got description:\n%s", diff)
+	}
+
+	o, err := e.Observe(context.Background(), v)
+	if err != nil {
+		t.Fatalf("e.Observe(): %v", err)
+	}
+	if !o.ResourceUpToDate {
+		t.Errorf("e.Observe(): want the created VirtualNetwork up to date")
+	}
+	if diff := cmp.Diff("cool", *v.Spec.ForProvider.Description); diff != "" {
+		t.Errorf("e.Observe(): -want description, +got description:\n%s", diff)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		err error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"UpdatedDescription": {
+			client: &external{
+				client: &fake.MockClient{
+					MockUpdate: func(vlanID string, updateRequest *vlanclient.UpdateRequest) (*packngo.VirtualNetwork, *packngo.Response, error) {
+						if diff := cmp.Diff("cooler", *updateRequest.Description); diff != "" {
+							t.Errorf("MockUpdate: -want, +got:\n%s", diff)
+						}
+						return &packngo.VirtualNetwork{ID: vlanID, Description: "cooler"}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  virtualNetwork(withDescription("cooler")),
+			},
+			want: want{},
+		},
+		"UpdateUnsupported": {
+			client: &external{
+				client: &fake.MockClient{
+					MockUpdate: func(vlanID string, updateRequest *vlanclient.UpdateRequest) (*packngo.VirtualNetwork, *packngo.Response, error) {
+						return nil, nil, errorMethodNotAllowed
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  virtualNetwork(withDescription("cooler")),
+			},
+			want: want{
+				err: errors.Wrap(errorMethodNotAllowed, errDescriptionImmutable),
+			},
+		},
+		"FailedToUpdate": {
+			client: &external{
+				client: &fake.MockClient{
+					MockUpdate: func(vlanID string, updateRequest *vlanclient.UpdateRequest) (*packngo.VirtualNetwork, *packngo.Response, error) {
+						return nil, nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  virtualNetwork(withDescription("cooler")),
+			},
+			want: want{
+				err: errors.Wrap(errorBoom, errUpdateVirtualNetwork),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.client.Update(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Update(): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		err error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Deleted": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGetAttachments: func(vlanID string) (*vlanclient.Attachments, *packngo.Response, error) {
+						return &vlanclient.Attachments{}, nil, nil
+					},
+					MockDelete: func(virtualNetworkID string) (*packngo.Response, error) {
+						return nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  virtualNetwork(),
+			},
+			want: want{},
+		},
+		"Attached": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGetAttachments: func(vlanID string) (*vlanclient.Attachments, *packngo.Response, error) {
+						return &vlanclient.Attachments{
+							Devices:       []vlanclient.Attachment{{Href: "/metal/v1/devices/device-1"}, {ID: "device-2"}},
+							MetalGateways: []vlanclient.Attachment{{Href: "/metal/v1/metal-gateways/gateway-1"}},
+						}, nil, nil
+					},
+					MockDelete: func(virtualNetworkID string) (*packngo.Response, error) {
+						t.Errorf("MockDelete: called for an attached VirtualNetwork")
+						return nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  virtualNetwork(),
+			},
+			want: want{
+				err: errors.Wrap(errors.New("VirtualNetwork can not be deleted while it is attached to Devices device-1, device-2 and MetalGateways gateway-1, detach them first"), errDeleteVirtualNetwork),
+			},
+		},
+		"AlreadyDeleted": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGetAttachments: func(vlanID string) (*vlanclient.Attachments, *packngo.Response, error) {
+						return nil, nil, &packngo.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  virtualNetwork(),
+			},
+			want: want{},
+		},
+		"FailedToGetAttachments": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGetAttachments: func(vlanID string) (*vlanclient.Attachments, *packngo.Response, error) {
+						return nil, nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  virtualNetwork(),
+			},
+			want: want{
+				err: errors.Wrap(errorBoom, errGetAttachments),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.client.Delete(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Delete(): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package attachment
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/volume/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	packetclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	attachmentclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/volume/attachment"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errManagedUpdateFailed     = "cannot update VolumeAttachment custom resource"
+	errTrackPCUsage            = "cannot track ProviderConfig usage"
+	errGetProviderConfigSecret = "cannot get ProviderConfig Secret"
+	errNewClient               = "cannot create new VolumeAttachment client"
+	errNotVolumeAttachment     = "managed resource is not a VolumeAttachment"
+	errGetVolume               = "cannot get Volume"
+	errGetDevice               = "cannot get Device"
+	errCreateVolumeAttachment  = "cannot create VolumeAttachment"
+	errDeleteVolumeAttachment  = "cannot delete VolumeAttachment"
+)
+
+// SetupVolumeAttachment adds a controller that reconciles VolumeAttachments
+func SetupVolumeAttachment(mgr ctrl.Manager, l logging.Logger, poll time.Duration) error {
+	name := managed.ControllerName(v1alpha1.VolumeAttachmentGroupKind)
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.VolumeAttachmentGroupVersionKind),
+		managed.WithExternalConnecter(clients.NewManagementPolicyConnecter(&connecter{
+			kube:  mgr.GetClient(),
+			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
+		})),
+		managed.WithInitializers(&managed.DefaultProviderConfig{}),
+		managed.WithConnectionPublishers(),
+		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+		managed.WithPollInterval(poll),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+	)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.VolumeAttachment{}).
+		Complete(clients.NewPollingReconciler(mgr, resource.ManagedKind(v1alpha1.VolumeAttachmentGroupVersionKind), r))
+}
+
+type connecter struct {
+	kube        client.Client
+	usage       resource.Tracker
+	newClientFn func(ctx context.Context, config *clients.Credentials) (attachmentclient.ClientWithDefaults, error)
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.VolumeAttachment); !ok {
+		return nil, errors.New(errNotVolumeAttachment)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	newClientFn := attachmentclient.NewClient
+	if c.newClientFn != nil {
+		newClientFn = c.newClientFn
+	}
+	cfg, err := clients.GetAuthInfo(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetProviderConfigSecret)
+	}
+	client, err := newClientFn(ctx, cfg)
+
+	return &external{kube: c.kube, client: client}, errors.Wrap(err, errNewClient)
+}
+
+type external struct {
+	kube   client.Client
+	client attachmentclient.ClientWithDefaults
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	a, ok := mg.(*v1alpha1.VolumeAttachment)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotVolumeAttachment)
+	}
+
+	// Volumes are detached from Devices when the Device is deleted
+	_, _, err := e.client.GetDevice(a.Spec.ForProvider.DeviceID, nil)
+	if packetclient.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetDevice)
+	}
+
+	volume, _, err := e.client.GetVolume(a.Spec.ForProvider.VolumeID, nil)
+	if packetclient.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetVolume)
+	}
+
+	attachment := attachmentclient.FindAttachment(volume, a.Spec.ForProvider.DeviceID)
+	if attachment == nil {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	a.Status.AtProvider = v1alpha1.VolumeAttachmentObservation{
+		ID:   attachment.ID,
+		Href: attachment.Href,
+	}
+	a.Status.SetConditions(xpv1.Available())
+
+	// VolumeAttachments can not be modified
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: true,
+	}, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	a, ok := mg.(*v1alpha1.VolumeAttachment)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotVolumeAttachment)
+	}
+
+	a.Status.SetConditions(xpv1.Creating())
+
+	attachment, _, err := e.client.Create(a.Spec.ForProvider.VolumeID, a.Spec.ForProvider.DeviceID)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateVolumeAttachment)
+	}
+
+	a.Status.AtProvider.ID = attachment.ID
+	meta.SetExternalName(a, attachment.ID)
+	if err := e.kube.Update(ctx, a); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errManagedUpdateFailed)
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	// NOTE: VolumeAttachments are immutable and are never updated.
+	return managed.ExternalUpdate{}, nil
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	a, ok := mg.(*v1alpha1.VolumeAttachment)
+	if !ok {
+		return errors.New(errNotVolumeAttachment)
+	}
+	a.SetConditions(xpv1.Deleting())
+
+	// A deleted Device has no attached Volumes, so there is nothing left to
+	// detach
+	_, _, err := e.client.GetDevice(a.Spec.ForProvider.DeviceID, nil)
+	if packetclient.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, errGetDevice)
+	}
+
+	_, err = e.client.Delete(meta.GetExternalName(a))
+	return errors.Wrap(resource.Ignore(packetclient.IsNotFound, err), errDeleteVolumeAttachment)
+}
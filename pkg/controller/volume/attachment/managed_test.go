@@ -0,0 +1,474 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package attachment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/volume/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/volume/attachment/fake"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const (
+	attachmentID = "attachment-id"
+	volumeID     = "volume-id"
+	deviceID     = "device-id"
+)
+
+var errorBoom = errors.New("boom")
+
+type strange struct {
+	resource.Managed
+}
+
+type attachmentModifier func(*v1alpha1.VolumeAttachment)
+
+func withExternalName(id string) attachmentModifier {
+	return func(a *v1alpha1.VolumeAttachment) { meta.SetExternalName(a, id) }
+}
+
+func withConditions(c ...xpv1.Condition) attachmentModifier {
+	return func(a *v1alpha1.VolumeAttachment) { a.Status.SetConditions(c...) }
+}
+
+func withObservation(o v1alpha1.VolumeAttachmentObservation) attachmentModifier {
+	return func(a *v1alpha1.VolumeAttachment) { a.Status.AtProvider = o }
+}
+
+func attachment(am ...attachmentModifier) *v1alpha1.VolumeAttachment {
+	a := &v1alpha1.VolumeAttachment{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cool-attachment"},
+		Spec: v1alpha1.VolumeAttachmentSpec{
+			ForProvider: v1alpha1.VolumeAttachmentParameters{
+				VolumeID: volumeID,
+				DeviceID: deviceID,
+			},
+		},
+	}
+	for _, m := range am {
+		m(a)
+	}
+	return a
+}
+
+func getDevice(err error) func(string, *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+	return func(id string, _ *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+		if err != nil {
+			return nil, nil, err
+		}
+		return &packngo.Device{ID: id}, nil, nil
+	}
+}
+
+func getVolume(attachments ...*packngo.VolumeAttachment) func(string, *packngo.GetOptions) (*packngo.Volume, *packngo.Response, error) {
+	return func(id string, _ *packngo.GetOptions) (*packngo.Volume, *packngo.Response, error) {
+		return &packngo.Volume{ID: id, Attachments: attachments}, nil, nil
+	}
+}
+
+var _ managed.ExternalClient = &external{}
+
+func TestObserve(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg          resource.Managed
+		observation managed.ExternalObservation
+		err         error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Attached": {
+			client: &external{client: &fake.MockClient{
+				MockGetDevice: getDevice(nil),
+				MockGetVolume: getVolume(
+					&packngo.VolumeAttachment{ID: "other", Device: packngo.Device{ID: "other-device"}},
+					nil,
+					&packngo.VolumeAttachment{
+						ID:     attachmentID,
+						Href:   "/metal/v1/storage/attachments/" + attachmentID,
+						Device: packngo.Device{Href: "/metal/v1/devices/" + deviceID},
+					}),
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  attachment(withExternalName(attachmentID)),
+			},
+			want: want{
+				mg: attachment(
+					withExternalName(attachmentID),
+					withObservation(v1alpha1.VolumeAttachmentObservation{
+						ID:   attachmentID,
+						Href: "/metal/v1/storage/attachments/" + attachmentID,
+					}),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"NotAttached": {
+			client: &external{client: &fake.MockClient{
+				MockGetDevice: getDevice(nil),
+				MockGetVolume: getVolume(&packngo.VolumeAttachment{ID: "other", Device: packngo.Device{ID: "other-device"}}),
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  attachment(),
+			},
+			want: want{
+				mg:          attachment(),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"DeviceNotFound": {
+			client: &external{client: &fake.MockClient{
+				MockGetDevice: getDevice(packettest.NotFound()),
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  attachment(),
+			},
+			want: want{
+				mg:          attachment(),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"VolumeNotFound": {
+			client: &external{client: &fake.MockClient{
+				MockGetDevice: getDevice(nil),
+				MockGetVolume: func(string, *packngo.GetOptions) (*packngo.Volume, *packngo.Response, error) {
+					return nil, nil, packettest.NotFound()
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  attachment(),
+			},
+			want: want{
+				mg:          attachment(),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"FailedToGetDevice": {
+			client: &external{client: &fake.MockClient{
+				MockGetDevice: getDevice(errorBoom),
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  attachment(),
+			},
+			want: want{
+				mg:  attachment(),
+				err: errors.Wrap(errorBoom, errGetDevice),
+			},
+		},
+		"FailedToGetVolume": {
+			client: &external{client: &fake.MockClient{
+				MockGetDevice: getDevice(nil),
+				MockGetVolume: func(string, *packngo.GetOptions) (*packngo.Volume, *packngo.Response, error) {
+					return nil, nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  attachment(),
+			},
+			want: want{
+				mg:  attachment(),
+				err: errors.Wrap(errorBoom, errGetVolume),
+			},
+		},
+		"NotVolumeAttachment": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotVolumeAttachment),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := tc.client.Observe(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Observe(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.observation, o); diff != "" {
+				t.Errorf("tc.client.Observe(): -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Created": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockCreate: func(vID, dID string) (*packngo.VolumeAttachment, *packngo.Response, error) {
+						if vID != volumeID || dID != deviceID {
+							t.Errorf("MockCreate: want %q, %q, got %q, %q", volumeID, deviceID, vID, dID)
+						}
+						return &packngo.VolumeAttachment{ID: attachmentID}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  attachment(),
+			},
+			want: want{
+				mg: attachment(
+					withExternalName(attachmentID),
+					withObservation(v1alpha1.VolumeAttachmentObservation{ID: attachmentID}),
+					withConditions(xpv1.Creating())),
+			},
+		},
+		"FailedToCreate": {
+			client: &external{client: &fake.MockClient{
+				MockCreate: func(string, string) (*packngo.VolumeAttachment, *packngo.Response, error) {
+					return nil, nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  attachment(),
+			},
+			want: want{
+				mg:  attachment(withConditions(xpv1.Creating())),
+				err: errors.Wrap(errorBoom, errCreateVolumeAttachment),
+			},
+		},
+		"FailedToUpdateManaged": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errorBoom)},
+				client: &fake.MockClient{
+					MockCreate: func(string, string) (*packngo.VolumeAttachment, *packngo.Response, error) {
+						return &packngo.VolumeAttachment{ID: attachmentID}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  attachment(),
+			},
+			want: want{
+				mg: attachment(
+					withExternalName(attachmentID),
+					withObservation(v1alpha1.VolumeAttachmentObservation{ID: attachmentID}),
+					withConditions(xpv1.Creating())),
+				err: errors.Wrap(errorBoom, errManagedUpdateFailed),
+			},
+		},
+		"NotVolumeAttachment": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotVolumeAttachment),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.client.Create(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Create(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	// VolumeAttachments are immutable, the fake panics if any API is called
+	e := &external{client: &fake.MockClient{}}
+
+	if _, err := e.Update(context.Background(), attachment(withExternalName(attachmentID))); err != nil {
+		t.Errorf("e.Update(): %v", err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Deleted": {
+			client: &external{client: &fake.MockClient{
+				MockGetDevice: getDevice(nil),
+				MockDelete: func(id string) (*packngo.Response, error) {
+					if id != attachmentID {
+						t.Errorf("MockDelete: want %q, got %q", attachmentID, id)
+					}
+					return nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  attachment(withExternalName(attachmentID)),
+			},
+			want: want{
+				mg: attachment(withExternalName(attachmentID), withConditions(xpv1.Deleting())),
+			},
+		},
+		"DeviceGone": {
+			client: &external{client: &fake.MockClient{
+				MockGetDevice: getDevice(packettest.NotFound()),
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  attachment(withExternalName(attachmentID)),
+			},
+			want: want{
+				mg: attachment(withExternalName(attachmentID), withConditions(xpv1.Deleting())),
+			},
+		},
+		"AlreadyDeleted": {
+			client: &external{client: &fake.MockClient{
+				MockGetDevice: getDevice(nil),
+				MockDelete: func(string) (*packngo.Response, error) {
+					return nil, packettest.NotFound()
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  attachment(withExternalName(attachmentID)),
+			},
+			want: want{
+				mg: attachment(withExternalName(attachmentID), withConditions(xpv1.Deleting())),
+			},
+		},
+		"FailedToGetDevice": {
+			client: &external{client: &fake.MockClient{
+				MockGetDevice: getDevice(errorBoom),
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  attachment(withExternalName(attachmentID)),
+			},
+			want: want{
+				mg:  attachment(withExternalName(attachmentID), withConditions(xpv1.Deleting())),
+				err: errors.Wrap(errorBoom, errGetDevice),
+			},
+		},
+		"FailedToDelete": {
+			client: &external{client: &fake.MockClient{
+				MockGetDevice: getDevice(nil),
+				MockDelete: func(string) (*packngo.Response, error) {
+					return nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  attachment(withExternalName(attachmentID)),
+			},
+			want: want{
+				mg:  attachment(withExternalName(attachmentID), withConditions(xpv1.Deleting())),
+				err: errors.Wrap(errorBoom, errDeleteVolumeAttachment),
+			},
+		},
+		"NotVolumeAttachment": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotVolumeAttachment),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.client.Delete(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Delete(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/volume/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	packetclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	volumeclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/volume"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errManagedUpdateFailed     = "cannot update Volume custom resource"
+	errTrackPCUsage            = "cannot track ProviderConfig usage"
+	errGetProviderConfigSecret = "cannot get ProviderConfig Secret"
+	errGenObservation          = "cannot generate observation"
+	errNewClient               = "cannot create new Volume client"
+	errNotVolume               = "managed resource is not a Volume"
+	errGetVolume               = "cannot get Volume"
+	errCreateVolume            = "cannot create Volume"
+	errUpdateVolume            = "cannot modify Volume"
+	errLockVolume              = "cannot lock Volume"
+	errUnlockVolume            = "cannot unlock Volume"
+	errDeleteVolume            = "cannot delete Volume"
+)
+
+// SetupVolume adds a controller that reconciles Volumes
+func SetupVolume(mgr ctrl.Manager, l logging.Logger, poll time.Duration) error {
+	name := managed.ControllerName(v1alpha1.VolumeGroupKind)
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.VolumeGroupVersionKind),
+		managed.WithExternalConnecter(clients.NewManagementPolicyConnecter(&connecter{
+			kube:  mgr.GetClient(),
+			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
+		})),
+		managed.WithInitializers(&managed.DefaultProviderConfig{}),
+		managed.WithConnectionPublishers(),
+		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+		managed.WithPollInterval(poll),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+	)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.Volume{}).
+		Complete(clients.NewPollingReconciler(mgr, resource.ManagedKind(v1alpha1.VolumeGroupVersionKind), r))
+}
+
+type connecter struct {
+	kube        client.Client
+	usage       resource.Tracker
+	newClientFn func(ctx context.Context, config *clients.Credentials) (volumeclient.ClientWithDefaults, error)
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.Volume); !ok {
+		return nil, errors.New(errNotVolume)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	newClientFn := volumeclient.NewClient
+	if c.newClientFn != nil {
+		newClientFn = c.newClientFn
+	}
+	cfg, err := clients.GetAuthInfo(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetProviderConfigSecret)
+	}
+	client, err := newClientFn(ctx, cfg)
+
+	return &external{kube: c.kube, client: client}, errors.Wrap(err, errNewClient)
+}
+
+type external struct {
+	kube   client.Client
+	client volumeclient.ClientWithDefaults
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	v, ok := mg.(*v1alpha1.Volume)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotVolume)
+	}
+
+	// Observe volume
+	volume, _, err := e.client.Get(meta.GetExternalName(v), nil)
+	if packetclient.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetVolume)
+	}
+
+	current := v.Spec.ForProvider.DeepCopy()
+	volumeclient.LateInitialize(&v.Spec.ForProvider, volume)
+	if !cmp.Equal(current, &v.Spec.ForProvider) {
+		if err := e.kube.Update(ctx, v); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errManagedUpdateFailed)
+		}
+	}
+
+	v.Status.AtProvider, err = volumeclient.GenerateObservation(volume)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGenObservation)
+	}
+
+	switch v.Status.AtProvider.State {
+	case v1alpha1.StateActive:
+		v.Status.SetConditions(xpv1.Available())
+	case v1alpha1.StateQueued,
+		v1alpha1.StateProvisioning:
+		v.Status.SetConditions(xpv1.Creating())
+	default:
+		v.Status.SetConditions(xpv1.Unavailable())
+	}
+
+	o := managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: volumeclient.IsUpToDate(v, volume),
+	}
+
+	return o, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	v, ok := mg.(*v1alpha1.Volume)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotVolume)
+	}
+
+	v.Status.SetConditions(xpv1.Creating())
+
+	facilityID := e.client.GetFacilityID(volumeclient.FacilityOrEmpty(v))
+	create := volumeclient.CreateFromVolume(v, facilityID)
+	volume, _, err := e.client.Create(create, e.client.GetProjectID(v.Spec.ForProvider.ProjectID))
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateVolume)
+	}
+
+	v.Status.AtProvider.ID = volume.ID
+	meta.SetExternalName(v, volume.ID)
+
+	// The resource exists once it is created, so failing to persist its
+	// external name is not reported as a failed creation. The reconciler is
+	// asked to persist the external name instead, retrying as it does so.
+	if err := e.kube.Update(ctx, v); err != nil {
+		return managed.ExternalCreation{ExternalNameAssigned: true}, nil
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	v, ok := mg.(*v1alpha1.Volume)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotVolume)
+	}
+
+	id := meta.GetExternalName(v)
+	volume, _, err := e.client.Get(id, nil)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errGetVolume)
+	}
+
+	// Locked volumes can not be modified, so unlock before updating and lock
+	// after updating unless the volume is to be unlocked
+	locked := volume.Locked
+	if v.Spec.ForProvider.Locked != nil {
+		locked = *v.Spec.ForProvider.Locked
+	}
+	if volume.Locked {
+		if _, err := e.client.Unlock(id); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errUnlockVolume)
+		}
+	}
+
+	if _, _, err := e.client.Update(id, volumeclient.NewUpdateVolumeRequest(v)); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateVolume)
+	}
+
+	if locked {
+		if _, err := e.client.Lock(id); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errLockVolume)
+		}
+	}
+
+	return managed.ExternalUpdate{}, nil
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	v, ok := mg.(*v1alpha1.Volume)
+	if !ok {
+		return errors.New(errNotVolume)
+	}
+	v.SetConditions(xpv1.Deleting())
+
+	// Locked volumes can not be deleted, so the volume is unlocked first
+	id := meta.GetExternalName(v)
+	volume, _, err := e.client.Get(id, nil)
+	if err != nil {
+		return errors.Wrap(resource.Ignore(packetclient.IsNotFound, err), errGetVolume)
+	}
+	if volume.Locked {
+		if _, err := e.client.Unlock(id); resource.Ignore(packetclient.IsNotFound, err) != nil {
+			return errors.Wrap(err, errUnlockVolume)
+		}
+	}
+
+	_, err = e.client.Delete(id)
+	return errors.Wrap(resource.Ignore(packetclient.IsNotFound, err), errDeleteVolume)
+}
@@ -0,0 +1,739 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/volume/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/volume/fake"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const (
+	volumeName = "my-cool-volume"
+	volumeID   = "volume-id"
+)
+
+var errorBoom = errors.New("boom")
+
+type strange struct {
+	resource.Managed
+}
+
+type volumeModifier func(*v1alpha1.Volume)
+
+func withExternalName(id string) volumeModifier {
+	return func(v *v1alpha1.Volume) { meta.SetExternalName(v, id) }
+}
+
+func withSize(s int) volumeModifier {
+	return func(v *v1alpha1.Volume) { v.Spec.ForProvider.Size = s }
+}
+
+func withLocked(l bool) volumeModifier {
+	return func(v *v1alpha1.Volume) { v.Spec.ForProvider.Locked = &l }
+}
+
+// withSpecified sets the parameters that are late initialized to those of
+// the observed volume
+func withSpecified() volumeModifier {
+	return func(v *v1alpha1.Volume) {
+		plan, facility, billingCycle, description, locked := "storage_1", "da11", "hourly", "cool", false
+		v.Spec.ForProvider.PlanID = &plan
+		v.Spec.ForProvider.Facility = &facility
+		v.Spec.ForProvider.BillingCycle = &billingCycle
+		v.Spec.ForProvider.Description = &description
+		v.Spec.ForProvider.Locked = &locked
+	}
+}
+
+func withConditions(c ...xpv1.Condition) volumeModifier {
+	return func(v *v1alpha1.Volume) { v.Status.SetConditions(c...) }
+}
+
+func withObservation(o v1alpha1.VolumeObservation) volumeModifier {
+	return func(v *v1alpha1.Volume) { v.Status.AtProvider = o }
+}
+
+func volume(vm ...volumeModifier) *v1alpha1.Volume {
+	v := &v1alpha1.Volume{
+		ObjectMeta: metav1.ObjectMeta{Name: volumeName},
+		Spec: v1alpha1.VolumeSpec{
+			ForProvider: v1alpha1.VolumeParameters{Size: 100},
+		},
+	}
+	for _, m := range vm {
+		m(v)
+	}
+	return v
+}
+
+// observed returns a volume in the supplied state that matches the
+// parameters set by withSpecified
+func observed(state string) *packngo.Volume {
+	return &packngo.Volume{
+		ID:           volumeID,
+		Name:         "volume-1",
+		Size:         100,
+		State:        state,
+		BillingCycle: "hourly",
+		Description:  "cool",
+		Plan:         &packngo.Plan{Slug: "storage_1"},
+		Facility:     &packngo.Facility{Code: "da11"},
+		Attachments: []*packngo.VolumeAttachment{
+			{ID: "attachment-id", Device: packngo.Device{Href: "/metal/v1/devices/device-id"}},
+		},
+	}
+}
+
+var _ managed.ExternalClient = &external{}
+
+func TestObserve(t *testing.T) {
+	observation := func(state string) v1alpha1.VolumeObservation {
+		return v1alpha1.VolumeObservation{
+			ID:          volumeID,
+			Name:        "volume-1",
+			State:       state,
+			Attachments: []v1alpha1.Attachment{{ID: "attachment-id", DeviceID: "device-id"}},
+		}
+	}
+
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg          resource.Managed
+		observation managed.ExternalObservation
+		err         error
+	}
+
+	// getting returns a client that gets the supplied volume
+	getting := func(v *packngo.Volume) *fake.MockClient {
+		return &fake.MockClient{
+			MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.Volume, *packngo.Response, error) {
+				if id != volumeID {
+					t.Errorf("MockGet: want volume %q, got %q", volumeID, id)
+				}
+				return v, nil, nil
+			},
+		}
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Available": {
+			client: &external{client: getting(observed(v1alpha1.StateActive))},
+			args: args{
+				ctx: context.Background(),
+				mg:  volume(withExternalName(volumeID), withSpecified()),
+			},
+			want: want{
+				mg: volume(
+					withExternalName(volumeID),
+					withSpecified(),
+					withObservation(observation(v1alpha1.StateActive)),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"LateInitialized": {
+			client: &external{
+				kube:   &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: getting(observed(v1alpha1.StateActive)),
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  volume(withExternalName(volumeID)),
+			},
+			want: want{
+				mg: volume(
+					withExternalName(volumeID),
+					withSpecified(),
+					withObservation(observation(v1alpha1.StateActive)),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"Provisioning": {
+			client: &external{client: getting(observed(v1alpha1.StateProvisioning))},
+			args: args{
+				ctx: context.Background(),
+				mg:  volume(withExternalName(volumeID), withSpecified()),
+			},
+			want: want{
+				mg: volume(
+					withExternalName(volumeID),
+					withSpecified(),
+					withObservation(observation(v1alpha1.StateProvisioning)),
+					withConditions(xpv1.Creating())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"Failed": {
+			client: &external{client: getting(observed(v1alpha1.StateFailed))},
+			args: args{
+				ctx: context.Background(),
+				mg:  volume(withExternalName(volumeID), withSpecified()),
+			},
+			want: want{
+				mg: volume(
+					withExternalName(volumeID),
+					withSpecified(),
+					withObservation(observation(v1alpha1.StateFailed)),
+					withConditions(xpv1.Unavailable())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"Resized": {
+			client: &external{client: getting(observed(v1alpha1.StateActive))},
+			args: args{
+				ctx: context.Background(),
+				mg:  volume(withExternalName(volumeID), withSpecified(), withSize(200)),
+			},
+			want: want{
+				mg: volume(
+					withExternalName(volumeID),
+					withSpecified(),
+					withSize(200),
+					withObservation(observation(v1alpha1.StateActive)),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+				},
+			},
+		},
+		"LockChanged": {
+			client: &external{client: getting(observed(v1alpha1.StateActive))},
+			args: args{
+				ctx: context.Background(),
+				mg:  volume(withExternalName(volumeID), withSpecified(), withLocked(true)),
+			},
+			want: want{
+				mg: volume(
+					withExternalName(volumeID),
+					withSpecified(),
+					withLocked(true),
+					withObservation(observation(v1alpha1.StateActive)),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+				},
+			},
+		},
+		"NotFound": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.Volume, *packngo.Response, error) {
+						return nil, nil, packettest.NotFound()
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  volume(withExternalName(volumeID)),
+			},
+			want: want{
+				mg:          volume(withExternalName(volumeID)),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"FailedToGetVolume": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.Volume, *packngo.Response, error) {
+						return nil, nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  volume(withExternalName(volumeID)),
+			},
+			want: want{
+				mg:  volume(withExternalName(volumeID)),
+				err: errors.Wrap(errorBoom, errGetVolume),
+			},
+		},
+		"NotVolume": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotVolume),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := tc.client.Observe(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Observe(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.observation, o); diff != "" {
+				t.Errorf("tc.client.Observe(): -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg       resource.Managed
+		creation managed.ExternalCreation
+		err      error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Created": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockGetFacilityID: func(facility string) string {
+						if facility != "" {
+							return facility
+						}
+						return "sv15"
+					},
+					MockGetProjectID: func(id string) string {
+						if id != "" {
+							return id
+						}
+						return "project"
+					},
+					MockCreate: func(createRequest *packngo.VolumeCreateRequest, projectID string) (*packngo.Volume, *packngo.Response, error) {
+						if projectID != "project" {
+							t.Errorf("MockCreate: want project %q, got %q", "project", projectID)
+						}
+						want := &packngo.VolumeCreateRequest{
+							Size:         100,
+							PlanID:       "storage_1",
+							FacilityID:   "da11",
+							BillingCycle: "hourly",
+							Description:  "cool",
+						}
+						if diff := cmp.Diff(want, createRequest); diff != "" {
+							t.Errorf("MockCreate: -want, +got:\n%s", diff)
+						}
+						return &packngo.Volume{ID: volumeID}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  volume(withSpecified()),
+			},
+			want: want{
+				mg: volume(
+					withSpecified(),
+					withExternalName(volumeID),
+					withObservation(v1alpha1.VolumeObservation{ID: volumeID}),
+					withConditions(xpv1.Creating())),
+			},
+		},
+		"CreatedInDefaultFacility": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockGetFacilityID: func(facility string) string {
+						if facility != "" {
+							return facility
+						}
+						return "sv15"
+					},
+					MockGetProjectID: func(string) string { return "project" },
+					MockCreate: func(createRequest *packngo.VolumeCreateRequest, projectID string) (*packngo.Volume, *packngo.Response, error) {
+						want := &packngo.VolumeCreateRequest{Size: 100, FacilityID: "sv15", Locked: true}
+						if diff := cmp.Diff(want, createRequest); diff != "" {
+							t.Errorf("MockCreate: -want, +got:\n%s", diff)
+						}
+						return &packngo.Volume{ID: volumeID}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  volume(withLocked(true)),
+			},
+			want: want{
+				mg: volume(
+					withLocked(true),
+					withExternalName(volumeID),
+					withObservation(v1alpha1.VolumeObservation{ID: volumeID}),
+					withConditions(xpv1.Creating())),
+			},
+		},
+		"FailedToCreate": {
+			client: &external{
+				client: &fake.MockClient{
+					MockGetFacilityID: func(string) string { return "sv15" },
+					MockGetProjectID:  func(string) string { return "project" },
+					MockCreate: func(createRequest *packngo.VolumeCreateRequest, projectID string) (*packngo.Volume, *packngo.Response, error) {
+						return nil, nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  volume(),
+			},
+			want: want{
+				mg:  volume(withConditions(xpv1.Creating())),
+				err: errors.Wrap(errorBoom, errCreateVolume),
+			},
+		},
+		"FailedToRecordExternalName": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errorBoom)},
+				client: &fake.MockClient{
+					MockGetFacilityID: func(string) string { return "sv15" },
+					MockGetProjectID:  func(string) string { return "project" },
+					MockCreate: func(createRequest *packngo.VolumeCreateRequest, projectID string) (*packngo.Volume, *packngo.Response, error) {
+						return &packngo.Volume{ID: volumeID}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  volume(),
+			},
+			want: want{
+				mg: volume(
+					withExternalName(volumeID),
+					withObservation(v1alpha1.VolumeObservation{ID: volumeID}),
+					withConditions(xpv1.Creating())),
+				creation: managed.ExternalCreation{ExternalNameAssigned: true},
+			},
+		},
+		"NotVolume": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotVolume),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, err := tc.client.Create(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Create(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.creation, c); diff != "" {
+				t.Errorf("tc.client.Create(): -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	type want struct {
+		err   error
+		calls []string
+	}
+
+	cases := map[string]struct {
+		mg        *v1alpha1.Volume
+		locked    bool
+		getErr    error
+		updateErr error
+		lockErr   error
+		want      want
+	}{
+		"Updated": {
+			mg:   volume(withExternalName(volumeID), withSpecified(), withSize(200)),
+			want: want{calls: []string{"update"}},
+		},
+		"Locked": {
+			mg:   volume(withExternalName(volumeID), withSpecified(), withLocked(true)),
+			want: want{calls: []string{"update", "lock"}},
+		},
+		"Unlocked": {
+			mg:     volume(withExternalName(volumeID), withSpecified()),
+			locked: true,
+			want:   want{calls: []string{"unlock", "update"}},
+		},
+		"StaysLocked": {
+			mg:     volume(withExternalName(volumeID), withSpecified(), withLocked(true), withSize(200)),
+			locked: true,
+			want:   want{calls: []string{"unlock", "update", "lock"}},
+		},
+		"LockNotRequested": {
+			mg:     volume(withExternalName(volumeID), withSize(200)),
+			locked: true,
+			want:   want{calls: []string{"unlock", "update", "lock"}},
+		},
+		"FailedToGetVolume": {
+			mg:     volume(withExternalName(volumeID), withSpecified()),
+			getErr: errorBoom,
+			want:   want{err: errors.Wrap(errorBoom, errGetVolume)},
+		},
+		"FailedToUnlock": {
+			mg:      volume(withExternalName(volumeID), withSpecified()),
+			locked:  true,
+			lockErr: errorBoom,
+			want: want{
+				err:   errors.Wrap(errorBoom, errUnlockVolume),
+				calls: []string{"unlock"},
+			},
+		},
+		"FailedToUpdate": {
+			mg:        volume(withExternalName(volumeID), withSpecified(), withLocked(true)),
+			updateErr: errorBoom,
+			want: want{
+				err:   errors.Wrap(errorBoom, errUpdateVolume),
+				calls: []string{"update"},
+			},
+		},
+		"FailedToLock": {
+			mg:      volume(withExternalName(volumeID), withSpecified(), withLocked(true)),
+			lockErr: errorBoom,
+			want: want{
+				err:   errors.Wrap(errorBoom, errLockVolume),
+				calls: []string{"update", "lock"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// calls records the order of the API calls that modify the volume
+			var calls []string
+			e := &external{client: &fake.MockClient{
+				MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.Volume, *packngo.Response, error) {
+					if tc.getErr != nil {
+						return nil, nil, tc.getErr
+					}
+					v := observed(v1alpha1.StateActive)
+					v.Locked = tc.locked
+					return v, nil, nil
+				},
+				MockUnlock: func(id string) (*packngo.Response, error) {
+					calls = append(calls, "unlock")
+					return nil, tc.lockErr
+				},
+				MockUpdate: func(id string, updateRequest *packngo.VolumeUpdateRequest) (*packngo.Volume, *packngo.Response, error) {
+					calls = append(calls, "update")
+					if id != volumeID {
+						t.Errorf("MockUpdate: want volume %q, got %q", volumeID, id)
+					}
+					if diff := cmp.Diff(tc.mg.Spec.ForProvider.Size, *updateRequest.Size); diff != "" {
+						t.Errorf("MockUpdate: -want size, +got size:\n%s", diff)
+					}
+					return &packngo.Volume{ID: volumeID}, nil, tc.updateErr
+				},
+				MockLock: func(id string) (*packngo.Response, error) {
+					calls = append(calls, "lock")
+					return nil, tc.lockErr
+				},
+			}}
+
+			_, err := e.Update(context.Background(), tc.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("e.Update(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.calls, calls); diff != "" {
+				t.Errorf("calls: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type want struct {
+		mg    resource.Managed
+		err   error
+		calls []string
+	}
+
+	cases := map[string]struct {
+		mg        resource.Managed
+		locked    bool
+		getErr    error
+		unlockErr error
+		deleteErr error
+		want      want
+	}{
+		"Deleted": {
+			mg: volume(withExternalName(volumeID)),
+			want: want{
+				mg:    volume(withExternalName(volumeID), withConditions(xpv1.Deleting())),
+				calls: []string{"delete"},
+			},
+		},
+		"UnlockedAndDeleted": {
+			mg:     volume(withExternalName(volumeID), withLocked(true)),
+			locked: true,
+			want: want{
+				mg:    volume(withExternalName(volumeID), withLocked(true), withConditions(xpv1.Deleting())),
+				calls: []string{"unlock", "delete"},
+			},
+		},
+		"AlreadyDeleted": {
+			mg:     volume(withExternalName(volumeID)),
+			getErr: packettest.NotFound(),
+			want: want{
+				mg: volume(withExternalName(volumeID), withConditions(xpv1.Deleting())),
+			},
+		},
+		"DeletedConcurrently": {
+			mg:        volume(withExternalName(volumeID)),
+			deleteErr: packettest.NotFound(),
+			want: want{
+				mg:    volume(withExternalName(volumeID), withConditions(xpv1.Deleting())),
+				calls: []string{"delete"},
+			},
+		},
+		"FailedToGetVolume": {
+			mg:     volume(withExternalName(volumeID)),
+			getErr: errorBoom,
+			want: want{
+				mg:  volume(withExternalName(volumeID), withConditions(xpv1.Deleting())),
+				err: errors.Wrap(errorBoom, errGetVolume),
+			},
+		},
+		"FailedToUnlock": {
+			mg:        volume(withExternalName(volumeID)),
+			locked:    true,
+			unlockErr: errorBoom,
+			want: want{
+				mg:    volume(withExternalName(volumeID), withConditions(xpv1.Deleting())),
+				err:   errors.Wrap(errorBoom, errUnlockVolume),
+				calls: []string{"unlock"},
+			},
+		},
+		"FailedToDelete": {
+			mg:        volume(withExternalName(volumeID)),
+			deleteErr: errorBoom,
+			want: want{
+				mg:    volume(withExternalName(volumeID), withConditions(xpv1.Deleting())),
+				err:   errors.Wrap(errorBoom, errDeleteVolume),
+				calls: []string{"delete"},
+			},
+		},
+		"NotVolume": {
+			mg: &strange{},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotVolume),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// calls records the order of the API calls that modify the volume
+			var calls []string
+			e := &external{client: &fake.MockClient{
+				MockGet: func(id string, getOpt *packngo.GetOptions) (*packngo.Volume, *packngo.Response, error) {
+					if tc.getErr != nil {
+						return nil, nil, tc.getErr
+					}
+					v := observed(v1alpha1.StateActive)
+					v.Locked = tc.locked
+					return v, nil, nil
+				},
+				MockUnlock: func(id string) (*packngo.Response, error) {
+					calls = append(calls, "unlock")
+					return nil, tc.unlockErr
+				},
+				MockDelete: func(id string) (*packngo.Response, error) {
+					calls = append(calls, "delete")
+					if id != volumeID {
+						t.Errorf("MockDelete: want volume %q, got %q", volumeID, id)
+					}
+					return nil, tc.deleteErr
+				},
+			}}
+
+			err := e.Delete(context.Background(), tc.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("e.Delete(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.calls, calls); diff != "" {
+				t.Errorf("calls: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vrf
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/vrf/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	vrfclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/vrf"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errManagedUpdateFailed     = "cannot update VRF custom resource"
+	errTrackPCUsage            = "cannot track ProviderConfig usage"
+	errGetProviderConfigSecret = "cannot get ProviderConfig Secret"
+	errGenObservation          = "cannot generate observation"
+	errNewClient               = "cannot create new VRF client"
+	errNotVRF                  = "managed resource is not a VRF"
+	errGetVRF                  = "cannot get VRF"
+	errCreateVRF               = "cannot create VRF"
+	errUpdateVRF               = "cannot modify VRF"
+	errDeleteVRF               = "cannot delete VRF"
+)
+
+// SetupVRF adds a controller that reconciles VRFs
+func SetupVRF(mgr ctrl.Manager, l logging.Logger, poll time.Duration) error {
+	name := managed.ControllerName(v1alpha1.VRFGroupKind)
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.VRFGroupVersionKind),
+		managed.WithExternalConnecter(clients.NewManagementPolicyConnecter(&connecter{
+			kube:  mgr.GetClient(),
+			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
+		})),
+		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+		managed.WithConnectionPublishers(),
+		managed.WithPollInterval(poll),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+	)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.VRF{}).
+		Complete(clients.NewPollingReconciler(mgr, resource.ManagedKind(v1alpha1.VRFGroupVersionKind), r))
+}
+
+type connecter struct {
+	kube        client.Client
+	usage       resource.Tracker
+	newClientFn func(ctx context.Context, config *clients.Credentials) (vrfclient.ClientWithDefaults, error)
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.VRF); !ok {
+		return nil, errors.New(errNotVRF)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	newClientFn := vrfclient.NewClient
+	if c.newClientFn != nil {
+		newClientFn = c.newClientFn
+	}
+	cfg, err := clients.GetAuthInfo(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetProviderConfigSecret)
+	}
+	client, err := newClientFn(ctx, cfg)
+
+	return &external{kube: c.kube, client: client}, errors.Wrap(err, errNewClient)
+}
+
+type external struct {
+	kube   client.Client
+	client vrfclient.ClientWithDefaults
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	v, ok := mg.(*v1alpha1.VRF)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotVRF)
+	}
+
+	vrf, _, err := e.client.Get(meta.GetExternalName(v))
+	if clients.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetVRF)
+	}
+
+	current := v.Spec.ForProvider.DeepCopy()
+	vrfclient.LateInitialize(&v.Spec.ForProvider, vrf)
+	if !cmp.Equal(current, &v.Spec.ForProvider) {
+		if err := e.kube.Update(ctx, v); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errManagedUpdateFailed)
+		}
+	}
+
+	v.Status.AtProvider, err = vrfclient.GenerateObservation(vrf)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGenObservation)
+	}
+
+	// VRFs are usable as soon as they are created
+	v.Status.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: vrfclient.IsUpToDate(v, vrf),
+	}, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	v, ok := mg.(*v1alpha1.VRF)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotVRF)
+	}
+
+	v.Status.SetConditions(xpv1.Creating())
+
+	vrf, _, err := e.client.Create(e.client.GetProjectID(v.Spec.ForProvider.ProjectID), vrfclient.CreateFromVRF(v))
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateVRF)
+	}
+
+	v.Status.AtProvider.ID = vrf.ID
+	meta.SetExternalName(v, vrf.ID)
+	if err := e.kube.Update(ctx, v); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errManagedUpdateFailed)
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	v, ok := mg.(*v1alpha1.VRF)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotVRF)
+	}
+
+	// Only the description and IP ranges of a VRF can be updated. The API
+	// rejects the removal of IP ranges that are in use.
+	_, _, err := e.client.Update(meta.GetExternalName(v), vrfclient.NewUpdateRequest(v))
+	return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateVRF)
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	v, ok := mg.(*v1alpha1.VRF)
+	if !ok {
+		return errors.New(errNotVRF)
+	}
+	v.SetConditions(xpv1.Deleting())
+
+	_, err := e.client.Delete(meta.GetExternalName(v))
+	return errors.Wrap(resource.Ignore(clients.IsNotFound, err), errDeleteVRF)
+}
@@ -0,0 +1,571 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vrf
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/vrf/v1alpha1"
+	vrfclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/vrf"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/vrf/fake"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const (
+	vrfName   = "my-cool-vrf"
+	vrfID     = "vrf-id"
+	projectID = "project-id"
+)
+
+var errorBoom = errors.New("boom")
+
+type strange struct {
+	resource.Managed
+}
+
+type vrfModifier func(*v1alpha1.VRF)
+
+func withExternalName(id string) vrfModifier {
+	return func(v *v1alpha1.VRF) { meta.SetExternalName(v, id) }
+}
+
+func withDescription(d string) vrfModifier {
+	return func(v *v1alpha1.VRF) { v.Spec.ForProvider.Description = &d }
+}
+
+func withLocalASN(asn int) vrfModifier {
+	return func(v *v1alpha1.VRF) { v.Spec.ForProvider.LocalASN = &asn }
+}
+
+func withIPRanges(r ...string) vrfModifier {
+	return func(v *v1alpha1.VRF) { v.Spec.ForProvider.IPRanges = r }
+}
+
+func withConditions(c ...xpv1.Condition) vrfModifier {
+	return func(v *v1alpha1.VRF) { v.Status.SetConditions(c...) }
+}
+
+func withObservation(o v1alpha1.VRFObservation) vrfModifier {
+	return func(v *v1alpha1.VRF) { v.Status.AtProvider = o }
+}
+
+func vrf(vm ...vrfModifier) *v1alpha1.VRF {
+	v := &v1alpha1.VRF{
+		ObjectMeta: metav1.ObjectMeta{Name: vrfName},
+		Spec: v1alpha1.VRFSpec{
+			ForProvider: v1alpha1.VRFParameters{
+				ProjectID: projectID,
+				Name:      vrfName,
+				Metro:     "da",
+			},
+		},
+	}
+	for _, m := range vm {
+		m(v)
+	}
+	return v
+}
+
+// observed returns a VRF with the supplied IP ranges
+func observed(ranges ...string) *vrfclient.VRF {
+	return &vrfclient.VRF{
+		ID:          vrfID,
+		Href:        "/metal/v1/vrfs/" + vrfID,
+		Name:        vrfName,
+		Description: "cool",
+		LocalASN:    65000,
+		IPRanges:    ranges,
+		Metro:       &packngo.Metro{Code: "da"},
+		Project:     &packngo.Project{ID: projectID},
+		CreatedAt:   "2021-01-02T03:04:05Z",
+	}
+}
+
+var _ managed.ExternalClient = &external{}
+
+func TestObserve(t *testing.T) {
+	created := metav1.NewTime(time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC))
+	observation := v1alpha1.VRFObservation{
+		ID:        vrfID,
+		Href:      "/metal/v1/vrfs/" + vrfID,
+		Metro:     "da",
+		CreatedAt: &created,
+	}
+
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg          resource.Managed
+		observation managed.ExternalObservation
+		err         error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"UpToDate": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(id string) (*vrfclient.VRF, *packngo.Response, error) {
+					if id != vrfID {
+						t.Errorf("MockGet: want %q, got %q", vrfID, id)
+					}
+					return observed("10.0.0.0/16", "2001:db8::/56"), nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg: vrf(
+					withExternalName(vrfID),
+					withDescription("cool"),
+					withLocalASN(65000),
+					withIPRanges("2001:db8::/56", "10.0.0.0/16")),
+			},
+			want: want{
+				mg: vrf(
+					withExternalName(vrfID),
+					withDescription("cool"),
+					withLocalASN(65000),
+					withIPRanges("2001:db8::/56", "10.0.0.0/16"),
+					withObservation(observation),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"LateInitialized": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockGet: func(string) (*vrfclient.VRF, *packngo.Response, error) {
+						return observed("10.0.0.0/16"), nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  vrf(withExternalName(vrfID)),
+			},
+			want: want{
+				mg: vrf(
+					withExternalName(vrfID),
+					withDescription("cool"),
+					withLocalASN(65000),
+					withIPRanges("10.0.0.0/16"),
+					withObservation(observation),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"NeedsUpdate": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(string) (*vrfclient.VRF, *packngo.Response, error) {
+					return observed("10.0.0.0/16"), nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg: vrf(
+					withExternalName(vrfID),
+					withDescription("cool"),
+					withLocalASN(65000),
+					withIPRanges("10.0.0.0/16", "10.1.0.0/16")),
+			},
+			want: want{
+				mg: vrf(
+					withExternalName(vrfID),
+					withDescription("cool"),
+					withLocalASN(65000),
+					withIPRanges("10.0.0.0/16", "10.1.0.0/16"),
+					withObservation(observation),
+					withConditions(xpv1.Available())),
+				observation: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+				},
+			},
+		},
+		"NotFound": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(string) (*vrfclient.VRF, *packngo.Response, error) {
+					return nil, nil, packettest.NotFound()
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  vrf(withExternalName(vrfID)),
+			},
+			want: want{
+				mg:          vrf(withExternalName(vrfID)),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"FailedToGet": {
+			client: &external{client: &fake.MockClient{
+				MockGet: func(string) (*vrfclient.VRF, *packngo.Response, error) {
+					return nil, nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  vrf(withExternalName(vrfID)),
+			},
+			want: want{
+				mg:  vrf(withExternalName(vrfID)),
+				err: errors.Wrap(errorBoom, errGetVRF),
+			},
+		},
+		"FailedToLateInitialize": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errorBoom)},
+				client: &fake.MockClient{
+					MockGet: func(string) (*vrfclient.VRF, *packngo.Response, error) {
+						return observed("10.0.0.0/16"), nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  vrf(withExternalName(vrfID)),
+			},
+			want: want{
+				mg: vrf(
+					withExternalName(vrfID),
+					withDescription("cool"),
+					withLocalASN(65000),
+					withIPRanges("10.0.0.0/16")),
+				err: errors.Wrap(errorBoom, errManagedUpdateFailed),
+			},
+		},
+		"NotVRF": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotVRF),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := tc.client.Observe(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Observe(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.observation, o); diff != "" {
+				t.Errorf("tc.client.Observe(): -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Created": {
+			client: &external{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client: &fake.MockClient{
+					MockGetProjectID: func(id string) string { return id },
+					MockCreate: func(pID string, createRequest *vrfclient.CreateRequest) (*vrfclient.VRF, *packngo.Response, error) {
+						if pID != projectID {
+							t.Errorf("MockCreate: want project %q, got %q", projectID, pID)
+						}
+						description := "cool"
+						want := &vrfclient.CreateRequest{
+							Name:        vrfName,
+							Description: &description,
+							Metro:       "da",
+							LocalASN:    65000,
+							IPRanges:    []string{"10.0.0.0/16"},
+						}
+						if diff := cmp.Diff(want, createRequest); diff != "" {
+							t.Errorf("MockCreate: -want, +got:\n%s", diff)
+						}
+						return &vrfclient.VRF{ID: vrfID}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  vrf(withDescription("cool"), withLocalASN(65000), withIPRanges("10.0.0.0/16")),
+			},
+			want: want{
+				mg: vrf(
+					withDescription("cool"),
+					withLocalASN(65000),
+					withIPRanges("10.0.0.0/16"),
+					withExternalName(vrfID),
+					withObservation(v1alpha1.VRFObservation{ID: vrfID}),
+					withConditions(xpv1.Creating())),
+			},
+		},
+		"FailedToCreate": {
+			client: &external{client: &fake.MockClient{
+				MockGetProjectID: func(id string) string { return id },
+				MockCreate: func(string, *vrfclient.CreateRequest) (*vrfclient.VRF, *packngo.Response, error) {
+					return nil, nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  vrf(),
+			},
+			want: want{
+				mg:  vrf(withConditions(xpv1.Creating())),
+				err: errors.Wrap(errorBoom, errCreateVRF),
+			},
+		},
+		"NotVRF": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotVRF),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.client.Create(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Create(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Updated": {
+			client: &external{client: &fake.MockClient{
+				MockUpdate: func(id string, updateRequest *vrfclient.UpdateRequest) (*vrfclient.VRF, *packngo.Response, error) {
+					if id != vrfID {
+						t.Errorf("MockUpdate: want %q, got %q", vrfID, id)
+					}
+					description, ranges := "cooler", []string{"10.0.0.0/16", "10.1.0.0/16"}
+					want := &vrfclient.UpdateRequest{Description: &description, IPRanges: &ranges}
+					if diff := cmp.Diff(want, updateRequest); diff != "" {
+						t.Errorf("MockUpdate: -want, +got:\n%s", diff)
+					}
+					return &vrfclient.VRF{ID: vrfID}, nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  vrf(withExternalName(vrfID), withDescription("cooler"), withIPRanges("10.0.0.0/16", "10.1.0.0/16")),
+			},
+			want: want{
+				mg: vrf(withExternalName(vrfID), withDescription("cooler"), withIPRanges("10.0.0.0/16", "10.1.0.0/16")),
+			},
+		},
+		"FailedToUpdate": {
+			client: &external{client: &fake.MockClient{
+				MockUpdate: func(string, *vrfclient.UpdateRequest) (*vrfclient.VRF, *packngo.Response, error) {
+					return nil, nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  vrf(withExternalName(vrfID)),
+			},
+			want: want{
+				mg:  vrf(withExternalName(vrfID)),
+				err: errors.Wrap(errorBoom, errUpdateVRF),
+			},
+		},
+		"NotVRF": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotVRF),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.client.Update(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Update(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		client managed.ExternalClient
+		args   args
+		want   want
+	}{
+		"Deleted": {
+			client: &external{client: &fake.MockClient{
+				MockDelete: func(id string) (*packngo.Response, error) {
+					if id != vrfID {
+						t.Errorf("MockDelete: want %q, got %q", vrfID, id)
+					}
+					return nil, nil
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  vrf(withExternalName(vrfID)),
+			},
+			want: want{
+				mg: vrf(withExternalName(vrfID), withConditions(xpv1.Deleting())),
+			},
+		},
+		"AlreadyDeleted": {
+			client: &external{client: &fake.MockClient{
+				MockDelete: func(string) (*packngo.Response, error) {
+					return nil, packettest.NotFound()
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  vrf(withExternalName(vrfID)),
+			},
+			want: want{
+				mg: vrf(withExternalName(vrfID), withConditions(xpv1.Deleting())),
+			},
+		},
+		"FailedToDelete": {
+			client: &external{client: &fake.MockClient{
+				MockDelete: func(string) (*packngo.Response, error) {
+					return nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  vrf(withExternalName(vrfID)),
+			},
+			want: want{
+				mg:  vrf(withExternalName(vrfID), withConditions(xpv1.Deleting())),
+				err: errors.Wrap(errorBoom, errDeleteVRF),
+			},
+		},
+		"NotVRF": {
+			client: &external{},
+			args: args{
+				ctx: context.Background(),
+				mg:  &strange{},
+			},
+			want: want{
+				mg:  &strange{},
+				err: errors.New(errNotVRF),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.client.Delete(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Delete(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
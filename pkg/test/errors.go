@@ -0,0 +1,40 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/packethost/packngo"
+)
+
+// APIError returns the error of an Equinix Metal API request that failed with
+// the supplied status code, as returned by the fake clients. The request is
+// set so that the error can be formatted.
+func APIError(status int) error {
+	return &packngo.ErrorResponse{Response: &http.Response{
+		Request:    &http.Request{Method: http.MethodGet, URL: &url.URL{}},
+		StatusCode: status,
+	}}
+}
+
+// NotFound returns the error of an Equinix Metal API request for a resource
+// that does not exist.
+func NotFound() error {
+	return APIError(http.StatusNotFound)
+}